@@ -80,4 +80,44 @@ export const apiDownload = async (url, filename = "file.pdf") => {
   }
 };
 
+// 🔹 Wait for background transcript ingestion (upload returns 202 + job ID)
+export const waitForTranscript = async (transcriptId, { intervalMs = 2000, onProgress } = {}) => {
+  for (;;) {
+    const res = await api.get(`/transcripts/${transcriptId}/status`);
+    const status = res.data;
+    if (onProgress) onProgress(status);
+
+    if (status.status === "done") return status;
+    if (status.status === "failed") {
+      throw new Error(status.error || "Transcript processing failed");
+    }
+    await new Promise((resolve) => setTimeout(resolve, intervalMs));
+  }
+};
+
+// 🔹 Student-facing messages for rejected uploads (error "code" from the API)
+const UPLOAD_ERROR_MESSAGES = {
+  missing_file: "Please choose a file to upload.",
+  empty_file: "The file is empty.",
+  file_too_large: "The file is too large.",
+  unsupported_file_type: "This file type is not supported. Upload a PDF, JPG/PNG photo, DOCX or CSV transcript.",
+  heic_not_supported: "HEIC photos are not supported. Please convert the photo to JPG or PNG.",
+  malformed_file: "The file is damaged and cannot be read.",
+  pdf_encrypted: "The PDF is encrypted. Save an unencrypted copy and upload it again.",
+  pdf_password_protected: "The PDF is password-protected. Remove the password and upload it again.",
+  too_many_pages: "The PDF has too many pages.",
+};
+
+// uploadErrorMessage returns a readable message for a failed transcript upload.
+export const uploadErrorMessage = (err, fallback = "Upload failed") => {
+  const data = err?.response?.data;
+  if (data?.code && UPLOAD_ERROR_MESSAGES[data.code]) {
+    // The server message carries the details (size limit, page count)
+    return data.code === "file_too_large" || data.code === "too_many_pages"
+      ? data.error
+      : UPLOAD_ERROR_MESSAGES[data.code];
+  }
+  return data?.error || fallback;
+};
+
 export default api;
@@ -2,7 +2,10 @@
 
 import React, { useState } from "react"
 import { Upload, FileText, CheckCircle } from "lucide-react"
-import api from "../api/axiosClient"
+import api, { uploadErrorMessage, waitForTranscript } from "../api/axiosClient"
+
+// Server sniffs the content; the extension list only filters the file picker
+const ACCEPTED_EXTENSIONS = [".pdf", ".png", ".jpg", ".jpeg", ".tif", ".tiff", ".webp", ".docx", ".csv"]
 
 export default function UploadSection({ onUpload }) {
 	const [dragActive, setDragActive] = useState(false)
@@ -30,7 +33,7 @@ export default function UploadSection({ onUpload }) {
 	}
 
 	const processFiles = async (files) => {
-		const pdfs = files.filter(f => f.name.toLowerCase().endsWith(".pdf"))
+		const pdfs = files.filter(f => ACCEPTED_EXTENSIONS.some(ext => f.name.toLowerCase().endsWith(ext)))
 		if (pdfs.length === 0) return
 		setUploadedFiles(prev => [...prev, ...pdfs.map(f => f.name)])
 
@@ -42,6 +45,9 @@ export default function UploadSection({ onUpload }) {
 			const up = await api.post("/transcripts/upload", form, { headers: { "Content-Type": "multipart/form-data" } })
 			const transcriptId = up.data.id
 
+			// wait for background extraction/OCR to finish
+			await waitForTranscript(transcriptId)
+
 			// create recommendation
 			const reco = await api.post("/recommendations", { transcript_id: transcriptId })
 			
@@ -57,7 +63,7 @@ export default function UploadSection({ onUpload }) {
 			})
 		} catch (err) {
 			console.error(err)
-			alert(err.response?.data?.error || "Upload/analysis failed")
+			alert(uploadErrorMessage(err, "Upload/analysis failed"))
 		} finally {
 			setLoading(false)
 		}
@@ -79,11 +85,11 @@ export default function UploadSection({ onUpload }) {
 						<Upload className="relative h-12 w-12 text-blue-600" />
 					</div>
 					<div>
-						<h3 className="text-lg font-semibold text-gray-900">Upload Your Academic Transcript (PDF)</h3>
-						<p className="mt-1 text-sm text-gray-500">Drag-drop or choose a file</p>
+						<h3 className="text-lg font-semibold text-gray-900">Upload Your Academic Transcript</h3>
+						<p className="mt-1 text-sm text-gray-500">Drag-drop or choose a file (PDF, photo/scan, DOCX or CSV)</p>
 					</div>
 					<label className="cursor-pointer group">
-						<input type="file" multiple onChange={handleFileInput} className="hidden" accept=".pdf" />
+						<input type="file" multiple onChange={handleFileInput} className="hidden" accept={ACCEPTED_EXTENSIONS.join(",")} />
 						<span className="inline-flex items-center gap-3 rounded-xl bg-blue-600 px-8 py-3.5 font-semibold text-white transition-all hover:bg-blue-500">
 							<Upload className="h-5 w-5" />
 							Browse Files
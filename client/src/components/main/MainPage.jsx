@@ -5,7 +5,7 @@ import PreferenceInput from './PreferenceInput';
 import { BarChart3, Brain, ChartSpline, LineChart, ScanSearch } from 'lucide-react';
 import ChatDrawer from './ChatDrawer';
 import UploadDocument from './UploadDocument';
-import api from '../../api/axiosClient';
+import api, { uploadErrorMessage, waitForTranscript } from '../../api/axiosClient';
 import RecommendationsSection from '../RecommendationsSection';
 
 export default function MainPage() {
@@ -56,6 +56,9 @@ export default function MainPage() {
             const up = await api.post("/transcripts/upload", form, { headers: { "Content-Type": "multipart/form-data" } })
             const transcriptId = up.data.id
 
+            // Extraction/OCR runs in the background; wait until it is done
+            await waitForTranscript(transcriptId)
+
             const userPreference = preference; // Capture the state
 
             // 2. Create Recommendation (Phase 2 Logic)
@@ -79,7 +82,11 @@ export default function MainPage() {
             
         } catch (err) {
             console.error("Analysis Error:", err.response?.data?.error || err.message, err);
-            alert("Failed to generate recommendations. Please check API key, logs, and ensure courses are seeded.");
+            if (err.response?.data?.code) {
+                alert(uploadErrorMessage(err));
+            } else {
+                alert("Failed to generate recommendations. Please check API key, logs, and ensure courses are seeded.");
+            }
         } finally {
             setLoading(false)
         }
@@ -4,6 +4,9 @@ import React, { useState } from "react"
 import { Upload, FileText, CheckCircle } from "lucide-react"
 import api from "../../api/axiosClient"
 
+// Server sniffs the content; the extension list only filters the file picker
+const ACCEPTED_EXTENSIONS = [".pdf", ".png", ".jpg", ".jpeg", ".tif", ".tiff", ".webp", ".docx", ".csv"]
+
 export default function UploadDocument({ onUpload, uploadedFiles, setUploadedFiles,loading, setLoading, setFileForAnalyze,  }) {
   const [dragActive, setDragActive] = useState(false)
 
@@ -30,7 +33,7 @@ export default function UploadDocument({ onUpload, uploadedFiles, setUploadedFil
   }
 
   const processFiles = async (files) => {
-    const pdfs = files.filter(f => f.name.toLowerCase().endsWith(".pdf"))
+    const pdfs = files.filter(f => ACCEPTED_EXTENSIONS.some(ext => f.name.toLowerCase().endsWith(ext)))
     if (pdfs.length === 0) return
     setUploadedFiles(prev => [...prev, ...pdfs.map(f => f.name)])
     setFileForAnalyze(pdfs[0])
@@ -54,11 +57,11 @@ export default function UploadDocument({ onUpload, uploadedFiles, setUploadedFil
             <Upload className="relative h-12 w-12 text-blue-600" />
           </div>
           <div>
-            <h3 className="text-lg font-semibold text-gray-900">Upload Your Academic Transcript (PDF)</h3>
-            <p className="mt-1 text-sm text-gray-500">Drag-drop or choose a file</p>
+            <h3 className="text-lg font-semibold text-gray-900">Upload Your Academic Transcript</h3>
+            <p className="mt-1 text-sm text-gray-500">Drag-drop or choose a file (PDF, photo/scan, DOCX or CSV)</p>
           </div>
           <label className="cursor-pointer group">
-            <input type="file" multiple onChange={handleFileInput} className="hidden" accept=".pdf" />
+            <input type="file" multiple onChange={handleFileInput} className="hidden" accept={ACCEPTED_EXTENSIONS.join(",")} />
             <span className="inline-flex items-center gap-3 rounded-xl bg-blue-600 px-8 py-3.5 font-semibold text-white transition-all hover:bg-blue-500">
               <Upload className="h-5 w-5" />
               Browse Files
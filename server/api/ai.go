@@ -13,6 +13,9 @@ import (
 	"time"
 )
 
+// defaultOpenAIModel is used when OPENAI_MODEL is not set
+const defaultOpenAIModel = "gpt-4o-mini"
+
 // aiMessage is the internal representation we use for chat messages.
 type aiMessage struct {
 	Role    string `json:"role"`
@@ -34,21 +37,42 @@ type openAIChatResponse struct {
 			Content string `json:"content"`
 		} `json:"message"`
 	} `json:"choices"`
+	Usage aiUsage `json:"usage"`
 	Error *struct {
 		Message string `json:"message"`
 		Type    string `json:"type"`
 	} `json:"error,omitempty"`
 }
 
+// aiUsage is the token count OpenAI reports for a call.
+type aiUsage struct {
+	PromptTokens     int `json:"prompt_tokens"`
+	CompletionTokens int `json:"completion_tokens"`
+	TotalTokens      int `json:"total_tokens"`
+}
+
+// add sums token counts over several calls.
+func (u *aiUsage) add(other aiUsage) {
+	u.PromptTokens += other.PromptTokens
+	u.CompletionTokens += other.CompletionTokens
+	u.TotalTokens += other.TotalTokens
+}
+
 // ------------------------------------------------------------------
 // callOpenAIChat: shared helper that hits OpenAI /v1/chat/completions
 // ------------------------------------------------------------------
 func callOpenAIChat(ctx context.Context, apiKey, model string, messages []aiMessage, expectJSON bool) (string, error) {
+	content, _, err := callOpenAIChatUsage(ctx, apiKey, model, messages, expectJSON)
+	return content, err
+}
+
+// callOpenAIChatUsage is callOpenAIChat that also returns the tokens used.
+func callOpenAIChatUsage(ctx context.Context, apiKey, model string, messages []aiMessage, expectJSON bool) (string, aiUsage, error) {
 	if apiKey == "" {
-		return "", fmt.Errorf("missing OpenAI API key")
+		return "", aiUsage{}, fmt.Errorf("missing OpenAI API key")
 	}
 	if model == "" {
-		model = "gpt-4o-mini"
+		model = defaultOpenAIModel
 	}
 
 	reqBody := openAIChatRequest{
@@ -68,7 +92,7 @@ func callOpenAIChat(ctx context.Context, apiKey, model string, messages []aiMess
 
 	req, err := http.NewRequestWithContext(ctx, "POST", "https://api.openai.com/v1/chat/completions", bytes.NewReader(b))
 	if err != nil {
-		return "", fmt.Errorf("failed to create OpenAI request: %w", err)
+		return "", aiUsage{}, fmt.Errorf("failed to create OpenAI request: %w", err)
 	}
 	req.Header.Set("Content-Type", "application/json")
 	req.Header.Set("Authorization", "Bearer "+apiKey)
@@ -77,32 +101,32 @@ func callOpenAIChat(ctx context.Context, apiKey, model string, messages []aiMess
 
 	resp, err := httpClient.Do(req)
 	if err != nil {
-		return "", fmt.Errorf("failed to reach OpenAI: %w", err)
+		return "", aiUsage{}, fmt.Errorf("failed to reach OpenAI: %w", err)
 	}
 	defer resp.Body.Close()
 
 	if resp.StatusCode >= 400 {
 		bodyBytes, _ := io.ReadAll(io.LimitReader(resp.Body, 500))
-		return "", fmt.Errorf("openai returned %d: %s", resp.StatusCode, string(bodyBytes))
+		return "", aiUsage{}, fmt.Errorf("openai returned %d: %s", resp.StatusCode, string(bodyBytes))
 	}
 
 	var out openAIChatResponse
 	if err := json.NewDecoder(resp.Body).Decode(&out); err != nil {
 		body, _ := io.ReadAll(io.LimitReader(resp.Body, 500))
 		log.Printf("[AI ERROR] failed to decode OpenAI response: %v\nResponse body (truncated): %s", err, string(body))
-		return "", fmt.Errorf("invalid OpenAI response: %w", err)
+		return "", aiUsage{}, fmt.Errorf("invalid OpenAI response: %w", err)
 	}
 
 	if out.Error != nil && out.Error.Message != "" {
-		return "", fmt.Errorf("openai error: %s (%s)", out.Error.Message, out.Error.Type)
+		return "", aiUsage{}, fmt.Errorf("openai error: %s (%s)", out.Error.Message, out.Error.Type)
 	}
 	if len(out.Choices) == 0 {
-		return "", fmt.Errorf("openai response had no choices")
+		return "", aiUsage{}, fmt.Errorf("openai response had no choices")
 	}
 
 	content := out.Choices[0].Message.Content
 	log.Printf("[AI] OpenAI response (first 200 chars): %s", truncate(content, 200))
-	return content, nil
+	return content, out.Usage, nil
 }
 
 // Helper: safe truncation for log output
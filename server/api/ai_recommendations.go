@@ -3,10 +3,12 @@
 package api
 
 import (
+	"context"
 	"database/sql"
 	"encoding/json"
 	"fmt"
-	"sort"
+	"log"
+	"slices"
 	"strings"
 	"time"
 
@@ -19,6 +21,11 @@ import (
 type createRecommendationRequest struct {
 	TranscriptID int64  `json:"transcript_id"`
 	Preference   string `json:"preference"`
+	// Optional: auto (default), ai or local; also accepted as ?mode=
+	Mode string `json:"mode"`
+	// Optional hard constraints, applied before ranking: e.g. English-only
+	// master-level courses of at most 5 ECTS in period 2, except TIES4560
+	courseFilter
 }
 
 // Response struct
@@ -30,46 +37,16 @@ type Recommendation struct {
 	Code        string  `json:"code,omitempty"`
 	Link        string  `json:"link,omitempty"`
 	CourseID    int64   `json:"course_id,omitempty"`
+	// Recommended (not required) prerequisites the student has not passed
+	MissingPrerequisites []string `json:"missing_prerequisites,omitempty"`
 }
 
 // -----------------------------------------------------------------------------
-// 1. HELPER: Extract Completed Courses (AI)
+// 1. HELPER: Filter Logic
 // -----------------------------------------------------------------------------
-func (s *Server) extractCompletedCourses(c *fiber.Ctx, transcriptText string) ([]string, error) {
-	messages := []aiMessage{
-		{
-			Role:    "system",
-			Content: "You are a data extraction assistant. Analyze the academic transcript and return a JSON object with a single key 'completed_codes' containing a list of strings. Each string must be a Course Code (e.g. 'CS101') the student has completed.",
-		},
-		{
-			Role:    "user",
-			Content: transcriptText,
-		},
-	}
-
-	raw, err := callOpenAIChat(c.Context(), s.config.OpenAIAPIKey, s.config.OpenAIModel, messages, true)
-	if err != nil {
-		return nil, err
-	}
-
-	var result struct {
-		CompletedCodes []string `json:"completed_codes"`
-	}
-	if err := json.Unmarshal([]byte(raw), &result); err != nil {
-		return []string{}, nil
-	}
-
-	normalized := make([]string, 0, len(result.CompletedCodes))
-	for _, code := range result.CompletedCodes {
-		normalized = append(normalized, strings.ToUpper(strings.TrimSpace(code)))
-	}
-	return normalized, nil
-}
-
-// -----------------------------------------------------------------------------
-// 2. HELPER: Filter Logic
-// -----------------------------------------------------------------------------
-func filterAvailableCourses(allCourses []db.Course, completedCodes []string) []db.Course {
+// filterAvailableCourses drops completed courses and those the student
+// dismissed with feedback.
+func filterAvailableCourses(allCourses []db.Course, completedCodes []string, dismissed map[int64]bool) []db.Course {
 	completedMap := make(map[string]bool)
 	for _, code := range completedCodes {
 		completedMap[code] = true
@@ -78,7 +55,7 @@ func filterAvailableCourses(allCourses []db.Course, completedCodes []string) []d
 	var available []db.Course
 	for _, course := range allCourses {
 		dbCode := strings.ToUpper(strings.TrimSpace(course.Code))
-		if !completedMap[dbCode] {
+		if !completedMap[dbCode] && !dismissed[course.ID] {
 			available = append(available, course)
 		}
 	}
@@ -86,7 +63,7 @@ func filterAvailableCourses(allCourses []db.Course, completedCodes []string) []d
 }
 
 // -----------------------------------------------------------------------------
-// 3. MAIN HANDLER: Create (Smart Filter)
+// 2. MAIN HANDLER: Create (Smart Filter)
 // -----------------------------------------------------------------------------
 func (s *Server) createRecommendation(c *fiber.Ctx) error {
 	payload, ok := c.Locals(authorizationPayloadKey).(*token.Payload)
@@ -94,25 +71,46 @@ func (s *Server) createRecommendation(c *fiber.Ctx) error {
 		return c.Status(fiber.StatusUnauthorized).JSON(errorResponse(fmt.Errorf("unauthorized")))
 	}
 
+	started := time.Now()
+
 	var req createRecommendationRequest
 	if err := c.BodyParser(&req); err != nil {
 		return c.Status(fiber.StatusBadRequest).JSON(errorResponse(err))
 	}
+	if err := req.courseFilter.normalize(); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(errorResponse(err))
+	}
+	if req.Mode == "" {
+		req.Mode = c.Query("mode")
+	}
+	mode, err := normalizeRecommendationMode(req.Mode)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(errorResponse(err))
+	}
 
-	// Fetch Transcript
-	transcript, err := s.store.GetTranscript(c.Context(), req.TranscriptID)
+	// Fetch Transcript (the user's own; their latest when no ID is given)
+	tr, status, err := s.selectTranscript(c.Context(), payload.Username, req.TranscriptID)
 	if err != nil {
+		return c.Status(status).JSON(errorResponse(err))
+	}
+	if tr == nil {
 		return c.Status(fiber.StatusNotFound).JSON(errorResponse(fmt.Errorf("transcript not found")))
 	}
+	transcript := *tr
+	req.TranscriptID = transcript.ID
 	if !transcript.TextExtracted.Valid || transcript.TextExtracted.String == "" {
+		if s.transcriptProcessing(c.Context(), transcript.ID) {
+			return c.Status(fiber.StatusConflict).JSON(errorResponse(fmt.Errorf("transcript is still being processed")))
+		}
 		return c.Status(fiber.StatusBadRequest).JSON(errorResponse(fmt.Errorf("transcript has no text content")))
 	}
 
-	// AI Step A: Extract History
-	completedCodes, err := s.extractCompletedCourses(c, transcript.TextExtracted.String)
+	// Step A: Read History (parsed once at upload)
+	transcriptCourses, err := s.transcriptCourses(c.Context(), transcript)
 	if err != nil {
-		return c.Status(fiber.StatusInternalServerError).JSON(errorResponse(fmt.Errorf("failed to analyze transcript history: %w", err)))
+		return c.Status(fiber.StatusInternalServerError).JSON(errorResponse(fmt.Errorf("failed to load transcript courses: %w", err)))
 	}
+	completedCodes := completedCourseCodes(transcriptCourses)
 
 	// DB: Get All Courses
 	allCourses, err := s.store.ListAllCourses(c.Context())
@@ -120,21 +118,186 @@ func (s *Server) createRecommendation(c *fiber.Ctx) error {
 		return c.Status(fiber.StatusInternalServerError).JSON(errorResponse(err))
 	}
 
+	// DB: Feedback on earlier recommendations
+	feedback, err := s.courseFeedback(c.Context(), payload.Username)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(errorResponse(fmt.Errorf("failed to load course feedback: %w", err)))
+	}
+
 	// Go: Filter Available
-	candidates := filterAvailableCourses(allCourses, completedCodes)
+	candidates := filterAvailableCourses(allCourses, completedCodes, feedback.Dismissed)
+	candidates, removedByConstraint := applyCourseFilter(candidates, req.courseFilter)
+
+	// Go: Prerequisites — drop courses the student cannot take yet, flag
+	// those with unmet recommended prerequisites. Courses marked as taken
+	// elsewhere count as passed.
+	passedCodes := append(slices.Clone(completedCodes), feedback.TakenCodes...)
+	prereqs, err := s.prerequisitesByCourse(c.Context(), candidates)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(errorResponse(err))
+	}
+	candidates, missingPrereqs, prereqDropped := applyPrerequisites(candidates, prereqs, passedCodes)
 	if len(candidates) == 0 {
 		return c.Status(fiber.StatusOK).JSON(fiber.Map{
-			"courses": []Recommendation{},
-			"message": "No new courses available.",
+			"courses":               []Recommendation{},
+			"message":               "No new courses available.",
+			"filters":               req.courseFilter,
+			"removed_by_constraint": removedByConstraint,
+			"prerequisites_not_met": prereqDropped,
 		})
 	}
 
+	// Rank: the model reranks the candidates; the local BM25 ranking is
+	// used on request, or when the model is not configured or fails
+	input := recommendationInput{
+		Preference:        req.Preference,
+		Transcript:        transcript,
+		TranscriptCourses: transcriptCourses,
+		CompletedCodes:    completedCodes,
+		Catalogue:         allCourses,
+		Candidates:        candidates,
+		MissingPrereqs:    missingPrereqs,
+		Liked:             feedback.Liked,
+	}
+	run := recommendationRun{
+		Mode:           recommendationModeLocal,
+		InputHash:      recommendationInputHash(req, mode, input),
+		CompletedCodes: completedCodes,
+	}
+	var finalRecs []Recommendation
+	switch {
+	case mode == recommendationModeLocal:
+	case mode == recommendationModeAuto && s.config.OpenAIAPIKey == "":
+		run.FallbackReason = "OpenAI is not configured"
+	default:
+		recs, err := s.aiRecommendations(c.Context(), payload.Username, input, &run)
+		if err == nil {
+			finalRecs, run.Mode = recs, recommendationModeAI
+		} else if mode == recommendationModeAI {
+			return c.Status(fiber.StatusInternalServerError).JSON(errorResponse(err))
+		} else {
+			log.Printf("[WARN] AI recommendation failed, using local ranking: %v", err)
+			run.FallbackReason = "OpenAI request failed"
+		}
+	}
+	if run.Mode == recommendationModeLocal {
+		finalRecs = localRecommendations(input)
+		run.Model, run.PromptVersion = localRecommenderModel, localRecommenderVersion
+		run.CandidateCount, run.EmbeddingModel = len(candidates), ""
+	}
+
+	// ***************************************************************
+	// FIX: Merge Scholarships into the Payload before saving
+	// ***************************************************************
+
+	// 1. Fetch the latest scholarships for this user
+	// FIX: Use the specific method signature provided by the user.
+	scholarships, err := s.store.ListScholarshipsByUser(c.Context(), payload.Username)
+	if err != nil {
+		// Log but do not fail the process if scholarships cannot be found
+		log.Printf("[WARN] Failed to list scholarships for payload merge: %v", err)
+	}
+
+	// 2. Encode the payload (which goes into the DB Payload column)
+	resultJSON, err := encodeRecommendationPayload(recommendationPayload{
+		Courses:      finalRecs,
+		Scholarships: scholarships,
+	})
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(errorResponse(err))
+	}
+
+	// 3. Save to DB
+
+	run.Latency = time.Since(started)
+	saved, err := s.store.CreateRecommendationTx(c.Context(), db.CreateRecommendationTxParams{
+		Recommendation: db.CreateRecommendationParams{
+			UserUsername: payload.Username,
+			TranscriptID: sql.NullInt64{Int64: req.TranscriptID, Valid: true},
+			Payload:      resultJSON,
+			Summary:      sql.NullString{String: "Course Recommendation", Valid: true},
+		},
+		Provenance: run.params(),
+	})
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(errorResponse(err))
+	}
+	reco := saved.Recommendation
+
+	// Response includes the final, combined results
+	response := fiber.Map{
+		"id":           reco.ID,
+		"created_at":   reco.CreatedAt,
+		"courses":      finalRecs,
+		"scholarships": scholarships, // Include for frontend display if needed
+		"user_pref":    req.Preference,
+		"analyzed_at":  time.Now(),
+
+		"filters":               req.courseFilter,
+		"removed_by_constraint": removedByConstraint,
+		"prerequisites_not_met": prereqDropped,
+		"mode":                  run.Mode,
+	}
+	if run.FallbackReason != "" {
+		response["fallback_reason"] = run.FallbackReason
+	}
+	return c.JSON(response)
+}
+
+// -----------------------------------------------------------------------------
+// 2b. HELPER: AI Ranking
+// -----------------------------------------------------------------------------
+
+// recommendationInput is what the recommenders rank candidates by
+type recommendationInput struct {
+	Preference        string
+	Transcript        db.Transcript
+	TranscriptCourses []db.TranscriptCourse
+	CompletedCodes    []string
+	Catalogue         []db.Course
+	Candidates        []db.Course
+	MissingPrereqs    map[int64][]string
+	Liked             []likedCourse
+}
+
+// recommendationPromptVersion names the prompt below in provenance; bump it
+// whenever the prompt changes
+const recommendationPromptVersion = "course-rerank-v3"
+
+// aiRecommendations asks the model to pick and explain the best candidates,
+// noting the model, candidates, calls and tokens in run.
+func (s *Server) aiRecommendations(ctx context.Context, username string, in recommendationInput, run *recommendationRun) ([]Recommendation, error) {
+	candidates := in.Candidates
+	run.Model, run.PromptVersion = s.openAIModel(), recommendationPromptVersion
+
+	// The free-text preference may hold personal data too
+	redactor := s.newRedactor(ctx, username)
+
+	// Go: Retrieval — only the courses closest to the preference and
+	// transcript go to the model for reranking
+	if limit := s.recommendationCandidates(); len(candidates) > limit {
+		query := retrievalQuery(redactor.Redact(in.Preference), in.TranscriptCourses)
+		retrieved, err := s.retrieveCandidates(ctx, candidates, query, limit)
+		if err != nil {
+			log.Printf("[WARN] Course retrieval failed, sending all %d candidates: %v", len(candidates), err)
+		} else {
+			candidates = retrieved
+			run.EmbeddingModel = s.embedder.Model()
+		}
+	}
+	run.CandidateCount = len(candidates)
+
 	// Prepare AI Prompt
 	type PromptCourse struct {
-		ID   int64  `json:"id"`
-		Code string `json:"code"`
-		Name string `json:"name"`
-		Desc string `json:"desc"`
+		ID                   int64    `json:"id"`
+		Code                 string   `json:"code"`
+		Name                 string   `json:"name"`
+		Desc                 string   `json:"desc"`
+		Credits              float64  `json:"credits,omitempty"`
+		Level                string   `json:"level,omitempty"`
+		Periods              []int32  `json:"periods,omitempty"`
+		Modality             string   `json:"modality,omitempty"`
+		MissingPrerequisites []string `json:"missing_prerequisites,omitempty"`
 	}
 	var promptList []PromptCourse
 	for _, c := range candidates {
@@ -146,10 +309,15 @@ func (s *Server) createRecommendation(c *fiber.Ctx) error {
 			}
 		}
 		promptList = append(promptList, PromptCourse{
-			ID:   c.ID,
-			Code: c.Code,
-			Name: c.Name,
-			Desc: desc,
+			ID:                   c.ID,
+			Code:                 c.Code,
+			Name:                 c.Name,
+			Desc:                 desc,
+			Credits:              c.Credits.Float64,
+			Level:                c.Level.String,
+			Periods:              c.TeachingPeriods,
+			Modality:             c.Modality.String,
+			MissingPrerequisites: in.MissingPrereqs[c.ID],
 		})
 	}
 	candidateBytes, _ := json.Marshal(promptList)
@@ -164,117 +332,39 @@ func (s *Server) createRecommendation(c *fiber.Ctx) error {
 		- "code" (string)
 		- "title" (string)
 		- "rationale" (string, why it fits)
-		- "match" (number 0-100)`
+		- "match" (number 0-100)
+	Courses with "missing_prerequisites" may still be chosen, but prefer courses without them
+	and mention the missing courses in the rationale.`
 
-	userPrompt := fmt.Sprintf("User Preference: %s\n\nAvailable Courses:\n%s", req.Preference, string(candidateBytes))
+	userPrompt := fmt.Sprintf("User Preference: %s\n\nAvailable Courses:\n%s", in.Preference, string(candidateBytes))
+	userPrompt += likedPromptNote(in.Liked)
+	userPrompt += transcriptLanguageNote(transcriptLanguage(in.Transcript))
 
 	messages := []aiMessage{
 		{Role: "system", Content: systemPrompt},
 		{Role: "user", Content: userPrompt},
 	}
 
-	rawResponse, err := callOpenAIChat(c.Context(), s.config.OpenAIAPIKey, s.config.OpenAIModel, messages, true)
-	if err != nil {
-		return c.Status(fiber.StatusInternalServerError).JSON(errorResponse(err))
-	}
-
-	// Parse AI Response
-	var aiResult struct {
-		Recommendations []struct {
-			CourseID  int64   `json:"course_id"`
-			Code      string  `json:"code"`
-			Title     string  `json:"title"`
-			Rationale string  `json:"rationale"`
-			Match     float64 `json:"match"`
-		} `json:"recommendations"`
-	}
-
-	if err := json.Unmarshal([]byte(rawResponse), &aiResult); err != nil {
-		var directArr []struct {
-			CourseID  int64   `json:"course_id"`
-			Code      string  `json:"code"`
-			Title     string  `json:"title"`
-			Rationale string  `json:"rationale"`
-			Match     float64 `json:"match"`
-		}
-		if err2 := json.Unmarshal([]byte(rawResponse), &directArr); err2 == nil {
-			aiResult.Recommendations = directArr
-		}
-	}
-
-	// Map back to Recommendation Struct
-	finalRecs := make([]Recommendation, 0)
-	for _, r := range aiResult.Recommendations {
-		link := ""
-		for _, c := range candidates {
-			if c.ID == r.CourseID && c.CourseLink.Valid {
-				link = c.CourseLink.String
-				break
-			}
-		}
-
-		finalRecs = append(finalRecs, Recommendation{
-			Type:        "course",
-			Title:       r.Title,
-			Code:        r.Code,
-			Description: r.Rationale,
-			Match:       r.Match,
-			Link:        link,
-			CourseID:    r.CourseID,
-		})
+	// Re-ask until enough of the picks are real candidates
+	in.Candidates = candidates
+	ask := func(messages []aiMessage) (string, error) {
+		run.Attempts++
+		content, usage, err := s.callOpenAIChatRedactedUsage(ctx, redactor, messages, true)
+		run.Usage.add(usage)
+		return content, err
 	}
-
-	sort.Slice(finalRecs, func(i, j int) bool { return finalRecs[i].Match > finalRecs[j].Match })
-
-	// ***************************************************************
-	// FIX: Merge Scholarships into the Payload before saving
-	// ***************************************************************
-
-	// 1. Fetch the latest scholarships for this user
-	// FIX: Use the specific method signature provided by the user.
-	scholarships, err := s.store.ListScholarshipsByUser(c.Context(), payload.Username)
+	recs, err := collectAIRecommendations(ask, messages, in)
 	if err != nil {
-		// Log but do not fail the process if scholarships cannot be found
-		fmt.Printf("[WARN] Failed to list scholarships for payload merge: %v\n", err)
-	}
-
-	// 2. Prepare the full payload map (which goes into the DB Payload column)
-	fullResultWrapper := fiber.Map{
-		"courses": finalRecs, // Already populated above
-	}
-
-	// 3. Add scholarships to the wrapper if found
-	if len(scholarships) > 0 {
-		// VITAL: Use the exact key name ("scholarships") that chat_ai.go is looking for.
-		fullResultWrapper["scholarships"] = scholarships
+		return nil, err
 	}
-
-	// 4. Marshal and Save to DB
-	resultJSON, _ := json.Marshal(fullResultWrapper)
-
-	reco, err := s.store.CreateRecommendation(c.Context(), db.CreateRecommendationParams{
-		UserUsername: payload.Username,
-		TranscriptID: sql.NullInt64{Int64: req.TranscriptID, Valid: true},
-		Payload:      resultJSON,
-		Summary:      sql.NullString{String: "Course Recommendation", Valid: true},
-	})
-	if err != nil {
-		return c.Status(fiber.StatusInternalServerError).JSON(errorResponse(err))
+	for i := range recs {
+		recs[i].Description = redactor.Restore(recs[i].Description)
 	}
-
-	// Response includes the final, combined results
-	return c.JSON(fiber.Map{
-		"id":           reco.ID,
-		"created_at":   reco.CreatedAt,
-		"courses":      finalRecs,
-		"scholarships": scholarships, // Include for frontend display if needed
-		"user_pref":    req.Preference,
-		"analyzed_at":  time.Now(),
-	})
+	return recs, nil
 }
 
 // -----------------------------------------------------------------------------
-// 4. HANDLERS: List & Get (Added these to fix build error)
+// 3. HANDLERS: List & Get (Added these to fix build error)
 // -----------------------------------------------------------------------------
 
 // GET /recommendations
@@ -355,14 +445,10 @@ func (s *Server) deleteCourseFromRecommendation(c *fiber.Ctx) error {
 		return c.Status(fiber.StatusForbidden).JSON(errorResponse(fmt.Errorf("forbidden")))
 	}
 
-	// 3. Unmarshal the existing Payload
-	// Use a struct that mirrors the Recommendation structure saved in the Payload column
-	var payloadMap struct {
-		Courses      []Recommendation    `json:"courses"`
-		Scholarships json.RawMessage     `json:"scholarships,omitempty"` // Preserve existing scholarships
-	}
-	if err := json.Unmarshal(reco.Payload, &payloadMap); err != nil {
-		return c.Status(fiber.StatusInternalServerError).JSON(errorResponse(fmt.Errorf("failed to parse recommendation payload: %w", err)))
+	// 3. Decode the existing Payload
+	payloadMap, err := decodeRecommendationPayload(reco.Payload)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(errorResponse(err))
 	}
 
 	// 4. Filter the Courses array to remove the specified course
@@ -381,17 +467,11 @@ func (s *Server) deleteCourseFromRecommendation(c *fiber.Ctx) error {
 		return c.Status(fiber.StatusNotFound).JSON(errorResponse(fmt.Errorf("course ID %d not found in recommendation %d", courseID, recoID)))
 	}
 
-	// 5. Re-package the entire payload (preserving scholarships)
-	newPayloadMap := fiber.Map{
-		"courses": updatedCourses,
-	}
-	if len(payloadMap.Scholarships) > 0 {
-		newPayloadMap["scholarships"] = payloadMap.Scholarships
-	}
-
-	newPayloadJSON, err := json.Marshal(newPayloadMap)
+	// 5. Re-encode the entire payload (preserving scholarships)
+	payloadMap.Courses = updatedCourses
+	newPayloadJSON, err := encodeRecommendationPayload(payloadMap)
 	if err != nil {
-		return c.Status(fiber.StatusInternalServerError).JSON(errorResponse(fmt.Errorf("failed to marshal new payload: %w", err)))
+		return c.Status(fiber.StatusInternalServerError).JSON(errorResponse(fmt.Errorf("failed to encode new payload: %w", err)))
 	}
 
 	// 6. Save the updated payload back to the database
@@ -43,6 +43,9 @@ func (s *Server) generateScholarships(c *fiber.Ctx) error {
 	}
 	txText := strings.TrimSpace(fullTr.TextExtracted.String)
 	if txText == "" {
+		if s.transcriptProcessing(c.Context(), fullTr.ID) {
+			return c.Status(fiber.StatusConflict).JSON(errorResponse(fmt.Errorf("transcript is still being processed")))
+		}
 		return c.Status(fiber.StatusBadRequest).JSON(errorResponse(fmt.Errorf("transcript has no extracted text")))
 	}
 
@@ -84,7 +87,8 @@ Respond ONLY in valid JSON format.
 
 	sb.WriteString("Transcript:\n\"\"\"\n")
 	sb.WriteString(txText)
-	sb.WriteString("\n\"\"\"\n\n")
+	sb.WriteString("\n\"\"\"\n")
+	sb.WriteString(transcriptLanguageNote(transcriptLanguage(fullTr)) + "\n\n")
 
 	if len(webResults) > 0 {
 		sb.WriteString("Scholarship Web Results:\n")
@@ -119,8 +123,8 @@ Each scholarship must contain:
 		},
 	}
 
-	// 5️⃣ Call OpenAI inference
-	resp, err := callOpenAIChat(c.Context(), s.config.OpenAIAPIKey, s.config.OpenAIModel, messages, true)
+	// 5️⃣ Call OpenAI inference (personal data masked)
+	resp, err := s.callOpenAIChatRedacted(c.Context(), s.newRedactor(c.Context(), payload.Username), messages, true)
 	if err != nil {
 		return c.Status(fiber.StatusInternalServerError).JSON(errorResponse(fmt.Errorf("openai failed: %v", err)))
 	}
@@ -4,6 +4,7 @@ package api
 
 import (
 	"fmt"
+	"log"
 	"strings"
 	"time"
 
@@ -30,29 +31,54 @@ func (s *Server) generateSummary(c *fiber.Ctx) error {
 
 	txText := strings.TrimSpace(fullTr.TextExtracted.String)
 	if txText == "" {
+		if s.transcriptProcessing(c.Context(), fullTr.ID) {
+			return c.Status(fiber.StatusConflict).JSON(errorResponse(fmt.Errorf("transcript is still being processed")))
+		}
 		return c.Status(fiber.StatusBadRequest).JSON(errorResponse(fmt.Errorf("transcript has no extracted text")))
 	}
 
+	// Prefer the structured course rows parsed at upload
+	courses, err := s.transcriptCourses(c.Context(), fullTr)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(errorResponse(err))
+	}
+	transcriptBlock := txText
+	if len(courses) > 0 {
+		transcriptBlock = "Courses (code | credits | grade | date | status):\n" + formatTranscriptCourses(courses)
+	}
+
+	// Progress since the previous upload, when there is one
+	progress, err := s.progressSinceLastUpload(c.Context(), fullTr)
+	if err != nil {
+		log.Printf("[AI-SUMMARY] Failed to diff transcript %d against the previous upload: %v", fullTr.ID, err)
+	}
+	if progress != "" {
+		transcriptBlock += "\n\nProgress since last upload:\n" + progress
+	}
+
 	// Build messages
 	prompt := fmt.Sprintf(`
 Summarize the student's transcript below into 3 concise paragraphs.
 Focus on academic strengths, software engineering skills, and AI or data science potential.
+If progress since the last upload is given, mention what the student achieved since then.
 
 Transcript:
 """%s"""
-`, txText)
+%s`, transcriptBlock, transcriptLanguageNote(transcriptLanguage(fullTr)))
 
 	messages := []aiMessage{
 		{Role: "system", Content: "You are an academic summarizer. Return only plain text summary, no markdown."},
 		{Role: "user", Content: prompt},
 	}
 
-	resp, err := callOpenAIChat(c.Context(), s.config.OpenAIAPIKey, s.config.OpenAIModel, messages, false)
+	// Personal data is masked for OpenAI and put back into the summary
+	redactor := s.newRedactor(c.Context(), payload.Username)
+	resp, err := s.callOpenAIChatRedacted(c.Context(), redactor, messages, false)
 	if err != nil {
 		return c.Status(fiber.StatusInternalServerError).JSON(errorResponse(fmt.Errorf("openai failed: %v", err)))
 	}
 
-	summaryText := strings.TrimSpace(resp)
+	summaryText := strings.TrimSpace(redactor.Restore(resp))
 
 	return c.JSON(fiber.Map{
 		"user":         payload.Username,
@@ -0,0 +1,69 @@
+// server/api/blobs.go
+
+package api
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/nibir1/go-fiber-postgres-REST-boilerplate/storage"
+	"github.com/nibir1/go-fiber-postgres-REST-boilerplate/util"
+)
+
+// Key prefixes of stored files; keys look like "uploads/<username>/<file>"
+const (
+	blobPrefixUploads   = "uploads"
+	blobPrefixSummaries = "summaries"
+)
+
+// newBlobStore creates the file store selected by STORAGE_BACKEND.
+func newBlobStore(config util.Config) (storage.BlobStore, error) {
+	switch strings.ToLower(strings.TrimSpace(config.StorageBackend)) {
+	case "", storage.Local:
+		return storage.NewLocalStore(config.StorageLocalDir)
+	case storage.S3:
+		return storage.NewS3Store(storage.S3Options{
+			Endpoint:        config.S3Endpoint,
+			Region:          config.S3Region,
+			Bucket:          config.S3Bucket,
+			AccessKeyID:     config.S3AccessKeyID,
+			SecretAccessKey: config.S3SecretAccessKey,
+			PathStyle:       config.S3UsePathStyle,
+		})
+	default:
+		return nil, fmt.Errorf("unknown storage backend %q", config.StorageBackend)
+	}
+}
+
+// uploadKey returns a new storage key for a file uploaded by username.
+func uploadKey(username, filename string) string {
+	return path.Join(blobPrefixUploads, username, fmt.Sprintf("%d_%s", time.Now().UnixNano(), sanitizeFilename(filename)))
+}
+
+// summaryKey returns the storage key of a generated summary PDF.
+func summaryKey(username, filename string) string {
+	return path.Join(blobPrefixSummaries, username, sanitizeFilename(filename))
+}
+
+// removeBlob deletes a stored file, logging instead of failing the request
+// when it cannot be removed.
+func (s *Server) removeBlob(ctx context.Context, key string) {
+	if strings.TrimSpace(key) == "" {
+		return
+	}
+	if err := s.blobs.Delete(ctx, key); err != nil {
+		log.Printf("[WARN] Failed to delete file %s: %v", key, err)
+	}
+}
+
+// presignExpiry is how long presigned download URLs stay valid.
+func (s *Server) presignExpiry() time.Duration {
+	if s.config.StoragePresignExpiry > 0 {
+		return s.config.StoragePresignExpiry
+	}
+	return 15 * time.Minute
+}
@@ -15,6 +15,7 @@ import (
 
 	"github.com/gofiber/fiber/v2"
 	db "github.com/nibir1/go-fiber-postgres-REST-boilerplate/db/sqlc" // Added for DB Params
+	"github.com/nibir1/go-fiber-postgres-REST-boilerplate/redact"
 	"github.com/nibir1/go-fiber-postgres-REST-boilerplate/token"
 )
 
@@ -53,46 +54,50 @@ func (s *Server) chatStream(c *fiber.Ctx) error {
 		if err == nil {
 			// Fetch the full Recommendation record
 			reco, err := s.store.GetRecommendation(c.Context(), recoID)
+			if err == nil && reco.UserUsername != payload.Username {
+				return c.Status(fiber.StatusNotFound).JSON(errorResponse(fmt.Errorf("recommendation not found")))
+			}
 
 			if err == nil {
 				var contextBuilder strings.Builder
 
-				// 3a. Inject Transcript Text
+				// 3a. Inject Transcript (structured course rows, raw text as fallback)
 				if reco.TranscriptID.Valid {
 					tr, trErr := s.store.GetTranscript(c.Context(), reco.TranscriptID.Int64)
-					if trErr == nil && tr.TextExtracted.Valid && strings.TrimSpace(tr.TextExtracted.String) != "" {
-						contextBuilder.WriteString(fmt.Sprintf("\n\n[USER ACADEMIC TRANSCRIPT TEXT]\n%s\n", tr.TextExtracted.String))
-					}
-				}
+					if trErr == nil {
+						courses, cErr := s.transcriptCourses(c.Context(), tr)
+						if cErr != nil {
+							log.Printf("[AI-CHAT] Failed to load transcript courses for transcript %d: %v", tr.ID, cErr)
+						}
+						if len(courses) > 0 {
+							contextBuilder.WriteString(fmt.Sprintf("\n\n[USER TRANSCRIPT COURSES (code | credits | grade | date | status)]\n%s", formatTranscriptCourses(courses)))
+						} else if tr.TextExtracted.Valid && strings.TrimSpace(tr.TextExtracted.String) != "" {
+							contextBuilder.WriteString(fmt.Sprintf("\n\n[USER ACADEMIC TRANSCRIPT TEXT]\n%s\n", tr.TextExtracted.String))
+						}
+						contextBuilder.WriteString(transcriptLanguageNote(transcriptLanguage(tr)))
 
-				// --- DEBUG STEP: Log the raw payload to find the correct key ---
-				if len(reco.Payload) > 2 {
-					log.Printf("[AI-CHAT] DEBUG RAW PAYLOAD: %s", string(reco.Payload))
+						if progress, pErr := s.progressSinceLastUpload(c.Context(), tr); pErr != nil {
+							log.Printf("[AI-CHAT] Failed to diff transcript %d against the previous upload: %v", tr.ID, pErr)
+						} else if progress != "" {
+							contextBuilder.WriteString(fmt.Sprintf("\n\n[PROGRESS SINCE LAST UPLOAD]\n%s", progress))
+						}
+					}
 				}
-				// ------------------------------------------------------------------
 
 				// 3b. Inject Recommendation Payload (Courses, Rationale)
 				if len(reco.Payload) > 2 {
-					var payloadMap map[string]json.RawMessage
-					if json.Unmarshal(reco.Payload, &payloadMap) == nil {
+					if recoPayload, pErr := decodeRecommendationPayload(reco.Payload); pErr == nil {
 
-						// i. Inject Recommended Courses
-						if coursesJSON, ok := payloadMap["courses"]; ok && len(coursesJSON) > 2 {
+						// i. Inject Recommended Courses. Payload scholarships are
+						// ignored, because we will fetch the FRESH list from the DB below.
+						if len(recoPayload.Courses) > 0 {
+							coursesJSON, _ := json.Marshal(recoPayload.Courses)
 							contextBuilder.WriteString(fmt.Sprintf("\n\n[RECOMMENDED COURSES JSON]\n%s\n", string(coursesJSON)))
 						}
 
-						// ii. We remove "scholarships" key from here if it exists in the JSON blob,
-						// because we will fetch the FRESH list from the DB below.
-						delete(payloadMap, "courses")
-						delete(payloadMap, "scholarships") // Ignore blob scholarships, use DB instead
-
-						// iii. Inject remaining payload
-						if remainingJSON, err := json.Marshal(payloadMap); err == nil && len(remainingJSON) > 2 {
-							contextBuilder.WriteString(fmt.Sprintf("\n\n[OTHER RECOMMENDATION DATA JSON]\n%s\n", string(remainingJSON)))
-						}
-
 					} else {
 						// Fallback: If parsing fails, inject the entire payload raw
+						log.Printf("[AI-CHAT] Failed to decode payload of Recommendation ID %d: %v", recoID, pErr)
 						contextBuilder.WriteString(fmt.Sprintf("\n\n[RAW RECOMMENDATION PAYLOAD JSON]\n%s\n", string(reco.Payload)))
 					}
 				}
@@ -156,12 +161,15 @@ func (s *Server) chatStream(c *fiber.Ctx) error {
 	// -------------------------------------------------------------------------
 
 	// --- Build messages for OpenAI (incorporate systemContext) ---
+	// Personal data in the context and the conversation is masked; the
+	// streamed answer is restored below before it reaches the user.
+	redactor := s.newRedactor(c.Context(), payload.Username)
 	var openAIMessages []map[string]string
 
 	// VITAL: Insert the enhanced system context as the first message
 	openAIMessages = append(openAIMessages, map[string]string{
 		"role":    "system",
-		"content": systemContext,
+		"content": redactor.Redact(systemContext),
 	})
 
 	// Append user history and current message
@@ -174,7 +182,7 @@ func (s *Server) chatStream(c *fiber.Ctx) error {
 		if content != "" {
 			openAIMessages = append(openAIMessages, map[string]string{
 				"role":    role,
-				"content": content,
+				"content": redactor.Redact(content),
 			})
 		}
 	}
@@ -218,6 +226,7 @@ func (s *Server) chatStream(c *fiber.Ctx) error {
 
 	// --- Process Stream ---
 	reader := bufio.NewReader(resp.Body)
+	restorer := redact.NewStreamRestorer(redactor)
 
 	for {
 		line, err := reader.ReadBytes('\n')
@@ -261,7 +270,7 @@ func (s *Server) chatStream(c *fiber.Ctx) error {
 			continue
 		}
 
-		token := chunk.Choices[0].Delta.Content
+		token := restorer.Write(chunk.Choices[0].Delta.Content)
 		if token == "" {
 			continue
 		}
@@ -272,6 +281,10 @@ func (s *Server) chatStream(c *fiber.Ctx) error {
 		writer.Flush()
 	}
 
+	if rest := restorer.Flush(); rest != "" {
+		fmt.Fprintf(writer, "data: %s\n\n", strings.ReplaceAll(rest, "\n", "\\n"))
+	}
+
 	fmt.Fprint(writer, "data: [DONE]\n\n")
 	writer.Flush()
 	return nil
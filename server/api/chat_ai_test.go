@@ -0,0 +1,50 @@
+// server/api/chat_ai_test.go
+
+package api
+
+import (
+	"bytes"
+	"database/sql"
+	"encoding/json"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/golang/mock/gomock"
+	mockdb "github.com/nibir1/go-fiber-postgres-REST-boilerplate/db/mock"
+	db "github.com/nibir1/go-fiber-postgres-REST-boilerplate/db/sqlc"
+	"github.com/nibir1/go-fiber-postgres-REST-boilerplate/util"
+	"github.com/stretchr/testify/require"
+)
+
+func TestChatStreamOtherUsersRecommendation(t *testing.T) {
+	username := util.RandomOwner()
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	store := mockdb.NewMockStore(ctrl)
+	reco := db.Recommendation{
+		ID:           5,
+		UserUsername: "someone_else",
+		TranscriptID: sql.NullInt64{Int64: 1, Valid: true},
+		Payload:      []byte(`{"schema_version":1,"courses":[]}`),
+	}
+	store.EXPECT().GetRecommendation(gomock.Any(), gomock.Eq(int64(5))).Times(1).Return(reco, nil)
+	store.EXPECT().GetTranscript(gomock.Any(), gomock.Any()).Times(0)
+	store.EXPECT().ListTranscriptCourses(gomock.Any(), gomock.Any()).Times(0)
+
+	server := newFiberTestServer(t, store)
+	data, err := json.Marshal(fiber.Map{"messages": []ChatMessage{{Role: "user", Content: "Which course next?"}}})
+	require.NoError(t, err)
+	req, err := http.NewRequest(http.MethodPost, "/api/chat/stream", bytes.NewReader(data))
+	require.NoError(t, err)
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-Recommendation-ID", "5")
+	addAuthorization(t, req, server.tokenMaker, authorizationTypeBearer, username, time.Minute)
+
+	resp, err := server.app.Test(req, -1)
+	require.NoError(t, err)
+	require.Equal(t, http.StatusNotFound, resp.StatusCode)
+}
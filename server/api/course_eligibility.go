@@ -0,0 +1,254 @@
+// server/api/course_eligibility.go
+
+package api
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+
+	"github.com/gofiber/fiber/v2"
+	db "github.com/nibir1/go-fiber-postgres-REST-boilerplate/db/sqlc"
+	"github.com/nibir1/go-fiber-postgres-REST-boilerplate/prereq"
+	"github.com/nibir1/go-fiber-postgres-REST-boilerplate/token"
+)
+
+// courseEligibilityResponse tells a student whether they meet a course's
+// prerequisites. Eligible is true when the prerequisites are met or only
+// advisory; Missing lists what is still needed either way.
+type courseEligibilityResponse struct {
+	Code              string              `json:"code"`
+	Name              string              `json:"name"`
+	PrerequisitesText string              `json:"prerequisites_text"`
+	Requirement       *prereq.Requirement `json:"requirement"`
+	Advisory          bool                `json:"advisory"`
+	PrerequisitesMet  bool                `json:"prerequisites_met"`
+	Eligible          bool                `json:"eligible"`
+	Missing           []string            `json:"missing"`
+	TranscriptID      *int64              `json:"transcript_id"`
+}
+
+// coursePrerequisites returns the prerequisites stored for course in row. A
+// missing row, or one parsed from text that has since changed outside the
+// API, falls back to parsing the text until the start-up sync stores it.
+func coursePrerequisites(course db.Course, row *db.CoursePrerequisite) (prereq.Prerequisites, error) {
+	if row == nil || row.Source != course.Prerequisites.String {
+		return prereq.Parse(course.Prerequisites.String, course.Code), nil
+	}
+	var requirement *prereq.Requirement
+	if err := json.Unmarshal(row.Requirement, &requirement); err != nil {
+		return prereq.Prerequisites{}, fmt.Errorf("invalid stored prerequisites of %s: %w", course.Code, err)
+	}
+	return prereq.Prerequisites{Requirement: requirement, Advisory: row.Advisory}, nil
+}
+
+// prerequisitesByCourse loads the stored prerequisites of courses by course
+// ID.
+func (s *Server) prerequisitesByCourse(ctx context.Context, courses []db.Course) (map[int64]prereq.Prerequisites, error) {
+	rows, err := s.store.ListCoursePrerequisites(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load course prerequisites: %w", err)
+	}
+	stored := make(map[int64]*db.CoursePrerequisite, len(rows))
+	for i := range rows {
+		stored[rows[i].CourseID] = &rows[i]
+	}
+
+	out := make(map[int64]prereq.Prerequisites, len(courses))
+	for _, course := range courses {
+		p, err := coursePrerequisites(course, stored[course.ID])
+		if err != nil {
+			return nil, err
+		}
+		out[course.ID] = p
+	}
+	return out, nil
+}
+
+// ParseCoursePrerequisites parses the prerequisites text of a course into
+// the row stored in course_prerequisites.
+func ParseCoursePrerequisites(code, text string) (db.CoursePrerequisitesParams, error) {
+	p := prereq.Parse(text, code)
+	requirement, err := json.Marshal(p.Requirement)
+	if err != nil {
+		return db.CoursePrerequisitesParams{}, fmt.Errorf("failed to encode prerequisites of %s: %w", code, err)
+	}
+	return db.CoursePrerequisitesParams{Source: text, Requirement: requirement, Advisory: p.Advisory}, nil
+}
+
+// syncCoursePrerequisites stores the parsed prerequisites of courses that
+// have none yet, e.g. those in the catalogue before the table existed, or
+// whose text changed outside the API.
+func (s *Server) syncCoursePrerequisites(ctx context.Context) {
+	courses, err := s.store.ListCoursesWithStalePrerequisites(ctx)
+	if err != nil {
+		log.Printf("[INIT] course prerequisites not synced: %v", err)
+		return
+	}
+	for _, course := range courses {
+		p, err := ParseCoursePrerequisites(course.Code, course.Prerequisites.String)
+		if err == nil {
+			_, err = s.store.UpsertCoursePrerequisites(ctx, db.UpsertCoursePrerequisitesParams{
+				CourseID:    course.ID,
+				Source:      p.Source,
+				Requirement: p.Requirement,
+				Advisory:    p.Advisory,
+			})
+		}
+		if err != nil {
+			log.Printf("[INIT] course prerequisites not synced: %v", err)
+			return
+		}
+	}
+	if len(courses) > 0 {
+		log.Printf("[INIT] parsed the prerequisites of %d courses", len(courses))
+	}
+}
+
+// courseCodeSet turns upper-cased course codes into a lookup set.
+func courseCodeSet(codes []string) map[string]bool {
+	set := make(map[string]bool, len(codes))
+	for _, code := range codes {
+		set[code] = true
+	}
+	return set
+}
+
+// applyPrerequisites drops candidates whose required prerequisites, looked
+// up by course ID in prereqs, are not in completedCodes. Candidates with
+// unmet advisory prerequisites stay, and what they miss is returned by course
+// ID. dropped lists the removed codes.
+func applyPrerequisites(candidates []db.Course, prereqs map[int64]prereq.Prerequisites, completedCodes []string) (eligible []db.Course, missing map[int64][]string, dropped []string) {
+	completed := courseCodeSet(completedCodes)
+	missing = make(map[int64][]string)
+	for _, course := range candidates {
+		p := prereqs[course.ID]
+		if p.Blocks(completed) {
+			dropped = append(dropped, course.Code)
+			continue
+		}
+		if m := p.Missing(completed); len(m) > 0 {
+			missing[course.ID] = m
+		}
+		eligible = append(eligible, course)
+	}
+	return eligible, missing, dropped
+}
+
+// selectTranscript returns the user's transcript with the given ID, or their
+// latest one when id is 0. It returns nil when the user has no transcripts.
+// On failure it also returns the HTTP status.
+func (s *Server) selectTranscript(ctx context.Context, username string, id int64) (*db.Transcript, int, error) {
+	if id == 0 {
+		transcripts, err := s.store.ListTranscripts(ctx, username)
+		if err != nil {
+			return nil, fiber.StatusInternalServerError, err
+		}
+		if len(transcripts) == 0 {
+			return nil, fiber.StatusOK, nil
+		}
+		id = transcripts[0].ID
+	}
+
+	tr, err := s.store.GetTranscript(ctx, id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, fiber.StatusNotFound, fmt.Errorf("transcript not found")
+		}
+		return nil, fiber.StatusInternalServerError, err
+	}
+	if tr.UserUsername != username {
+		return nil, fiber.StatusForbidden, fmt.Errorf("forbidden")
+	}
+	return &tr, fiber.StatusOK, nil
+}
+
+// completedCourseSet returns the codes of the courses passed on tr; a nil
+// transcript has none.
+func (s *Server) completedCourseSet(ctx context.Context, tr *db.Transcript) (map[string]bool, error) {
+	if tr == nil {
+		return map[string]bool{}, nil
+	}
+	rows, err := s.transcriptCourses(ctx, *tr)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load transcript courses: %w", err)
+	}
+	return courseCodeSet(completedCourseCodes(rows)), nil
+}
+
+func transcriptIDOf(tr *db.Transcript) *int64 {
+	if tr == nil {
+		return nil
+	}
+	return &tr.ID
+}
+
+// GET /api/courses/:code/eligibility?transcript_id=12
+// Checks the course's prerequisites against the passed courses of a
+// transcript (default: the latest one).
+func (s *Server) getCourseEligibility(c *fiber.Ctx) error {
+	// 0) Auth
+	payload, ok := c.Locals(authorizationPayloadKey).(*token.Payload)
+	if !ok || payload == nil {
+		return c.Status(fiber.StatusUnauthorized).JSON(errorResponse(fmt.Errorf("unauthorized")))
+	}
+
+	// 1) Course
+	code := normalizeCourseCode(c.Params("code"))
+	if code == "" {
+		return c.Status(fiber.StatusBadRequest).JSON(errorResponse(fmt.Errorf("missing code")))
+	}
+	course, err := s.store.GetCourseByCode(c.Context(), code)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return c.Status(fiber.StatusNotFound).JSON(errorResponse(fmt.Errorf("course not found")))
+		}
+		return c.Status(fiber.StatusInternalServerError).JSON(errorResponse(err))
+	}
+
+	// 2) Completed courses
+	transcriptID, err := queryPositiveInt(c, "transcript_id", 0)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(errorResponse(err))
+	}
+	tr, status, err := s.selectTranscript(c.Context(), payload.Username, int64(transcriptID))
+	if err != nil {
+		return c.Status(status).JSON(errorResponse(err))
+	}
+	completed, err := s.completedCourseSet(c.Context(), tr)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(errorResponse(err))
+	}
+
+	// 3) Check
+	var stored *db.CoursePrerequisite
+	row, err := s.store.GetCoursePrerequisites(c.Context(), course.ID)
+	switch {
+	case err == nil:
+		stored = &row
+	case !errors.Is(err, sql.ErrNoRows):
+		return c.Status(fiber.StatusInternalServerError).JSON(errorResponse(fmt.Errorf("failed to load course prerequisites: %w", err)))
+	}
+	p, err := coursePrerequisites(course, stored)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(errorResponse(err))
+	}
+	missing := p.Missing(completed)
+	if missing == nil {
+		missing = []string{}
+	}
+	return c.JSON(courseEligibilityResponse{
+		Code:              course.Code,
+		Name:              course.Name,
+		PrerequisitesText: course.Prerequisites.String,
+		Requirement:       p.Requirement,
+		Advisory:          p.Advisory,
+		PrerequisitesMet:  p.Met(completed),
+		Eligible:          !p.Blocks(completed),
+		Missing:           missing,
+		TranscriptID:      transcriptIDOf(tr),
+	})
+}
@@ -0,0 +1,222 @@
+// server/api/course_eligibility_test.go
+
+package api
+
+import (
+	"database/sql"
+	"encoding/json"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	mockdb "github.com/nibir1/go-fiber-postgres-REST-boilerplate/db/mock"
+	db "github.com/nibir1/go-fiber-postgres-REST-boilerplate/db/sqlc"
+	"github.com/nibir1/go-fiber-postgres-REST-boilerplate/prereq"
+	"github.com/nibir1/go-fiber-postgres-REST-boilerplate/util"
+	"github.com/stretchr/testify/require"
+)
+
+// newTestCoursePrerequisites returns the course_prerequisites rows of courses.
+func newTestCoursePrerequisites(t *testing.T, courses ...db.Course) []db.CoursePrerequisite {
+	rows := make([]db.CoursePrerequisite, 0, len(courses))
+	for _, c := range courses {
+		p := prereq.Parse(c.Prerequisites.String, c.Code)
+		requirement, err := json.Marshal(p.Requirement)
+		require.NoError(t, err)
+		rows = append(rows, db.CoursePrerequisite{
+			CourseID:    c.ID,
+			Source:      c.Prerequisites.String,
+			Requirement: requirement,
+			Advisory:    p.Advisory,
+		})
+	}
+	return rows
+}
+
+// testPrerequisites parses the prerequisites of courses by course ID.
+func testPrerequisites(courses ...db.Course) map[int64]prereq.Prerequisites {
+	out := make(map[int64]prereq.Prerequisites, len(courses))
+	for _, c := range courses {
+		out[c.ID] = prereq.Parse(c.Prerequisites.String, c.Code)
+	}
+	return out
+}
+
+func TestCoursePrerequisites(t *testing.T) {
+	course := newTestCatalogueCourse("TIES471", "Real-time Graphics")
+	course.Prerequisites = sql.NullString{String: "TIEA311", Valid: true}
+
+	// The stored graph wins over the text
+	row := db.CoursePrerequisite{
+		CourseID:    course.ID,
+		Source:      "TIEA311",
+		Requirement: []byte(`{"kind":"course","code":"TIEA211"}`),
+		Advisory:    true,
+	}
+	p, err := coursePrerequisites(course, &row)
+	require.NoError(t, err)
+	require.Equal(t, "TIEA211", p.Requirement.Code)
+	require.True(t, p.Advisory)
+
+	// Rows parsed from older text, and missing rows, are parsed again
+	row.Source = "TIEA211"
+	p, err = coursePrerequisites(course, &row)
+	require.NoError(t, err)
+	require.Equal(t, "TIEA311", p.Requirement.Code)
+	require.False(t, p.Advisory)
+
+	p, err = coursePrerequisites(course, nil)
+	require.NoError(t, err)
+	require.Equal(t, "TIEA311", p.Requirement.Code)
+
+	// No codes are stored as JSON null
+	course.Prerequisites = sql.NullString{}
+	p, err = coursePrerequisites(course, &newTestCoursePrerequisites(t, course)[0])
+	require.NoError(t, err)
+	require.Nil(t, p.Requirement)
+
+	_, err = coursePrerequisites(course, &db.CoursePrerequisite{Requirement: []byte(`{`)})
+	require.Error(t, err)
+}
+
+func TestApplyPrerequisites(t *testing.T) {
+	required := newTestCatalogueCourse("TIES513", "Computer Graphics")
+	required.ID = 1
+	required.Prerequisites = sql.NullString{String: "TIES4211 (or the earlier TIEA211), TIEA311 and TIES471", Valid: true}
+	recommended := newTestCatalogueCourse("KYBS3040", "Cyber Security")
+	recommended.ID = 2
+	recommended.Prerequisites = sql.NullString{String: "It is recommended you have already completed TJTSM51.", Valid: true}
+	open := newTestCatalogueCourse("TJTS5012", "Additional Research Methods Module")
+	open.ID = 3
+
+	prereqs := testPrerequisites(required, recommended, open)
+	eligible, missing, dropped := applyPrerequisites([]db.Course{required, recommended, open}, prereqs, []string{"TIEA311"})
+	require.Equal(t, []db.Course{recommended, open}, eligible)
+	require.Equal(t, map[int64][]string{2: {"TJTSM51"}}, missing)
+	require.Equal(t, []string{"TIES513"}, dropped)
+
+	eligible, _, dropped = applyPrerequisites([]db.Course{required}, prereqs, []string{"TIEA211", "TIEA311", "TIES471"})
+	require.Len(t, eligible, 1)
+	require.Empty(t, dropped)
+}
+
+func TestGetCourseEligibilityAPI(t *testing.T) {
+	username := util.RandomOwner()
+	tr := newTestTranscript(t, username)
+
+	course := newTestCatalogueCourse("TIES513", "Computer Graphics")
+	course.Prerequisites = sql.NullString{String: "TIES4211 (or the earlier TIEA211), TIEA311 and TIES471", Valid: true}
+	rows := []db.TranscriptCourse{
+		testCourse("TIEA311", 5, "4", courseStatusCompleted),
+		testCourse("TIES471", 5, "0", courseStatusFailed),
+	}
+
+	type eligibility struct {
+		Eligible     bool     `json:"eligible"`
+		Missing      []string `json:"missing"`
+		TranscriptID *int64   `json:"transcript_id"`
+	}
+
+	testCases := []struct {
+		name          string
+		query         string
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(t *testing.T, resp *http.Response)
+	}{
+		{
+			name: "LatestTranscript",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetCourseByCode(gomock.Any(), gomock.Eq("TIES513")).Times(1).Return(course, nil)
+				store.EXPECT().ListTranscripts(gomock.Any(), gomock.Eq(username)).Times(1).
+					Return([]db.ListTranscriptsRow{{ID: tr.ID, UserUsername: username}}, nil)
+				store.EXPECT().GetTranscript(gomock.Any(), gomock.Eq(tr.ID)).Times(1).Return(tr, nil)
+				store.EXPECT().ListTranscriptCourses(gomock.Any(), gomock.Eq(tr.ID)).Times(1).Return(rows, nil)
+				store.EXPECT().GetCoursePrerequisites(gomock.Any(), gomock.Eq(course.ID)).Times(1).
+					Return(newTestCoursePrerequisites(t, course)[0], nil)
+			},
+			checkResponse: func(t *testing.T, resp *http.Response) {
+				require.Equal(t, http.StatusOK, resp.StatusCode)
+
+				var body eligibility
+				require.NoError(t, json.NewDecoder(resp.Body).Decode(&body))
+				require.False(t, body.Eligible)
+				require.Equal(t, []string{"TIES4211 or TIEA211", "TIES471"}, body.Missing)
+				require.NotNil(t, body.TranscriptID)
+				require.Equal(t, tr.ID, *body.TranscriptID)
+			},
+		},
+		{
+			name: "NoTranscripts",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetCourseByCode(gomock.Any(), gomock.Any()).Times(1).Return(course, nil)
+				store.EXPECT().ListTranscripts(gomock.Any(), gomock.Eq(username)).Times(1).Return([]db.ListTranscriptsRow{}, nil)
+				store.EXPECT().GetTranscript(gomock.Any(), gomock.Any()).Times(0)
+				store.EXPECT().GetCoursePrerequisites(gomock.Any(), gomock.Eq(course.ID)).Times(1).
+					Return(db.CoursePrerequisite{}, sql.ErrNoRows)
+			},
+			checkResponse: func(t *testing.T, resp *http.Response) {
+				require.Equal(t, http.StatusOK, resp.StatusCode)
+
+				var body eligibility
+				require.NoError(t, json.NewDecoder(resp.Body).Decode(&body))
+				require.False(t, body.Eligible)
+				require.Len(t, body.Missing, 3)
+				require.Nil(t, body.TranscriptID)
+			},
+		},
+		{
+			name:  "OtherUsersTranscript",
+			query: "?transcript_id=1",
+			buildStubs: func(store *mockdb.MockStore) {
+				other := newTestTranscript(t, "someone_else")
+				store.EXPECT().GetCourseByCode(gomock.Any(), gomock.Any()).Times(1).Return(course, nil)
+				store.EXPECT().GetTranscript(gomock.Any(), gomock.Eq(int64(1))).Times(1).Return(other, nil)
+				store.EXPECT().ListTranscriptCourses(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, resp *http.Response) {
+				require.Equal(t, http.StatusForbidden, resp.StatusCode)
+			},
+		},
+		{
+			name:  "InvalidTranscriptID",
+			query: "?transcript_id=abc",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetCourseByCode(gomock.Any(), gomock.Any()).Times(1).Return(course, nil)
+				store.EXPECT().GetTranscript(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, resp *http.Response) {
+				require.Equal(t, http.StatusBadRequest, resp.StatusCode)
+			},
+		},
+		{
+			name: "CourseNotFound",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetCourseByCode(gomock.Any(), gomock.Any()).Times(1).Return(db.Course{}, sql.ErrNoRows)
+				store.EXPECT().ListTranscripts(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, resp *http.Response) {
+				require.Equal(t, http.StatusNotFound, resp.StatusCode)
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStubs(store)
+
+			server := newFiberTestServer(t, store)
+			req, err := http.NewRequest(http.MethodGet, "/api/courses/ties513/eligibility"+tc.query, nil)
+			require.NoError(t, err)
+			addAuthorization(t, req, server.tokenMaker, authorizationTypeBearer, username, time.Minute)
+
+			resp, err := server.app.Test(req, -1)
+			require.NoError(t, err)
+			tc.checkResponse(t, resp)
+		})
+	}
+}
@@ -0,0 +1,183 @@
+// server/api/course_feedback.go
+
+package api
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	db "github.com/nibir1/go-fiber-postgres-REST-boilerplate/db/sqlc"
+	"github.com/nibir1/go-fiber-postgres-REST-boilerplate/token"
+)
+
+// Feedback kinds on a recommended course. Liked courses steer the ranking
+// towards similar topics; the others dismiss the course for good.
+const (
+	feedbackLike          = "like"
+	feedbackDislike       = "dislike"
+	feedbackAlreadyTaken  = "already_taken"
+	feedbackNotInterested = "not_interested"
+)
+
+// Longest reason kept, in characters
+const maxFeedbackReason = 500
+
+// normalizeFeedbackKind checks kind, accepting dashes and any case
+// ("Already-Taken").
+func normalizeFeedbackKind(kind string) (string, error) {
+	k := strings.ReplaceAll(strings.ToLower(strings.TrimSpace(kind)), "-", "_")
+	switch k {
+	case feedbackLike, feedbackDislike, feedbackAlreadyTaken, feedbackNotInterested:
+		return k, nil
+	default:
+		return "", fmt.Errorf("invalid feedback %q, use like, dislike, already-taken or not-interested", kind)
+	}
+}
+
+type createCourseFeedbackRequest struct {
+	Feedback string `json:"feedback"`
+	Reason   string `json:"reason"`
+}
+
+type courseFeedbackResponse struct {
+	RecommendationID int64     `json:"recommendation_id"`
+	CourseID         int64     `json:"course_id"`
+	Feedback         string    `json:"feedback"`
+	Reason           string    `json:"reason,omitempty"`
+	UpdatedAt        time.Time `json:"updated_at"`
+}
+
+// POST /recommendations/:id/courses/:course_id/feedback
+func (s *Server) createCourseFeedback(c *fiber.Ctx) error {
+	payload, ok := c.Locals(authorizationPayloadKey).(*token.Payload)
+	if !ok || payload == nil {
+		return c.Status(fiber.StatusUnauthorized).JSON(errorResponse(fmt.Errorf("unauthorized")))
+	}
+
+	recoID, err := parseIDParam(c, "id")
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(errorResponse(fmt.Errorf("invalid recommendation ID")))
+	}
+	courseID, err := parseIDParam(c, "course_id")
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(errorResponse(fmt.Errorf("invalid course ID")))
+	}
+
+	var req createCourseFeedbackRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(errorResponse(err))
+	}
+	kind, err := normalizeFeedbackKind(req.Feedback)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(errorResponse(err))
+	}
+	reason := strings.TrimSpace(req.Reason)
+	if len([]rune(reason)) > maxFeedbackReason {
+		return c.Status(fiber.StatusBadRequest).JSON(errorResponse(fmt.Errorf("reason is longer than %d characters", maxFeedbackReason)))
+	}
+
+	reco, err := s.store.GetRecommendation(c.Context(), recoID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return c.Status(fiber.StatusNotFound).JSON(errorResponse(fmt.Errorf("recommendation not found")))
+		}
+		return c.Status(fiber.StatusInternalServerError).JSON(errorResponse(err))
+	}
+	if reco.UserUsername != payload.Username {
+		return c.Status(fiber.StatusForbidden).JSON(errorResponse(fmt.Errorf("forbidden")))
+	}
+
+	// Feedback is on what was recommended, not on any catalogue course
+	saved, err := decodeRecommendationPayload(reco.Payload)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(errorResponse(err))
+	}
+	found := false
+	for _, course := range saved.Courses {
+		if course.CourseID == courseID {
+			found = true
+			break
+		}
+	}
+	if !found {
+		return c.Status(fiber.StatusNotFound).JSON(errorResponse(fmt.Errorf("course ID %d not found in recommendation %d", courseID, recoID)))
+	}
+
+	feedback, err := s.store.UpsertCourseFeedback(c.Context(), db.UpsertCourseFeedbackParams{
+		UserUsername:     payload.Username,
+		CourseID:         courseID,
+		RecommendationID: sql.NullInt64{Int64: recoID, Valid: true},
+		Kind:             kind,
+		Reason:           sqlStringOrNull(reason),
+	})
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(errorResponse(fmt.Errorf("failed to save feedback: %w", err)))
+	}
+
+	return c.JSON(courseFeedbackResponse{
+		RecommendationID: recoID,
+		CourseID:         feedback.CourseID,
+		Feedback:         feedback.Kind,
+		Reason:           feedback.Reason.String,
+		UpdatedAt:        feedback.UpdatedAt,
+	})
+}
+
+// likedCourse is a recommended course the student liked
+type likedCourse struct {
+	ID     int64
+	Code   string
+	Name   string
+	Reason string
+}
+
+// courseFeedback is what a student's feedback means for new
+// recommendations
+type courseFeedback struct {
+	Dismissed  map[int64]bool // never recommend again
+	TakenCodes []string       // taken elsewhere; count as passed for prerequisites
+	Liked      []likedCourse  // latest first
+}
+
+// courseFeedback loads the student's latest feedback per course.
+func (s *Server) courseFeedback(ctx context.Context, username string) (courseFeedback, error) {
+	rows, err := s.store.ListCourseFeedback(ctx, username)
+	if err != nil {
+		return courseFeedback{}, err
+	}
+	fb := courseFeedback{Dismissed: map[int64]bool{}}
+	for _, row := range rows {
+		switch row.Kind {
+		case feedbackLike:
+			fb.Liked = append(fb.Liked, likedCourse{ID: row.CourseID, Code: row.Code, Name: row.Name, Reason: row.Reason.String})
+		case feedbackAlreadyTaken:
+			fb.TakenCodes = append(fb.TakenCodes, normalizeCourseCode(row.Code))
+			fb.Dismissed[row.CourseID] = true
+		default:
+			fb.Dismissed[row.CourseID] = true
+		}
+	}
+	return fb, nil
+}
+
+// likedPromptNote tells the model which recommended courses the student
+// liked, so similar topics weigh more. Empty when nothing was liked.
+func likedPromptNote(liked []likedCourse) string {
+	if len(liked) == 0 {
+		return ""
+	}
+	var b strings.Builder
+	b.WriteString("\n\nThe student liked these earlier recommendations; give courses on similar topics more weight:\n")
+	for _, l := range liked {
+		fmt.Fprintf(&b, "- %s %s", l.Code, l.Name)
+		if l.Reason != "" {
+			fmt.Fprintf(&b, " (%s)", l.Reason)
+		}
+		b.WriteString("\n")
+	}
+	return strings.TrimRight(b.String(), "\n")
+}
@@ -0,0 +1,155 @@
+// server/api/course_feedback_test.go
+
+package api
+
+import (
+	"bytes"
+	"database/sql"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/golang/mock/gomock"
+	mockdb "github.com/nibir1/go-fiber-postgres-REST-boilerplate/db/mock"
+	db "github.com/nibir1/go-fiber-postgres-REST-boilerplate/db/sqlc"
+	"github.com/nibir1/go-fiber-postgres-REST-boilerplate/util"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFilterAvailableCourses(t *testing.T) {
+	catalogue := recommendationTestCatalogue()
+	available := filterAvailableCourses(catalogue, []string{"KIEL1001"}, map[int64]bool{3: true})
+	require.Len(t, available, 1)
+	require.Equal(t, "TIES3270", available[0].Code)
+}
+
+func TestCreateCourseFeedbackAPI(t *testing.T) {
+	username := util.RandomOwner()
+	reco := db.Recommendation{
+		ID:           5,
+		UserUsername: username,
+		Payload:      []byte(`{"courses":[{"type":"course","title":"Cyber Security","code":"TIES3270","course_id":1}]}`),
+		CreatedAt:    time.Now(),
+	}
+
+	testCases := []struct {
+		name          string
+		courseID      string
+		body          fiber.Map
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(t *testing.T, resp *http.Response)
+	}{
+		{
+			name:     "OK",
+			courseID: "1",
+			body:     fiber.Map{"feedback": "Already-Taken", "reason": " Took it at exchange "},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetRecommendation(gomock.Any(), gomock.Eq(int64(5))).Times(1).Return(reco, nil)
+				arg := db.UpsertCourseFeedbackParams{
+					UserUsername:     username,
+					CourseID:         1,
+					RecommendationID: sql.NullInt64{Int64: 5, Valid: true},
+					Kind:             feedbackAlreadyTaken,
+					Reason:           sql.NullString{String: "Took it at exchange", Valid: true},
+				}
+				store.EXPECT().UpsertCourseFeedback(gomock.Any(), gomock.Eq(arg)).Times(1).
+					Return(db.CourseFeedback{UserUsername: username, CourseID: 1, Kind: arg.Kind, Reason: arg.Reason, UpdatedAt: time.Now()}, nil)
+			},
+			checkResponse: func(t *testing.T, resp *http.Response) {
+				require.Equal(t, http.StatusOK, resp.StatusCode)
+
+				var body courseFeedbackResponse
+				require.NoError(t, json.NewDecoder(resp.Body).Decode(&body))
+				require.Equal(t, int64(5), body.RecommendationID)
+				require.Equal(t, int64(1), body.CourseID)
+				require.Equal(t, feedbackAlreadyTaken, body.Feedback)
+				require.Equal(t, "Took it at exchange", body.Reason)
+			},
+		},
+		{
+			name:     "InvalidFeedback",
+			courseID: "1",
+			body:     fiber.Map{"feedback": "love"},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetRecommendation(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, resp *http.Response) {
+				require.Equal(t, http.StatusBadRequest, resp.StatusCode)
+			},
+		},
+		{
+			name:     "ReasonTooLong",
+			courseID: "1",
+			body:     fiber.Map{"feedback": "dislike", "reason": strings.Repeat("a", maxFeedbackReason+1)},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetRecommendation(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, resp *http.Response) {
+				require.Equal(t, http.StatusBadRequest, resp.StatusCode)
+			},
+		},
+		{
+			name:     "CourseNotRecommended",
+			courseID: "3",
+			body:     fiber.Map{"feedback": "like"},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetRecommendation(gomock.Any(), gomock.Eq(int64(5))).Times(1).Return(reco, nil)
+				store.EXPECT().UpsertCourseFeedback(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, resp *http.Response) {
+				require.Equal(t, http.StatusNotFound, resp.StatusCode)
+			},
+		},
+		{
+			name:     "OtherUsersRecommendation",
+			courseID: "1",
+			body:     fiber.Map{"feedback": "like"},
+			buildStubs: func(store *mockdb.MockStore) {
+				other := reco
+				other.UserUsername = "someone_else"
+				store.EXPECT().GetRecommendation(gomock.Any(), gomock.Eq(int64(5))).Times(1).Return(other, nil)
+				store.EXPECT().UpsertCourseFeedback(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, resp *http.Response) {
+				require.Equal(t, http.StatusForbidden, resp.StatusCode)
+			},
+		},
+		{
+			name:     "RecommendationNotFound",
+			courseID: "1",
+			body:     fiber.Map{"feedback": "like"},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetRecommendation(gomock.Any(), gomock.Eq(int64(5))).Times(1).Return(db.Recommendation{}, sql.ErrNoRows)
+			},
+			checkResponse: func(t *testing.T, resp *http.Response) {
+				require.Equal(t, http.StatusNotFound, resp.StatusCode)
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStubs(store)
+
+			server := newFiberTestServer(t, store)
+			data, err := json.Marshal(tc.body)
+			require.NoError(t, err)
+			url := "/api/recommendations/5/courses/" + tc.courseID + "/feedback"
+			req, err := http.NewRequest(http.MethodPost, url, bytes.NewReader(data))
+			require.NoError(t, err)
+			req.Header.Set("Content-Type", "application/json")
+			addAuthorization(t, req, server.tokenMaker, authorizationTypeBearer, username, time.Minute)
+
+			resp, err := server.app.Test(req, -1)
+			require.NoError(t, err)
+			tc.checkResponse(t, resp)
+		})
+	}
+}
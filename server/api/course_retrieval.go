@@ -0,0 +1,87 @@
+// server/api/course_retrieval.go
+
+package api
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+
+	db "github.com/nibir1/go-fiber-postgres-REST-boilerplate/db/sqlc"
+	"github.com/nibir1/go-fiber-postgres-REST-boilerplate/embedding"
+	"github.com/nibir1/go-fiber-postgres-REST-boilerplate/util"
+)
+
+// Courses sent to the model for reranking when RECOMMENDATION_CANDIDATES
+// is not set
+const defaultRecommendationCandidates = 40
+
+// NewEmbedder creates the course embedder selected by EMBEDDING_PROVIDER.
+// The import-courses command uses it too, so both embed with the same model.
+func NewEmbedder(config util.Config) (embedding.Embedder, error) {
+	provider := strings.ToLower(strings.TrimSpace(config.EmbeddingProvider))
+	if provider == "" {
+		provider = embedding.Local
+	}
+	return embedding.New(provider, embedding.Options{
+		OpenAIAPIKey: config.OpenAIAPIKey,
+		OpenAIModel:  config.EmbeddingModel,
+	})
+}
+
+// recommendationCandidates returns how many courses are sent to the model.
+func (s *Server) recommendationCandidates() int {
+	if s.config.RecommendationCandidates > 0 {
+		return s.config.RecommendationCandidates
+	}
+	return defaultRecommendationCandidates
+}
+
+// retrievalQuery is the text candidates are compared with: the student's
+// preference, then the names of the courses on their transcript.
+func retrievalQuery(preference string, rows []db.TranscriptCourse) string {
+	parts := []string{preference}
+	for _, row := range rows {
+		if row.Name.Valid {
+			parts = append(parts, row.Name.String)
+		}
+	}
+	return strings.Join(parts, "\n")
+}
+
+// retrieveCandidates keeps the n candidates whose embeddings are most
+// similar to query, most similar first. Missing or outdated course
+// embeddings are computed and stored on the way.
+func (s *Server) retrieveCandidates(ctx context.Context, candidates []db.Course, query string, n int) ([]db.Course, error) {
+	vectors, err := s.embedder.Embed(ctx, []string{query})
+	if err != nil {
+		return nil, fmt.Errorf("failed to embed query: %w", err)
+	}
+
+	s.courseIndexMu.Lock()
+	index, embedded, err := embedding.Sync(ctx, s.store, s.embedder, s.courseIndex, candidates)
+	if index != nil {
+		s.courseIndex = index
+	}
+	s.courseIndexMu.Unlock()
+	if err != nil {
+		return nil, err
+	}
+	if embedded > 0 {
+		log.Printf("[AI] Embedded %d courses with %s", embedded, s.embedder.Model())
+	}
+
+	byID := make(map[int64]db.Course, len(candidates))
+	ids := make([]int64, 0, len(candidates))
+	for _, c := range candidates {
+		byID[c.ID] = c
+		ids = append(ids, c.ID)
+	}
+	matches := index.Search(vectors[0], ids, n)
+	out := make([]db.Course, 0, len(matches))
+	for _, m := range matches {
+		out = append(out, byID[m.CourseID])
+	}
+	return out, nil
+}
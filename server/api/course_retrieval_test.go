@@ -0,0 +1,71 @@
+// server/api/course_retrieval_test.go
+
+package api
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/require"
+
+	mockdb "github.com/nibir1/go-fiber-postgres-REST-boilerplate/db/mock"
+	db "github.com/nibir1/go-fiber-postgres-REST-boilerplate/db/sqlc"
+	"github.com/nibir1/go-fiber-postgres-REST-boilerplate/util"
+)
+
+func TestNewEmbedder(t *testing.T) {
+	e, err := NewEmbedder(util.Config{})
+	require.NoError(t, err)
+	require.Equal(t, "local-hash-512", e.Model())
+
+	_, err = NewEmbedder(util.Config{EmbeddingProvider: "openai"})
+	require.Error(t, err)
+
+	e, err = NewEmbedder(util.Config{EmbeddingProvider: "OpenAI", OpenAIAPIKey: "sk-test", EmbeddingModel: "text-embedding-3-large"})
+	require.NoError(t, err)
+	require.Equal(t, "openai-text-embedding-3-large", e.Model())
+}
+
+func TestRetrieveCandidates(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+	store := mockdb.NewMockStore(ctrl)
+	server := newFiberTestServer(t, store)
+
+	security := newTestCatalogueCourse("TIES3270", "Cyber Security")
+	security.ID = 1
+	security.LearningOutcomes = sql.NullString{String: "Threats, cryptography and network security", Valid: true}
+	finnish := newTestCatalogueCourse("KIEL1001", "Finnish 1")
+	finnish.ID = 2
+	finnish.LearningOutcomes = sql.NullString{String: "Everyday Finnish conversation", Valid: true}
+	ml := newTestCatalogueCourse("TIEA2150", "Machine Learning")
+	ml.ID = 3
+	ml.LearningOutcomes = sql.NullString{String: "Neural networks and statistical learning", Valid: true}
+	candidates := []db.Course{finnish, ml, security}
+
+	// Embeddings are loaded once, then kept in memory
+	store.EXPECT().
+		ListCourseEmbeddings(gomock.Any(), server.embedder.Model()).
+		Times(1).
+		Return([]db.CourseEmbedding{}, nil)
+	store.EXPECT().
+		UpsertCourseEmbedding(gomock.Any(), gomock.Any()).
+		Times(3).
+		DoAndReturn(func(_ context.Context, arg db.UpsertCourseEmbeddingParams) (db.CourseEmbedding, error) {
+			return db.CourseEmbedding{CourseID: arg.CourseID, Model: arg.Model, ContentHash: arg.ContentHash, Embedding: arg.Embedding}, nil
+		})
+
+	rows := []db.TranscriptCourse{testCourse("TIES1000", 5, "5", courseStatusCompleted)}
+	rows[0].Name = sql.NullString{String: "Introduction to Network Security", Valid: true}
+	query := retrievalQuery("cryptography", rows)
+	require.Equal(t, "cryptography\nIntroduction to Network Security", query)
+
+	for range 2 {
+		got, err := server.retrieveCandidates(context.Background(), candidates, query, 2)
+		require.NoError(t, err)
+		require.Len(t, got, 2)
+		require.Equal(t, "TIES3270", got[0].Code)
+	}
+}
@@ -0,0 +1,108 @@
+// server/api/course_search.go
+
+package api
+
+import (
+	"fmt"
+	"html"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/gofiber/fiber/v2"
+	db "github.com/nibir1/go-fiber-postgres-REST-boilerplate/db/sqlc"
+)
+
+// Limits of GET /api/courses/search
+const (
+	defaultCourseSearchLimit = 20
+	maxCourseSearchLimit     = 50
+	maxCourseSearchQueryLen  = 200
+)
+
+// Markers ts_headline puts around matched words (see SearchCourses)
+const (
+	headlineStart = "\x02"
+	headlineStop  = "\x03"
+)
+
+// courseSearchResult is one ranked hit. NameHighlight and Snippet are HTML:
+// the course text is escaped and matches are wrapped in <mark>.
+type courseSearchResult struct {
+	ID            int64   `json:"id"`
+	Code          string  `json:"code"`
+	Name          string  `json:"name"`
+	Language      string  `json:"language"`
+	Organiser     string  `json:"organiser"`
+	Rank          float64 `json:"rank"`
+	NameHighlight string  `json:"name_highlight"`
+	Snippet       string  `json:"snippet"`
+}
+
+func newCourseSearchResult(row db.SearchCoursesRow) courseSearchResult {
+	return courseSearchResult{
+		ID:            row.ID,
+		Code:          row.Code,
+		Name:          row.Name,
+		Language:      row.Language.String,
+		Organiser:     row.Organiser.String,
+		Rank:          row.Rank,
+		NameHighlight: highlightHTML(pickHeadline(row.NameHighlightEn, row.NameHighlightFi)),
+		Snippet:       highlightHTML(pickHeadline(row.SnippetEn, row.SnippetFi)),
+	}
+}
+
+// pickHeadline returns the headline with more highlighted words, preferring
+// English on a tie.
+func pickHeadline(en, fi string) string {
+	if strings.Count(fi, headlineStart) > strings.Count(en, headlineStart) {
+		return fi
+	}
+	return en
+}
+
+// highlightHTML escapes a headline and turns its match markers into <mark> tags.
+func highlightHTML(headline string) string {
+	s := html.EscapeString(strings.TrimSpace(headline))
+	s = strings.ReplaceAll(s, headlineStart, "<mark>")
+	return strings.ReplaceAll(s, headlineStop, "</mark>")
+}
+
+// GET /api/courses/search?q=reinforcement+learning&limit=20
+// Ranks full-text matches over name, learning outcomes and prerequisites
+// (English and Finnish) together with fuzzy matches on code and name, so
+// "tietoturva", "machine learnin" and "tjts501" all find something.
+func (s *Server) searchCourses(c *fiber.Ctx) error {
+	// 1) Query
+	q := strings.TrimSpace(c.Query("q"))
+	if q == "" {
+		return c.Status(fiber.StatusBadRequest).JSON(errorResponse(fmt.Errorf("q is required")))
+	}
+	if utf8.RuneCountInString(q) > maxCourseSearchQueryLen {
+		return c.Status(fiber.StatusBadRequest).JSON(errorResponse(fmt.Errorf("q is longer than %d characters", maxCourseSearchQueryLen)))
+	}
+	limit, err := queryPositiveInt(c, "limit", defaultCourseSearchLimit)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(errorResponse(err))
+	}
+	if limit > maxCourseSearchLimit {
+		limit = maxCourseSearchLimit
+	}
+
+	// 2) Search
+	rows, err := s.store.SearchCourses(c.Context(), db.SearchCoursesParams{
+		Query:      q,
+		MaxResults: int32(limit),
+	})
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(errorResponse(err))
+	}
+
+	results := make([]courseSearchResult, 0, len(rows))
+	for _, row := range rows {
+		results = append(results, newCourseSearchResult(row))
+	}
+	return c.JSON(fiber.Map{
+		"query":   q,
+		"results": results,
+	})
+}
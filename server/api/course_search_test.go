@@ -0,0 +1,109 @@
+// server/api/course_search_test.go
+
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	mockdb "github.com/nibir1/go-fiber-postgres-REST-boilerplate/db/mock"
+	db "github.com/nibir1/go-fiber-postgres-REST-boilerplate/db/sqlc"
+	"github.com/nibir1/go-fiber-postgres-REST-boilerplate/util"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHighlightHTML(t *testing.T) {
+	require.Equal(t, "Basics of <mark>information security</mark> &amp; &lt;b&gt;",
+		highlightHTML(" Basics of \x02information security\x03 & <b> "))
+
+	// The Finnish headline wins when only Finnish stemming matched
+	require.Equal(t, "\x02Tietoturvan\x03 perusteet", pickHeadline("Tietoturvan perusteet", "\x02Tietoturvan\x03 perusteet"))
+	require.Equal(t, "\x02Security\x03", pickHeadline("\x02Security\x03", "\x02Security\x03"))
+}
+
+func TestSearchCoursesAPI(t *testing.T) {
+	username := util.RandomOwner()
+
+	testCases := []struct {
+		name          string
+		query         string
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(t *testing.T, resp *http.Response)
+	}{
+		{
+			name:  "OK",
+			query: "q=" + url.QueryEscape(" tietoturva ") + "&limit=500",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					SearchCourses(gomock.Any(), gomock.Eq(db.SearchCoursesParams{Query: "tietoturva", MaxResults: maxCourseSearchLimit})).
+					Times(1).
+					Return([]db.SearchCoursesRow{{
+						ID:              3,
+						Code:            "TJTS5700",
+						Name:            "Tietoturvan perusteet",
+						Rank:            0.42,
+						NameHighlightEn: "Tietoturvan perusteet",
+						NameHighlightFi: "\x02Tietoturvan\x03 perusteet",
+						SnippetEn:       "Kurssilla opitaan tietoturvan",
+						SnippetFi:       "Kurssilla opitaan \x02tietoturvan\x03",
+					}}, nil)
+			},
+			checkResponse: func(t *testing.T, resp *http.Response) {
+				require.Equal(t, http.StatusOK, resp.StatusCode)
+
+				var body struct {
+					Results []courseSearchResult `json:"results"`
+				}
+				require.NoError(t, json.NewDecoder(resp.Body).Decode(&body))
+				require.Len(t, body.Results, 1)
+				require.Equal(t, "TJTS5700", body.Results[0].Code)
+				require.Equal(t, "<mark>Tietoturvan</mark> perusteet", body.Results[0].NameHighlight)
+				require.Equal(t, "Kurssilla opitaan <mark>tietoturvan</mark>", body.Results[0].Snippet)
+			},
+		},
+		{
+			name:  "MissingQuery",
+			query: "q=%20",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().SearchCourses(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, resp *http.Response) {
+				require.Equal(t, http.StatusBadRequest, resp.StatusCode)
+			},
+		},
+		{
+			name:  "QueryTooLong",
+			query: "q=" + strings.Repeat("a", maxCourseSearchQueryLen+1),
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().SearchCourses(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, resp *http.Response) {
+				require.Equal(t, http.StatusBadRequest, resp.StatusCode)
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStubs(store)
+
+			server := newFiberTestServer(t, store)
+			req, err := http.NewRequest(http.MethodGet, "/api/courses/search?"+tc.query, nil)
+			require.NoError(t, err)
+			addAuthorization(t, req, server.tokenMaker, authorizationTypeBearer, username, time.Minute)
+
+			resp, err := server.app.Test(req, -1)
+			require.NoError(t, err)
+			tc.checkResponse(t, resp)
+		})
+	}
+}
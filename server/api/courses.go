@@ -0,0 +1,519 @@
+// server/api/courses.go
+
+package api
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/lib/pq"
+	"github.com/nibir1/go-fiber-postgres-REST-boilerplate/catalog"
+	db "github.com/nibir1/go-fiber-postgres-REST-boilerplate/db/sqlc"
+)
+
+// Page size of GET /api/courses
+const (
+	defaultCoursePageSize = 20
+	maxCoursePageSize     = 100
+)
+
+// courseResponse is a catalogue course with empty strings (and zero credits)
+// for missing fields
+type courseResponse struct {
+	ID               int64     `json:"id"`
+	Code             string    `json:"code"`
+	Name             string    `json:"name"`
+	Language         string    `json:"language"`
+	GradingScale     string    `json:"grading_scale"`
+	Organiser        string    `json:"organiser"`
+	LearningOutcomes string    `json:"learning_outcomes"`
+	Prerequisites    string    `json:"prerequisites"`
+	TeacherName      string    `json:"teacher_name"`
+	TeacherEmail     string    `json:"teacher_email"`
+	CourseLink       string    `json:"course_link"`
+	Credits          float64   `json:"credits"`
+	Level            string    `json:"level"`
+	TeachingPeriods  []int32   `json:"teaching_periods"`
+	Modality         string    `json:"modality"`
+	Campus           string    `json:"campus"`
+	CreatedAt        time.Time `json:"created_at"`
+}
+
+func newCourseResponse(course db.Course) courseResponse {
+	return courseResponse{
+		ID:               course.ID,
+		Code:             course.Code,
+		Name:             course.Name,
+		Language:         course.Language.String,
+		GradingScale:     course.GradingScale.String,
+		Organiser:        course.Organiser.String,
+		LearningOutcomes: course.LearningOutcomes.String,
+		Prerequisites:    course.Prerequisites.String,
+		TeacherName:      course.TeacherName.String,
+		TeacherEmail:     course.TeacherEmail.String,
+		CourseLink:       course.CourseLink.String,
+		Credits:          course.Credits.Float64,
+		Level:            course.Level.String,
+		TeachingPeriods:  courseTeachingPeriods(course),
+		Modality:         course.Modality.String,
+		Campus:           course.Campus.String,
+		CreatedAt:        course.CreatedAt,
+	}
+}
+
+// courseTeachingPeriods never returns nil, so periods encode as [] in JSON.
+func courseTeachingPeriods(course db.Course) []int32 {
+	if course.TeachingPeriods == nil {
+		return []int32{}
+	}
+	return course.TeachingPeriods
+}
+
+// courseRequest is the body of course create and update. On update, omitted
+// fields keep their value and an empty string clears an optional field.
+type courseRequest struct {
+	Code             *string  `json:"code"`
+	Name             *string  `json:"name"`
+	Language         *string  `json:"language"`
+	GradingScale     *string  `json:"grading_scale"`
+	Organiser        *string  `json:"organiser"`
+	LearningOutcomes *string  `json:"learning_outcomes"`
+	Prerequisites    *string  `json:"prerequisites"`
+	TeacherName      *string  `json:"teacher_name"`
+	TeacherEmail     *string  `json:"teacher_email"`
+	CourseLink       *string  `json:"course_link"`
+	Credits          *float64 `json:"credits"` // 0 clears
+	Level            *string  `json:"level"`
+	TeachingPeriods  *[]int32 `json:"teaching_periods"`
+	Modality         *string  `json:"modality"`
+	Campus           *string  `json:"campus"`
+}
+
+// apply copies the fields present in the request onto arg; level and
+// modality are normalised ("Master's" -> "master").
+func (r courseRequest) apply(arg *db.UpdateCourseParams) error {
+	set := func(dst *sql.NullString, v *string) {
+		if v != nil {
+			*dst = sqlStringOrNull(*v)
+		}
+	}
+	if r.Name != nil {
+		arg.Name = strings.TrimSpace(*r.Name)
+	}
+	set(&arg.Language, r.Language)
+	set(&arg.GradingScale, r.GradingScale)
+	set(&arg.Organiser, r.Organiser)
+	set(&arg.LearningOutcomes, r.LearningOutcomes)
+	set(&arg.Prerequisites, r.Prerequisites)
+	set(&arg.TeacherName, r.TeacherName)
+	set(&arg.TeacherEmail, r.TeacherEmail)
+	set(&arg.CourseLink, r.CourseLink)
+	set(&arg.Campus, r.Campus)
+
+	if r.Credits != nil {
+		if *r.Credits < 0 {
+			return errors.New("credits cannot be negative")
+		}
+		arg.Credits = sql.NullFloat64{Float64: *r.Credits, Valid: *r.Credits > 0}
+	}
+	if r.Level != nil {
+		level, err := catalog.NormalizeLevel(*r.Level)
+		if err != nil {
+			return err
+		}
+		arg.Level = sqlStringOrNull(level)
+	}
+	if r.TeachingPeriods != nil {
+		periods, err := catalog.NormalizePeriods(*r.TeachingPeriods)
+		if err != nil {
+			return err
+		}
+		arg.TeachingPeriods = periods
+	}
+	if r.Modality != nil {
+		modality, err := catalog.NormalizeModality(*r.Modality)
+		if err != nil {
+			return err
+		}
+		arg.Modality = sqlStringOrNull(modality)
+	}
+	return nil
+}
+
+// courseFilter narrows the catalogue on course details; zero fields match
+// every course. Courses missing a filtered detail do not match. Language,
+// organiser, teacher and campus are case-insensitive substring matches, as
+// in GET /api/courses.
+type courseFilter struct {
+	Language     string   `json:"language"`
+	Organiser    string   `json:"organiser"`
+	Teacher      string   `json:"teacher"`
+	Level        string   `json:"level"`
+	Credits      float64  `json:"credits"`
+	MinCredits   float64  `json:"min_credits"`
+	MaxCredits   float64  `json:"max_credits"`
+	Period       int32    `json:"period"`
+	Modality     string   `json:"modality"`
+	Campus       string   `json:"campus"`
+	ExcludeCodes []string `json:"exclude_codes"`
+}
+
+// normalize checks the filter values and puts level, modality and course
+// codes in their stored form.
+func (f *courseFilter) normalize() error {
+	var err error
+	if f.Level, err = catalog.NormalizeLevel(f.Level); err != nil {
+		return err
+	}
+	if f.Modality, err = catalog.NormalizeModality(f.Modality); err != nil {
+		return err
+	}
+	if f.Credits < 0 || f.MinCredits < 0 || f.MaxCredits < 0 {
+		return errors.New("credits cannot be negative")
+	}
+	if f.MaxCredits > 0 && f.MinCredits > f.MaxCredits {
+		return errors.New("min_credits cannot be above max_credits")
+	}
+	if f.Period < 0 || f.Period > catalog.MaxPeriod {
+		return fmt.Errorf("period must be between 1 and %d", catalog.MaxPeriod)
+	}
+	f.Language = strings.TrimSpace(f.Language)
+	f.Organiser = strings.TrimSpace(f.Organiser)
+	f.Teacher = strings.TrimSpace(f.Teacher)
+	f.Campus = strings.TrimSpace(f.Campus)
+	var codes []string
+	for _, code := range f.ExcludeCodes {
+		if code = normalizeCourseCode(code); code != "" && !slices.Contains(codes, code) {
+			codes = append(codes, code)
+		}
+	}
+	f.ExcludeCodes = codes
+	return nil
+}
+
+// courseConstraint is one set field of a courseFilter
+type courseConstraint struct {
+	name  string // the field's JSON name
+	match func(course db.Course) bool
+}
+
+// constraints returns the set fields of f, in field order.
+func (f courseFilter) constraints() []courseConstraint {
+	contains := func(field sql.NullString, want string) bool {
+		return strings.Contains(strings.ToLower(field.String), strings.ToLower(want))
+	}
+	var cs []courseConstraint
+	add := func(set bool, name string, match func(db.Course) bool) {
+		if set {
+			cs = append(cs, courseConstraint{name: name, match: match})
+		}
+	}
+	add(f.Language != "", "language", func(c db.Course) bool { return contains(c.Language, f.Language) })
+	add(f.Organiser != "", "organiser", func(c db.Course) bool { return contains(c.Organiser, f.Organiser) })
+	add(f.Teacher != "", "teacher", func(c db.Course) bool { return contains(c.TeacherName, f.Teacher) })
+	add(f.Level != "", "level", func(c db.Course) bool { return c.Level.String == f.Level })
+	add(f.Credits != 0, "credits", func(c db.Course) bool { return c.Credits.Valid && c.Credits.Float64 == f.Credits })
+	add(f.MinCredits != 0, "min_credits", func(c db.Course) bool { return c.Credits.Valid && c.Credits.Float64 >= f.MinCredits })
+	add(f.MaxCredits != 0, "max_credits", func(c db.Course) bool { return c.Credits.Valid && c.Credits.Float64 <= f.MaxCredits })
+	add(f.Period != 0, "period", func(c db.Course) bool { return slices.Contains(c.TeachingPeriods, f.Period) })
+	add(f.Modality != "", "modality", func(c db.Course) bool { return c.Modality.String == f.Modality })
+	add(f.Campus != "", "campus", func(c db.Course) bool { return contains(c.Campus, f.Campus) })
+	add(len(f.ExcludeCodes) > 0, "exclude_codes", func(c db.Course) bool {
+		return !slices.Contains(f.ExcludeCodes, normalizeCourseCode(c.Code))
+	})
+	return cs
+}
+
+// filterCourses keeps the courses matching f.
+func filterCourses(courses []db.Course, f courseFilter) []db.Course {
+	out, _ := applyCourseFilter(courses, f)
+	return out
+}
+
+// applyCourseFilter keeps the courses matching f and counts, per set field,
+// the courses it removed. A course failing several fields counts against
+// the first of them.
+func applyCourseFilter(courses []db.Course, f courseFilter) ([]db.Course, map[string]int) {
+	constraints := f.constraints()
+	removed := make(map[string]int, len(constraints))
+	for _, c := range constraints {
+		removed[c.name] = 0
+	}
+
+	var out []db.Course
+courses:
+	for _, course := range courses {
+		for _, c := range constraints {
+			if !c.match(course) {
+				removed[c.name]++
+				continue courses
+			}
+		}
+		out = append(out, course)
+	}
+	return out, removed
+}
+
+// courseFilterQuery reads a courseFilter from the query string.
+func courseFilterQuery(c *fiber.Ctx) (courseFilter, error) {
+	f := courseFilter{
+		Language:  c.Query("language"),
+		Organiser: c.Query("organiser"),
+		Teacher:   c.Query("teacher"),
+		Level:     c.Query("level"),
+		Modality:  c.Query("modality"),
+		Campus:    c.Query("campus"),
+	}
+	if raw := strings.TrimSpace(c.Query("credits")); raw != "" {
+		credits, err := catalog.ParseCredits(raw)
+		if err != nil {
+			return f, err
+		}
+		f.Credits = credits
+	}
+	period, err := queryPositiveInt(c, "period", 0)
+	if err != nil {
+		return f, err
+	}
+	f.Period = int32(period)
+	return f, f.normalize()
+}
+
+// normalizeCourseCode upper-cases a course code, e.g. "tjts5012" -> "TJTS5012"
+func normalizeCourseCode(code string) string {
+	return strings.ToUpper(strings.TrimSpace(code))
+}
+
+// queryPositiveInt reads an optional positive integer query parameter.
+func queryPositiveInt(c *fiber.Ctx, name string, fallback int) (int, error) {
+	raw := strings.TrimSpace(c.Query(name))
+	if raw == "" {
+		return fallback, nil
+	}
+	n, err := strconv.Atoi(raw)
+	if err != nil || n <= 0 {
+		return 0, fmt.Errorf("invalid %s", name)
+	}
+	return n, nil
+}
+
+// -----------------------------------------------------------------------------
+// CATALOGUE
+// -----------------------------------------------------------------------------
+
+// GET /api/courses?page=1&page_size=20&language=English&organiser=...&teacher=...
+// &level=master&credits=5&period=2&modality=online&campus=...
+// Language, organiser, teacher and campus are case-insensitive substring
+// matches; results are ordered by code.
+func (s *Server) listCourses(c *fiber.Ctx) error {
+	// 1) Paging
+	page, err := queryPositiveInt(c, "page", 1)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(errorResponse(err))
+	}
+	pageSize, err := queryPositiveInt(c, "page_size", defaultCoursePageSize)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(errorResponse(err))
+	}
+	if pageSize > maxCoursePageSize {
+		pageSize = maxCoursePageSize
+	}
+
+	// 2) Filters
+	details, err := courseFilterQuery(c)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(errorResponse(err))
+	}
+	filters := db.CountCoursesParams{
+		Language:  sqlStringOrNull(details.Language),
+		Organiser: sqlStringOrNull(details.Organiser),
+		Teacher:   sqlStringOrNull(details.Teacher),
+		Level:     sqlStringOrNull(details.Level),
+		Credits:   sql.NullFloat64{Float64: details.Credits, Valid: details.Credits > 0},
+		Period:    sql.NullInt32{Int32: details.Period, Valid: details.Period > 0},
+		Modality:  sqlStringOrNull(details.Modality),
+		Campus:    sqlStringOrNull(details.Campus),
+	}
+
+	total, err := s.store.CountCourses(c.Context(), filters)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(errorResponse(err))
+	}
+	courses, err := s.store.ListCourses(c.Context(), db.ListCoursesParams{
+		Language:  filters.Language,
+		Organiser: filters.Organiser,
+		Teacher:   filters.Teacher,
+		Level:     filters.Level,
+		Credits:   filters.Credits,
+		Period:    filters.Period,
+		Modality:  filters.Modality,
+		Campus:    filters.Campus,
+		Limit:     int64(pageSize),
+		Offset:    int64(page-1) * int64(pageSize),
+	})
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(errorResponse(err))
+	}
+
+	items := make([]courseResponse, 0, len(courses))
+	for _, course := range courses {
+		items = append(items, newCourseResponse(course))
+	}
+	return c.JSON(fiber.Map{
+		"courses":   items,
+		"page":      page,
+		"page_size": pageSize,
+		"total":     total,
+	})
+}
+
+// GET /api/courses/:code
+func (s *Server) getCourse(c *fiber.Ctx) error {
+	code := normalizeCourseCode(c.Params("code"))
+	if code == "" {
+		return c.Status(fiber.StatusBadRequest).JSON(errorResponse(fmt.Errorf("missing code")))
+	}
+
+	course, err := s.store.GetCourseByCode(c.Context(), code)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return c.Status(fiber.StatusNotFound).JSON(errorResponse(fmt.Errorf("course not found")))
+		}
+		return c.Status(fiber.StatusInternalServerError).JSON(errorResponse(err))
+	}
+	return c.JSON(newCourseResponse(course))
+}
+
+// -----------------------------------------------------------------------------
+// ADMIN CATALOGUE MAINTENANCE
+// -----------------------------------------------------------------------------
+
+// POST /api/courses  (admin)
+func (s *Server) createCourse(c *fiber.Ctx) error {
+	var req courseRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(errorResponse(err))
+	}
+	if req.Code == nil || normalizeCourseCode(*req.Code) == "" || req.Name == nil || strings.TrimSpace(*req.Name) == "" {
+		return c.Status(fiber.StatusBadRequest).JSON(errorResponse(errors.New("code and name are required")))
+	}
+
+	arg := db.UpdateCourseParams{TeachingPeriods: []int32{}}
+	if err := req.apply(&arg); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(errorResponse(err))
+	}
+	code := normalizeCourseCode(*req.Code)
+	prereqs, err := ParseCoursePrerequisites(code, arg.Prerequisites.String)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(errorResponse(err))
+	}
+	course, err := s.store.CreateCourseTx(c.Context(), db.CreateCourseTxParams{Course: db.CreateCourseParams{
+		Code:             code,
+		Name:             arg.Name,
+		Language:         arg.Language,
+		GradingScale:     arg.GradingScale,
+		Organiser:        arg.Organiser,
+		LearningOutcomes: arg.LearningOutcomes,
+		Prerequisites:    arg.Prerequisites,
+		TeacherName:      arg.TeacherName,
+		TeacherEmail:     arg.TeacherEmail,
+		CourseLink:       arg.CourseLink,
+		Credits:          arg.Credits,
+		Level:            arg.Level,
+		TeachingPeriods:  arg.TeachingPeriods,
+		Modality:         arg.Modality,
+		Campus:           arg.Campus,
+	}, Prerequisites: prereqs})
+	if err != nil {
+		if pqErr, ok := err.(*pq.Error); ok && pqErr.Code.Name() == "unique_violation" {
+			return c.Status(fiber.StatusConflict).JSON(errorResponse(fmt.Errorf("course %s already exists", code)))
+		}
+		return c.Status(fiber.StatusInternalServerError).JSON(errorResponse(err))
+	}
+	return c.Status(fiber.StatusCreated).JSON(newCourseResponse(course))
+}
+
+// PUT /api/courses/:code  (admin)
+// Updates the fields present in the body; the code itself cannot change.
+func (s *Server) updateCourse(c *fiber.Ctx) error {
+	code := normalizeCourseCode(c.Params("code"))
+	if code == "" {
+		return c.Status(fiber.StatusBadRequest).JSON(errorResponse(fmt.Errorf("missing code")))
+	}
+
+	var req courseRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(errorResponse(err))
+	}
+	if req.Code != nil && normalizeCourseCode(*req.Code) != code {
+		return c.Status(fiber.StatusBadRequest).JSON(errorResponse(errors.New("course code cannot be changed")))
+	}
+	if req.Name != nil && strings.TrimSpace(*req.Name) == "" {
+		return c.Status(fiber.StatusBadRequest).JSON(errorResponse(errors.New("name cannot be empty")))
+	}
+
+	existing, err := s.store.GetCourseByCode(c.Context(), code)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return c.Status(fiber.StatusNotFound).JSON(errorResponse(fmt.Errorf("course not found")))
+		}
+		return c.Status(fiber.StatusInternalServerError).JSON(errorResponse(err))
+	}
+
+	arg := db.UpdateCourseParams{
+		Code:             existing.Code,
+		Name:             existing.Name,
+		Language:         existing.Language,
+		GradingScale:     existing.GradingScale,
+		Organiser:        existing.Organiser,
+		LearningOutcomes: existing.LearningOutcomes,
+		Prerequisites:    existing.Prerequisites,
+		TeacherName:      existing.TeacherName,
+		TeacherEmail:     existing.TeacherEmail,
+		CourseLink:       existing.CourseLink,
+		Credits:          existing.Credits,
+		Level:            existing.Level,
+		TeachingPeriods:  courseTeachingPeriods(existing),
+		Modality:         existing.Modality,
+		Campus:           existing.Campus,
+	}
+	if err := req.apply(&arg); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(errorResponse(err))
+	}
+
+	prereqs, err := ParseCoursePrerequisites(arg.Code, arg.Prerequisites.String)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(errorResponse(err))
+	}
+	course, err := s.store.UpdateCourseTx(c.Context(), db.UpdateCourseTxParams{Course: arg, Prerequisites: prereqs})
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return c.Status(fiber.StatusNotFound).JSON(errorResponse(fmt.Errorf("course not found")))
+		}
+		return c.Status(fiber.StatusInternalServerError).JSON(errorResponse(err))
+	}
+	return c.JSON(newCourseResponse(course))
+}
+
+// DELETE /api/courses/:code  (admin)
+func (s *Server) deleteCourse(c *fiber.Ctx) error {
+	code := normalizeCourseCode(c.Params("code"))
+	if code == "" {
+		return c.Status(fiber.StatusBadRequest).JSON(errorResponse(fmt.Errorf("missing code")))
+	}
+
+	n, err := s.store.DeleteCourse(c.Context(), code)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(errorResponse(err))
+	}
+	if n == 0 {
+		return c.Status(fiber.StatusNotFound).JSON(errorResponse(fmt.Errorf("course not found")))
+	}
+	return c.JSON(fiber.Map{"message": "course deleted", "code": code})
+}
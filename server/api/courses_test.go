@@ -0,0 +1,487 @@
+// server/api/courses_test.go
+
+package api
+
+import (
+	"bytes"
+	"database/sql"
+	"encoding/json"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/golang/mock/gomock"
+	"github.com/lib/pq"
+	mockdb "github.com/nibir1/go-fiber-postgres-REST-boilerplate/db/mock"
+	db "github.com/nibir1/go-fiber-postgres-REST-boilerplate/db/sqlc"
+	"github.com/nibir1/go-fiber-postgres-REST-boilerplate/token"
+	"github.com/nibir1/go-fiber-postgres-REST-boilerplate/util"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestCatalogueCourse(code, name string) db.Course {
+	return db.Course{
+		ID:          1,
+		Code:        code,
+		Name:        name,
+		Language:    sql.NullString{String: "English, Finnish", Valid: true},
+		Organiser:   sql.NullString{String: "Faculty of Information Technology", Valid: true},
+		TeacherName: sql.NullString{String: "Jukka Vuorinen", Valid: true},
+		CreatedAt:   time.Now(),
+	}
+}
+
+func TestFilterCourses(t *testing.T) {
+	online := newTestCatalogueCourse("TIES454", "Agent Technologies for Developers")
+	online.Credits = sql.NullFloat64{Float64: 5, Valid: true}
+	online.Level = sql.NullString{String: "master", Valid: true}
+	online.TeachingPeriods = []int32{1, 2}
+	online.Modality = sql.NullString{String: "online", Valid: true}
+	campus := newTestCatalogueCourse("TIEA311", "Introduction to Graphics")
+	campus.Credits = sql.NullFloat64{Float64: 5, Valid: true}
+	campus.TeachingPeriods = []int32{2}
+	campus.Modality = sql.NullString{String: "on_campus", Valid: true}
+	unknown := newTestCatalogueCourse("TJTS5012", "Additional Research Methods Module")
+	courses := []db.Course{online, campus, unknown}
+
+	require.Equal(t, courses, filterCourses(courses, courseFilter{}))
+	require.Equal(t, []db.Course{online, campus}, filterCourses(courses, courseFilter{Credits: 5, Period: 2}))
+	require.Equal(t, []db.Course{online}, filterCourses(courses, courseFilter{Level: "master"}))
+	require.Equal(t, []db.Course{campus}, filterCourses(courses, courseFilter{Modality: "on_campus"}))
+
+	f := courseFilter{Level: "Advanced studies", Modality: "Contact teaching"}
+	require.NoError(t, f.normalize())
+	require.Equal(t, courseFilter{Level: "master", Modality: "on_campus"}, f)
+	require.Error(t, (&courseFilter{Period: 6}).normalize())
+	require.Error(t, (&courseFilter{MinCredits: 10, MaxCredits: 5}).normalize())
+
+	f = courseFilter{Teacher: " vuorinen ", ExcludeCodes: []string{" tiea311", "TIEA311", ""}}
+	require.NoError(t, f.normalize())
+	require.Equal(t, courseFilter{Teacher: "vuorinen", ExcludeCodes: []string{"TIEA311"}}, f)
+}
+
+func TestApplyCourseFilter(t *testing.T) {
+	small := newTestCatalogueCourse("TIES454", "Agent Technologies for Developers")
+	small.Credits = sql.NullFloat64{Float64: 3, Valid: true}
+	large := newTestCatalogueCourse("TIEA311", "Introduction to Graphics")
+	large.Credits = sql.NullFloat64{Float64: 10, Valid: true}
+	finnish := newTestCatalogueCourse("KIEL1001", "Finnish 1")
+	finnish.Language = sql.NullString{String: "Finnish", Valid: true}
+	finnish.Credits = sql.NullFloat64{Float64: 12, Valid: true}
+	unknown := newTestCatalogueCourse("TJTS5012", "Additional Research Methods Module")
+	courses := []db.Course{small, large, finnish, unknown}
+
+	kept, removed := applyCourseFilter(courses, courseFilter{})
+	require.Equal(t, courses, kept)
+	require.Empty(t, removed)
+
+	// Finnish fails the credit range too but counts against language only;
+	// a course without credits fails any credit constraint
+	kept, removed = applyCourseFilter(courses, courseFilter{
+		Language:     "english",
+		Teacher:      "Vuorinen",
+		MaxCredits:   10,
+		ExcludeCodes: []string{"TIEA311"},
+	})
+	require.Equal(t, []db.Course{small}, kept)
+	require.Equal(t, map[string]int{"language": 1, "teacher": 0, "max_credits": 1, "exclude_codes": 1}, removed)
+
+	kept, removed = applyCourseFilter(courses, courseFilter{MinCredits: 5, Organiser: "information technology"})
+	require.Equal(t, []db.Course{large, finnish}, kept)
+	require.Equal(t, map[string]int{"organiser": 0, "min_credits": 2}, removed)
+}
+
+func TestListCoursesAPI(t *testing.T) {
+	username := util.RandomOwner()
+	course := newTestCatalogueCourse("TJTS5012", "Additional Research Methods Module")
+
+	testCases := []struct {
+		name          string
+		url           string
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(t *testing.T, resp *http.Response)
+	}{
+		{
+			name: "OKFiltered",
+			url:  "/api/courses?page=2&page_size=500&language=english&teacher=vuorinen",
+			buildStubs: func(store *mockdb.MockStore) {
+				filters := db.CountCoursesParams{
+					Language: sql.NullString{String: "english", Valid: true},
+					Teacher:  sql.NullString{String: "vuorinen", Valid: true},
+				}
+				store.EXPECT().CountCourses(gomock.Any(), gomock.Eq(filters)).Times(1).Return(int64(101), nil)
+				store.EXPECT().
+					ListCourses(gomock.Any(), gomock.Eq(db.ListCoursesParams{
+						Language: filters.Language,
+						Teacher:  filters.Teacher,
+						Limit:    maxCoursePageSize,
+						Offset:   maxCoursePageSize,
+					})).
+					Times(1).
+					Return([]db.Course{course}, nil)
+			},
+			checkResponse: func(t *testing.T, resp *http.Response) {
+				require.Equal(t, http.StatusOK, resp.StatusCode)
+
+				var body struct {
+					Courses  []courseResponse `json:"courses"`
+					Page     int              `json:"page"`
+					PageSize int              `json:"page_size"`
+					Total    int64            `json:"total"`
+				}
+				require.NoError(t, json.NewDecoder(resp.Body).Decode(&body))
+				require.Len(t, body.Courses, 1)
+				require.Equal(t, "TJTS5012", body.Courses[0].Code)
+				require.Equal(t, "English, Finnish", body.Courses[0].Language)
+				require.Equal(t, 2, body.Page)
+				require.Equal(t, maxCoursePageSize, body.PageSize)
+				require.Equal(t, int64(101), body.Total)
+			},
+		},
+		{
+			name: "OKDetailFilters",
+			url:  "/api/courses?level=masters&credits=5+cr&period=2&modality=remote&campus=seminaarinmäki",
+			buildStubs: func(store *mockdb.MockStore) {
+				filters := db.CountCoursesParams{
+					Level:    sql.NullString{String: "master", Valid: true},
+					Credits:  sql.NullFloat64{Float64: 5, Valid: true},
+					Period:   sql.NullInt32{Int32: 2, Valid: true},
+					Modality: sql.NullString{String: "online", Valid: true},
+					Campus:   sql.NullString{String: "seminaarinmäki", Valid: true},
+				}
+				store.EXPECT().CountCourses(gomock.Any(), gomock.Eq(filters)).Times(1).Return(int64(0), nil)
+				store.EXPECT().
+					ListCourses(gomock.Any(), gomock.Eq(db.ListCoursesParams{
+						Level:    filters.Level,
+						Credits:  filters.Credits,
+						Period:   filters.Period,
+						Modality: filters.Modality,
+						Campus:   filters.Campus,
+						Limit:    defaultCoursePageSize,
+					})).
+					Times(1).
+					Return([]db.Course{}, nil)
+			},
+			checkResponse: func(t *testing.T, resp *http.Response) {
+				require.Equal(t, http.StatusOK, resp.StatusCode)
+			},
+		},
+		{
+			name: "InvalidLevel",
+			url:  "/api/courses?level=licentiate",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().CountCourses(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, resp *http.Response) {
+				require.Equal(t, http.StatusBadRequest, resp.StatusCode)
+			},
+		},
+		{
+			name: "InvalidPage",
+			url:  "/api/courses?page=0",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().ListCourses(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, resp *http.Response) {
+				require.Equal(t, http.StatusBadRequest, resp.StatusCode)
+			},
+		},
+		{
+			name: "GetByCode",
+			url:  "/api/courses/tjts5012",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetCourseByCode(gomock.Any(), gomock.Eq("TJTS5012")).Times(1).Return(course, nil)
+			},
+			checkResponse: func(t *testing.T, resp *http.Response) {
+				require.Equal(t, http.StatusOK, resp.StatusCode)
+
+				var body courseResponse
+				require.NoError(t, json.NewDecoder(resp.Body).Decode(&body))
+				require.Equal(t, course.Name, body.Name)
+			},
+		},
+		{
+			name: "GetByCodeNotFound",
+			url:  "/api/courses/NOPE100",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetCourseByCode(gomock.Any(), gomock.Eq("NOPE100")).Times(1).Return(db.Course{}, sql.ErrNoRows)
+			},
+			checkResponse: func(t *testing.T, resp *http.Response) {
+				require.Equal(t, http.StatusNotFound, resp.StatusCode)
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStubs(store)
+
+			server := newFiberTestServer(t, store)
+			req, err := http.NewRequest(http.MethodGet, tc.url, nil)
+			require.NoError(t, err)
+			addAuthorization(t, req, server.tokenMaker, authorizationTypeBearer, username, time.Minute)
+
+			resp, err := server.app.Test(req, -1)
+			require.NoError(t, err)
+			tc.checkResponse(t, resp)
+		})
+	}
+}
+
+func TestCourseAdminAPI(t *testing.T) {
+	admin := db.User{Username: util.RandomOwner(), Role: userRoleAdmin}
+	student := db.User{Username: util.RandomOwner(), Role: "student"}
+	course := newTestCatalogueCourse("TIES454", "Agent Technologies for Developers")
+
+	testCases := []struct {
+		name          string
+		method        string
+		url           string
+		body          fiber.Map
+		user          db.User
+		buildStubs    func(store *mockdb.MockStore)
+		setupAuth     func(t *testing.T, req *http.Request, maker token.Maker, user db.User)
+		checkResponse func(t *testing.T, resp *http.Response)
+	}{
+		{
+			name:   "CreateOK",
+			method: http.MethodPost,
+			url:    "/api/courses",
+			body: fiber.Map{"code": " ties454 ", "name": "Agent Technologies for Developers", "language": "English, Finnish", "course_link": "",
+				"prerequisites": "TIEA311 and TIES471"},
+			user: admin,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetUser(gomock.Any(), gomock.Eq(admin.Username)).Times(1).Return(admin, nil)
+				store.EXPECT().
+					CreateCourseTx(gomock.Any(), gomock.Eq(db.CreateCourseTxParams{
+						Course: db.CreateCourseParams{
+							Code:            "TIES454",
+							Name:            "Agent Technologies for Developers",
+							Language:        sql.NullString{String: "English, Finnish", Valid: true},
+							Prerequisites:   sql.NullString{String: "TIEA311 and TIES471", Valid: true},
+							TeachingPeriods: []int32{},
+						},
+						Prerequisites: db.CoursePrerequisitesParams{
+							Source:      "TIEA311 and TIES471",
+							Requirement: []byte(`{"kind":"all","items":[{"kind":"course","code":"TIEA311"},{"kind":"course","code":"TIES471"}]}`),
+						},
+					})).
+					Times(1).
+					Return(course, nil)
+			},
+			setupAuth: bearerAuth,
+			checkResponse: func(t *testing.T, resp *http.Response) {
+				require.Equal(t, http.StatusCreated, resp.StatusCode)
+			},
+		},
+		{
+			name:   "CreateWithDetails",
+			method: http.MethodPost,
+			url:    "/api/courses",
+			body: fiber.Map{"code": "TIES454", "name": "Agent Technologies for Developers", "credits": 5,
+				"level": "Master's", "teaching_periods": []int{2, 1, 2}, "modality": "Remote", "campus": "Mattilanniemi"},
+			user: admin,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetUser(gomock.Any(), gomock.Any()).Times(1).Return(admin, nil)
+				store.EXPECT().
+					CreateCourseTx(gomock.Any(), gomock.Eq(db.CreateCourseTxParams{
+						Course: db.CreateCourseParams{
+							Code:            "TIES454",
+							Name:            "Agent Technologies for Developers",
+							Credits:         sql.NullFloat64{Float64: 5, Valid: true},
+							Level:           sql.NullString{String: "master", Valid: true},
+							TeachingPeriods: []int32{1, 2},
+							Modality:        sql.NullString{String: "online", Valid: true},
+							Campus:          sql.NullString{String: "Mattilanniemi", Valid: true},
+						},
+						Prerequisites: db.CoursePrerequisitesParams{Requirement: []byte("null")},
+					})).
+					Times(1).
+					Return(course, nil)
+			},
+			setupAuth: bearerAuth,
+			checkResponse: func(t *testing.T, resp *http.Response) {
+				require.Equal(t, http.StatusCreated, resp.StatusCode)
+			},
+		},
+		{
+			name:   "CreateInvalidDetails",
+			method: http.MethodPost,
+			url:    "/api/courses",
+			body:   fiber.Map{"code": "TIES454", "name": "Agent Technologies for Developers", "teaching_periods": []int{7}},
+			user:   admin,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetUser(gomock.Any(), gomock.Any()).Times(1).Return(admin, nil)
+				store.EXPECT().CreateCourseTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			setupAuth: bearerAuth,
+			checkResponse: func(t *testing.T, resp *http.Response) {
+				require.Equal(t, http.StatusBadRequest, resp.StatusCode)
+			},
+		},
+		{
+			name:   "CreateDuplicate",
+			method: http.MethodPost,
+			url:    "/api/courses",
+			body:   fiber.Map{"code": "TIES454", "name": "Agent Technologies for Developers"},
+			user:   admin,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetUser(gomock.Any(), gomock.Any()).Times(1).Return(admin, nil)
+				store.EXPECT().CreateCourseTx(gomock.Any(), gomock.Any()).Times(1).
+					Return(db.Course{}, &pq.Error{Code: "23505"})
+			},
+			setupAuth: bearerAuth,
+			checkResponse: func(t *testing.T, resp *http.Response) {
+				require.Equal(t, http.StatusConflict, resp.StatusCode)
+			},
+		},
+		{
+			name:   "CreateMissingName",
+			method: http.MethodPost,
+			url:    "/api/courses",
+			body:   fiber.Map{"code": "TIES454"},
+			user:   admin,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetUser(gomock.Any(), gomock.Any()).Times(1).Return(admin, nil)
+				store.EXPECT().CreateCourseTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			setupAuth: bearerAuth,
+			checkResponse: func(t *testing.T, resp *http.Response) {
+				require.Equal(t, http.StatusBadRequest, resp.StatusCode)
+			},
+		},
+		{
+			name:   "CreateNotAdmin",
+			method: http.MethodPost,
+			url:    "/api/courses",
+			body:   fiber.Map{"code": "TIES454", "name": "Agent Technologies for Developers"},
+			user:   student,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetUser(gomock.Any(), gomock.Eq(student.Username)).Times(1).Return(student, nil)
+				store.EXPECT().CreateCourseTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			setupAuth: bearerAuth,
+			checkResponse: func(t *testing.T, resp *http.Response) {
+				require.Equal(t, http.StatusForbidden, resp.StatusCode)
+			},
+		},
+		{
+			name:   "CreateNoAuthorization",
+			method: http.MethodPost,
+			url:    "/api/courses",
+			body:   fiber.Map{"code": "TIES454", "name": "Agent Technologies for Developers"},
+			user:   admin,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetUser(gomock.Any(), gomock.Any()).Times(0)
+				store.EXPECT().CreateCourseTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			setupAuth: func(t *testing.T, req *http.Request, maker token.Maker, user db.User) {},
+			checkResponse: func(t *testing.T, resp *http.Response) {
+				require.Equal(t, http.StatusUnauthorized, resp.StatusCode)
+			},
+		},
+		{
+			name:   "UpdatePartial",
+			method: http.MethodPut,
+			url:    "/api/courses/ties454",
+			body:   fiber.Map{"teacher_name": "Bekir Afsar", "organiser": ""},
+			user:   admin,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetUser(gomock.Any(), gomock.Any()).Times(1).Return(admin, nil)
+				store.EXPECT().GetCourseByCode(gomock.Any(), gomock.Eq("TIES454")).Times(1).Return(course, nil)
+				store.EXPECT().
+					UpdateCourseTx(gomock.Any(), gomock.Eq(db.UpdateCourseTxParams{
+						Course: db.UpdateCourseParams{
+							Code:            course.Code,
+							Name:            course.Name,
+							Language:        course.Language,
+							TeacherName:     sql.NullString{String: "Bekir Afsar", Valid: true},
+							TeachingPeriods: []int32{},
+						},
+						Prerequisites: db.CoursePrerequisitesParams{Requirement: []byte("null")},
+					})).
+					Times(1).
+					Return(course, nil)
+			},
+			setupAuth: bearerAuth,
+			checkResponse: func(t *testing.T, resp *http.Response) {
+				require.Equal(t, http.StatusOK, resp.StatusCode)
+			},
+		},
+		{
+			name:   "UpdateCodeChange",
+			method: http.MethodPut,
+			url:    "/api/courses/TIES454",
+			body:   fiber.Map{"code": "TIES455"},
+			user:   admin,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetUser(gomock.Any(), gomock.Any()).Times(1).Return(admin, nil)
+				store.EXPECT().UpdateCourseTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			setupAuth: bearerAuth,
+			checkResponse: func(t *testing.T, resp *http.Response) {
+				require.Equal(t, http.StatusBadRequest, resp.StatusCode)
+			},
+		},
+		{
+			name:   "DeleteOK",
+			method: http.MethodDelete,
+			url:    "/api/courses/TIES454",
+			user:   admin,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetUser(gomock.Any(), gomock.Any()).Times(1).Return(admin, nil)
+				store.EXPECT().DeleteCourse(gomock.Any(), gomock.Eq("TIES454")).Times(1).Return(int64(1), nil)
+			},
+			setupAuth: bearerAuth,
+			checkResponse: func(t *testing.T, resp *http.Response) {
+				require.Equal(t, http.StatusOK, resp.StatusCode)
+			},
+		},
+		{
+			name:   "DeleteNotFound",
+			method: http.MethodDelete,
+			url:    "/api/courses/TIES999",
+			user:   admin,
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetUser(gomock.Any(), gomock.Any()).Times(1).Return(admin, nil)
+				store.EXPECT().DeleteCourse(gomock.Any(), gomock.Eq("TIES999")).Times(1).Return(int64(0), nil)
+			},
+			setupAuth: bearerAuth,
+			checkResponse: func(t *testing.T, resp *http.Response) {
+				require.Equal(t, http.StatusNotFound, resp.StatusCode)
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStubs(store)
+
+			server := newFiberTestServer(t, store)
+
+			data, err := json.Marshal(tc.body)
+			require.NoError(t, err)
+			req, err := http.NewRequest(tc.method, tc.url, bytes.NewReader(data))
+			require.NoError(t, err)
+			req.Header.Set("Content-Type", "application/json")
+			tc.setupAuth(t, req, server.tokenMaker, tc.user)
+
+			resp, err := server.app.Test(req, -1)
+			require.NoError(t, err)
+			tc.checkResponse(t, resp)
+		})
+	}
+}
+
+func bearerAuth(t *testing.T, req *http.Request, maker token.Maker, user db.User) {
+	addAuthorization(t, req, maker, authorizationTypeBearer, user.Username, time.Minute)
+}
@@ -3,7 +3,9 @@
 package api
 
 import (
+	"crypto/sha256"
 	"database/sql"
+	"encoding/hex"
 	"fmt"
 	"io"
 	"mime/multipart"
@@ -11,7 +13,6 @@ import (
 	"path/filepath"
 	"strconv"
 	"strings"
-	"time"
 
 	"github.com/gofiber/fiber/v2"
 )
@@ -20,32 +21,33 @@ import (
 //  HELPERS
 // -----------------------------------------------------------------------------
 
-// saveUploadedFile persists the uploaded file under the server's upload dir.
-func (s *Server) saveUploadedFile(fileHeader *multipart.FileHeader) (string, error) {
+// saveUploadedFile copies the uploaded file to a temporary file and returns
+// its path and the hex SHA-256 of its content. Files over maxBytes are
+// rejected with a file_too_large error. The caller removes the file.
+func saveUploadedFile(fileHeader *multipart.FileHeader, maxBytes int64) (string, string, error) {
 	src, err := fileHeader.Open()
 	if err != nil {
-		return "", err
+		return "", "", err
 	}
 	defer src.Close()
 
-	filename := fmt.Sprintf("%d_%s", time.Now().UnixNano(), sanitizeFilename(fileHeader.Filename))
-	dstPath := s.absUploadPath(filename)
-
-	// Ensure upload dir exists
-	if err := os.MkdirAll(filepath.Dir(dstPath), 0o755); err != nil {
-		return "", err
-	}
-
-	out, err := os.Create(dstPath)
+	out, err := os.CreateTemp("", "upload_*"+filepath.Ext(sanitizeFilename(fileHeader.Filename)))
 	if err != nil {
-		return "", err
+		return "", "", err
 	}
 	defer out.Close()
 
-	if _, err := io.Copy(out, src); err != nil {
-		return "", err
+	// Read one byte past the limit to tell a full file from a too large one
+	hash := sha256.New()
+	n, err := io.Copy(io.MultiWriter(out, hash), io.LimitReader(src, maxBytes+1))
+	if err == nil && n > maxBytes {
+		err = withCode(uploadErrTooLarge, fmt.Errorf("file is larger than the %d MB limit", maxBytes>>20))
+	}
+	if err != nil {
+		_ = os.Remove(out.Name())
+		return "", "", err
 	}
-	return dstPath, nil
+	return out.Name(), hex.EncodeToString(hash.Sum(nil)), nil
 }
 
 func sanitizeFilename(name string) string {
@@ -54,16 +56,6 @@ func sanitizeFilename(name string) string {
 	return base
 }
 
-// absUploadPath returns a full path inside the configured upload directory.
-// Falls back to "./uploads" if not configured.
-func (s *Server) absUploadPath(filename string) string {
-	uploadDir := s.config.UploadDir
-	if strings.TrimSpace(uploadDir) == "" {
-		uploadDir = "uploads"
-	}
-	return filepath.Join(uploadDir, filename)
-}
-
 // sqlStringOrNull converts a string to sql.NullString properly.
 func sqlStringOrNull(v string) sql.NullString {
 	v = strings.TrimSpace(v)
@@ -0,0 +1,108 @@
+// server/api/local_recommendations.go
+
+package api
+
+import (
+	"fmt"
+	"math"
+	"strings"
+
+	db "github.com/nibir1/go-fiber-postgres-REST-boilerplate/db/sqlc"
+	"github.com/nibir1/go-fiber-postgres-REST-boilerplate/recommend"
+)
+
+// Recommendation modes: "auto" asks OpenAI and falls back to the local
+// ranking when it is not configured or fails, "ai" only asks OpenAI and
+// "local" never does
+const (
+	recommendationModeAuto  = "auto"
+	recommendationModeAI    = "ai"
+	recommendationModeLocal = "local"
+)
+
+// Courses the local recommender returns, like the 3-5 asked from the model
+const localRecommendationCount = 5
+
+// How local runs are named in provenance; bump the version whenever the
+// ranking changes
+const (
+	localRecommenderModel   = "bm25"
+	localRecommenderVersion = "bm25-v2"
+)
+
+// normalizeRecommendationMode checks mode; empty means auto.
+func normalizeRecommendationMode(mode string) (string, error) {
+	switch m := strings.ToLower(strings.TrimSpace(mode)); m {
+	case "", recommendationModeAuto:
+		return recommendationModeAuto, nil
+	case recommendationModeAI, recommendationModeLocal:
+		return m, nil
+	default:
+		return "", fmt.Errorf("invalid mode %q, use auto, ai or local", mode)
+	}
+}
+
+// localRecommendations ranks the candidates by BM25 against the preference
+// and the names of the transcript courses and liked courses. Match is relative to the best
+// course, which scores 100.
+func localRecommendations(in recommendationInput) []Recommendation {
+	courses := make([]recommend.Course, 0, len(in.Candidates))
+	byID := make(map[int64]db.Course, len(in.Candidates))
+	for _, c := range in.Candidates {
+		courses = append(courses, recommend.Course{ID: c.ID, Name: c.Name, LearningOutcomes: c.LearningOutcomes.String})
+		byID[c.ID] = c
+	}
+	query := recommend.Query{Preference: in.Preference}
+	for _, row := range in.TranscriptCourses {
+		if row.Name.Valid {
+			query.History = append(query.History, row.Name.String)
+		}
+	}
+	for _, l := range in.Liked {
+		query.Liked = append(query.Liked, l.Name)
+	}
+
+	matches := recommend.Rank(courses, query, localRecommendationCount)
+	recs := make([]Recommendation, 0, len(matches))
+	for _, m := range matches {
+		course := byID[m.CourseID]
+		recs = append(recs, Recommendation{
+			Type:                 "course",
+			Title:                course.Name,
+			Code:                 course.Code,
+			Description:          localRationale(m, in.MissingPrereqs[m.CourseID]),
+			Match:                math.Round(100 * m.Score / matches[0].Score),
+			Link:                 course.CourseLink.String,
+			CourseID:             course.ID,
+			MissingPrerequisites: in.MissingPrereqs[m.CourseID],
+		})
+	}
+	return recs
+}
+
+// localRationale explains a local match, e.g. "Matches your interest in
+// security and cryptography. Builds on Network Security."
+func localRationale(m recommend.Match, missing []string) string {
+	var parts []string
+	switch len(m.Terms) {
+	case 0:
+	case 1:
+		parts = append(parts, fmt.Sprintf("Matches your interest in %s.", m.Terms[0]))
+	default:
+		parts = append(parts, fmt.Sprintf("Matches your interest in %s and %s.",
+			strings.Join(m.Terms[:len(m.Terms)-1], ", "), m.Terms[len(m.Terms)-1]))
+	}
+	if m.Related != "" {
+		parts = append(parts, fmt.Sprintf("Builds on %s.", m.Related))
+	}
+	if m.Liked != "" {
+		parts = append(parts, fmt.Sprintf("Similar to %s, which you liked.", m.Liked))
+	}
+	if len(missing) > 0 {
+		parts = append(parts, fmt.Sprintf("Recommended to take %s first.", strings.Join(missing, ", ")))
+	}
+	if len(parts) == 0 {
+		return "Related to your studies."
+	}
+	return strings.Join(parts, " ")
+}
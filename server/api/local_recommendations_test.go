@@ -0,0 +1,253 @@
+// server/api/local_recommendations_test.go
+
+package api
+
+import (
+	"bytes"
+	"database/sql"
+	"encoding/json"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/golang/mock/gomock"
+	mockdb "github.com/nibir1/go-fiber-postgres-REST-boilerplate/db/mock"
+	db "github.com/nibir1/go-fiber-postgres-REST-boilerplate/db/sqlc"
+	"github.com/nibir1/go-fiber-postgres-REST-boilerplate/util"
+	"github.com/stretchr/testify/require"
+)
+
+func recommendationTestCatalogue() []db.Course {
+	security := newTestCatalogueCourse("TIES3270", "Cyber Security")
+	security.ID = 1
+	security.LearningOutcomes = sql.NullString{String: "Threats, cryptography and network security", Valid: true}
+	security.CourseLink = sql.NullString{String: "https://example.com/TIES3270", Valid: true}
+	finnish := newTestCatalogueCourse("KIEL1001", "Finnish 1")
+	finnish.ID = 2
+	finnish.LearningOutcomes = sql.NullString{String: "Everyday Finnish conversation", Valid: true}
+	ml := newTestCatalogueCourse("TIEA2150", "Machine Learning")
+	ml.ID = 3
+	ml.LearningOutcomes = sql.NullString{String: "Neural networks and statistical learning", Valid: true}
+	return []db.Course{finnish, ml, security}
+}
+
+func TestCreateRecommendationLocal(t *testing.T) {
+	username := util.RandomOwner()
+	tr := newTestTranscript(t, username)
+	history := testCourse("TIES1000", 5, "4", courseStatusCompleted)
+	history.Name = sql.NullString{String: "Network Programming", Valid: true}
+
+	// Everything up to ranking; no OpenAI key is configured in tests
+	buildRankingStubs := func(store *mockdb.MockStore, feedback ...db.ListCourseFeedbackRow) {
+		store.EXPECT().GetTranscript(gomock.Any(), gomock.Eq(tr.ID)).Times(1).Return(tr, nil)
+		store.EXPECT().ListTranscriptCourses(gomock.Any(), gomock.Eq(tr.ID)).Times(1).Return([]db.TranscriptCourse{history}, nil)
+		store.EXPECT().ListAllCourses(gomock.Any()).Times(1).Return(recommendationTestCatalogue(), nil)
+		store.EXPECT().ListCourseFeedback(gomock.Any(), gomock.Eq(username)).Times(1).Return(feedback, nil)
+		store.EXPECT().ListCoursePrerequisites(gomock.Any()).Times(1).
+			Return(newTestCoursePrerequisites(t, recommendationTestCatalogue()...), nil)
+	}
+	buildSaveStubs := func(store *mockdb.MockStore, candidates int) {
+		store.EXPECT().ListScholarshipsByUser(gomock.Any(), gomock.Eq(username)).Times(1).Return(nil, nil)
+		store.EXPECT().CreateRecommendationTx(gomock.Any(), gomock.Any()).Times(1).
+			DoAndReturn(func(_ any, arg db.CreateRecommendationTxParams) (db.CreateRecommendationTxResult, error) {
+				p := arg.Provenance
+				require.Equal(t, recommendationModeLocal, p.Mode)
+				require.Equal(t, localRecommenderModel, p.Model)
+				require.Equal(t, localRecommenderVersion, p.PromptVersion)
+				require.Equal(t, int32(candidates), p.CandidateCount)
+				require.Equal(t, []string{"TIES1000"}, p.CompletedCodes)
+				require.Len(t, p.InputHash, 64)
+				require.Zero(t, p.Attempts)
+				r := arg.Recommendation
+				saved, err := decodeRecommendationPayload(r.Payload)
+				require.NoError(t, err)
+				require.Equal(t, recommendationPayloadVersion, saved.SchemaVersion)
+				return db.CreateRecommendationTxResult{
+					Recommendation: db.Recommendation{ID: 5, UserUsername: r.UserUsername, TranscriptID: r.TranscriptID, Payload: r.Payload},
+				}, nil
+			})
+	}
+
+	type recommendationResponse struct {
+		Courses        []Recommendation `json:"courses"`
+		Mode           string           `json:"mode"`
+		FallbackReason string           `json:"fallback_reason"`
+		Removed        map[string]int   `json:"removed_by_constraint"`
+	}
+
+	testCases := []struct {
+		name          string
+		url           string
+		body          fiber.Map
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(t *testing.T, resp *http.Response)
+	}{
+		{
+			name: "LocalMode",
+			url:  "/api/recommendations?mode=local",
+			body: fiber.Map{"transcript_id": tr.ID, "preference": "cryptography and security"},
+			buildStubs: func(store *mockdb.MockStore) {
+				buildRankingStubs(store)
+				buildSaveStubs(store, 3)
+			},
+			checkResponse: func(t *testing.T, resp *http.Response) {
+				require.Equal(t, http.StatusOK, resp.StatusCode)
+
+				var body recommendationResponse
+				require.NoError(t, json.NewDecoder(resp.Body).Decode(&body))
+				require.Equal(t, recommendationModeLocal, body.Mode)
+				require.Empty(t, body.FallbackReason)
+				require.Len(t, body.Courses, 2)
+				require.Equal(t, Recommendation{
+					Type:        "course",
+					Title:       "Cyber Security",
+					Code:        "TIES3270",
+					Description: "Matches your interest in security and cryptography. Builds on Network Programming.",
+					Match:       100,
+					Link:        "https://example.com/TIES3270",
+					CourseID:    1,
+				}, body.Courses[0])
+				require.Equal(t, "TIEA2150", body.Courses[1].Code)
+				require.Less(t, body.Courses[1].Match, float64(100))
+			},
+		},
+		{
+			name: "Feedback",
+			url:  "/api/recommendations?mode=local",
+			body: fiber.Map{"transcript_id": tr.ID, "preference": "machine learning"},
+			buildStubs: func(store *mockdb.MockStore) {
+				buildRankingStubs(store,
+					db.ListCourseFeedbackRow{CourseID: 3, Code: "TIEA2150", Name: "Machine Learning", Kind: feedbackNotInterested},
+					db.ListCourseFeedbackRow{CourseID: 9, Code: "TIES4560", Name: "Applied Cryptography", Kind: feedbackLike},
+				)
+				buildSaveStubs(store, 2)
+			},
+			checkResponse: func(t *testing.T, resp *http.Response) {
+				require.Equal(t, http.StatusOK, resp.StatusCode)
+
+				// Machine Learning was dismissed; the liked course pulls
+				// Cyber Security in instead
+				var body recommendationResponse
+				require.NoError(t, json.NewDecoder(resp.Body).Decode(&body))
+				require.Len(t, body.Courses, 1)
+				require.Equal(t, "TIES3270", body.Courses[0].Code)
+				require.Contains(t, body.Courses[0].Description, "Similar to Applied Cryptography, which you liked.")
+			},
+		},
+		{
+			name: "Constraints",
+			url:  "/api/recommendations?mode=local",
+			body: fiber.Map{
+				"transcript_id": tr.ID,
+				"preference":    "machine learning and security",
+				"language":      "english",
+				"exclude_codes": []string{"tiea2150"},
+			},
+			buildStubs: func(store *mockdb.MockStore) {
+				buildRankingStubs(store)
+				buildSaveStubs(store, 2)
+			},
+			checkResponse: func(t *testing.T, resp *http.Response) {
+				require.Equal(t, http.StatusOK, resp.StatusCode)
+
+				var body recommendationResponse
+				require.NoError(t, json.NewDecoder(resp.Body).Decode(&body))
+				require.Equal(t, map[string]int{"language": 0, "exclude_codes": 1}, body.Removed)
+				require.Len(t, body.Courses, 1)
+				require.Equal(t, "TIES3270", body.Courses[0].Code)
+			},
+		},
+		{
+			name: "InvalidCreditRange",
+			url:  "/api/recommendations",
+			body: fiber.Map{"transcript_id": tr.ID, "min_credits": 10, "max_credits": 5},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetTranscript(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, resp *http.Response) {
+				require.Equal(t, http.StatusBadRequest, resp.StatusCode)
+			},
+		},
+		{
+			name: "AutoFallsBackWithoutOpenAI",
+			url:  "/api/recommendations",
+			body: fiber.Map{"transcript_id": tr.ID, "preference": "machine learning"},
+			buildStubs: func(store *mockdb.MockStore) {
+				buildRankingStubs(store)
+				buildSaveStubs(store, 3)
+			},
+			checkResponse: func(t *testing.T, resp *http.Response) {
+				require.Equal(t, http.StatusOK, resp.StatusCode)
+
+				var body recommendationResponse
+				require.NoError(t, json.NewDecoder(resp.Body).Decode(&body))
+				require.Equal(t, recommendationModeLocal, body.Mode)
+				require.Equal(t, "OpenAI is not configured", body.FallbackReason)
+				require.NotEmpty(t, body.Courses)
+				require.Equal(t, "TIEA2150", body.Courses[0].Code)
+			},
+		},
+		{
+			name: "AIModeWithoutOpenAI",
+			url:  "/api/recommendations",
+			body: fiber.Map{"transcript_id": tr.ID, "preference": "machine learning", "mode": "ai"},
+			buildStubs: func(store *mockdb.MockStore) {
+				buildRankingStubs(store)
+				store.EXPECT().GetUser(gomock.Any(), gomock.Eq(username)).AnyTimes().Return(db.User{}, sql.ErrNoRows)
+				store.EXPECT().CreateRecommendationTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, resp *http.Response) {
+				require.Equal(t, http.StatusInternalServerError, resp.StatusCode)
+			},
+		},
+		{
+			name: "OtherUsersTranscript",
+			url:  "/api/recommendations",
+			body: fiber.Map{"transcript_id": tr.ID, "preference": "machine learning"},
+			buildStubs: func(store *mockdb.MockStore) {
+				other := tr
+				other.UserUsername = "someone_else"
+				store.EXPECT().GetTranscript(gomock.Any(), gomock.Eq(tr.ID)).Times(1).Return(other, nil)
+				store.EXPECT().ListTranscriptCourses(gomock.Any(), gomock.Any()).Times(0)
+				store.EXPECT().ReplaceTranscriptCoursesTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, resp *http.Response) {
+				require.Equal(t, http.StatusForbidden, resp.StatusCode)
+			},
+		},
+		{
+			name: "InvalidMode",
+			url:  "/api/recommendations",
+			body: fiber.Map{"transcript_id": tr.ID, "mode": "magic"},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetTranscript(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, resp *http.Response) {
+				require.Equal(t, http.StatusBadRequest, resp.StatusCode)
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStubs(store)
+
+			server := newFiberTestServer(t, store)
+			data, err := json.Marshal(tc.body)
+			require.NoError(t, err)
+			req, err := http.NewRequest(http.MethodPost, tc.url, bytes.NewReader(data))
+			require.NoError(t, err)
+			req.Header.Set("Content-Type", "application/json")
+			addAuthorization(t, req, server.tokenMaker, authorizationTypeBearer, username, time.Minute)
+
+			resp, err := server.app.Test(req, -1)
+			require.NoError(t, err)
+			tc.checkResponse(t, resp)
+		})
+	}
+}
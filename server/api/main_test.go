@@ -21,6 +21,7 @@ import (
 func newFiberTestServer(t *testing.T, store db.Store) *Server {
 	config := util.Config{
 		TokenSymmetricKey: util.RandomString(32),
+		StorageLocalDir:   t.TempDir(),
 	}
 	server, err := NewServer(config, store)
 	require.NoError(t, err)
@@ -3,9 +3,13 @@
 package api
 
 import (
+	"database/sql"
+	"errors"
+	"fmt"
 	"strings"
 
 	"github.com/gofiber/fiber/v2"
+	db "github.com/nibir1/go-fiber-postgres-REST-boilerplate/db/sqlc"
 	"github.com/nibir1/go-fiber-postgres-REST-boilerplate/token"
 )
 
@@ -16,6 +20,9 @@ const (
 	authorizationPayloadKey = "authorization_payload"
 )
 
+// userRoleAdmin is the users.role of staff who maintain the course catalogue
+const userRoleAdmin = "admin"
+
 // authMiddlewareFiber returns a Fiber middleware function that validates JWT/Paseto tokens.
 // It ensures requests to protected routes include a valid Authorization header.
 func authMiddlewareFiber(tokenMaker token.Maker) fiber.Handler {
@@ -65,3 +72,28 @@ func authMiddlewareFiber(tokenMaker token.Maker) fiber.Handler {
 		return c.Next()
 	}
 }
+
+// adminMiddlewareFiber only lets users with the admin role through. It runs
+// after authMiddlewareFiber and reads the role from the database, so a revoked
+// role takes effect without waiting for the token to expire.
+func adminMiddlewareFiber(store db.Store) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		payload, ok := c.Locals(authorizationPayloadKey).(*token.Payload)
+		if !ok || payload == nil {
+			return c.Status(fiber.StatusUnauthorized).JSON(errorResponse(fmt.Errorf("unauthorized")))
+		}
+
+		user, err := store.GetUser(c.Context(), payload.Username)
+		if err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				return c.Status(fiber.StatusUnauthorized).JSON(errorResponse(fmt.Errorf("unauthorized")))
+			}
+			return c.Status(fiber.StatusInternalServerError).JSON(errorResponse(err))
+		}
+		if user.Role != userRoleAdmin {
+			return c.Status(fiber.StatusForbidden).JSON(errorResponse(fmt.Errorf("admin role required")))
+		}
+
+		return c.Next()
+	}
+}
@@ -0,0 +1,80 @@
+// server/api/recommendation_payload.go
+
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"strings"
+
+	db "github.com/nibir1/go-fiber-postgres-REST-boilerplate/db/sqlc"
+)
+
+// recommendationPayloadVersion is the schema_version written to new
+// payloads; bump it, and teach decodeRecommendationPayload the old shape,
+// whenever recommendationPayload changes
+const recommendationPayloadVersion = 1
+
+// recommendationPayload is the payload column of a recommendation. Read it
+// with decodeRecommendationPayload and write it with
+// encodeRecommendationPayload only.
+type recommendationPayload struct {
+	SchemaVersion int              `json:"schema_version"`
+	Courses       []Recommendation `json:"courses"`
+	// The user's scholarships when the recommendation was made; chat and
+	// PDF reports read the current ones from the database instead
+	Scholarships []db.Scholarship `json:"scholarships,omitempty"`
+}
+
+// decodeRecommendationPayload reads a stored payload. Payloads written
+// before schema_version existed have the version 1 fields.
+func decodeRecommendationPayload(raw []byte) (recommendationPayload, error) {
+	var p recommendationPayload
+	if err := json.Unmarshal(raw, &p); err != nil {
+		return recommendationPayload{}, fmt.Errorf("failed to parse recommendation payload: %w", err)
+	}
+	switch p.SchemaVersion {
+	case 0:
+		p.SchemaVersion = 1
+	case recommendationPayloadVersion:
+	default:
+		return recommendationPayload{}, fmt.Errorf("unsupported recommendation payload schema_version %d", p.SchemaVersion)
+	}
+	return p, nil
+}
+
+// encodeRecommendationPayload stamps p with the current version and
+// returns it as JSON, or an error if p is malformed.
+func encodeRecommendationPayload(p recommendationPayload) ([]byte, error) {
+	p.SchemaVersion = recommendationPayloadVersion
+	if p.Courses == nil {
+		p.Courses = []Recommendation{}
+	}
+	if err := p.validate(); err != nil {
+		return nil, fmt.Errorf("invalid recommendation payload: %w", err)
+	}
+	return json.Marshal(p)
+}
+
+// validate checks that every course is a titled course with a 0-100 match,
+// recommended once.
+func (p recommendationPayload) validate() error {
+	seen := make(map[int64]bool, len(p.Courses))
+	for i, c := range p.Courses {
+		switch {
+		case c.Type != "course":
+			return fmt.Errorf("courses[%d]: type must be \"course\", got %q", i, c.Type)
+		case strings.TrimSpace(c.Title) == "":
+			return fmt.Errorf("courses[%d]: missing title", i)
+		case math.IsNaN(c.Match) || c.Match < 0 || c.Match > 100:
+			return fmt.Errorf("courses[%d]: match must be between 0 and 100", i)
+		case c.CourseID < 0:
+			return fmt.Errorf("courses[%d]: invalid course_id %d", i, c.CourseID)
+		case c.CourseID > 0 && seen[c.CourseID]:
+			return fmt.Errorf("courses[%d]: course_id %d appears twice", i, c.CourseID)
+		}
+		seen[c.CourseID] = true
+	}
+	return nil
+}
@@ -0,0 +1,77 @@
+// server/api/recommendation_payload_test.go
+
+package api
+
+import (
+	"encoding/json"
+	"math"
+	"testing"
+
+	db "github.com/nibir1/go-fiber-postgres-REST-boilerplate/db/sqlc"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDecodeRecommendationPayload(t *testing.T) {
+	// Written before payloads were versioned
+	p, err := decodeRecommendationPayload([]byte(`{"courses":[{"type":"course","title":"Cyber Security","match":90,"course_id":1}],"scholarships":[{"id":3,"title":"Erasmus"}]}`))
+	require.NoError(t, err)
+	require.Equal(t, recommendationPayloadVersion, p.SchemaVersion)
+	require.Equal(t, []Recommendation{{Type: "course", Title: "Cyber Security", Match: 90, CourseID: 1}}, p.Courses)
+	require.Equal(t, []db.Scholarship{{ID: 3, Title: "Erasmus"}}, p.Scholarships)
+
+	p, err = decodeRecommendationPayload([]byte(`{"schema_version":1,"courses":[]}`))
+	require.NoError(t, err)
+	require.Empty(t, p.Courses)
+
+	_, err = decodeRecommendationPayload([]byte(`{"schema_version":2,"courses":[]}`))
+	require.EqualError(t, err, "unsupported recommendation payload schema_version 2")
+	_, err = decodeRecommendationPayload([]byte(`[{"title":"Cyber Security"}]`))
+	require.Error(t, err)
+	_, err = decodeRecommendationPayload([]byte(`{"courses":"none"}`))
+	require.Error(t, err)
+}
+
+func TestEncodeRecommendationPayload(t *testing.T) {
+	course := Recommendation{Type: "course", Title: "Cyber Security", Code: "TIES3270", Match: 90, CourseID: 1}
+
+	raw, err := encodeRecommendationPayload(recommendationPayload{})
+	require.NoError(t, err)
+	require.JSONEq(t, `{"schema_version":1,"courses":[]}`, string(raw))
+
+	// Round trip
+	in := recommendationPayload{Courses: []Recommendation{course}, Scholarships: []db.Scholarship{{ID: 3, Title: "Erasmus"}}}
+	raw, err = encodeRecommendationPayload(in)
+	require.NoError(t, err)
+	out, err := decodeRecommendationPayload(raw)
+	require.NoError(t, err)
+	in.SchemaVersion = recommendationPayloadVersion
+	require.Equal(t, in, out)
+
+	var stored map[string]json.RawMessage
+	require.NoError(t, json.Unmarshal(raw, &stored))
+	require.Equal(t, "1", string(stored["schema_version"]))
+
+	malformed := map[string]func(c *Recommendation){
+		"courses[1]: type must be \"course\", got \"\"": func(c *Recommendation) { c.Type = "" },
+		"courses[1]: missing title":                     func(c *Recommendation) { c.Title = " " },
+		"courses[1]: match must be between 0 and 100":   func(c *Recommendation) { c.Match = 101 },
+		"courses[1]: invalid course_id -2":              func(c *Recommendation) { c.CourseID = -2 },
+		"courses[1]: course_id 1 appears twice":         func(c *Recommendation) {},
+	}
+	for want, change := range malformed {
+		bad := course
+		change(&bad)
+		_, err := encodeRecommendationPayload(recommendationPayload{Courses: []Recommendation{course, bad}})
+		require.EqualError(t, err, "invalid recommendation payload: "+want)
+	}
+
+	nan := course
+	nan.Match = math.NaN()
+	_, err = encodeRecommendationPayload(recommendationPayload{Courses: []Recommendation{nan}})
+	require.Error(t, err)
+
+	// Courses without an ID (older recommendations) may repeat
+	noID := Recommendation{Type: "course", Title: "Study skills", Match: 40}
+	_, err = encodeRecommendationPayload(recommendationPayload{Courses: []Recommendation{noID, noID}})
+	require.NoError(t, err)
+}
@@ -0,0 +1,161 @@
+// server/api/recommendation_provenance.go
+
+package api
+
+import (
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	db "github.com/nibir1/go-fiber-postgres-REST-boilerplate/db/sqlc"
+	"github.com/nibir1/go-fiber-postgres-REST-boilerplate/token"
+)
+
+// recommendationRun records how a recommendation was produced; it is saved
+// as the recommendation's provenance.
+type recommendationRun struct {
+	Mode           string // ai or local
+	Model          string
+	PromptVersion  string
+	InputHash      string
+	CandidateCount int // courses offered to the ranker
+	CompletedCodes []string
+	EmbeddingModel string // set when candidates were narrowed by embeddings
+	FallbackReason string
+	Attempts       int // model calls, including retries
+	Usage          aiUsage
+	Latency        time.Duration
+}
+
+// params converts the run for storage.
+func (run recommendationRun) params() db.CreateRecommendationProvenanceParams {
+	completed := run.CompletedCodes
+	if completed == nil {
+		completed = []string{}
+	}
+	return db.CreateRecommendationProvenanceParams{
+		Mode:             run.Mode,
+		Model:            run.Model,
+		PromptVersion:    run.PromptVersion,
+		InputHash:        run.InputHash,
+		CandidateCount:   int32(run.CandidateCount),
+		CompletedCodes:   completed,
+		EmbeddingModel:   sqlStringOrNull(run.EmbeddingModel),
+		FallbackReason:   sqlStringOrNull(run.FallbackReason),
+		Attempts:         int32(run.Attempts),
+		PromptTokens:     int32(run.Usage.PromptTokens),
+		CompletionTokens: int32(run.Usage.CompletionTokens),
+		TotalTokens:      int32(run.Usage.TotalTokens),
+		LatencyMs:        run.Latency.Milliseconds(),
+	}
+}
+
+// recommendationInputHash identifies what a recommendation was made from:
+// the request, the courses the student has completed, the candidates left
+// after filtering and the courses they liked. Equal hashes with different
+// advice point at the ranker, not the input.
+func recommendationInputHash(req createRecommendationRequest, mode string, in recommendationInput) string {
+	ids := make([]int64, 0, len(in.Candidates))
+	for _, c := range in.Candidates {
+		ids = append(ids, c.ID)
+	}
+	liked := make([]int64, 0, len(in.Liked))
+	for _, l := range in.Liked {
+		liked = append(liked, l.ID)
+	}
+	b, _ := json.Marshal(struct {
+		TranscriptID   int64        `json:"transcript_id"`
+		Preference     string       `json:"preference"`
+		Mode           string       `json:"mode"`
+		Filters        courseFilter `json:"filters"`
+		CompletedCodes []string     `json:"completed_codes"`
+		CandidateIDs   []int64      `json:"candidate_ids"`
+		LikedIDs       []int64      `json:"liked_ids"`
+	}{req.TranscriptID, req.Preference, mode, req.courseFilter, in.CompletedCodes, ids, liked})
+	sum := sha256.Sum256(b)
+	return hex.EncodeToString(sum[:])
+}
+
+// aiTokenUsage is the token part of a provenance response
+type aiTokenUsage struct {
+	Prompt     int32 `json:"prompt"`
+	Completion int32 `json:"completion"`
+	Total      int32 `json:"total"`
+}
+
+type recommendationProvenanceResponse struct {
+	RecommendationID int64        `json:"recommendation_id"`
+	Mode             string       `json:"mode"`
+	Model            string       `json:"model"`
+	PromptVersion    string       `json:"prompt_version"`
+	InputHash        string       `json:"input_hash"`
+	CandidateCount   int32        `json:"candidate_count"`
+	CompletedCodes   []string     `json:"completed_codes"`
+	EmbeddingModel   string       `json:"embedding_model,omitempty"`
+	FallbackReason   string       `json:"fallback_reason,omitempty"`
+	Attempts         int32        `json:"attempts"`
+	TokenUsage       aiTokenUsage `json:"token_usage"`
+	LatencyMs        int64        `json:"latency_ms"`
+	CreatedAt        time.Time    `json:"created_at"`
+}
+
+func newRecommendationProvenanceResponse(p db.RecommendationProvenance) recommendationProvenanceResponse {
+	completed := p.CompletedCodes
+	if completed == nil {
+		completed = []string{}
+	}
+	return recommendationProvenanceResponse{
+		RecommendationID: p.RecommendationID,
+		Mode:             p.Mode,
+		Model:            p.Model,
+		PromptVersion:    p.PromptVersion,
+		InputHash:        p.InputHash,
+		CandidateCount:   p.CandidateCount,
+		CompletedCodes:   completed,
+		EmbeddingModel:   p.EmbeddingModel.String,
+		FallbackReason:   p.FallbackReason.String,
+		Attempts:         p.Attempts,
+		TokenUsage:       aiTokenUsage{Prompt: p.PromptTokens, Completion: p.CompletionTokens, Total: p.TotalTokens},
+		LatencyMs:        p.LatencyMs,
+		CreatedAt:        p.CreatedAt,
+	}
+}
+
+// GET /recommendations/:id/provenance
+func (s *Server) getRecommendationProvenance(c *fiber.Ctx) error {
+	payload, ok := c.Locals(authorizationPayloadKey).(*token.Payload)
+	if !ok || payload == nil {
+		return c.Status(fiber.StatusUnauthorized).JSON(errorResponse(fmt.Errorf("unauthorized")))
+	}
+
+	id, err := parseIDParam(c, "id")
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(errorResponse(err))
+	}
+
+	reco, err := s.store.GetRecommendation(c.Context(), id)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return c.Status(fiber.StatusNotFound).JSON(errorResponse(fmt.Errorf("recommendation not found")))
+		}
+		return c.Status(fiber.StatusInternalServerError).JSON(errorResponse(err))
+	}
+	if reco.UserUsername != payload.Username {
+		return c.Status(fiber.StatusForbidden).JSON(errorResponse(fmt.Errorf("forbidden")))
+	}
+
+	provenance, err := s.store.GetRecommendationProvenance(c.Context(), reco.ID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			// Recommendations made before provenance was recorded
+			return c.Status(fiber.StatusNotFound).JSON(errorResponse(fmt.Errorf("no provenance recorded for this recommendation")))
+		}
+		return c.Status(fiber.StatusInternalServerError).JSON(errorResponse(err))
+	}
+
+	return c.JSON(newRecommendationProvenanceResponse(provenance))
+}
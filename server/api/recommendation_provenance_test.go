@@ -0,0 +1,139 @@
+// server/api/recommendation_provenance_test.go
+
+package api
+
+import (
+	"database/sql"
+	"encoding/json"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	mockdb "github.com/nibir1/go-fiber-postgres-REST-boilerplate/db/mock"
+	db "github.com/nibir1/go-fiber-postgres-REST-boilerplate/db/sqlc"
+	"github.com/nibir1/go-fiber-postgres-REST-boilerplate/util"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRecommendationInputHash(t *testing.T) {
+	req := createRecommendationRequest{TranscriptID: 1, Preference: "security"}
+	in := recommendationInput{CompletedCodes: []string{"TIES1000"}, Candidates: recommendationTestCatalogue()}
+
+	hash := recommendationInputHash(req, recommendationModeAuto, in)
+	require.Equal(t, hash, recommendationInputHash(req, recommendationModeAuto, in))
+
+	other := req
+	other.Preference = "machine learning"
+	require.NotEqual(t, hash, recommendationInputHash(other, recommendationModeAuto, in))
+
+	changed := in
+	changed.CompletedCodes = nil
+	require.NotEqual(t, hash, recommendationInputHash(req, recommendationModeAuto, changed))
+	changed = in
+	changed.Candidates = in.Candidates[:2]
+	require.NotEqual(t, hash, recommendationInputHash(req, recommendationModeAuto, changed))
+	changed = in
+	changed.Liked = []likedCourse{{ID: 7, Code: "TIES4560", Name: "Applied Cryptography"}}
+	require.NotEqual(t, hash, recommendationInputHash(req, recommendationModeAuto, changed))
+}
+
+func TestGetRecommendationProvenanceAPI(t *testing.T) {
+	username := util.RandomOwner()
+	reco := db.Recommendation{ID: 5, UserUsername: username, Payload: []byte(`{"courses":[]}`), CreatedAt: time.Now()}
+	provenance := db.RecommendationProvenance{
+		RecommendationID: 5,
+		Mode:             recommendationModeAI,
+		Model:            "gpt-4o-mini",
+		PromptVersion:    recommendationPromptVersion,
+		InputHash:        "abc123",
+		CandidateCount:   40,
+		CompletedCodes:   []string{"TIES1000"},
+		EmbeddingModel:   sql.NullString{String: "local-hash-512", Valid: true},
+		Attempts:         2,
+		PromptTokens:     1800,
+		CompletionTokens: 300,
+		TotalTokens:      2100,
+		LatencyMs:        4200,
+		CreatedAt:        time.Now(),
+	}
+
+	testCases := []struct {
+		name          string
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(t *testing.T, resp *http.Response)
+	}{
+		{
+			name: "OK",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetRecommendation(gomock.Any(), gomock.Eq(int64(5))).Times(1).Return(reco, nil)
+				store.EXPECT().GetRecommendationProvenance(gomock.Any(), gomock.Eq(int64(5))).Times(1).Return(provenance, nil)
+			},
+			checkResponse: func(t *testing.T, resp *http.Response) {
+				require.Equal(t, http.StatusOK, resp.StatusCode)
+
+				var body recommendationProvenanceResponse
+				require.NoError(t, json.NewDecoder(resp.Body).Decode(&body))
+				require.Equal(t, "gpt-4o-mini", body.Model)
+				require.Equal(t, recommendationPromptVersion, body.PromptVersion)
+				require.Equal(t, int32(40), body.CandidateCount)
+				require.Equal(t, []string{"TIES1000"}, body.CompletedCodes)
+				require.Equal(t, "local-hash-512", body.EmbeddingModel)
+				require.Empty(t, body.FallbackReason)
+				require.Equal(t, aiTokenUsage{Prompt: 1800, Completion: 300, Total: 2100}, body.TokenUsage)
+				require.Equal(t, int64(4200), body.LatencyMs)
+			},
+		},
+		{
+			name: "OtherUsersRecommendation",
+			buildStubs: func(store *mockdb.MockStore) {
+				other := reco
+				other.UserUsername = "someone_else"
+				store.EXPECT().GetRecommendation(gomock.Any(), gomock.Eq(int64(5))).Times(1).Return(other, nil)
+				store.EXPECT().GetRecommendationProvenance(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, resp *http.Response) {
+				require.Equal(t, http.StatusForbidden, resp.StatusCode)
+			},
+		},
+		{
+			name: "RecordedBeforeProvenance",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetRecommendation(gomock.Any(), gomock.Eq(int64(5))).Times(1).Return(reco, nil)
+				store.EXPECT().GetRecommendationProvenance(gomock.Any(), gomock.Eq(int64(5))).Times(1).
+					Return(db.RecommendationProvenance{}, sql.ErrNoRows)
+			},
+			checkResponse: func(t *testing.T, resp *http.Response) {
+				require.Equal(t, http.StatusNotFound, resp.StatusCode)
+			},
+		},
+		{
+			name: "NotFound",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetRecommendation(gomock.Any(), gomock.Eq(int64(5))).Times(1).Return(db.Recommendation{}, sql.ErrNoRows)
+			},
+			checkResponse: func(t *testing.T, resp *http.Response) {
+				require.Equal(t, http.StatusNotFound, resp.StatusCode)
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStubs(store)
+
+			server := newFiberTestServer(t, store)
+			req, err := http.NewRequest(http.MethodGet, "/api/recommendations/5/provenance", nil)
+			require.NoError(t, err)
+			addAuthorization(t, req, server.tokenMaker, authorizationTypeBearer, username, time.Minute)
+
+			resp, err := server.app.Test(req, -1)
+			require.NoError(t, err)
+			tc.checkResponse(t, resp)
+		})
+	}
+}
@@ -0,0 +1,184 @@
+// server/api/recommendation_validation.go
+
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"math"
+	"sort"
+	"strings"
+
+	db "github.com/nibir1/go-fiber-postgres-REST-boilerplate/db/sqlc"
+)
+
+// How many valid courses the model must pick before we stop re-asking,
+// matching the 3-5 in the prompt, and how often it is asked in total
+const (
+	aiMinRecommendations = 3
+	aiMaxRecommendations = 5
+	aiMaxAttempts        = 3
+)
+
+// aiCourseItem is one course as the model returns it
+type aiCourseItem struct {
+	CourseID  int64   `json:"course_id"`
+	Code      string  `json:"code"`
+	Title     string  `json:"title"`
+	Rationale string  `json:"rationale"`
+	Match     float64 `json:"match"`
+}
+
+// rejectedAIItem is a course the model picked that cannot be recommended
+type rejectedAIItem struct {
+	Item   aiCourseItem
+	Reason string
+}
+
+// parseAIRecommendations reads {"recommendations": [...]} or a bare array.
+func parseAIRecommendations(raw string) ([]aiCourseItem, error) {
+	var wrapped struct {
+		Recommendations []aiCourseItem `json:"recommendations"`
+	}
+	err := json.Unmarshal([]byte(raw), &wrapped)
+	if err == nil {
+		return wrapped.Recommendations, nil
+	}
+	var direct []aiCourseItem
+	if err2 := json.Unmarshal([]byte(raw), &direct); err2 != nil {
+		return nil, fmt.Errorf("invalid AI response: %w", err)
+	}
+	return direct, nil
+}
+
+// validateAIRecommendations keeps the items that name a candidate not
+// already chosen. Code, title and link come from the catalogue, not the
+// model, and the match is clamped to 0-100.
+func validateAIRecommendations(items []aiCourseItem, in recommendationInput, chosen map[int64]bool) ([]Recommendation, []rejectedAIItem) {
+	candidates := make(map[int64]db.Course, len(in.Candidates))
+	for _, c := range in.Candidates {
+		candidates[c.ID] = c
+	}
+
+	var valid []Recommendation
+	var rejected []rejectedAIItem
+	for _, item := range items {
+		course, ok := candidates[item.CourseID]
+		if !ok {
+			rejected = append(rejected, rejectedAIItem{Item: item, Reason: in.rejectReason(item.CourseID)})
+			continue
+		}
+		if chosen[course.ID] {
+			rejected = append(rejected, rejectedAIItem{Item: item, Reason: "duplicate"})
+			continue
+		}
+		chosen[course.ID] = true
+		valid = append(valid, Recommendation{
+			Type:                 "course",
+			Title:                course.Name,
+			Code:                 course.Code,
+			Description:          item.Rationale,
+			Match:                math.Max(0, math.Min(100, item.Match)),
+			Link:                 course.CourseLink.String,
+			CourseID:             course.ID,
+			MissingPrerequisites: in.MissingPrereqs[course.ID],
+		})
+	}
+	return valid, rejected
+}
+
+// rejectReason explains why a course the model picked is not a candidate.
+func (in recommendationInput) rejectReason(courseID int64) string {
+	for _, c := range in.Catalogue {
+		if c.ID != courseID {
+			continue
+		}
+		if courseCodeSet(in.CompletedCodes)[normalizeCourseCode(c.Code)] {
+			return "already completed"
+		}
+		return "not a candidate"
+	}
+	return "unknown course"
+}
+
+// collectAIRecommendations asks the model until it has picked at least
+// aiMinRecommendations valid courses (or all candidates, if fewer), at most
+// aiMaxAttempts times. Each retry tells the model what was wrong. It fails
+// only when no attempt gave a valid course.
+func collectAIRecommendations(ask func(messages []aiMessage) (string, error), messages []aiMessage, in recommendationInput) ([]Recommendation, error) {
+	want := min(aiMinRecommendations, len(in.Candidates))
+	chosen := map[int64]bool{}
+	var recs []Recommendation
+	var lastErr error
+
+	for attempt := 1; attempt <= aiMaxAttempts && len(recs) < want; attempt++ {
+		raw, err := ask(messages)
+		if err != nil {
+			// Transport and API errors are not the model's fault; retrying
+			// them here would only multiply the wait
+			if len(recs) > 0 {
+				break
+			}
+			return nil, err
+		}
+
+		items, err := parseAIRecommendations(raw)
+		if err != nil {
+			log.Printf("[AI] Recommendation attempt %d: %v", attempt, err)
+			lastErr = err
+			messages = append(messages,
+				aiMessage{Role: "assistant", Content: raw},
+				aiMessage{Role: "user", Content: `That was not valid JSON. Answer with a JSON object {"recommendations": [...]} only.`},
+			)
+			continue
+		}
+
+		valid, rejected := validateAIRecommendations(items, in, chosen)
+		recs = append(recs, valid...)
+		for _, r := range rejected {
+			log.Printf("[AI] Rejected recommendation course_id=%d code=%q: %s", r.Item.CourseID, r.Item.Code, r.Reason)
+		}
+		if len(recs) >= want {
+			break
+		}
+		messages = append(messages,
+			aiMessage{Role: "assistant", Content: raw},
+			aiMessage{Role: "user", Content: retryPrompt(rejected, chosen, want-len(recs))},
+		)
+	}
+
+	if len(recs) == 0 {
+		if lastErr != nil {
+			return nil, lastErr
+		}
+		return nil, fmt.Errorf("the model recommended no valid courses")
+	}
+	sort.SliceStable(recs, func(i, j int) bool { return recs[i].Match > recs[j].Match })
+	if len(recs) > aiMaxRecommendations {
+		recs = recs[:aiMaxRecommendations]
+	}
+	return recs, nil
+}
+
+// retryPrompt asks for more courses after an answer with too few valid ones.
+func retryPrompt(rejected []rejectedAIItem, chosen map[int64]bool, missing int) string {
+	var b strings.Builder
+	if len(rejected) > 0 {
+		b.WriteString("These picks were invalid:\n")
+		for _, r := range rejected {
+			fmt.Fprintf(&b, "- course_id %d (%s): %s\n", r.Item.CourseID, r.Item.Code, r.Reason)
+		}
+	}
+	fmt.Fprintf(&b, "Recommend %d more course(s), using only course_id values from 'Available Courses'", missing)
+	if len(chosen) > 0 {
+		ids := make([]string, 0, len(chosen))
+		for id := range chosen {
+			ids = append(ids, fmt.Sprint(id))
+		}
+		sort.Strings(ids)
+		fmt.Fprintf(&b, " and not %s, which you already chose", strings.Join(ids, ", "))
+	}
+	b.WriteString(". Use the same JSON format.")
+	return b.String()
+}
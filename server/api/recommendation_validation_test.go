@@ -0,0 +1,133 @@
+// server/api/recommendation_validation_test.go
+
+package api
+
+import (
+	"errors"
+	"testing"
+
+	db "github.com/nibir1/go-fiber-postgres-REST-boilerplate/db/sqlc"
+	"github.com/stretchr/testify/require"
+)
+
+// validationTestInput offers courses 1-3 of the recommendation test
+// catalogue plus a completed course 4 that is not a candidate
+func validationTestInput() recommendationInput {
+	candidates := recommendationTestCatalogue()
+	completed := newTestCatalogueCourse("TIES1000", "Network Programming")
+	completed.ID = 4
+	return recommendationInput{
+		CompletedCodes: []string{"TIES1000"},
+		Catalogue:      append([]db.Course{completed}, candidates...),
+		Candidates:     candidates,
+		MissingPrereqs: map[int64][]string{3: {"TIEA311"}},
+	}
+}
+
+func TestParseAIRecommendations(t *testing.T) {
+	items, err := parseAIRecommendations(`{"recommendations": [{"course_id": 1, "rationale": "fits", "match": 80}]}`)
+	require.NoError(t, err)
+	require.Equal(t, []aiCourseItem{{CourseID: 1, Rationale: "fits", Match: 80}}, items)
+
+	items, err = parseAIRecommendations(`[{"course_id": 2}]`)
+	require.NoError(t, err)
+	require.Equal(t, []aiCourseItem{{CourseID: 2}}, items)
+
+	_, err = parseAIRecommendations(`Sure! Here are some courses`)
+	require.Error(t, err)
+}
+
+func TestValidateAIRecommendations(t *testing.T) {
+	in := validationTestInput()
+	chosen := map[int64]bool{}
+	valid, rejected := validateAIRecommendations([]aiCourseItem{
+		{CourseID: 1, Code: "WRONG1", Title: "Hallucinated title", Rationale: "fits", Match: 140},
+		{CourseID: 4, Code: "TIES1000", Match: 90},
+		{CourseID: 99, Code: "FAKE99", Match: 90},
+		{CourseID: 1, Code: "TIES3270", Match: 70},
+		{CourseID: 3, Code: "TIEA2150", Match: -5},
+	}, in, chosen)
+
+	require.Equal(t, []Recommendation{
+		{Type: "course", Title: "Cyber Security", Code: "TIES3270", Description: "fits", Match: 100, Link: "https://example.com/TIES3270", CourseID: 1},
+		{Type: "course", Title: "Machine Learning", Code: "TIEA2150", Match: 0, CourseID: 3, MissingPrerequisites: []string{"TIEA311"}},
+	}, valid)
+	require.Equal(t, []string{"already completed", "unknown course", "duplicate"},
+		[]string{rejected[0].Reason, rejected[1].Reason, rejected[2].Reason})
+	require.Equal(t, map[int64]bool{1: true, 3: true}, chosen)
+
+	// A catalogue course left out by filters is not a candidate either
+	in.Candidates = in.Candidates[:1]
+	_, rejected = validateAIRecommendations([]aiCourseItem{{CourseID: 3}}, in, map[int64]bool{})
+	require.Equal(t, "not a candidate", rejected[0].Reason)
+}
+
+func TestCollectAIRecommendations(t *testing.T) {
+	initial := []aiMessage{{Role: "system", Content: "advise"}, {Role: "user", Content: "courses"}}
+
+	// replies returns an ask func answering with responses in turn and
+	// recording how many messages each call got
+	replies := func(responses ...string) (func([]aiMessage) (string, error), *[]int) {
+		var calls []int
+		return func(messages []aiMessage) (string, error) {
+			calls = append(calls, len(messages))
+			if len(calls) > len(responses) {
+				return "", errors.New("unexpected call")
+			}
+			return responses[len(calls)-1], nil
+		}, &calls
+	}
+
+	t.Run("EnoughFirstTime", func(t *testing.T) {
+		ask, calls := replies(`{"recommendations": [{"course_id": 2, "match": 50}, {"course_id": 1, "match": 90}, {"course_id": 3, "match": 70}]}`)
+		recs, err := collectAIRecommendations(ask, initial, validationTestInput())
+		require.NoError(t, err)
+		require.Equal(t, []int{2}, *calls)
+		require.Equal(t, []int64{1, 3, 2}, []int64{recs[0].CourseID, recs[1].CourseID, recs[2].CourseID})
+	})
+
+	t.Run("RetriesUntilEnough", func(t *testing.T) {
+		ask, calls := replies(
+			`not json`,
+			`{"recommendations": [{"course_id": 1, "match": 90}, {"course_id": 99, "match": 95}]}`,
+			`{"recommendations": [{"course_id": 1, "match": 90}, {"course_id": 2, "match": 60}, {"course_id": 3, "match": 65}]}`,
+		)
+		recs, err := collectAIRecommendations(ask, initial, validationTestInput())
+		require.NoError(t, err)
+		// Each retry carries the previous answer and the complaint
+		require.Equal(t, []int{2, 4, 6}, *calls)
+		require.Len(t, recs, 3)
+		require.Equal(t, int64(1), recs[0].CourseID)
+	})
+
+	t.Run("KeepsFewValidAfterLastAttempt", func(t *testing.T) {
+		ask, calls := replies(
+			`{"recommendations": [{"course_id": 2, "match": 60}]}`,
+			`{"recommendations": [{"course_id": 2, "match": 60}]}`,
+			`{"recommendations": [{"course_id": 4, "match": 60}]}`,
+		)
+		recs, err := collectAIRecommendations(ask, initial, validationTestInput())
+		require.NoError(t, err)
+		require.Len(t, *calls, aiMaxAttempts)
+		require.Len(t, recs, 1)
+	})
+
+	t.Run("NoValidCourses", func(t *testing.T) {
+		ask, _ := replies(`[{"course_id": 99}]`, `[{"course_id": 98}]`, `[]`)
+		_, err := collectAIRecommendations(ask, initial, validationTestInput())
+		require.EqualError(t, err, "the model recommended no valid courses")
+	})
+
+	t.Run("OpenAIError", func(t *testing.T) {
+		ask, calls := replies()
+		_, err := collectAIRecommendations(ask, initial, validationTestInput())
+		require.Error(t, err)
+		require.Len(t, *calls, 1)
+	})
+}
+
+func TestRetryPrompt(t *testing.T) {
+	prompt := retryPrompt([]rejectedAIItem{{Item: aiCourseItem{CourseID: 99, Code: "FAKE99"}, Reason: "unknown course"}}, map[int64]bool{3: true, 1: true}, 1)
+	require.Equal(t, "These picks were invalid:\n- course_id 99 (FAKE99): unknown course\n"+
+		"Recommend 1 more course(s), using only course_id values from 'Available Courses' and not 1, 3, which you already chose. Use the same JSON format.", prompt)
+}
@@ -0,0 +1,61 @@
+// server/api/redaction.go
+
+package api
+
+import (
+	"context"
+	"log"
+
+	"github.com/nibir1/go-fiber-postgres-REST-boilerplate/redact"
+)
+
+// newRedactor builds the PII redactor for one request of a user: the user's
+// profile name is always masked, and their strict_redaction setting selects
+// the level. Without a profile the standard patterns still apply.
+func (s *Server) newRedactor(ctx context.Context, username string) *redact.Redactor {
+	user, err := s.store.GetUser(ctx, username)
+	if err != nil {
+		log.Printf("[WARN] Could not load redaction settings for %s: %v", username, err)
+		return redact.New(redact.Standard)
+	}
+
+	level := redact.Standard
+	if user.StrictRedaction {
+		level = redact.Strict
+	}
+	return redact.New(level, user.FullName)
+}
+
+// redactMessages masks personal data in every message before it leaves the server.
+func redactMessages(r *redact.Redactor, messages []aiMessage) []aiMessage {
+	out := make([]aiMessage, len(messages))
+	for i, m := range messages {
+		out[i] = aiMessage{Role: m.Role, Content: r.Redact(m.Content)}
+	}
+	return out
+}
+
+// callOpenAIChatRedacted is callOpenAIChat with the messages redacted first.
+// The response still holds placeholders; callers restore what they show to users.
+func (s *Server) callOpenAIChatRedacted(ctx context.Context, r *redact.Redactor, messages []aiMessage, expectJSON bool) (string, error) {
+	content, _, err := s.callOpenAIChatRedactedUsage(ctx, r, messages, expectJSON)
+	return content, err
+}
+
+// callOpenAIChatRedactedUsage is callOpenAIChatRedacted that also returns
+// the tokens used.
+func (s *Server) callOpenAIChatRedactedUsage(ctx context.Context, r *redact.Redactor, messages []aiMessage, expectJSON bool) (string, aiUsage, error) {
+	messages = redactMessages(r, messages)
+	if counts := r.Counts(); len(counts) > 0 {
+		log.Printf("[AI] Redacted personal data before sending: %v", counts)
+	}
+	return callOpenAIChatUsage(ctx, s.config.OpenAIAPIKey, s.openAIModel(), messages, expectJSON)
+}
+
+// openAIModel returns the configured chat model.
+func (s *Server) openAIModel() string {
+	if s.config.OpenAIModel != "" {
+		return s.config.OpenAIModel
+	}
+	return defaultOpenAIModel
+}
@@ -4,9 +4,10 @@ package api
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log"
-	"os"
+	"sync"
 
 	"github.com/go-playground/validator/v10"
 	"github.com/gofiber/fiber/v2"
@@ -14,6 +15,9 @@ import (
 	"github.com/gofiber/fiber/v2/middleware/logger"
 
 	db "github.com/nibir1/go-fiber-postgres-REST-boilerplate/db/sqlc"
+	"github.com/nibir1/go-fiber-postgres-REST-boilerplate/embedding"
+	"github.com/nibir1/go-fiber-postgres-REST-boilerplate/extractor"
+	"github.com/nibir1/go-fiber-postgres-REST-boilerplate/storage"
 	"github.com/nibir1/go-fiber-postgres-REST-boilerplate/token"
 	"github.com/nibir1/go-fiber-postgres-REST-boilerplate/util"
 
@@ -27,14 +31,28 @@ import (
 
 // Server serves all HTTP routes for the EduSphere backend.
 type Server struct {
-	config       util.Config
-	store        db.Store
-	tokenMaker   token.Maker
-	app          *fiber.App
-	validate     *validator.Validate
-
-	uploadsDir   string
-	summariesDir string
+	config     util.Config
+	store      db.Store
+	tokenMaker token.Maker
+	app        *fiber.App
+	validate   *validator.Validate
+
+	// blobs stores uploaded transcripts and generated summary PDFs
+	blobs storage.BlobStore
+
+	// transcriptJobWake nudges idle ingestion workers after an upload
+	transcriptJobWake chan struct{}
+
+	// textExtractors turns PDFs into text, tried in configured order;
+	// ocrExtractor reads image transcripts
+	textExtractors *extractor.Chain
+	ocrExtractor   extractor.TextExtractor
+
+	// embedder turns courses and preferences into vectors; courseIndex
+	// caches the course vectors loaded from the database
+	embedder      embedding.Embedder
+	courseIndexMu sync.Mutex
+	courseIndex   *embedding.Index
 }
 
 // NewServer creates and configures a new Fiber web server.
@@ -44,7 +62,10 @@ func NewServer(config util.Config, store db.Store) (*Server, error) {
 		return nil, fmt.Errorf("cannot create token maker: %w", err)
 	}
 
-	app := fiber.New(fiber.Config{})
+	app := fiber.New(fiber.Config{
+		BodyLimit:    bodyLimit(config),
+		ErrorHandler: fiberErrorHandler(config),
+	})
 
 	// --- Global Middleware ---
 	app.Use(logger.New())
@@ -55,29 +76,44 @@ func NewServer(config util.Config, store db.Store) (*Server, error) {
 	}
 	app.Use(cors.New(cors.Config{
 		AllowOrigins:     allowedOrigins,
-		AllowMethods:     "GET,POST,PUT,DELETE,OPTIONS",
+		AllowMethods:     "GET,POST,PUT,PATCH,DELETE,OPTIONS",
 		AllowHeaders:     "Origin, Content-Type, Accept, Authorization",
 		ExposeHeaders:    "Content-Length, Content-Type",
 		AllowCredentials: true,
 	}))
 
+	textExtractors, ocrExtractor, err := newTextExtractors(config)
+	if err != nil {
+		return nil, fmt.Errorf("cannot create text extractors: %w", err)
+	}
+
+	blobs, err := newBlobStore(config)
+	if err != nil {
+		return nil, fmt.Errorf("cannot create blob store: %w", err)
+	}
+
+	embedder, err := NewEmbedder(config)
+	if err != nil {
+		return nil, fmt.Errorf("cannot create embedder: %w", err)
+	}
+
 	validate := validator.New()
 	validate.RegisterValidation("currency", validCurrency)
 
 	server := &Server{
-		config:       config,
-		store:        store,
-		tokenMaker:   tokenMaker,
-		app:          app,
-		validate:     validate,
-		uploadsDir:   "./uploads",
-		summariesDir: "./summaries",
+		config:     config,
+		store:      store,
+		tokenMaker: tokenMaker,
+		app:        app,
+		validate:   validate,
+		blobs:      blobs,
+
+		transcriptJobWake: make(chan struct{}, 1),
+		textExtractors:    textExtractors,
+		ocrExtractor:      ocrExtractor,
+		embedder:          embedder,
 	}
 
-	// Ensure upload and summary directories exist
-	_ = os.MkdirAll(server.uploadsDir, 0o755)
-	_ = os.MkdirAll(server.summariesDir, 0o755)
-
 	// Register all API routes
 	server.setUpRoutes()
 	return server, nil
@@ -101,12 +137,32 @@ func (server *Server) setUpRoutes() {
 	// --- PROTECTED ROUTES (Require Authorization) ---
 	auth := api.Group("/", authMiddlewareFiber(server.tokenMaker))
 
+	// --- User Settings ---
+	auth.Put("/users/me/settings", server.updateUserSettings)
+
 	// ====== EDU-SPHERE CORE FEATURES ======
 
 	// --- Transcript Management ---
 	auth.Post("/transcripts/upload", server.uploadTranscript)
 	auth.Get("/transcripts", server.listTranscripts)
 	auth.Get("/transcripts/:id", server.getTranscript)
+	auth.Get("/transcripts/:id/status", server.getTranscriptStatus)
+	auth.Get("/transcripts/:id/stats", server.getTranscriptStats)
+	auth.Get("/transcripts/:id/diff/:other_id", server.diffTranscripts)
+	auth.Get("/transcripts/:id/text", server.getTranscriptText)
+	auth.Patch("/transcripts/:id/text", server.updateTranscriptText)
+	auth.Put("/transcripts/:id/file", server.replaceTranscriptFile)
+	auth.Delete("/transcripts/:id", server.deleteTranscript)
+
+	// --- Course Catalogue (create/update/delete are admin-only) ---
+	adminOnly := adminMiddlewareFiber(server.store)
+	auth.Get("/courses", server.listCourses)
+	auth.Get("/courses/search", server.searchCourses) // before /courses/:code
+	auth.Get("/courses/:code", server.getCourse)
+	auth.Get("/courses/:code/eligibility", server.getCourseEligibility)
+	auth.Post("/courses", adminOnly, server.createCourse)
+	auth.Put("/courses/:code", adminOnly, server.updateCourse)
+	auth.Delete("/courses/:code", adminOnly, server.deleteCourse)
 
 	// --- Recommendations ---
 	// Create (Smart Filtered Recommendation)
@@ -115,12 +171,22 @@ func (server *Server) setUpRoutes() {
 	// List & Get (History)
 	auth.Get("/recommendations", server.listRecommendations)
 	auth.Get("/recommendations/:id", server.getRecommendation)
+	auth.Get("/recommendations/:id/provenance", server.getRecommendationProvenance) // Model, prompt, tokens, latency
 	
 	// ⭐ VITAL FIX: Register the DELETE route for removing a recommended course
 	auth.Delete("/recommendations/:reco_id/courses/:course_id", server.deleteCourseFromRecommendation)
+	// Like, dislike, already-taken or not-interested; steers later recommendations
+	auth.Post("/recommendations/:id/courses/:course_id/feedback", server.createCourseFeedback)
 
 	// REMOVED: auth.Post("/recommendations/generate", ...) because we merged it into createRecommendation
 
+	// --- Study Plans (export via POST /summaries with study_plan_id) ---
+	auth.Post("/study-plans", server.createStudyPlan)
+	auth.Get("/study-plans", server.listStudyPlans)
+	auth.Get("/study-plans/:id", server.getStudyPlan)
+	auth.Put("/study-plans/:id", server.updateStudyPlan)
+	auth.Delete("/study-plans/:id", server.deleteStudyPlan)
+
 	// --- Scholarships (AI + Web Search) ---
 	auth.Post("/scholarships/generate", server.generateScholarships)
 
@@ -140,7 +206,7 @@ func (server *Server) setUpRoutes() {
 	auth.Post("/chat/stream", server.chatStream)
 }
 
-// Start launches the Fiber HTTP server and warms up the OpenAI model.
+// Start launches the Fiber HTTP server, the transcript workers and warms up the OpenAI model.
 func (s *Server) Start(address string) error {
 	// Warm up OpenAI model to reduce first-request latency
 	go func() {
@@ -160,11 +226,38 @@ func (s *Server) Start(address string) error {
 		}
 	}()
 
+	// Store the parsed prerequisites of courses that have none yet
+	go s.syncCoursePrerequisites(context.Background())
+
+	// Start background transcript ingestion (resumes jobs left over from a restart)
+	s.startTranscriptWorkers(context.Background())
+
 	// Start Fiber server
 	return s.app.Listen(address)
 }
 
-// errorResponse provides a consistent JSON error payload.
+// errorResponse provides a consistent JSON error payload. Errors carrying a
+// machine-readable code (see codedError) also report it as "code".
 func errorResponse(err error) fiber.Map {
+	var ce *codedError
+	if errors.As(err, &ce) {
+		return fiber.Map{"error": err.Error(), "code": ce.Code}
+	}
 	return fiber.Map{"error": err.Error()}
+}
+
+// codedError is an error the client can tell apart by its code, e.g. to show a
+// translated message to the student
+type codedError struct {
+	Code string
+	Err  error
+}
+
+func (e *codedError) Error() string { return e.Err.Error() }
+
+func (e *codedError) Unwrap() error { return e.Err }
+
+// withCode attaches a machine-readable code to err.
+func withCode(code string, err error) error {
+	return &codedError{Code: code, Err: err}
 }
\ No newline at end of file
@@ -0,0 +1,557 @@
+// server/api/study_plans.go
+
+package api
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"strings"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	db "github.com/nibir1/go-fiber-postgres-REST-boilerplate/db/sqlc"
+	"github.com/nibir1/go-fiber-postgres-REST-boilerplate/grading"
+	"github.com/nibir1/go-fiber-postgres-REST-boilerplate/prereq"
+	"github.com/nibir1/go-fiber-postgres-REST-boilerplate/studyplan"
+	"github.com/nibir1/go-fiber-postgres-REST-boilerplate/token"
+)
+
+// Study plan defaults and limits
+const (
+	defaultStudyPlanSemesters = 4
+	maxStudyPlanSemesters     = 8
+	defaultSemesterCredits    = 30
+	maxSemesterCredits        = 60
+	maxStudyPlanTitleLen      = 200
+	// Size assumed for catalogue courses without credits; JYU courses are mostly 5 ECTS
+	defaultCourseCredits = 5
+)
+
+// createStudyPlanRequest lists the courses to plan, by code and/or the
+// courses of a saved recommendation, and the planning constraints.
+type createStudyPlanRequest struct {
+	Title            string   `json:"title"`
+	TranscriptID     int64    `json:"transcript_id"`
+	RecommendationID int64    `json:"recommendation_id"`
+	CourseCodes      []string `json:"course_codes"`
+	Semesters        int      `json:"semesters"`
+	MaxCredits       float64  `json:"max_credits_per_semester"`
+	Language         string   `json:"language"`
+	Start            string   `json:"start"`
+}
+
+// updateStudyPlanRequest renames a plan and/or rearranges its semesters;
+// omitted fields are kept.
+type updateStudyPlanRequest struct {
+	Title     *string            `json:"title"`
+	Semesters []studyplan.Layout `json:"semesters"`
+}
+
+type studyPlanResponse struct {
+	ID           int64          `json:"id"`
+	Title        string         `json:"title"`
+	TranscriptID *int64         `json:"transcript_id"`
+	Plan         studyplan.Plan `json:"plan"`
+	CreatedAt    time.Time      `json:"created_at"`
+	UpdatedAt    time.Time      `json:"updated_at"`
+}
+
+func newStudyPlanResponse(row db.StudyPlan) (studyPlanResponse, error) {
+	resp := studyPlanResponse{
+		ID:        row.ID,
+		Title:     row.Title,
+		CreatedAt: row.CreatedAt,
+		UpdatedAt: row.UpdatedAt,
+	}
+	if row.TranscriptID.Valid {
+		resp.TranscriptID = &row.TranscriptID.Int64
+	}
+	if err := json.Unmarshal(row.Plan, &resp.Plan); err != nil {
+		return studyPlanResponse{}, fmt.Errorf("failed to parse study plan %d: %w", row.ID, err)
+	}
+	return resp, nil
+}
+
+// options validates the planning constraints and fills in defaults.
+func (r createStudyPlanRequest) options(now time.Time) (studyplan.Options, error) {
+	opts := studyplan.Options{
+		Start:      studyplan.NextTerm(now),
+		Semesters:  r.Semesters,
+		MaxCredits: r.MaxCredits,
+		Language:   strings.TrimSpace(r.Language),
+	}
+	if strings.TrimSpace(r.Start) != "" {
+		start, err := studyplan.ParseTerm(r.Start)
+		if err != nil {
+			return opts, err
+		}
+		opts.Start = start
+	}
+	switch {
+	case opts.Semesters == 0:
+		opts.Semesters = defaultStudyPlanSemesters
+	case opts.Semesters < 0 || opts.Semesters > maxStudyPlanSemesters:
+		return opts, fmt.Errorf("semesters must be between 1 and %d", maxStudyPlanSemesters)
+	}
+	switch {
+	case opts.MaxCredits == 0:
+		opts.MaxCredits = defaultSemesterCredits
+	case opts.MaxCredits < 0 || opts.MaxCredits > maxSemesterCredits:
+		return opts, fmt.Errorf("max_credits_per_semester must be between 1 and %d", maxSemesterCredits)
+	}
+	return opts, nil
+}
+
+// plannerCatalogue converts catalogue rows and their prerequisites for the
+// planner; courses without credits count as defaultCourseCredits.
+func plannerCatalogue(courses []db.Course, prereqs map[int64]prereq.Prerequisites) []studyplan.Course {
+	out := make([]studyplan.Course, 0, len(courses))
+	for _, c := range courses {
+		credits := float64(defaultCourseCredits)
+		if c.Credits.Valid {
+			credits = c.Credits.Float64
+		}
+		out = append(out, studyplan.Course{
+			ID:            c.ID,
+			Code:          c.Code,
+			Name:          c.Name,
+			Language:      c.Language.String,
+			Credits:       credits,
+			Prerequisites: prereqs[c.ID],
+		})
+	}
+	return out
+}
+
+// recommendedCourseCodes returns the course codes of a saved recommendation.
+func recommendedCourseCodes(reco db.Recommendation) ([]string, error) {
+	payload, err := decodeRecommendationPayload(reco.Payload)
+	if err != nil {
+		return nil, err
+	}
+	codes := make([]string, 0, len(payload.Courses))
+	for _, c := range payload.Courses {
+		if c.Code != "" {
+			codes = append(codes, c.Code)
+		}
+	}
+	return codes, nil
+}
+
+// getOwnedStudyPlan loads a study plan of the user; on failure it also
+// returns the HTTP status.
+func (s *Server) getOwnedStudyPlan(ctx context.Context, username string, id int64) (db.StudyPlan, int, error) {
+	row, err := s.store.GetStudyPlan(ctx, id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return db.StudyPlan{}, fiber.StatusNotFound, fmt.Errorf("study plan not found")
+		}
+		return db.StudyPlan{}, fiber.StatusInternalServerError, err
+	}
+	if row.UserUsername != username {
+		return db.StudyPlan{}, fiber.StatusForbidden, fmt.Errorf("forbidden")
+	}
+	return row, fiber.StatusOK, nil
+}
+
+// -----------------------------------------------------------------------------
+// HANDLERS
+// -----------------------------------------------------------------------------
+
+// POST /api/study-plans
+// Builds a semester-by-semester plan for the requested courses from the
+// catalogue and the passed courses of a transcript (default: the latest),
+// adding missing prerequisites, and saves it.
+func (s *Server) createStudyPlan(c *fiber.Ctx) error {
+	// 0) Auth
+	payload, ok := c.Locals(authorizationPayloadKey).(*token.Payload)
+	if !ok || payload == nil {
+		return c.Status(fiber.StatusUnauthorized).JSON(errorResponse(fmt.Errorf("unauthorized")))
+	}
+
+	// 1) Parse + validate
+	var req createStudyPlanRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(errorResponse(err))
+	}
+	opts, err := req.options(time.Now())
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(errorResponse(err))
+	}
+	title := strings.TrimSpace(req.Title)
+	if title == "" {
+		title = fmt.Sprintf("Study plan from %s", opts.Start)
+	}
+	if len(title) > maxStudyPlanTitleLen {
+		return c.Status(fiber.StatusBadRequest).JSON(errorResponse(fmt.Errorf("title is longer than %d characters", maxStudyPlanTitleLen)))
+	}
+
+	// 2) Courses to plan
+	targets := append([]string(nil), req.CourseCodes...)
+	if req.RecommendationID != 0 {
+		reco, err := s.store.GetRecommendation(c.Context(), req.RecommendationID)
+		if err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				return c.Status(fiber.StatusNotFound).JSON(errorResponse(fmt.Errorf("recommendation not found")))
+			}
+			return c.Status(fiber.StatusInternalServerError).JSON(errorResponse(err))
+		}
+		if reco.UserUsername != payload.Username {
+			return c.Status(fiber.StatusForbidden).JSON(errorResponse(fmt.Errorf("forbidden")))
+		}
+		codes, err := recommendedCourseCodes(reco)
+		if err != nil {
+			return c.Status(fiber.StatusInternalServerError).JSON(errorResponse(err))
+		}
+		targets = append(targets, codes...)
+	}
+	if len(targets) == 0 {
+		return c.Status(fiber.StatusBadRequest).JSON(errorResponse(fmt.Errorf("course_codes or recommendation_id is required")))
+	}
+
+	// 3) Completed courses
+	tr, status, err := s.selectTranscript(c.Context(), payload.Username, req.TranscriptID)
+	if err != nil {
+		return c.Status(status).JSON(errorResponse(err))
+	}
+	completed, err := s.completedCourseSet(c.Context(), tr)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(errorResponse(err))
+	}
+
+	// 4) Plan
+	courses, err := s.store.ListAllCourses(c.Context())
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(errorResponse(err))
+	}
+	prereqs, err := s.prerequisitesByCourse(c.Context(), courses)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(errorResponse(err))
+	}
+	plan := studyplan.Build(plannerCatalogue(courses, prereqs), completed, targets, opts)
+	planJSON, err := json.Marshal(plan)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(errorResponse(err))
+	}
+
+	// 5) Save
+	arg := db.CreateStudyPlanParams{
+		UserUsername: payload.Username,
+		Title:        title,
+		Plan:         planJSON,
+	}
+	if tr != nil {
+		arg.TranscriptID = sql.NullInt64{Int64: tr.ID, Valid: true}
+	}
+	row, err := s.store.CreateStudyPlan(c.Context(), arg)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(errorResponse(err))
+	}
+	resp, err := newStudyPlanResponse(row)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(errorResponse(err))
+	}
+	return c.Status(fiber.StatusCreated).JSON(resp)
+}
+
+// GET /api/study-plans
+func (s *Server) listStudyPlans(c *fiber.Ctx) error {
+	payload, ok := c.Locals(authorizationPayloadKey).(*token.Payload)
+	if !ok || payload == nil {
+		return c.Status(fiber.StatusUnauthorized).JSON(errorResponse(fmt.Errorf("unauthorized")))
+	}
+
+	rows, err := s.store.ListStudyPlans(c.Context(), payload.Username)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(errorResponse(err))
+	}
+	plans := make([]studyPlanResponse, 0, len(rows))
+	for _, row := range rows {
+		resp, err := newStudyPlanResponse(row)
+		if err != nil {
+			return c.Status(fiber.StatusInternalServerError).JSON(errorResponse(err))
+		}
+		plans = append(plans, resp)
+	}
+	return c.JSON(plans)
+}
+
+// GET /api/study-plans/:id
+func (s *Server) getStudyPlan(c *fiber.Ctx) error {
+	payload, ok := c.Locals(authorizationPayloadKey).(*token.Payload)
+	if !ok || payload == nil {
+		return c.Status(fiber.StatusUnauthorized).JSON(errorResponse(fmt.Errorf("unauthorized")))
+	}
+	id, err := parseIDParam(c, "id")
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(errorResponse(err))
+	}
+
+	row, status, err := s.getOwnedStudyPlan(c.Context(), payload.Username, id)
+	if err != nil {
+		return c.Status(status).JSON(errorResponse(err))
+	}
+	resp, err := newStudyPlanResponse(row)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(errorResponse(err))
+	}
+	return c.JSON(resp)
+}
+
+// PUT /api/study-plans/:id
+// Body: {"title": "...", "semesters": [{"term": "Autumn 2026", "codes": ["TIES4211", ...]}, ...]}
+// The semesters replace the plan's layout. The plan is re-checked against the
+// prerequisites and the credit cap; problems are returned as warnings, not
+// rejected.
+func (s *Server) updateStudyPlan(c *fiber.Ctx) error {
+	// 0) Auth
+	payload, ok := c.Locals(authorizationPayloadKey).(*token.Payload)
+	if !ok || payload == nil {
+		return c.Status(fiber.StatusUnauthorized).JSON(errorResponse(fmt.Errorf("unauthorized")))
+	}
+
+	// 1) Parse
+	id, err := parseIDParam(c, "id")
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(errorResponse(err))
+	}
+	var req updateStudyPlanRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(errorResponse(err))
+	}
+	if req.Semesters != nil && (len(req.Semesters) == 0 || len(req.Semesters) > maxStudyPlanSemesters) {
+		return c.Status(fiber.StatusBadRequest).JSON(errorResponse(fmt.Errorf("semesters must list between 1 and %d semesters", maxStudyPlanSemesters)))
+	}
+
+	// 2) Fetch + ownership check
+	row, status, err := s.getOwnedStudyPlan(c.Context(), payload.Username, id)
+	if err != nil {
+		return c.Status(status).JSON(errorResponse(err))
+	}
+	current, err := newStudyPlanResponse(row)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(errorResponse(err))
+	}
+
+	// 3) Apply
+	title := row.Title
+	if req.Title != nil {
+		title = strings.TrimSpace(*req.Title)
+		if title == "" || len(title) > maxStudyPlanTitleLen {
+			return c.Status(fiber.StatusBadRequest).JSON(errorResponse(fmt.Errorf("title must be 1 to %d characters", maxStudyPlanTitleLen)))
+		}
+	}
+	plan := current.Plan
+	if req.Semesters != nil {
+		// The plan's transcript may have been deleted since; check against none then
+		var tr *db.Transcript
+		if row.TranscriptID.Valid {
+			if t, err := s.store.GetTranscript(c.Context(), row.TranscriptID.Int64); err == nil {
+				tr = &t
+			} else if !errors.Is(err, sql.ErrNoRows) {
+				return c.Status(fiber.StatusInternalServerError).JSON(errorResponse(err))
+			}
+		}
+		completed, err := s.completedCourseSet(c.Context(), tr)
+		if err != nil {
+			return c.Status(fiber.StatusInternalServerError).JSON(errorResponse(err))
+		}
+		courses, err := s.store.ListAllCourses(c.Context())
+		if err != nil {
+			return c.Status(fiber.StatusInternalServerError).JSON(errorResponse(err))
+		}
+		prereqs, err := s.prerequisitesByCourse(c.Context(), courses)
+		if err != nil {
+			return c.Status(fiber.StatusInternalServerError).JSON(errorResponse(err))
+		}
+		if plan, err = plan.Rearrange(plannerCatalogue(courses, prereqs), completed, req.Semesters); err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(errorResponse(err))
+		}
+	}
+	planJSON, err := json.Marshal(plan)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(errorResponse(err))
+	}
+
+	// 4) Save
+	updated, err := s.store.UpdateStudyPlan(c.Context(), db.UpdateStudyPlanParams{
+		ID:    row.ID,
+		Title: title,
+		Plan:  planJSON,
+	})
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(errorResponse(err))
+	}
+	resp, err := newStudyPlanResponse(updated)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(errorResponse(err))
+	}
+	return c.JSON(resp)
+}
+
+// DELETE /api/study-plans/:id
+// Also deletes the summary PDFs exported from the plan.
+func (s *Server) deleteStudyPlan(c *fiber.Ctx) error {
+	payload, ok := c.Locals(authorizationPayloadKey).(*token.Payload)
+	if !ok || payload == nil {
+		return c.Status(fiber.StatusUnauthorized).JSON(errorResponse(fmt.Errorf("unauthorized")))
+	}
+	id, err := parseIDParam(c, "id")
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(errorResponse(err))
+	}
+
+	row, status, err := s.getOwnedStudyPlan(c.Context(), payload.Username, id)
+	if err != nil {
+		return c.Status(status).JSON(errorResponse(err))
+	}
+
+	// Summary rows go with the plan (ON DELETE CASCADE); their files do not
+	keys, err := s.store.ListStudyPlanSummaryKeys(c.Context(), sql.NullInt64{Int64: row.ID, Valid: true})
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(errorResponse(err))
+	}
+	if err := s.store.DeleteStudyPlan(c.Context(), db.DeleteStudyPlanParams{
+		ID:           row.ID,
+		UserUsername: payload.Username,
+	}); err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(errorResponse(err))
+	}
+	for _, key := range keys {
+		if key.Valid {
+			s.removeBlob(c.Context(), key.String)
+		}
+	}
+	return c.SendStatus(fiber.StatusNoContent)
+}
+
+// -----------------------------------------------------------------------------
+// PDF EXPORT (POST /api/summaries with study_plan_id)
+// -----------------------------------------------------------------------------
+
+// createStudyPlanSummaryPDF renders a study plan through the summary PDF
+// pipeline: the PDF is stored and listed, downloaded and deleted like any
+// other summary.
+func (s *Server) createStudyPlanSummaryPDF(c *fiber.Ctx, username string, req createSummaryReq) error {
+	row, status, err := s.getOwnedStudyPlan(c.Context(), username, req.StudyPlanID)
+	if err != nil {
+		return c.Status(status).JSON(errorResponse(err))
+	}
+	plan, err := newStudyPlanResponse(row)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(errorResponse(err))
+	}
+
+	summaryText := strings.TrimSpace(req.SummaryText)
+	if summaryText == "" {
+		summaryText = s.latestSummaryText(c.Context(), username)
+	}
+
+	var stats *grading.Stats
+	if row.TranscriptID.Valid {
+		if tr, err := s.store.GetTranscript(c.Context(), row.TranscriptID.Int64); err == nil {
+			if st, err := s.transcriptStats(c.Context(), tr); err == nil {
+				stats = &st
+			} else {
+				log.Printf("[WARN] Could not compute stats for transcript %d: %v", tr.ID, err)
+			}
+		}
+	}
+
+	var buf bytes.Buffer
+	if err := writeStudyPlanPDF(&buf, row.Title, plan.Plan, summaryText, stats, username); err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(errorResponse(fmt.Errorf("failed to create PDF: %v", err)))
+	}
+	key := summaryKey(username, fmt.Sprintf("study_plan_%d_%d.pdf", row.ID, time.Now().Unix()))
+	sum, err := s.storeSummaryPDF(c.Context(), &buf, db.CreateSummaryParams{
+		UserUsername: username,
+		SummaryText:  sqlNullString(summaryText),
+		PdfKey:       sqlNullString(key),
+		StudyPlanID:  sqlNullInt64(row.ID),
+	})
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(errorResponse(err))
+	}
+
+	log.Printf("[INFO] Study plan PDF created for user %s: %s", username, key)
+
+	return c.Status(fiber.StatusCreated).JSON(fiber.Map{
+		"id":            sum.ID,
+		"user":          username,
+		"study_plan_id": row.ID,
+		"pdf_key":       sum.PdfKey.String,
+		"created_at":    sum.CreatedAt,
+	})
+}
+
+// writeStudyPlanPDF renders a study plan semester by semester, followed by
+// the courses that could not be scheduled and any warnings.
+func writeStudyPlanPDF(w io.Writer, title string, plan studyplan.Plan, summaryText string, stats *grading.Stats, username string) error {
+	pdf := newSummaryPDF("EduSphere Study Plan", username)
+	writePDFStanding(pdf, stats)
+	writePDFSummaryText(pdf, summaryText)
+
+	// --- Plan ---
+	pdf.SetFont("Helvetica", "B", 14)
+	pdf.Cell(0, 8, cleanText(title))
+	pdf.Ln(8)
+	pdf.SetFont("Helvetica", "", 11)
+	settings := fmt.Sprintf("%d semesters from %s, at most %g credits per semester, %g credits in total",
+		plan.Settings.Semesters, plan.Settings.Start, plan.Settings.MaxCredits, plan.TotalCredits)
+	if plan.Settings.Language != "" {
+		settings += fmt.Sprintf(", taught in %s", plan.Settings.Language)
+	}
+	pdf.MultiCell(0, 6, settings, "", "", false)
+	pdf.Ln(4)
+
+	for _, sem := range plan.Semesters {
+		pdf.SetFont("Helvetica", "B", 12)
+		pdf.Cell(0, 7, fmt.Sprintf("%s (%g cr)", sem.Term, sem.Credits))
+		pdf.Ln(7)
+		pdf.SetFont("Helvetica", "", 11)
+		if len(sem.Courses) == 0 {
+			pdf.Cell(0, 6, "No courses planned.")
+			pdf.Ln(6)
+		}
+		for _, course := range sem.Courses {
+			line := fmt.Sprintf("- %s %s, %g cr", course.Code, cleanText(course.Name), course.Credits)
+			if len(course.RequiredBy) > 0 {
+				line += " (prerequisite for " + strings.Join(course.RequiredBy, ", ") + ")"
+			}
+			pdf.MultiCell(0, 6, line, "", "", false)
+		}
+		pdf.Ln(4)
+	}
+
+	if len(plan.Unscheduled) > 0 {
+		pdf.SetFont("Helvetica", "B", 12)
+		pdf.Cell(0, 7, "Not scheduled")
+		pdf.Ln(7)
+		pdf.SetFont("Helvetica", "", 11)
+		for _, u := range plan.Unscheduled {
+			pdf.MultiCell(0, 6, fmt.Sprintf("- %s %s: %s", u.Code, cleanText(u.Name), u.Reason), "", "", false)
+		}
+		pdf.Ln(4)
+	}
+
+	if len(plan.Warnings) > 0 {
+		pdf.SetFont("Helvetica", "B", 12)
+		pdf.Cell(0, 7, "Warnings")
+		pdf.Ln(7)
+		pdf.SetFont("Helvetica", "", 11)
+		for _, warning := range plan.Warnings {
+			pdf.MultiCell(0, 6, "- "+warning, "", "", false)
+		}
+	}
+
+	if err := pdf.Output(w); err != nil {
+		return fmt.Errorf("failed to write PDF: %v", err)
+	}
+	return nil
+}
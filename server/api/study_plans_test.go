@@ -0,0 +1,201 @@
+// server/api/study_plans_test.go
+
+package api
+
+import (
+	"bytes"
+	"database/sql"
+	"encoding/json"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/golang/mock/gomock"
+	mockdb "github.com/nibir1/go-fiber-postgres-REST-boilerplate/db/mock"
+	db "github.com/nibir1/go-fiber-postgres-REST-boilerplate/db/sqlc"
+	"github.com/nibir1/go-fiber-postgres-REST-boilerplate/studyplan"
+	"github.com/nibir1/go-fiber-postgres-REST-boilerplate/util"
+	"github.com/stretchr/testify/require"
+)
+
+func studyPlanTestCatalogue() []db.Course {
+	intro := newTestCatalogueCourse("TIEA311", "Introduction to Graphics")
+	intro.ID = 1
+	graphics := newTestCatalogueCourse("TIES471", "Real-time Graphics")
+	graphics.ID = 2
+	graphics.Prerequisites = sql.NullString{String: "TIEA311", Valid: true}
+	return []db.Course{intro, graphics}
+}
+
+func TestStudyPlansAPI(t *testing.T) {
+	username := util.RandomOwner()
+	tr := newTestTranscript(t, username)
+	catalogue := studyPlanTestCatalogue()
+
+	opts := studyplan.Options{Start: studyplan.Term{Season: studyplan.Autumn, Year: 2026}, Semesters: 2, MaxCredits: 30}
+	plan := studyplan.Build(plannerCatalogue(catalogue, testPrerequisites(catalogue...)), nil, []string{"TIES471"}, opts)
+	planJSON, err := json.Marshal(plan)
+	require.NoError(t, err)
+	saved := db.StudyPlan{
+		ID:           7,
+		UserUsername: username,
+		TranscriptID: sql.NullInt64{Int64: tr.ID, Valid: true},
+		Title:        "Graphics",
+		Plan:         planJSON,
+		CreatedAt:    time.Now(),
+		UpdatedAt:    time.Now(),
+	}
+
+	testCases := []struct {
+		name          string
+		method        string
+		url           string
+		body          fiber.Map
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(t *testing.T, resp *http.Response)
+	}{
+		{
+			name:   "CreateOK",
+			method: http.MethodPost,
+			url:    "/api/study-plans",
+			body:   fiber.Map{"title": "Graphics", "course_codes": []string{"ties471"}, "start": "Autumn 2026", "semesters": 2},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().ListTranscripts(gomock.Any(), gomock.Eq(username)).Times(1).
+					Return([]db.ListTranscriptsRow{{ID: tr.ID, UserUsername: username}}, nil)
+				store.EXPECT().GetTranscript(gomock.Any(), gomock.Eq(tr.ID)).Times(1).Return(tr, nil)
+				store.EXPECT().ListTranscriptCourses(gomock.Any(), gomock.Eq(tr.ID)).Times(1).
+					Return([]db.TranscriptCourse{testCourse("TIEA311", 5, "4", courseStatusCompleted)}, nil)
+				store.EXPECT().ListAllCourses(gomock.Any()).Times(1).Return(catalogue, nil)
+				store.EXPECT().ListCoursePrerequisites(gomock.Any()).Times(1).
+					Return(newTestCoursePrerequisites(t, catalogue...), nil)
+				store.EXPECT().CreateStudyPlan(gomock.Any(), gomock.Any()).Times(1).
+					DoAndReturn(func(_ any, arg db.CreateStudyPlanParams) (db.StudyPlan, error) {
+						require.Equal(t, "Graphics", arg.Title)
+						require.Equal(t, tr.ID, arg.TranscriptID.Int64)
+						return db.StudyPlan{ID: 7, UserUsername: username, TranscriptID: arg.TranscriptID, Title: arg.Title, Plan: arg.Plan}, nil
+					})
+			},
+			checkResponse: func(t *testing.T, resp *http.Response) {
+				require.Equal(t, http.StatusCreated, resp.StatusCode)
+
+				var body studyPlanResponse
+				require.NoError(t, json.NewDecoder(resp.Body).Decode(&body))
+				require.Len(t, body.Plan.Semesters, 2)
+				require.Equal(t, "TIES471", body.Plan.Semesters[0].Courses[0].Code)
+				require.Empty(t, body.Plan.Semesters[1].Courses)
+				require.Equal(t, float64(defaultCourseCredits), body.Plan.TotalCredits)
+			},
+		},
+		{
+			name:   "CreateWithoutCourses",
+			method: http.MethodPost,
+			url:    "/api/study-plans",
+			body:   fiber.Map{"title": "Empty"},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().CreateStudyPlan(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, resp *http.Response) {
+				require.Equal(t, http.StatusBadRequest, resp.StatusCode)
+			},
+		},
+		{
+			name:   "CreateInvalidStart",
+			method: http.MethodPost,
+			url:    "/api/study-plans",
+			body:   fiber.Map{"course_codes": []string{"TIES471"}, "start": "winter 2026"},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().CreateStudyPlan(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, resp *http.Response) {
+				require.Equal(t, http.StatusBadRequest, resp.StatusCode)
+			},
+		},
+		{
+			name:   "UpdateWarnsAboutPrerequisites",
+			method: http.MethodPut,
+			url:    "/api/study-plans/7",
+			body: fiber.Map{"semesters": []fiber.Map{
+				{"term": "Autumn 2026", "codes": []string{"TIES471"}},
+				{"term": "Spring 2027", "codes": []string{"TIEA311"}},
+			}},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetStudyPlan(gomock.Any(), gomock.Eq(int64(7))).Times(1).Return(saved, nil)
+				store.EXPECT().GetTranscript(gomock.Any(), gomock.Eq(tr.ID)).Times(1).Return(db.Transcript{}, sql.ErrNoRows)
+				store.EXPECT().ListAllCourses(gomock.Any()).Times(1).Return(catalogue, nil)
+				store.EXPECT().ListCoursePrerequisites(gomock.Any()).Times(1).
+					Return(newTestCoursePrerequisites(t, catalogue...), nil)
+				store.EXPECT().UpdateStudyPlan(gomock.Any(), gomock.Any()).Times(1).
+					DoAndReturn(func(_ any, arg db.UpdateStudyPlanParams) (db.StudyPlan, error) {
+						row := saved
+						row.Plan = arg.Plan
+						return row, nil
+					})
+			},
+			checkResponse: func(t *testing.T, resp *http.Response) {
+				require.Equal(t, http.StatusOK, resp.StatusCode)
+
+				var body studyPlanResponse
+				require.NoError(t, json.NewDecoder(resp.Body).Decode(&body))
+				require.Equal(t, []string{"TIES471 (Autumn 2026) is planned before its prerequisites: TIEA311"}, body.Plan.Warnings)
+			},
+		},
+		{
+			name:   "UpdateOtherUsersPlan",
+			method: http.MethodPut,
+			url:    "/api/study-plans/7",
+			body:   fiber.Map{"title": "Mine now"},
+			buildStubs: func(store *mockdb.MockStore) {
+				other := saved
+				other.UserUsername = "someone_else"
+				store.EXPECT().GetStudyPlan(gomock.Any(), gomock.Eq(int64(7))).Times(1).Return(other, nil)
+				store.EXPECT().UpdateStudyPlan(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, resp *http.Response) {
+				require.Equal(t, http.StatusForbidden, resp.StatusCode)
+			},
+		},
+		{
+			name:   "DeleteRemovesSummaries",
+			method: http.MethodDelete,
+			url:    "/api/study-plans/7",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetStudyPlan(gomock.Any(), gomock.Eq(int64(7))).Times(1).Return(saved, nil)
+				store.EXPECT().ListStudyPlanSummaryKeys(gomock.Any(), gomock.Eq(sql.NullInt64{Int64: 7, Valid: true})).Times(1).
+					Return([]sql.NullString{{String: "summaries/" + username + "/study_plan_7_1.pdf", Valid: true}}, nil)
+				store.EXPECT().DeleteStudyPlan(gomock.Any(), gomock.Eq(db.DeleteStudyPlanParams{ID: 7, UserUsername: username})).Times(1).Return(nil)
+			},
+			checkResponse: func(t *testing.T, resp *http.Response) {
+				require.Equal(t, http.StatusNoContent, resp.StatusCode)
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStubs(store)
+
+			server := newFiberTestServer(t, store)
+			var body *bytes.Reader
+			if tc.body != nil {
+				data, err := json.Marshal(tc.body)
+				require.NoError(t, err)
+				body = bytes.NewReader(data)
+			} else {
+				body = bytes.NewReader(nil)
+			}
+			req, err := http.NewRequest(tc.method, tc.url, body)
+			require.NoError(t, err)
+			req.Header.Set("Content-Type", "application/json")
+			addAuthorization(t, req, server.tokenMaker, authorizationTypeBearer, username, time.Minute)
+
+			resp, err := server.app.Test(req, -1)
+			require.NoError(t, err)
+			tc.checkResponse(t, resp)
+		})
+	}
+}
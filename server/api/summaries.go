@@ -3,13 +3,15 @@
 package api
 
 import (
+	"bytes"
+	"context"
 	"database/sql"
-	"encoding/json"
+	"errors"
 	"fmt"
 	"html"
+	"io"
 	"log"
-	"os"
-	"path/filepath"
+	"path"
 	"regexp"
 	"strings"
 	"time"
@@ -17,16 +19,20 @@ import (
 	"github.com/gofiber/fiber/v2"
 	"github.com/jung-kurt/gofpdf"
 	db "github.com/nibir1/go-fiber-postgres-REST-boilerplate/db/sqlc"
+	"github.com/nibir1/go-fiber-postgres-REST-boilerplate/grading"
+	"github.com/nibir1/go-fiber-postgres-REST-boilerplate/storage"
 	"github.com/nibir1/go-fiber-postgres-REST-boilerplate/token"
 )
 
 type createSummaryReq struct {
 	RecommendationID    int64  `json:"recommendation_id"`
+	StudyPlanID         int64  `json:"study_plan_id"`
 	SummaryText         string `json:"summary_text"`
 	IncludeScholarships bool   `json:"include_scholarships"`
 }
 
 // POST /api/summaries
+// Exports a recommendation, or with study_plan_id a study plan, as a PDF.
 func (s *Server) createSummaryPDF(c *fiber.Ctx) error {
 	payload, ok := c.Locals(authorizationPayloadKey).(*token.Payload)
 	if !ok || payload == nil {
@@ -37,6 +43,9 @@ func (s *Server) createSummaryPDF(c *fiber.Ctx) error {
 	if err := c.BodyParser(&req); err != nil {
 		return c.Status(fiber.StatusBadRequest).JSON(errorResponse(err))
 	}
+	if req.StudyPlanID != 0 {
+		return s.createStudyPlanSummaryPDF(c, payload.Username, req)
+	}
 
 	// Validate recommendation ownership
 	reco, err := s.store.GetRecommendation(c.Context(), req.RecommendationID)
@@ -60,43 +69,75 @@ func (s *Server) createSummaryPDF(c *fiber.Ctx) error {
 	// 🔹 If no summary text provided, fallback to latest AI-generated summary (if exists)
 	summaryText := strings.TrimSpace(req.SummaryText)
 	if summaryText == "" {
-		// Try to find last generated summary for the same user
-		prevSummaries, _ := s.store.ListSummaries(c.Context(), payload.Username)
-		if len(prevSummaries) > 0 && prevSummaries[0].SummaryText.Valid {
-			summaryText = prevSummaries[0].SummaryText.String
+		summaryText = s.latestSummaryText(c.Context(), payload.Username)
+	}
+
+	// 🔹 Academic standing of the transcript the recommendation was made for
+	var stats *grading.Stats
+	if reco.TranscriptID.Valid {
+		if tr, err := s.store.GetTranscript(c.Context(), reco.TranscriptID.Int64); err == nil {
+			if st, err := s.transcriptStats(c.Context(), tr); err == nil {
+				stats = &st
+			} else {
+				log.Printf("[WARN] Could not compute stats for transcript %d: %v", tr.ID, err)
+			}
 		}
 	}
 
-	// 🔹 Generate PDF
+	// 🔹 Generate PDF and store it
 	filename := fmt.Sprintf("summary_%d_%d.pdf", req.RecommendationID, time.Now().Unix())
-	outPath := filepath.Join(s.summariesDir, filename)
+	key := summaryKey(payload.Username, filename)
 
-	if err := writeRecoPDF(outPath, reco, summaryText, scholarships, payload.Username); err != nil {
+	var buf bytes.Buffer
+	if err := writeRecoPDF(&buf, reco, summaryText, scholarships, stats, payload.Username); err != nil {
 		return c.Status(fiber.StatusInternalServerError).JSON(errorResponse(fmt.Errorf("failed to create PDF: %v", err)))
 	}
 
-	// 🔹 Save summary record in DB
-	row, err := s.store.CreateSummary(c.Context(), db.CreateSummaryParams{
+	// 🔹 Store the PDF and save the summary record in DB
+	row, err := s.storeSummaryPDF(c.Context(), &buf, db.CreateSummaryParams{
 		UserUsername:     payload.Username,
 		RecommendationID: sqlNullInt64(req.RecommendationID),
 		SummaryText:      sqlNullString(summaryText),
-		PdfPath:          sqlNullString(outPath),
+		PdfKey:           sqlNullString(key),
 	})
 	if err != nil {
-		_ = os.Remove(outPath)
-		return c.Status(fiber.StatusInternalServerError).JSON(errorResponse(fmt.Errorf("failed to save summary: %v", err)))
+		return c.Status(fiber.StatusInternalServerError).JSON(errorResponse(err))
 	}
 
-	log.Printf("[INFO] Summary PDF created for user %s: %s", payload.Username, outPath)
+	log.Printf("[INFO] Summary PDF created for user %s: %s", payload.Username, key)
 
 	return c.Status(fiber.StatusCreated).JSON(fiber.Map{
 		"id":         row.ID,
 		"user":       payload.Username,
-		"pdf_path":   row.PdfPath.String,
+		"pdf_key":    row.PdfKey.String,
 		"created_at": row.CreatedAt,
 	})
 }
 
+// latestSummaryText returns the text of the user's most recent summary, if any.
+func (s *Server) latestSummaryText(ctx context.Context, username string) string {
+	prevSummaries, _ := s.store.ListSummaries(ctx, username)
+	if len(prevSummaries) > 0 && prevSummaries[0].SummaryText.Valid {
+		return prevSummaries[0].SummaryText.String
+	}
+	return ""
+}
+
+// storeSummaryPDF uploads a rendered PDF under arg.PdfKey and records the
+// summary; the file is removed again if the record cannot be saved.
+func (s *Server) storeSummaryPDF(ctx context.Context, pdf *bytes.Buffer, arg db.CreateSummaryParams) (db.Summary, error) {
+	key := arg.PdfKey.String
+	if err := s.blobs.Put(ctx, key, pdf, int64(pdf.Len()), "application/pdf"); err != nil {
+		return db.Summary{}, fmt.Errorf("failed to store PDF: %v", err)
+	}
+	row, err := s.store.CreateSummary(ctx, arg)
+	if err != nil {
+		s.removeBlob(ctx, key)
+		return db.Summary{}, fmt.Errorf("failed to save summary: %v", err)
+	}
+	return row, nil
+}
+
 // GET /api/summaries
 func (s *Server) listSummaries(c *fiber.Ctx) error {
 	payload, ok := c.Locals(authorizationPayloadKey).(*token.Payload)
@@ -110,7 +151,9 @@ func (s *Server) listSummaries(c *fiber.Ctx) error {
 	return c.JSON(items)
 }
 
-// GET /api/summaries/:id/download
+// GET /api/summaries/:id/download[?presign=true]
+// Streams the PDF. With presign=true and an S3 backend it instead returns a
+// temporary URL the client downloads from the bucket directly.
 func (s *Server) downloadSummaryPDF(c *fiber.Ctx) error {
 	payload, ok := c.Locals(authorizationPayloadKey).(*token.Payload)
 	if !ok || payload == nil {
@@ -134,19 +177,41 @@ func (s *Server) downloadSummaryPDF(c *fiber.Ctx) error {
 		return c.Status(fiber.StatusForbidden).JSON(errorResponse(fmt.Errorf("forbidden")))
 	}
 
-	// ✅ Check that pdf_path exists and is valid
-	if !sum.PdfPath.Valid || strings.TrimSpace(sum.PdfPath.String) == "" {
-		return c.Status(fiber.StatusNotFound).JSON(errorResponse(fmt.Errorf("summary file path missing in database")))
+	// ✅ Check that pdf_key exists and is valid
+	if !sum.PdfKey.Valid || strings.TrimSpace(sum.PdfKey.String) == "" {
+		return c.Status(fiber.StatusNotFound).JSON(errorResponse(fmt.Errorf("summary file key missing in database")))
 	}
+	key := sum.PdfKey.String
 
-	// ✅ Check file existence before serving
-	if _, err := os.Stat(sum.PdfPath.String); os.IsNotExist(err) {
-		log.Printf("[WARN] Missing PDF file for summary ID %d at path %s", id, sum.PdfPath.String)
-		return c.Status(fiber.StatusNotFound).JSON(errorResponse(fmt.Errorf("summary file not found on server")))
+	// ✅ Presigned URL, when the store supports it
+	if c.QueryBool("presign") {
+		presigner, ok := s.blobs.(storage.Presigner)
+		if !ok {
+			return c.Status(fiber.StatusBadRequest).JSON(errorResponse(fmt.Errorf("presigned downloads are not supported by the %s storage backend", s.config.StorageBackend)))
+		}
+		expiry := s.presignExpiry()
+		url, err := presigner.PresignGet(c.Context(), key, expiry)
+		if err != nil {
+			return c.Status(fiber.StatusInternalServerError).JSON(errorResponse(err))
+		}
+		return c.JSON(fiber.Map{
+			"url":        url,
+			"expires_at": time.Now().Add(expiry).UTC(),
+		})
 	}
 
-	// ✅ Serve file if it exists
-	return c.Download(sum.PdfPath.String)
+	// ✅ Stream the file
+	rc, err := s.blobs.Get(c.Context(), key)
+	if err != nil {
+		if errors.Is(err, storage.ErrNotFound) {
+			log.Printf("[WARN] Missing PDF file for summary ID %d at key %s", id, key)
+			return c.Status(fiber.StatusNotFound).JSON(errorResponse(fmt.Errorf("summary file not found on server")))
+		}
+		return c.Status(fiber.StatusInternalServerError).JSON(errorResponse(err))
+	}
+	c.Set(fiber.HeaderContentType, "application/pdf")
+	c.Set(fiber.HeaderContentDisposition, fmt.Sprintf(`attachment; filename="%s"`, path.Base(key)))
+	return c.SendStream(rc)
 }
 
 // DELETE /api/summaries/:id
@@ -173,17 +238,9 @@ func (s *Server) deleteSummary(c *fiber.Ctx) error {
 		return c.Status(fiber.StatusForbidden).JSON(errorResponse(fmt.Errorf("forbidden")))
 	}
 
-	// ✅ Delete PDF file from disk if exists
-	if sum.PdfPath.Valid && strings.TrimSpace(sum.PdfPath.String) != "" {
-		if err := os.Remove(sum.PdfPath.String); err != nil {
-			if os.IsNotExist(err) {
-				log.Printf("[WARN] Tried to delete missing PDF for summary ID %d: %s", id, sum.PdfPath.String)
-			} else {
-				log.Printf("[WARN] Failed to delete PDF for summary ID %d: %v", id, err)
-			}
-		} else {
-			log.Printf("[INFO] Deleted PDF file for summary ID %d: %s", id, sum.PdfPath.String)
-		}
+	// ✅ Delete PDF file if exists
+	if sum.PdfKey.Valid {
+		s.removeBlob(c.Context(), sum.PdfKey.String)
 	}
 
 	// ✅ Delete DB record
@@ -199,33 +256,12 @@ func (s *Server) deleteSummary(c *fiber.Ctx) error {
 }
 
 // ---- PDF Generation ----
-// writeRecoPDF generates a professional PDF report including summary, courses, and scholarships.
-func writeRecoPDF(path string, reco db.Recommendation, summaryText string, scholarships []db.Scholarship, username string) error {
-	pdf := gofpdf.New("P", "mm", "A4", "")
-	pdf.AddPage()
-
-	// --- Header ---
-	pdf.SetFont("Helvetica", "B", 18)
-	pdf.Cell(0, 10, "EduSphere Academic Summary Report")
-	pdf.Ln(12)
-
-	pdf.SetFont("Helvetica", "", 11)
-	pdf.Cell(0, 6, fmt.Sprintf("Generated for: %s", username))
-	pdf.Ln(6)
-	pdf.Cell(0, 6, fmt.Sprintf("Date: %s", time.Now().Format("January 2, 2006, 15:04")))
-	pdf.Ln(10)
-
-	// --- Summary Section ---
-	summaryText = strings.TrimSpace(cleanText(summaryText))
-	if summaryText != "" {
-		pdf.SetFont("Helvetica", "B", 14)
-		pdf.Cell(0, 8, "Transcript Summary")
-		pdf.Ln(8)
-
-		pdf.SetFont("Helvetica", "", 11)
-		pdf.MultiCell(0, 6, summaryText, "", "", false)
-		pdf.Ln(8)
-	}
+// writeRecoPDF generates a professional PDF report including academic standing, summary, courses, and scholarships.
+// stats may be nil when the recommendation is not linked to a transcript.
+func writeRecoPDF(w io.Writer, reco db.Recommendation, summaryText string, scholarships []db.Scholarship, stats *grading.Stats, username string) error {
+	pdf := newSummaryPDF("EduSphere Academic Summary Report", username)
+	writePDFStanding(pdf, stats)
+	writePDFSummaryText(pdf, summaryText)
 
 	// --- Recommended Courses ---
 	pdf.SetFont("Helvetica", "B", 14)
@@ -233,22 +269,10 @@ func writeRecoPDF(path string, reco db.Recommendation, summaryText string, schol
 	pdf.Ln(10)
 	pdf.SetFont("Helvetica", "", 11)
 
-	// VITAL FIX: Changed JSON tag from "rationale" back to "description"
-	// to match the field name saved by the ai_recommendations.go handler.
-	type RecoCourse struct {
-		Type        string  `json:"type"`
-		Title       string  `json:"title"`
-		Description string  `json:"description"` // <--- FIXED: The JSON key in the DB is "description"
-		Match       float64 `json:"match"`
-		Code        string  `json:"code"`
-		Link        string  `json:"link"`
-		CourseID    int64   `json:"course_id"`
-	}
-
-	var payload struct {
-		Courses []RecoCourse `json:"courses"`
+	payload, err := decodeRecommendationPayload(reco.Payload)
+	if err != nil {
+		log.Printf("[WARN] Failed to read recommendation %d for PDF: %v", reco.ID, err)
 	}
-	_ = json.Unmarshal(reco.Payload, &payload)
 
 	if len(payload.Courses) == 0 {
 		pdf.MultiCell(0, 6, "No recommended courses available for this record.", "", "", false)
@@ -328,12 +352,68 @@ func writeRecoPDF(path string, reco db.Recommendation, summaryText string, schol
 	}
 
 	// --- Save PDF ---
-	if err := pdf.OutputFileAndClose(filepath.Clean(path)); err != nil {
+	if err := pdf.Output(w); err != nil {
 		return fmt.Errorf("failed to write PDF: %v", err)
 	}
 	return nil
 }
 
+// newSummaryPDF starts an A4 report with the EduSphere header.
+func newSummaryPDF(title, username string) *gofpdf.Fpdf {
+	pdf := gofpdf.New("P", "mm", "A4", "")
+	pdf.AddPage()
+
+	// --- Header ---
+	pdf.SetFont("Helvetica", "B", 18)
+	pdf.Cell(0, 10, title)
+	pdf.Ln(12)
+
+	pdf.SetFont("Helvetica", "", 11)
+	pdf.Cell(0, 6, fmt.Sprintf("Generated for: %s", username))
+	pdf.Ln(6)
+	pdf.Cell(0, 6, fmt.Sprintf("Date: %s", time.Now().Format("January 2, 2006, 15:04")))
+	pdf.Ln(10)
+	return pdf
+}
+
+// writePDFStanding adds the Academic Standing section; stats may be nil.
+func writePDFStanding(pdf *gofpdf.Fpdf, stats *grading.Stats) {
+	if stats == nil {
+		return
+	}
+	pdf.SetFont("Helvetica", "B", 14)
+	pdf.Cell(0, 8, "Academic Standing")
+	pdf.Ln(8)
+
+	pdf.SetFont("Helvetica", "", 11)
+	if stats.GradedCredits > 0 {
+		pdf.Cell(0, 6, fmt.Sprintf("Weighted GPA: %.2f / 5 (US equivalent %.2f / 4.0)", stats.GPA, stats.GPAUS))
+	} else {
+		pdf.Cell(0, 6, "Weighted GPA: n/a (no numerically graded courses)")
+	}
+	pdf.Ln(6)
+	pdf.Cell(0, 6, fmt.Sprintf("Total credits: %g ECTS (%g graded, %g pass/fail)",
+		stats.TotalCredits, stats.GradedCredits, stats.PassFailCredits))
+	pdf.Ln(6)
+	pdf.Cell(0, 6, fmt.Sprintf("Courses passed: %d, failed: %d", stats.PassedCourses, stats.FailedCourses))
+	pdf.Ln(10)
+}
+
+// writePDFSummaryText adds the Transcript Summary section when there is text.
+func writePDFSummaryText(pdf *gofpdf.Fpdf, summaryText string) {
+	summaryText = strings.TrimSpace(cleanText(summaryText))
+	if summaryText == "" {
+		return
+	}
+	pdf.SetFont("Helvetica", "B", 14)
+	pdf.Cell(0, 8, "Transcript Summary")
+	pdf.Ln(8)
+
+	pdf.SetFont("Helvetica", "", 11)
+	pdf.MultiCell(0, 6, summaryText, "", "", false)
+	pdf.Ln(8)
+}
+
 // cleanText removes HTML, escaped characters, and non-printable symbols.
 func cleanText(s string) string {
 	s = html.UnescapeString(s)                          // convert entities like &amp;
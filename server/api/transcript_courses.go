@@ -0,0 +1,357 @@
+// server/api/transcript_courses.go
+
+package api
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"log"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	db "github.com/nibir1/go-fiber-postgres-REST-boilerplate/db/sqlc"
+	"github.com/nibir1/go-fiber-postgres-REST-boilerplate/extractor"
+)
+
+// Course statuses stored in transcript_courses.status
+const (
+	courseStatusCompleted  = "completed"
+	courseStatusFailed     = "failed"
+	courseStatusInProgress = "in_progress"
+)
+
+// parsedCourse is one course row recovered from a transcript, before it is persisted.
+type parsedCourse struct {
+	Code    string    `json:"code"`
+	Name    string    `json:"name"`
+	Credits float64   `json:"credits"`
+	Grade   string    `json:"grade"`
+	Date    time.Time `json:"-"`
+	Status  string    `json:"status"`
+}
+
+var (
+	// JYU/Sisu course codes, e.g. TJTS5012, TIES454, ITKA203, MATA101A
+	courseCodePattern = regexp.MustCompile(`^\s*([A-ZÅÄÖ]{2,6}[0-9]{3,5}[A-Z]?)\s+(.+)$`)
+	// 12.05.2023, 1.9.2022 or 2023-05-12
+	courseDatePattern = regexp.MustCompile(`(\d{1,2})\.(\d{1,2})\.(\d{4})|(\d{4})-(\d{2})-(\d{2})`)
+	// "5 cr", "5,0 op", "3 ECTS", "10 credits"
+	courseCreditsPattern = regexp.MustCompile(`(?i)(?:^|\s)(\d{1,2}(?:[.,]\d{1,2})?)\s*(?:cr|op|ects|credits|opintopistettä|ov)(?:\s|$)`)
+	// "Name 5 4" when the credit unit is missing
+	courseTrailingPattern = regexp.MustCompile(`^(.*\S)\s+(\d{1,2}(?:[.,]\d{1,2})?)\s+(\S+)$`)
+	// Grades used on JYU transcripts plus the common ECTS / US variants
+	courseGradePattern = regexp.MustCompile(`(?i)^(?:[0-5]|[0-4][.,]\d{1,2}|[A-F][+-]?|hyv(?:äksytty)?\.?|hyl(?:ätty)?\.?|pass(?:ed)?|fail(?:ed)?|approved|rejected|completed)$`)
+	courseFailPattern  = regexp.MustCompile(`(?i)^(?:0|f|hyl(?:ätty)?\.?|fail(?:ed)?|rejected)$`)
+)
+
+// -----------------------------------------------------------------------------
+// SISU / JYU LAYOUT PARSER
+// -----------------------------------------------------------------------------
+
+// parseSisuTranscript reads the common JYU/Sisu "transcript of records" layout,
+// where every course sits on its own line:
+//
+//	TJTS5012 Additional Research Methods Module 5 cr 4 12.05.2023
+//	ITKA203  Käyttöjärjestelmät                 5 op hyv. 14.12.2021
+//
+// Lines that do not start with a course code are ignored.
+func parseSisuTranscript(text string) []parsedCourse {
+	byCode := make(map[string]parsedCourse)
+	var order []string
+
+	for _, line := range strings.Split(text, "\n") {
+		course, ok := parseSisuLine(line)
+		if !ok {
+			continue
+		}
+
+		prev, seen := byCode[course.Code]
+		if !seen {
+			order = append(order, course.Code)
+			byCode[course.Code] = course
+			continue
+		}
+		// Retakes: keep the completed attempt, then the latest one
+		if preferCourseAttempt(course, prev) {
+			byCode[course.Code] = course
+		}
+	}
+
+	courses := make([]parsedCourse, 0, len(order))
+	for _, code := range order {
+		courses = append(courses, byCode[code])
+	}
+	return courses
+}
+
+// parseSisuLine parses a single transcript line into a course record.
+func parseSisuLine(line string) (parsedCourse, bool) {
+	line = strings.Join(strings.Fields(line), " ")
+	m := courseCodePattern.FindStringSubmatch(line)
+	if m == nil {
+		return parsedCourse{}, false
+	}
+
+	course := parsedCourse{Code: strings.ToUpper(m[1])}
+	rest := m[2]
+
+	// 1) Completion date
+	if dm := courseDatePattern.FindStringSubmatchIndex(rest); dm != nil {
+		course.Date = parseTranscriptDate(rest[dm[0]:dm[1]])
+		rest = strings.TrimSpace(rest[:dm[0]] + " " + rest[dm[1]:])
+	}
+
+	// 2) Credits with a unit; the grade follows the credits
+	if cm := courseCreditsPattern.FindStringSubmatchIndex(rest); cm != nil {
+		course.Credits = parseDecimal(rest[cm[2]:cm[3]])
+		course.Name = strings.TrimSpace(rest[:cm[0]])
+		for _, tok := range strings.Fields(rest[cm[1]:]) {
+			if courseGradePattern.MatchString(tok) {
+				course.Grade = tok
+				break
+			}
+		}
+	} else if tm := courseTrailingPattern.FindStringSubmatch(rest); tm != nil && courseGradePattern.MatchString(tm[3]) {
+		course.Name = tm[1]
+		course.Credits = parseDecimal(tm[2])
+		course.Grade = tm[3]
+	} else {
+		course.Name = rest
+	}
+
+	course.Name = strings.Trim(course.Name, " -|,;")
+	if course.Name == "" {
+		return parsedCourse{}, false
+	}
+
+	course.Status = courseStatus(course.Grade, course.Date)
+	return course, true
+}
+
+// courseStatus derives the status of a course attempt from its grade and date.
+func courseStatus(grade string, date time.Time) string {
+	grade = strings.TrimSpace(grade)
+	switch {
+	case grade != "" && courseFailPattern.MatchString(grade):
+		return courseStatusFailed
+	case grade == "" && date.IsZero():
+		return courseStatusInProgress
+	default:
+		return courseStatusCompleted
+	}
+}
+
+// preferCourseAttempt reports whether attempt a should replace attempt b.
+func preferCourseAttempt(a, b parsedCourse) bool {
+	if (a.Status == courseStatusCompleted) != (b.Status == courseStatusCompleted) {
+		return a.Status == courseStatusCompleted
+	}
+	return a.Date.After(b.Date)
+}
+
+func parseTranscriptDate(s string) time.Time {
+	for _, layout := range []string{"2.1.2006", "02.01.2006", "2006-01-02"} {
+		if t, err := time.Parse(layout, s); err == nil {
+			return t
+		}
+	}
+	return time.Time{}
+}
+
+func parseDecimal(s string) float64 {
+	f, err := strconv.ParseFloat(strings.ReplaceAll(strings.TrimSpace(s), ",", "."), 64)
+	if err != nil {
+		return 0
+	}
+	return f
+}
+
+// -----------------------------------------------------------------------------
+// AI FALLBACK
+// -----------------------------------------------------------------------------
+
+// extractTranscriptCoursesAI asks OpenAI for the course table when the layout
+// parser does not recognise the transcript. The owner's personal data is
+// masked; course rows carry none, so nothing is restored.
+func (s *Server) extractTranscriptCoursesAI(ctx context.Context, username, transcriptText, language string) ([]parsedCourse, error) {
+	messages := []aiMessage{
+		{
+			Role: "system",
+			Content: `You are a data extraction assistant. Analyze the academic transcript and return a JSON object with a single key "courses" containing an array.
+Each item must have:
+	- "code" (string, course code e.g. "TJTS5012")
+	- "name" (string)
+	- "credits" (number, ECTS credits, 0 if unknown)
+	- "grade" (string, exactly as printed, "" if none)
+	- "date" (string, completion date as YYYY-MM-DD, "" if none)
+	- "status" ("completed", "failed" or "in_progress")` + transcriptLanguageNote(language),
+		},
+		{
+			Role:    "user",
+			Content: transcriptText,
+		},
+	}
+
+	raw, err := s.callOpenAIChatRedacted(ctx, s.newRedactor(ctx, username), messages, true)
+	if err != nil {
+		return nil, err
+	}
+
+	var result struct {
+		Courses []struct {
+			Code    string  `json:"code"`
+			Name    string  `json:"name"`
+			Credits float64 `json:"credits"`
+			Grade   string  `json:"grade"`
+			Date    string  `json:"date"`
+			Status  string  `json:"status"`
+		} `json:"courses"`
+	}
+	if err := json.Unmarshal([]byte(raw), &result); err != nil {
+		return nil, fmt.Errorf("invalid course extraction response: %w", err)
+	}
+
+	courses := make([]parsedCourse, 0, len(result.Courses))
+	for _, c := range result.Courses {
+		code := strings.ToUpper(strings.TrimSpace(c.Code))
+		if code == "" {
+			continue
+		}
+		course := parsedCourse{
+			Code:    code,
+			Name:    strings.TrimSpace(c.Name),
+			Credits: c.Credits,
+			Grade:   strings.TrimSpace(c.Grade),
+			Date:    parseTranscriptDate(strings.TrimSpace(c.Date)),
+			Status:  strings.ToLower(strings.TrimSpace(c.Status)),
+		}
+		switch course.Status {
+		case courseStatusCompleted, courseStatusFailed, courseStatusInProgress:
+		default:
+			course.Status = courseStatus(course.Grade, course.Date)
+		}
+		courses = append(courses, course)
+	}
+	return courses, nil
+}
+
+// -----------------------------------------------------------------------------
+// PERSISTENCE
+// -----------------------------------------------------------------------------
+
+// parseTranscriptCourses runs the layout parser and falls back to OpenAI when
+// nothing was recognised. It returns the courses and the name of the parser used.
+// language is the Tesseract code detected at ingestion ("" if unknown);
+// username is the transcript owner, whose personal data the AI fallback masks.
+func (s *Server) parseTranscriptCourses(ctx context.Context, username, text, language string) ([]parsedCourse, string) {
+	if strings.TrimSpace(text) == "" {
+		return nil, "none"
+	}
+
+	if courses := parseSisuTranscript(text); len(courses) > 0 {
+		return courses, "sisu"
+	}
+
+	if s.config.OpenAIAPIKey == "" {
+		return nil, "none"
+	}
+	courses, err := s.extractTranscriptCoursesAI(ctx, username, text, language)
+	if err != nil {
+		log.Printf("[TRANSCRIPT] AI course extraction failed: %v", err)
+		return nil, "none"
+	}
+	return courses, "ai"
+}
+
+// saveTranscriptCourses replaces the stored course rows of a transcript.
+func (s *Server) saveTranscriptCourses(ctx context.Context, transcriptID int64, courses []parsedCourse) ([]db.TranscriptCourse, error) {
+	params := make([]db.CreateTranscriptCourseParams, 0, len(courses))
+	for _, c := range courses {
+		params = append(params, db.CreateTranscriptCourseParams{
+			TranscriptID: transcriptID,
+			Code:         c.Code,
+			Name:         sqlStringOrNull(c.Name),
+			Credits:      sql.NullFloat64{Float64: c.Credits, Valid: c.Credits > 0},
+			Grade:        sqlStringOrNull(c.Grade),
+			CompletedOn:  sql.NullTime{Time: c.Date, Valid: !c.Date.IsZero()},
+			Status:       c.Status,
+		})
+	}
+
+	return s.store.ReplaceTranscriptCoursesTx(ctx, db.ReplaceTranscriptCoursesTxParams{
+		TranscriptID: transcriptID,
+		Courses:      params,
+	})
+}
+
+// transcriptCourses returns the structured course rows of a transcript. It
+// never parses: the ingestion workers fill the rows, including parse-only
+// jobs for transcripts uploaded before parsing existed.
+func (s *Server) transcriptCourses(ctx context.Context, tr db.Transcript) ([]db.TranscriptCourse, error) {
+	return s.store.ListTranscriptCourses(ctx, tr.ID)
+}
+
+// transcriptMeta decodes the transcripts.meta JSONB column.
+func transcriptMeta(raw []byte) map[string]any {
+	meta := map[string]any{}
+	if len(raw) > 0 {
+		_ = json.Unmarshal(raw, &meta)
+	}
+	return meta
+}
+
+// transcriptLanguage returns the language code detected at ingestion, or ""
+// for transcripts ingested before detection existed.
+func transcriptLanguage(tr db.Transcript) string {
+	language, _ := transcriptMeta(tr.Meta)["language"].(string)
+	return language
+}
+
+// transcriptLanguageNote tells the model which language the transcript is
+// written in, so Finnish or Swedish course names and grades are read correctly.
+func transcriptLanguageNote(language string) string {
+	if language == "" {
+		return ""
+	}
+	name := extractor.LanguageName(language)
+	return fmt.Sprintf("\nThe transcript is written in %s; course names and grade words may be in %s. Answer in English.", name, name)
+}
+
+// completedCourseCodes returns the upper-cased codes of all passed courses.
+func completedCourseCodes(rows []db.TranscriptCourse) []string {
+	codes := make([]string, 0, len(rows))
+	for _, r := range rows {
+		if r.Status == courseStatusCompleted {
+			codes = append(codes, strings.ToUpper(strings.TrimSpace(r.Code)))
+		}
+	}
+	sort.Strings(codes)
+	return codes
+}
+
+// formatTranscriptCourses renders course rows as a compact list for AI prompts.
+func formatTranscriptCourses(rows []db.TranscriptCourse) string {
+	var sb strings.Builder
+	for _, r := range rows {
+		sb.WriteString("- " + r.Code)
+		if r.Name.Valid {
+			sb.WriteString(" " + r.Name.String)
+		}
+		if r.Credits.Valid {
+			sb.WriteString(fmt.Sprintf(" | %g cr", r.Credits.Float64))
+		}
+		if r.Grade.Valid {
+			sb.WriteString(" | grade " + r.Grade.String)
+		}
+		if r.CompletedOn.Valid {
+			sb.WriteString(" | " + r.CompletedOn.Time.Format("2006-01-02"))
+		}
+		sb.WriteString(" | " + r.Status + "\n")
+	}
+	return sb.String()
+}
@@ -0,0 +1,83 @@
+// server/api/transcript_courses_test.go
+
+package api
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	mockdb "github.com/nibir1/go-fiber-postgres-REST-boilerplate/db/mock"
+	db "github.com/nibir1/go-fiber-postgres-REST-boilerplate/db/sqlc"
+	"github.com/nibir1/go-fiber-postgres-REST-boilerplate/util"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseSisuTranscript(t *testing.T) {
+	text := `University of Jyväskylä
+Transcript of Records
+Code      Name                                   Credits  Grade  Date
+TJTS5012  Additional Research Methods Module     5 cr     4      12.05.2023
+ITKA203   Käyttöjärjestelmät                     5 op     hyv.   14.12.2021
+TIES4211  Algorithms 2                           5,0 op   0      01.03.2022
+TIES4211  Algorithms 2                           5,0 op   3      20.05.2022
+TIES454   Agent Technologies for Developers 5 2
+MATA101   Calculus 1
+Total 15 cr`
+
+	courses := parseSisuTranscript(text)
+	require.Len(t, courses, 5)
+
+	require.Equal(t, "TJTS5012", courses[0].Code)
+	require.Equal(t, "Additional Research Methods Module", courses[0].Name)
+	require.Equal(t, 5.0, courses[0].Credits)
+	require.Equal(t, "4", courses[0].Grade)
+	require.Equal(t, time.Date(2023, 5, 12, 0, 0, 0, 0, time.UTC), courses[0].Date)
+	require.Equal(t, courseStatusCompleted, courses[0].Status)
+
+	require.Equal(t, "Käyttöjärjestelmät", courses[1].Name)
+	require.Equal(t, "hyv.", courses[1].Grade)
+	require.Equal(t, courseStatusCompleted, courses[1].Status)
+
+	// The passed retake wins over the failed attempt
+	require.Equal(t, "TIES4211", courses[2].Code)
+	require.Equal(t, "3", courses[2].Grade)
+	require.Equal(t, courseStatusCompleted, courses[2].Status)
+
+	require.Equal(t, "Agent Technologies for Developers", courses[3].Name)
+	require.Equal(t, 5.0, courses[3].Credits)
+	require.Equal(t, "2", courses[3].Grade)
+
+	require.Equal(t, "Calculus 1", courses[4].Name)
+	require.Equal(t, courseStatusInProgress, courses[4].Status)
+}
+
+func TestCourseStatus(t *testing.T) {
+	date := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	require.Equal(t, courseStatusFailed, courseStatus("0", date))
+	require.Equal(t, courseStatusFailed, courseStatus("hyl.", date))
+	require.Equal(t, courseStatusFailed, courseStatus("Fail", date))
+	require.Equal(t, courseStatusCompleted, courseStatus("5", date))
+	require.Equal(t, courseStatusCompleted, courseStatus("Pass", time.Time{}))
+	require.Equal(t, courseStatusCompleted, courseStatus("", date))
+	require.Equal(t, courseStatusInProgress, courseStatus("", time.Time{}))
+}
+
+func TestTranscriptCoursesDoesNotParse(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	// Unparsed transcript: rows are left to the ingestion workers
+	tr := newTestTranscript(t, util.RandomOwner())
+	store := mockdb.NewMockStore(ctrl)
+	store.EXPECT().ListTranscriptCourses(gomock.Any(), gomock.Eq(tr.ID)).Times(1).Return([]db.TranscriptCourse{}, nil)
+	store.EXPECT().ReplaceTranscriptCoursesTx(gomock.Any(), gomock.Any()).Times(0)
+	store.EXPECT().UpdateTranscriptMeta(gomock.Any(), gomock.Any()).Times(0)
+
+	server := newFiberTestServer(t, store)
+	rows, err := server.transcriptCourses(context.Background(), tr)
+	require.NoError(t, err)
+	require.Empty(t, rows)
+}
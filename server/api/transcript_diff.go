@@ -0,0 +1,215 @@
+// server/api/transcript_diff.go
+
+package api
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+	db "github.com/nibir1/go-fiber-postgres-REST-boilerplate/db/sqlc"
+	"github.com/nibir1/go-fiber-postgres-REST-boilerplate/token"
+)
+
+// diffCourse is a course that appears in the newer transcript
+type diffCourse struct {
+	Code    string  `json:"code"`
+	Name    string  `json:"name,omitempty"`
+	Credits float64 `json:"credits"`
+	Grade   string  `json:"grade,omitempty"`
+}
+
+// gradeChange is a course whose grade differs between the two transcripts
+type gradeChange struct {
+	Code      string `json:"code"`
+	Name      string `json:"name,omitempty"`
+	FromGrade string `json:"from_grade"`
+	ToGrade   string `json:"to_grade"`
+}
+
+// transcriptDiff is the progress between an older and a newer transcript
+type transcriptDiff struct {
+	FromTranscriptID int64         `json:"from_transcript_id"`
+	ToTranscriptID   int64         `json:"to_transcript_id"`
+	NewlyCompleted   []diffCourse  `json:"newly_completed"`
+	GradeChanges     []gradeChange `json:"grade_changes"`
+	CreditsBefore    float64       `json:"credits_before"`
+	CreditsAfter     float64       `json:"credits_after"`
+	CreditsGained    float64       `json:"credits_gained"`
+}
+
+// diffTranscriptCourses compares the course rows of an older and a newer transcript.
+func diffTranscriptCourses(older, newer []db.TranscriptCourse) transcriptDiff {
+	diff := transcriptDiff{
+		NewlyCompleted: []diffCourse{},
+		GradeChanges:   []gradeChange{},
+	}
+
+	before := make(map[string]db.TranscriptCourse, len(older))
+	for _, r := range older {
+		before[strings.ToUpper(strings.TrimSpace(r.Code))] = r
+		if r.Status == courseStatusCompleted {
+			diff.CreditsBefore += r.Credits.Float64
+		}
+	}
+
+	for _, r := range newer {
+		code := strings.ToUpper(strings.TrimSpace(r.Code))
+		if r.Status == courseStatusCompleted {
+			diff.CreditsAfter += r.Credits.Float64
+		}
+
+		prev, seen := before[code]
+		if r.Status == courseStatusCompleted && (!seen || prev.Status != courseStatusCompleted) {
+			diff.NewlyCompleted = append(diff.NewlyCompleted, diffCourse{
+				Code:    code,
+				Name:    r.Name.String,
+				Credits: r.Credits.Float64,
+				Grade:   r.Grade.String,
+			})
+		}
+		if seen && prev.Grade.Valid && r.Grade.Valid && !strings.EqualFold(prev.Grade.String, r.Grade.String) {
+			diff.GradeChanges = append(diff.GradeChanges, gradeChange{
+				Code:      code,
+				Name:      r.Name.String,
+				FromGrade: prev.Grade.String,
+				ToGrade:   r.Grade.String,
+			})
+		}
+	}
+
+	sort.Slice(diff.NewlyCompleted, func(i, j int) bool { return diff.NewlyCompleted[i].Code < diff.NewlyCompleted[j].Code })
+	sort.Slice(diff.GradeChanges, func(i, j int) bool { return diff.GradeChanges[i].Code < diff.GradeChanges[j].Code })
+	diff.CreditsGained = diff.CreditsAfter - diff.CreditsBefore
+	return diff
+}
+
+// formatTranscriptDiff renders a diff as a short "progress since last upload"
+// block for AI prompts.
+func formatTranscriptDiff(diff transcriptDiff) string {
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("Credits: %g -> %g (%+g)\n", diff.CreditsBefore, diff.CreditsAfter, diff.CreditsGained))
+
+	if len(diff.NewlyCompleted) == 0 {
+		sb.WriteString("Newly completed: none\n")
+	} else {
+		sb.WriteString("Newly completed:\n")
+		for _, c := range diff.NewlyCompleted {
+			sb.WriteString(fmt.Sprintf("- %s %s | %g cr | grade %s\n", c.Code, c.Name, c.Credits, c.Grade))
+		}
+	}
+
+	if len(diff.GradeChanges) > 0 {
+		sb.WriteString("Grade changes:\n")
+		for _, g := range diff.GradeChanges {
+			sb.WriteString(fmt.Sprintf("- %s %s: %s -> %s\n", g.Code, g.Name, g.FromGrade, g.ToGrade))
+		}
+	}
+	return sb.String()
+}
+
+// progressSinceLastUpload diffs a transcript against the same user's previous
+// upload. It returns "" when there is no earlier transcript to compare with.
+func (s *Server) progressSinceLastUpload(ctx context.Context, tr db.Transcript) (string, error) {
+	transcripts, err := s.store.ListTranscripts(ctx, tr.UserUsername)
+	if err != nil {
+		return "", err
+	}
+
+	// Listed newest first: the previous upload is the first one older than tr
+	var previousID int64
+	for _, t := range transcripts {
+		if t.ID < tr.ID {
+			previousID = t.ID
+			break
+		}
+	}
+	if previousID == 0 {
+		return "", nil
+	}
+
+	previous, err := s.store.GetTranscript(ctx, previousID)
+	if err != nil {
+		return "", err
+	}
+	older, err := s.transcriptCourses(ctx, previous)
+	if err != nil {
+		return "", err
+	}
+	newer, err := s.transcriptCourses(ctx, tr)
+	if err != nil {
+		return "", err
+	}
+	if len(older) == 0 || len(newer) == 0 {
+		return "", nil
+	}
+
+	return formatTranscriptDiff(diffTranscriptCourses(older, newer)), nil
+}
+
+// -----------------------------------------------------------------------------
+// HANDLER
+// -----------------------------------------------------------------------------
+
+// GET /api/transcripts/:id/diff/:other_id
+// The older of the two transcripts (by upload order) is the baseline.
+func (s *Server) diffTranscripts(c *fiber.Ctx) error {
+	// 0) Auth
+	payload, ok := c.Locals(authorizationPayloadKey).(*token.Payload)
+	if !ok || payload == nil {
+		return c.Status(fiber.StatusUnauthorized).JSON(errorResponse(fmt.Errorf("unauthorized")))
+	}
+
+	// 1) Parse path params
+	id, err := parseIDParam(c, "id")
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(errorResponse(err))
+	}
+	otherID, err := parseIDParam(c, "other_id")
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(errorResponse(err))
+	}
+
+	// 2) Fetch both transcripts + ownership check
+	pair := make([]db.Transcript, 0, 2)
+	for _, tid := range []int64{id, otherID} {
+		tr, err := s.store.GetTranscript(c.Context(), tid)
+		if err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				return c.Status(fiber.StatusNotFound).JSON(errorResponse(fmt.Errorf("transcript %d not found", tid)))
+			}
+			return c.Status(fiber.StatusInternalServerError).JSON(errorResponse(err))
+		}
+		if tr.UserUsername != payload.Username {
+			return c.Status(fiber.StatusForbidden).JSON(errorResponse(fmt.Errorf("forbidden")))
+		}
+		if !tr.TextExtracted.Valid && s.transcriptProcessing(c.Context(), tr.ID) {
+			return c.Status(fiber.StatusConflict).JSON(errorResponse(fmt.Errorf("transcript %d is still being processed", tr.ID)))
+		}
+		pair = append(pair, tr)
+	}
+
+	older, newer := pair[0], pair[1]
+	if newer.CreatedAt.Before(older.CreatedAt) || (newer.CreatedAt.Equal(older.CreatedAt) && newer.ID < older.ID) {
+		older, newer = newer, older
+	}
+
+	// 3) Structured course rows of both
+	olderCourses, err := s.transcriptCourses(c.Context(), older)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(errorResponse(err))
+	}
+	newerCourses, err := s.transcriptCourses(c.Context(), newer)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(errorResponse(err))
+	}
+
+	diff := diffTranscriptCourses(olderCourses, newerCourses)
+	diff.FromTranscriptID = older.ID
+	diff.ToTranscriptID = newer.ID
+	return c.JSON(diff)
+}
@@ -0,0 +1,66 @@
+// server/api/transcript_diff_test.go
+
+package api
+
+import (
+	"database/sql"
+	"testing"
+
+	db "github.com/nibir1/go-fiber-postgres-REST-boilerplate/db/sqlc"
+	"github.com/stretchr/testify/require"
+)
+
+func testCourse(code string, credits float64, grade, status string) db.TranscriptCourse {
+	return db.TranscriptCourse{
+		Code:    code,
+		Name:    sql.NullString{String: code + " name", Valid: true},
+		Credits: sql.NullFloat64{Float64: credits, Valid: credits > 0},
+		Grade:   sql.NullString{String: grade, Valid: grade != ""},
+		Status:  status,
+	}
+}
+
+func TestDiffTranscriptCourses(t *testing.T) {
+	older := []db.TranscriptCourse{
+		testCourse("TJTS5012", 5, "3", courseStatusCompleted),
+		testCourse("TIES4211", 5, "0", courseStatusFailed),
+		testCourse("ITKA203", 5, "", courseStatusInProgress),
+	}
+	newer := []db.TranscriptCourse{
+		testCourse("tjts5012", 5, "4", courseStatusCompleted),
+		testCourse("TIES4211", 5, "2", courseStatusCompleted),
+		testCourse("ITKA203", 5, "hyv.", courseStatusCompleted),
+		testCourse("TIES454", 3, "5", courseStatusCompleted),
+	}
+
+	diff := diffTranscriptCourses(older, newer)
+
+	require.Len(t, diff.NewlyCompleted, 3)
+	require.Equal(t, "ITKA203", diff.NewlyCompleted[0].Code)
+	require.Equal(t, "TIES4211", diff.NewlyCompleted[1].Code)
+	require.Equal(t, "TIES454", diff.NewlyCompleted[2].Code)
+
+	// Re-graded course and the passed retake both count as grade changes
+	require.Len(t, diff.GradeChanges, 2)
+	require.Equal(t, gradeChange{Code: "TIES4211", Name: "TIES4211 name", FromGrade: "0", ToGrade: "2"}, diff.GradeChanges[0])
+	require.Equal(t, "TJTS5012", diff.GradeChanges[1].Code)
+
+	require.Equal(t, 5.0, diff.CreditsBefore)
+	require.Equal(t, 18.0, diff.CreditsAfter)
+	require.Equal(t, 13.0, diff.CreditsGained)
+
+	text := formatTranscriptDiff(diff)
+	require.Contains(t, text, "Credits: 5 -> 18 (+13)")
+	require.Contains(t, text, "- TIES454 TIES454 name | 3 cr | grade 5")
+	require.Contains(t, text, "- TJTS5012 tjts5012 name: 3 -> 4")
+}
+
+func TestDiffTranscriptCoursesNoProgress(t *testing.T) {
+	courses := []db.TranscriptCourse{testCourse("TJTS5012", 5, "3", courseStatusCompleted)}
+
+	diff := diffTranscriptCourses(courses, courses)
+	require.Empty(t, diff.NewlyCompleted)
+	require.Empty(t, diff.GradeChanges)
+	require.Zero(t, diff.CreditsGained)
+	require.Contains(t, formatTranscriptDiff(diff), "Newly completed: none")
+}
@@ -0,0 +1,522 @@
+// server/api/transcript_ingest.go
+
+package api
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"database/sql"
+	"encoding/csv"
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+	db "github.com/nibir1/go-fiber-postgres-REST-boilerplate/db/sqlc"
+	"github.com/nibir1/go-fiber-postgres-REST-boilerplate/extractor"
+	"github.com/nibir1/go-fiber-postgres-REST-boilerplate/util"
+)
+
+// Transcript file kinds recognised by content sniffing (transcripts.meta.kind)
+const (
+	transcriptKindPDF   = "pdf"
+	transcriptKindImage = "image"
+	transcriptKindDOCX  = "docx"
+	transcriptKindCSV   = "csv"
+)
+
+// Ingestion paths recorded in transcripts.meta.ingest_path
+const (
+	ingestPathPDFText  = "pdf_text"
+	ingestPathPDFOCR   = "pdf_ocr"
+	ingestPathImageOCR = "image_ocr"
+	ingestPathDOCX     = "docx"
+	ingestPathCSV      = "csv"
+)
+
+// -----------------------------------------------------------------------------
+// RECEIVING FILES
+// -----------------------------------------------------------------------------
+
+// transcriptUpload is a received transcript file with its hash and sniffed
+// type. It sits in a temporary file until storeUpload copies it to the blob store.
+type transcriptUpload struct {
+	Path        string // temporary file
+	Filename    string // client file name
+	Key         string // storage key, set by storeUpload
+	Size        int64
+	SHA256      string
+	Kind        string
+	ContentType string
+	Pages       int // PDF page count; 1 for images, 0 otherwise
+}
+
+// receiveTranscriptFile saves the multipart "file" field to a temporary file,
+// hashes it, detects its type from the content (not the extension) and
+// validates it. On error it returns the HTTP status to respond with; rejected
+// files get an upload error code and nothing is left on disk.
+// Callers must call cleanup once done with the upload.
+func (s *Server) receiveTranscriptFile(c *fiber.Ctx) (transcriptUpload, int, error) {
+	fileHeader, err := c.FormFile("file")
+	if err != nil {
+		return transcriptUpload{}, fiber.StatusBadRequest, withCode(uploadErrMissingFile, fmt.Errorf("missing file: %w", err))
+	}
+	if fileHeader.Size > uploadMaxBytes(s.config) {
+		return transcriptUpload{}, fiber.StatusRequestEntityTooLarge, errFileTooLarge(s.config)
+	}
+
+	path, sum, err := saveUploadedFile(fileHeader, uploadMaxBytes(s.config))
+	if err != nil {
+		return transcriptUpload{}, uploadErrorStatus(err), err
+	}
+	upload := transcriptUpload{Path: path, Filename: fileHeader.Filename, Size: fileHeader.Size, SHA256: sum}
+
+	upload.Kind, upload.ContentType, err = sniffTranscriptKind(path)
+	if err == nil {
+		upload.Pages, err = validateTranscriptFile(s.config, path, upload.Kind, upload.ContentType)
+	}
+	if err != nil {
+		upload.cleanup()
+		return transcriptUpload{}, uploadErrorStatus(err), err
+	}
+	return upload, 0, nil
+}
+
+// storeUpload copies the upload to the blob store under a new key of username.
+func (s *Server) storeUpload(ctx context.Context, u *transcriptUpload, username string) error {
+	f, err := os.Open(u.Path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	key := uploadKey(username, u.Filename)
+	if err := s.blobs.Put(ctx, key, f, u.Size, u.ContentType); err != nil {
+		return fmt.Errorf("failed to store file: %w", err)
+	}
+	u.Key = key
+	return nil
+}
+
+// cleanup removes the temporary file of the upload.
+func (u transcriptUpload) cleanup() {
+	if u.Path != "" {
+		_ = os.Remove(u.Path)
+	}
+}
+
+// meta returns the initial transcripts.meta of an upload; the ingestion
+// worker adds the extraction details.
+func (u transcriptUpload) meta(source string) []byte {
+	metaJSON, _ := json.Marshal(map[string]any{
+		"ocr_used":     false,
+		"source":       source,
+		"kind":         u.Kind,
+		"content_type": u.ContentType,
+		"pages":        u.Pages,
+	})
+	return metaJSON
+}
+
+// duplicateTranscriptResponse answers an upload whose content is already
+// stored. A transcript whose ingestion failed gets a new job, since the
+// re-upload is most likely a retry.
+func (s *Server) duplicateTranscriptResponse(c *fiber.Ctx, tr db.Transcript) error {
+	job, err := s.store.GetLatestTranscriptJob(c.Context(), tr.ID)
+	status := transcriptJobDone // transcripts uploaded before background ingestion
+	if err == nil {
+		status = job.Status
+	} else if !errors.Is(err, sql.ErrNoRows) {
+		return c.Status(fiber.StatusInternalServerError).JSON(errorResponse(err))
+	}
+
+	if status == transcriptJobFailed {
+		if job, err = s.store.CreateTranscriptJob(c.Context(), tr.ID); err != nil {
+			return c.Status(fiber.StatusInternalServerError).JSON(errorResponse(err))
+		}
+		status = job.Status
+		s.notifyTranscriptWorkers()
+	}
+
+	code := fiber.StatusOK
+	if status != transcriptJobDone {
+		code = fiber.StatusAccepted
+	}
+	return c.Status(code).JSON(fiber.Map{
+		"id":         tr.ID,
+		"job_id":     job.ID,
+		"status":     status,
+		"file_key":   tr.FileKey,
+		"created_at": tr.CreatedAt,
+		"duplicate":  true,
+	})
+}
+
+// -----------------------------------------------------------------------------
+// CONTENT SNIFFING
+// -----------------------------------------------------------------------------
+
+// sniffTranscriptKind looks at the file content (not the file name) to decide
+// how a transcript should be ingested. It returns the kind and the detected MIME type.
+func sniffTranscriptKind(path string) (string, string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return "", "", err
+	}
+	defer f.Close()
+
+	head := make([]byte, 512)
+	n, err := io.ReadFull(f, head)
+	if err != nil && !errors.Is(err, io.ErrUnexpectedEOF) && !errors.Is(err, io.EOF) {
+		return "", "", err
+	}
+	head = head[:n]
+	if n == 0 {
+		return "", "", withCode(uploadErrEmptyFile, errors.New("file is empty"))
+	}
+
+	contentType := http.DetectContentType(head)
+	switch {
+	case contentType == "application/pdf":
+		return transcriptKindPDF, contentType, nil
+
+	case contentType == "image/png", contentType == "image/jpeg", contentType == "image/gif",
+		contentType == "image/webp", contentType == "image/bmp":
+		return transcriptKindImage, contentType, nil
+
+	case bytes.HasPrefix(head, []byte("II*\x00")), bytes.HasPrefix(head, []byte("MM\x00*")):
+		return transcriptKindImage, "image/tiff", nil
+
+	case contentType == "application/zip":
+		if isDOCX(path) {
+			return transcriptKindDOCX, "application/vnd.openxmlformats-officedocument.wordprocessingml.document", nil
+		}
+
+	case strings.HasPrefix(contentType, "text/plain"):
+		if looksLikeCSV(head) {
+			return transcriptKindCSV, "text/csv", nil
+		}
+
+	case bytes.Contains(head[:min(n, 32)], []byte("ftypheic")), bytes.Contains(head[:min(n, 32)], []byte("ftypheix")):
+		return "", contentType, withCode(uploadErrHEIC, errors.New("HEIC images are not supported, please convert the photo to JPG or PNG"))
+	}
+
+	return "", contentType, withCode(uploadErrUnsupportedType, fmt.Errorf("unsupported file type %q: upload a PDF, JPG/PNG image, DOCX or CSV transcript", contentType))
+}
+
+// isDOCX reports whether the zip archive at path is a Word document.
+func isDOCX(path string) bool {
+	zr, err := zip.OpenReader(path)
+	if err != nil {
+		return false
+	}
+	defer zr.Close()
+
+	for _, f := range zr.File {
+		if f.Name == "word/document.xml" {
+			return true
+		}
+	}
+	return false
+}
+
+// looksLikeCSV checks whether the first line of a text file is a delimited header.
+func looksLikeCSV(head []byte) bool {
+	firstLine, _, _ := strings.Cut(string(head), "\n")
+	return strings.Count(firstLine, ",") >= 2 || strings.Count(firstLine, ";") >= 2 || strings.Count(firstLine, "\t") >= 2
+}
+
+// -----------------------------------------------------------------------------
+// TEXT EXTRACTORS (PDFs and images)
+// -----------------------------------------------------------------------------
+
+// newTextExtractors builds the configured PDF extractor chain and the OCR
+// extractor used for image transcripts. Tesseract is left out of the chain
+// when OCR fallback is disabled.
+func newTextExtractors(config util.Config) (*extractor.Chain, extractor.TextExtractor, error) {
+	opts := extractor.Options{
+		PdftotextPath: config.PdftotextPath,
+		PdftoppmPath:  config.PdftoppmPath,
+		OCRLanguages:  ocrLanguages(config),
+	}
+
+	names := strings.Split(config.TextExtractors, ",")
+	if strings.TrimSpace(config.TextExtractors) == "" {
+		names = []string{extractor.PDF, extractor.Pdftotext, extractor.Tesseract}
+	}
+	if !config.OCRFallbackEnabled {
+		enabled := names[:0]
+		for _, name := range names {
+			if strings.TrimSpace(name) != extractor.Tesseract {
+				enabled = append(enabled, name)
+			}
+		}
+		names = enabled
+	}
+
+	chain, err := extractor.NewChain(names, config.TextExtractorMinConfidence, opts)
+	if err != nil {
+		return nil, nil, err
+	}
+	ocr, err := extractor.New(extractor.Tesseract, opts)
+	if err != nil {
+		return nil, nil, err
+	}
+	return chain, ocr, nil
+}
+
+// ocrLanguages returns the configured Tesseract languages, English by default.
+func ocrLanguages(config util.Config) []string {
+	var languages []string
+	for _, lang := range strings.Split(config.OCRLanguages, ",") {
+		if lang = strings.ToLower(strings.TrimSpace(lang)); lang != "" {
+			languages = append(languages, lang)
+		}
+	}
+	if len(languages) == 0 {
+		languages = []string{"eng"}
+	}
+	return languages
+}
+
+// recordExtraction keeps the per-page results in the transcript meta, so
+// poorly recognised pages can be found later.
+func recordExtraction(meta map[string]any, res extractor.Result, attempts []extractor.Attempt, minConfidence float64) {
+	meta["extractor"] = res.Extractor
+	meta["pages"] = res.Pages
+	meta["confidence"] = res.Confidence()
+	meta["low_confidence_pages"] = res.LowConfidencePages(minConfidence)
+	if lang := res.Language(); lang != "" {
+		meta["language"] = lang
+	}
+	if attempts != nil {
+		meta["extraction_attempts"] = attempts
+	}
+	if res.Extractor == extractor.Tesseract {
+		meta["ocr_used"] = true
+		meta["ocr_confidence"] = res.Confidence()
+	}
+}
+
+// -----------------------------------------------------------------------------
+// DOCX
+// -----------------------------------------------------------------------------
+
+// extractDOCXText reads the text of word/document.xml. Paragraphs become lines
+// and table rows are flattened to one line each, so course tables keep the
+// "code name credits grade date" shape the transcript parser expects.
+func extractDOCXText(path string) (string, error) {
+	zr, err := zip.OpenReader(path)
+	if err != nil {
+		return "", fmt.Errorf("failed to open DOCX: %w", err)
+	}
+	defer zr.Close()
+
+	var doc *zip.File
+	for _, f := range zr.File {
+		if f.Name == "word/document.xml" {
+			doc = f
+			break
+		}
+	}
+	if doc == nil {
+		return "", errors.New("DOCX has no word/document.xml")
+	}
+
+	rc, err := doc.Open()
+	if err != nil {
+		return "", fmt.Errorf("failed to read DOCX: %w", err)
+	}
+	defer rc.Close()
+
+	return docxXMLToText(rc)
+}
+
+// docxXMLToText converts WordprocessingML to plain text.
+func docxXMLToText(r io.Reader) (string, error) {
+	var (
+		buf       strings.Builder
+		cellDepth int
+		inText    bool
+	)
+
+	dec := xml.NewDecoder(r)
+	for {
+		tok, err := dec.Token()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return "", fmt.Errorf("invalid DOCX XML: %w", err)
+		}
+
+		switch t := tok.(type) {
+		case xml.StartElement:
+			switch t.Name.Local {
+			case "t":
+				inText = true
+			case "tab", "br":
+				buf.WriteString(" ")
+			case "tc":
+				cellDepth++
+			}
+		case xml.EndElement:
+			switch t.Name.Local {
+			case "t":
+				inText = false
+			case "p":
+				if cellDepth > 0 {
+					buf.WriteString(" ")
+				} else {
+					buf.WriteString("\n")
+				}
+			case "tc":
+				cellDepth--
+				buf.WriteString(" ")
+			case "tr":
+				buf.WriteString("\n")
+			}
+		case xml.CharData:
+			if inText {
+				buf.Write(t)
+			}
+		}
+	}
+
+	text := strings.TrimSpace(buf.String())
+	if text == "" {
+		return "", errors.New("DOCX contains no text")
+	}
+	return text, nil
+}
+
+// -----------------------------------------------------------------------------
+// CSV (study registry exports)
+// -----------------------------------------------------------------------------
+
+// csvColumnAliases maps normalised header names (English and Finnish) to course fields.
+var csvColumnAliases = map[string]string{
+	"code":               "code",
+	"course code":        "code",
+	"koodi":              "code",
+	"kurssikoodi":        "code",
+	"opintojakson koodi": "code",
+
+	"name":              "name",
+	"course":            "name",
+	"course name":       "name",
+	"nimi":              "name",
+	"opintojakso":       "name",
+	"opintojakson nimi": "name",
+
+	"credits":       "credits",
+	"cr":            "credits",
+	"ects":          "credits",
+	"op":            "credits",
+	"opintopisteet": "credits",
+	"laajuus":       "credits",
+
+	"grade":    "grade",
+	"arvosana": "grade",
+	"arvio":    "grade",
+
+	"date":            "date",
+	"completion date": "date",
+	"date completed":  "date",
+	"suorituspäivä":   "date",
+	"päivämäärä":      "date",
+	"pvm":             "date",
+
+	"status": "status",
+	"tila":   "status",
+}
+
+// parseTranscriptCSV maps the columns of a CSV export to course records. It also
+// returns a plain-text rendering of the rows to store as the transcript text.
+func parseTranscriptCSV(r io.Reader) ([]parsedCourse, string, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, "", err
+	}
+	data = bytes.TrimPrefix(data, []byte("\xef\xbb\xbf")) // UTF-8 BOM from Excel exports
+
+	firstLine, _, _ := strings.Cut(string(data), "\n")
+	delimiter := ','
+	for _, d := range []rune{';', '\t'} {
+		if strings.Count(firstLine, string(d)) > strings.Count(firstLine, string(delimiter)) {
+			delimiter = d
+		}
+	}
+
+	cr := csv.NewReader(bytes.NewReader(data))
+	cr.Comma = delimiter
+	cr.FieldsPerRecord = -1
+	cr.TrimLeadingSpace = true
+
+	records, err := cr.ReadAll()
+	if err != nil {
+		return nil, "", fmt.Errorf("invalid CSV: %w", err)
+	}
+	if len(records) < 2 {
+		return nil, "", errors.New("CSV has no course rows")
+	}
+
+	columns := make(map[string]int)
+	for i, header := range records[0] {
+		if field, ok := csvColumnAliases[strings.ToLower(strings.TrimSpace(header))]; ok {
+			if _, dup := columns[field]; !dup {
+				columns[field] = i
+			}
+		}
+	}
+	if _, ok := columns["code"]; !ok {
+		return nil, "", errors.New("CSV has no course code column")
+	}
+
+	get := func(rec []string, field string) string {
+		i, ok := columns[field]
+		if !ok || i >= len(rec) {
+			return ""
+		}
+		return strings.TrimSpace(rec[i])
+	}
+
+	var (
+		courses []parsedCourse
+		text    strings.Builder
+	)
+	for _, rec := range records[1:] {
+		code := strings.ToUpper(get(rec, "code"))
+		if code == "" {
+			continue
+		}
+
+		course := parsedCourse{
+			Code:    code,
+			Name:    get(rec, "name"),
+			Credits: parseDecimal(get(rec, "credits")),
+			Grade:   get(rec, "grade"),
+			Date:    parseTranscriptDate(get(rec, "date")),
+			Status:  strings.ToLower(get(rec, "status")),
+		}
+		switch course.Status {
+		case courseStatusCompleted, courseStatusFailed, courseStatusInProgress:
+		default:
+			course.Status = courseStatus(course.Grade, course.Date)
+		}
+		courses = append(courses, course)
+
+		text.WriteString(strings.Join([]string{code, course.Name, get(rec, "credits") + " cr", course.Grade, get(rec, "date")}, " ") + "\n")
+	}
+
+	if len(courses) == 0 {
+		return nil, "", errors.New("CSV has no course rows")
+	}
+	return courses, text.String(), nil
+}
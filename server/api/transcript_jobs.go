@@ -0,0 +1,316 @@
+// server/api/transcript_jobs.go
+
+package api
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"runtime/debug"
+	"time"
+
+	db "github.com/nibir1/go-fiber-postgres-REST-boilerplate/db/sqlc"
+	"github.com/nibir1/go-fiber-postgres-REST-boilerplate/extractor"
+	"github.com/nibir1/go-fiber-postgres-REST-boilerplate/storage"
+)
+
+// Transcript ingestion job statuses (transcript_jobs.status)
+const (
+	transcriptJobQueued     = "queued"
+	transcriptJobExtracting = "extracting"
+	transcriptJobOCR        = "ocr"
+	transcriptJobDone       = "done"
+	transcriptJobFailed     = "failed"
+)
+
+// maxTranscriptJobAttempts stops a job that keeps crashing the worker from
+// being picked up forever.
+const maxTranscriptJobAttempts = 3
+
+// -----------------------------------------------------------------------------
+// WORKERS
+// -----------------------------------------------------------------------------
+
+// startTranscriptWorkers launches the background ingestion workers. Jobs live in
+// Postgres, so anything queued or interrupted before a restart is picked up again.
+func (s *Server) startTranscriptWorkers(ctx context.Context) {
+	workers := s.config.TranscriptWorkers
+	if workers <= 0 {
+		workers = 1
+	}
+
+	log.Printf("[INIT] Starting %d transcript ingestion worker(s)", workers)
+	for i := 1; i <= workers; i++ {
+		go s.runTranscriptWorker(ctx, i)
+	}
+}
+
+// notifyTranscriptWorkers wakes an idle worker without blocking the caller.
+func (s *Server) notifyTranscriptWorkers() {
+	select {
+	case s.transcriptJobWake <- struct{}{}:
+	default:
+	}
+}
+
+// runTranscriptWorker claims queued jobs one at a time until ctx is cancelled.
+func (s *Server) runTranscriptWorker(ctx context.Context, workerID int) {
+	pollInterval := s.config.TranscriptJobPollInterval
+	if pollInterval <= 0 {
+		pollInterval = 5 * time.Second
+	}
+	staleAfter := s.config.TranscriptJobStaleAfter
+	if staleAfter <= 0 {
+		staleAfter = 10 * time.Minute
+	}
+
+	for {
+		if ctx.Err() != nil {
+			return
+		}
+
+		// Requeue jobs whose worker died mid-way (e.g. the API restarted)
+		if n, err := s.store.RequeueStaleTranscriptJobs(ctx, time.Now().Add(-staleAfter)); err != nil {
+			log.Printf("[JOBS] worker %d: requeue stale jobs failed: %v", workerID, err)
+		} else if n > 0 {
+			log.Printf("[JOBS] worker %d: requeued %d interrupted job(s)", workerID, n)
+		}
+
+		job, err := s.store.ClaimTranscriptJob(ctx)
+		if err == nil {
+			s.processTranscriptJob(ctx, job)
+			continue
+		}
+		if !errors.Is(err, sql.ErrNoRows) {
+			log.Printf("[JOBS] worker %d: claim failed: %v", workerID, err)
+		}
+
+		// Nothing to do: sleep until the next poll or an upload wakes us
+		select {
+		case <-ctx.Done():
+			return
+		case <-s.transcriptJobWake:
+		case <-time.After(pollInterval):
+		}
+	}
+}
+
+// processTranscriptJob runs one ingestion job and records its final status.
+// A panic while ingesting (e.g. a crafted upload) fails the job instead of
+// taking the API down.
+func (s *Server) processTranscriptJob(ctx context.Context, job db.TranscriptJob) {
+	started := time.Now()
+
+	var err error
+	if job.Attempts > maxTranscriptJobAttempts {
+		err = fmt.Errorf("gave up after %d attempts", maxTranscriptJobAttempts)
+	} else {
+		err = s.ingestTranscriptSafely(ctx, job)
+	}
+
+	status := transcriptJobDone
+	var errText sql.NullString
+	if err != nil {
+		status = transcriptJobFailed
+		errText = sqlStringOrNull(err.Error())
+		log.Printf("[JOBS] transcript %d (job %d) failed: %v", job.TranscriptID, job.ID, err)
+	} else {
+		log.Printf("[JOBS] transcript %d (job %d) done in %s", job.TranscriptID, job.ID, time.Since(started).Round(time.Millisecond))
+	}
+
+	if ferr := s.store.FinishTranscriptJob(ctx, db.FinishTranscriptJobParams{
+		ID:     job.ID,
+		Status: status,
+		Error:  errText,
+	}); ferr != nil {
+		log.Printf("[JOBS] failed to record status of job %d: %v", job.ID, ferr)
+	}
+}
+
+// -----------------------------------------------------------------------------
+// INGESTION
+// -----------------------------------------------------------------------------
+
+// ingestTranscriptSafely runs ingestTranscript, returning a panic as an error.
+func (s *Server) ingestTranscriptSafely(ctx context.Context, job db.TranscriptJob) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			log.Printf("[JOBS] transcript %d (job %d) panicked: %v\n%s", job.TranscriptID, job.ID, r, debug.Stack())
+			err = fmt.Errorf("ingestion panicked: %v", r)
+		}
+	}()
+	return s.ingestTranscript(ctx, job)
+}
+
+// ingestTranscript extracts the transcript text according to the sniffed file
+// kind, parses the course table and stores both on the transcript. Manually
+// corrected text, and the text of parse-only (backfill) jobs, is kept as is;
+// only the courses are parsed again.
+func (s *Server) ingestTranscript(ctx context.Context, job db.TranscriptJob) error {
+	tr, err := s.store.GetTranscript(ctx, job.TranscriptID)
+	if err != nil {
+		return fmt.Errorf("failed to load transcript: %w", err)
+	}
+	meta := transcriptMeta(tr.Meta)
+
+	// 1) Text (and for CSV exports, the courses themselves)
+	var (
+		text    string
+		courses []parsedCourse
+		parser  string
+	)
+	source, _ := meta["text_source"].(string)
+	switch {
+	case (source == transcriptTextManual || job.ParseOnly) && tr.TextExtracted.Valid:
+		text = tr.TextExtracted.String
+	case job.ParseOnly:
+		return fmt.Errorf("parse-only job but transcript has no text")
+	default:
+		text, courses, parser, err = s.extractTranscriptFile(ctx, job, tr, meta)
+		if err != nil {
+			return err
+		}
+	}
+
+	// OCR detects the language per page; other paths detect it from the text
+	language, _ := meta["language"].(string)
+	if language == "" {
+		language = extractor.DetectLanguage(text, ocrLanguages(s.config))
+		meta["language"] = language
+	}
+	// Length only: the text holds the student's name, number and birth date
+	log.Printf("[JOBS] transcript %d: %d characters via %v, language %q", tr.ID, len([]rune(text)), meta["ingest_path"], language)
+
+	// 2) Parse the course table once, so later features read structured rows
+	if parser == "" {
+		courses, parser = s.parseTranscriptCourses(ctx, tr.UserUsername, text, language)
+	}
+	meta["course_parser"] = parser
+	if _, err := s.saveTranscriptCourses(ctx, tr.ID, courses); err != nil {
+		return fmt.Errorf("failed to save transcript courses: %w", err)
+	}
+
+	// 3) Store the text last: a transcript with text is ready for use
+	metaJSON, _ := json.Marshal(meta)
+	if err := s.store.UpdateTranscriptText(ctx, db.UpdateTranscriptTextParams{
+		ID:            tr.ID,
+		TextExtracted: sqlStringOrNull(text),
+		Meta:          metaJSON,
+	}); err != nil {
+		return fmt.Errorf("failed to save transcript text: %w", err)
+	}
+	return nil
+}
+
+// extractTranscriptFile reads the text of the uploaded file by its sniffed
+// kind. CSV exports also yield the course rows and the parser name.
+func (s *Server) extractTranscriptFile(ctx context.Context, job db.TranscriptJob, tr db.Transcript, meta map[string]any) (string, []parsedCourse, string, error) {
+	kind, _ := meta["kind"].(string)
+	if kind == "" {
+		kind = transcriptKindPDF // uploaded before content sniffing
+	}
+
+	// The extractors need a file on this host; with S3 this is a downloaded copy
+	path, cleanup, err := storage.LocalCopy(ctx, s.blobs, tr.FileKey)
+	if err != nil {
+		return "", nil, "", fmt.Errorf("failed to load transcript file: %w", err)
+	}
+	defer cleanup()
+
+	var (
+		text    string
+		courses []parsedCourse
+		parser  string
+	)
+	switch kind {
+	case transcriptKindCSV:
+		f, err := os.Open(path)
+		if err != nil {
+			return "", nil, "", fmt.Errorf("failed to open CSV: %w", err)
+		}
+		courses, text, err = parseTranscriptCSV(f)
+		f.Close()
+		if err != nil {
+			return "", nil, "", err
+		}
+		parser = "csv"
+		meta["ingest_path"] = ingestPathCSV
+
+	case transcriptKindDOCX:
+		if text, err = extractDOCXText(path); err != nil {
+			return "", nil, "", err
+		}
+		meta["ingest_path"] = ingestPathDOCX
+
+	case transcriptKindImage:
+		if !s.config.OCRFallbackEnabled {
+			return "", nil, "", fmt.Errorf("image transcripts need OCR, which is disabled")
+		}
+		s.jobProgress(ctx, job.ID, transcriptJobOCR)(0, 1)
+		res, err := s.ocrExtractor.Extract(ctx, path, s.jobProgress(ctx, job.ID, transcriptJobOCR))
+		if err != nil {
+			return "", nil, "", fmt.Errorf("OCR failed: %w", err)
+		}
+		if text = res.Text(); text == "" {
+			return "", nil, "", fmt.Errorf("OCR produced empty text")
+		}
+		recordExtraction(meta, res, nil, s.config.TextExtractorMinConfidence)
+		meta["ingest_path"] = ingestPathImageOCR
+
+	default:
+		if text, err = s.ingestPDFText(ctx, job, path, meta); err != nil {
+			return "", nil, "", err
+		}
+	}
+	return text, courses, parser, nil
+}
+
+// ingestPDFText runs the configured extractor chain (selectable text first,
+// OCR for scans) and records which extractor won.
+func (s *Server) ingestPDFText(ctx context.Context, job db.TranscriptJob, path string, meta map[string]any) (string, error) {
+	res, attempts, err := s.textExtractors.Extract(ctx, path, func(name string) extractor.Progress {
+		if name == extractor.Tesseract {
+			return s.jobProgress(ctx, job.ID, transcriptJobOCR)
+		}
+		return s.jobProgress(ctx, job.ID, transcriptJobExtracting)
+	})
+	if err != nil {
+		return "", fmt.Errorf("no text could be extracted: %w", err)
+	}
+
+	recordExtraction(meta, res, attempts, s.config.TextExtractorMinConfidence)
+	meta["ingest_path"] = ingestPathPDFText
+	if res.Extractor == extractor.Tesseract {
+		meta["ingest_path"] = ingestPathPDFOCR
+	}
+	return res.Text(), nil
+}
+
+// jobProgress returns an extractor.Progress that records per-page progress of a job.
+func (s *Server) jobProgress(ctx context.Context, jobID int64, status string) extractor.Progress {
+	return func(done, total int) {
+		err := s.store.UpdateTranscriptJobProgress(ctx, db.UpdateTranscriptJobProgressParams{
+			ID:         jobID,
+			Status:     status,
+			PagesTotal: int32(total),
+			PagesDone:  int32(done),
+		})
+		if err != nil {
+			log.Printf("[JOBS] failed to update progress of job %d: %v", jobID, err)
+		}
+	}
+}
+
+// transcriptProcessing reports whether the latest ingestion job of a
+// transcript has not finished yet.
+func (s *Server) transcriptProcessing(ctx context.Context, transcriptID int64) bool {
+	job, err := s.store.GetLatestTranscriptJob(ctx, transcriptID)
+	if err != nil {
+		return false
+	}
+	return job.Status != transcriptJobDone && job.Status != transcriptJobFailed
+}
@@ -0,0 +1,57 @@
+// server/api/transcript_jobs_test.go
+
+package api
+
+import (
+	"context"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	mockdb "github.com/nibir1/go-fiber-postgres-REST-boilerplate/db/mock"
+	db "github.com/nibir1/go-fiber-postgres-REST-boilerplate/db/sqlc"
+	"github.com/nibir1/go-fiber-postgres-REST-boilerplate/util"
+	"github.com/stretchr/testify/require"
+)
+
+func TestProcessTranscriptJobRecoversPanic(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	store := mockdb.NewMockStore(ctrl)
+	job := db.TranscriptJob{ID: 7, TranscriptID: 1, Attempts: 1}
+	store.EXPECT().GetTranscript(gomock.Any(), gomock.Eq(job.TranscriptID)).Times(1).
+		DoAndReturn(func(context.Context, int64) (db.Transcript, error) {
+			panic("malformed content stream")
+		})
+	store.EXPECT().FinishTranscriptJob(gomock.Any(), gomock.Any()).Times(1).
+		DoAndReturn(func(_ context.Context, arg db.FinishTranscriptJobParams) error {
+			require.Equal(t, job.ID, arg.ID)
+			require.Equal(t, transcriptJobFailed, arg.Status)
+			require.Contains(t, arg.Error.String, "malformed content stream")
+			return nil
+		})
+
+	server := newFiberTestServer(t, store)
+	require.NotPanics(t, func() { server.processTranscriptJob(context.Background(), job) })
+}
+
+func TestIngestTranscriptParseOnly(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	store := mockdb.NewMockStore(ctrl)
+	// No blob is stored: a parse-only job must not read the file
+	tr := newTestTranscript(t, util.RandomOwner())
+	job := db.TranscriptJob{ID: 7, TranscriptID: tr.ID, Attempts: 1, ParseOnly: true}
+	store.EXPECT().GetTranscript(gomock.Any(), gomock.Eq(tr.ID)).Times(1).Return(tr, nil)
+	store.EXPECT().ReplaceTranscriptCoursesTx(gomock.Any(), gomock.Any()).Times(1).Return([]db.TranscriptCourse{}, nil)
+	store.EXPECT().UpdateTranscriptText(gomock.Any(), gomock.Any()).Times(1).
+		DoAndReturn(func(_ context.Context, arg db.UpdateTranscriptTextParams) error {
+			require.Equal(t, tr.TextExtracted, arg.TextExtracted)
+			require.Contains(t, string(arg.Meta), "course_parser")
+			return nil
+		})
+
+	server := newFiberTestServer(t, store)
+	require.NoError(t, server.ingestTranscript(context.Background(), job))
+}
@@ -0,0 +1,111 @@
+// server/api/transcript_stats.go
+
+package api
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+	db "github.com/nibir1/go-fiber-postgres-REST-boilerplate/db/sqlc"
+	"github.com/nibir1/go-fiber-postgres-REST-boilerplate/grading"
+	"github.com/nibir1/go-fiber-postgres-REST-boilerplate/token"
+)
+
+// transcriptStatsResponse is the academic standing of one transcript
+type transcriptStatsResponse struct {
+	TranscriptID int64 `json:"transcript_id"`
+	grading.Stats
+}
+
+// gradingCourses converts transcript course rows for the grading module.
+// In-progress courses have neither a grade nor credits yet and are skipped;
+// scales maps catalogue course codes to their grading scale.
+func gradingCourses(rows []db.TranscriptCourse, scales map[string]grading.Scale) []grading.Course {
+	courses := make([]grading.Course, 0, len(rows))
+	for _, r := range rows {
+		if r.Status == courseStatusInProgress {
+			continue
+		}
+		code := strings.ToUpper(strings.TrimSpace(r.Code))
+		courses = append(courses, grading.Course{
+			Code:    code,
+			Credits: r.Credits.Float64,
+			Grade:   r.Grade.String,
+			Scale:   scales[code],
+		})
+	}
+	return courses
+}
+
+// courseGradingScales maps catalogue course codes to their grading scale.
+// Missing catalogue data only loses the hints, so errors are logged.
+func (s *Server) courseGradingScales(ctx context.Context) map[string]grading.Scale {
+	scales := make(map[string]grading.Scale)
+	courses, err := s.store.ListAllCourses(ctx)
+	if err != nil {
+		log.Printf("[WARN] Could not load course grading scales: %v", err)
+		return scales
+	}
+	for _, c := range courses {
+		if scale := grading.ParseScale(c.GradingScale.String); scale != grading.ScaleUnknown {
+			scales[strings.ToUpper(strings.TrimSpace(c.Code))] = scale
+		}
+	}
+	return scales
+}
+
+// transcriptStats computes the GPA and credit totals of a transcript.
+func (s *Server) transcriptStats(ctx context.Context, tr db.Transcript) (grading.Stats, error) {
+	rows, err := s.transcriptCourses(ctx, tr)
+	if err != nil {
+		return grading.Stats{}, err
+	}
+	return grading.Compute(gradingCourses(rows, s.courseGradingScales(ctx))), nil
+}
+
+// -----------------------------------------------------------------------------
+// HANDLER
+// -----------------------------------------------------------------------------
+
+// GET /api/transcripts/:id/stats
+func (s *Server) getTranscriptStats(c *fiber.Ctx) error {
+	// 0) Auth
+	payload, ok := c.Locals(authorizationPayloadKey).(*token.Payload)
+	if !ok || payload == nil {
+		return c.Status(fiber.StatusUnauthorized).JSON(errorResponse(fmt.Errorf("unauthorized")))
+	}
+
+	// 1) Parse path param
+	id, err := parseIDParam(c, "id")
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(errorResponse(err))
+	}
+
+	// 2) Fetch transcript + ownership check
+	tr, err := s.store.GetTranscript(c.Context(), id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return c.Status(fiber.StatusNotFound).JSON(errorResponse(fmt.Errorf("not found")))
+		}
+		return c.Status(fiber.StatusInternalServerError).JSON(errorResponse(err))
+	}
+	if tr.UserUsername != payload.Username {
+		return c.Status(fiber.StatusForbidden).JSON(errorResponse(fmt.Errorf("forbidden")))
+	}
+	if !tr.TextExtracted.Valid && s.transcriptProcessing(c.Context(), tr.ID) {
+		return c.Status(fiber.StatusConflict).JSON(errorResponse(fmt.Errorf("transcript is still being processed")))
+	}
+
+	// 3) Compute
+	stats, err := s.transcriptStats(c.Context(), tr)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(errorResponse(err))
+	}
+
+	return c.JSON(transcriptStatsResponse{TranscriptID: tr.ID, Stats: stats})
+}
@@ -0,0 +1,109 @@
+// server/api/transcript_stats_test.go
+
+package api
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	mockdb "github.com/nibir1/go-fiber-postgres-REST-boilerplate/db/mock"
+	db "github.com/nibir1/go-fiber-postgres-REST-boilerplate/db/sqlc"
+	"github.com/nibir1/go-fiber-postgres-REST-boilerplate/util"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetTranscriptStatsAPI(t *testing.T) {
+	username := util.RandomOwner()
+
+	rows := []db.TranscriptCourse{
+		testCourse("TJTS5012", 5, "5", courseStatusCompleted),
+		testCourse("TIES454", 5, "3", courseStatusCompleted),
+		testCourse("ITKA203", 5, "hyv.", courseStatusCompleted),
+		testCourse("MATA101", 5, "0", courseStatusFailed),
+		testCourse("TIES4211", 5, "", courseStatusInProgress),
+	}
+	catalogue := []db.Course{
+		{Code: "TJTS5012", GradingScale: sql.NullString{String: "General scale, 0-5", Valid: true}},
+		{Code: "ITKA203", GradingScale: sql.NullString{String: "Pass-Fail", Valid: true}},
+	}
+
+	testCases := []struct {
+		name          string
+		owner         string
+		buildStubs    func(store *mockdb.MockStore, tr db.Transcript)
+		checkResponse func(t *testing.T, resp *http.Response)
+	}{
+		{
+			name:  "OK",
+			owner: username,
+			buildStubs: func(store *mockdb.MockStore, tr db.Transcript) {
+				store.EXPECT().GetTranscript(gomock.Any(), gomock.Eq(tr.ID)).Times(1).Return(tr, nil)
+				store.EXPECT().ListTranscriptCourses(gomock.Any(), gomock.Eq(tr.ID)).Times(1).Return(rows, nil)
+				store.EXPECT().ListAllCourses(gomock.Any()).Times(1).Return(catalogue, nil)
+			},
+			checkResponse: func(t *testing.T, resp *http.Response) {
+				require.Equal(t, http.StatusOK, resp.StatusCode)
+
+				var body struct {
+					TranscriptID  int64   `json:"transcript_id"`
+					GPA           float64 `json:"gpa"`
+					GPAUS         float64 `json:"gpa_us"`
+					TotalCredits  float64 `json:"total_credits"`
+					FailedCredits float64 `json:"failed_credits"`
+				}
+				require.NoError(t, json.NewDecoder(resp.Body).Decode(&body))
+				require.Equal(t, int64(1), body.TranscriptID)
+				require.Equal(t, 4.0, body.GPA)
+				require.Equal(t, 3.2, body.GPAUS)
+				require.Equal(t, 15.0, body.TotalCredits)
+				require.Equal(t, 5.0, body.FailedCredits)
+			},
+		},
+		{
+			name:  "Forbidden",
+			owner: "someone_else",
+			buildStubs: func(store *mockdb.MockStore, tr db.Transcript) {
+				store.EXPECT().GetTranscript(gomock.Any(), gomock.Eq(tr.ID)).Times(1).Return(tr, nil)
+				store.EXPECT().ListTranscriptCourses(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, resp *http.Response) {
+				require.Equal(t, http.StatusForbidden, resp.StatusCode)
+			},
+		},
+		{
+			name:  "NotFound",
+			owner: username,
+			buildStubs: func(store *mockdb.MockStore, tr db.Transcript) {
+				store.EXPECT().GetTranscript(gomock.Any(), gomock.Eq(tr.ID)).Times(1).Return(db.Transcript{}, sql.ErrNoRows)
+			},
+			checkResponse: func(t *testing.T, resp *http.Response) {
+				require.Equal(t, http.StatusNotFound, resp.StatusCode)
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			tr := newTestTranscript(t, tc.owner)
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStubs(store, tr)
+
+			server := newFiberTestServer(t, store)
+			req, err := http.NewRequest(http.MethodGet, fmt.Sprintf("/api/transcripts/%d/stats", tr.ID), nil)
+			require.NoError(t, err)
+			addAuthorization(t, req, server.tokenMaker, authorizationTypeBearer, username, time.Minute)
+
+			resp, err := server.app.Test(req, -1)
+			require.NoError(t, err)
+			tc.checkResponse(t, resp)
+		})
+	}
+}
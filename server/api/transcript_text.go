@@ -0,0 +1,171 @@
+// server/api/transcript_text.go
+
+package api
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/gofiber/fiber/v2"
+	db "github.com/nibir1/go-fiber-postgres-REST-boilerplate/db/sqlc"
+	"github.com/nibir1/go-fiber-postgres-REST-boilerplate/token"
+)
+
+// Origins of a transcript's text (transcripts.meta.text_source, transcript_text_revisions.source)
+const (
+	transcriptTextExtracted = "extracted"
+	transcriptTextManual    = "manual"
+)
+
+// maxTranscriptTextChars bounds a manually corrected transcript text
+const maxTranscriptTextChars = 200_000
+
+type updateTranscriptTextRequest struct {
+	Text string `json:"text"`
+}
+
+// transcriptTextRevisionResponse is one version of a transcript's text
+type transcriptTextRevisionResponse struct {
+	Revision  int32     `json:"revision"`
+	Source    string    `json:"source"`
+	Text      string    `json:"text"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// -----------------------------------------------------------------------------
+// HANDLERS
+// -----------------------------------------------------------------------------
+
+// GET /api/transcripts/:id/text
+// Returns the full current text and every stored revision (newest first).
+func (s *Server) getTranscriptText(c *fiber.Ctx) error {
+	// 0) Auth
+	payload, ok := c.Locals(authorizationPayloadKey).(*token.Payload)
+	if !ok || payload == nil {
+		return c.Status(fiber.StatusUnauthorized).JSON(errorResponse(fmt.Errorf("unauthorized")))
+	}
+
+	// 1) Parse path param
+	id, err := parseIDParam(c, "id")
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(errorResponse(err))
+	}
+
+	// 2) Fetch transcript + ownership check
+	tr, err := s.store.GetTranscript(c.Context(), id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return c.Status(fiber.StatusNotFound).JSON(errorResponse(fmt.Errorf("not found")))
+		}
+		return c.Status(fiber.StatusInternalServerError).JSON(errorResponse(err))
+	}
+	if tr.UserUsername != payload.Username {
+		return c.Status(fiber.StatusForbidden).JSON(errorResponse(fmt.Errorf("forbidden")))
+	}
+
+	// 3) Revisions
+	revisions, err := s.store.ListTranscriptTextRevisions(c.Context(), tr.ID)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(errorResponse(err))
+	}
+	items := make([]transcriptTextRevisionResponse, 0, len(revisions))
+	for _, r := range revisions {
+		items = append(items, transcriptTextRevisionResponse{
+			Revision:  r.Revision,
+			Source:    r.Source,
+			Text:      r.Text,
+			CreatedAt: r.CreatedAt,
+		})
+	}
+
+	source, _ := transcriptMeta(tr.Meta)["text_source"].(string)
+	if source == "" {
+		source = transcriptTextExtracted
+	}
+	return c.JSON(fiber.Map{
+		"id":        tr.ID,
+		"text":      tr.TextExtracted.String,
+		"source":    source,
+		"revisions": items,
+	})
+}
+
+// PATCH /api/transcripts/:id/text  {"text": "..."}
+// Saves a corrected transcript text as a new revision (the extracted text is
+// kept as revision 1), re-parses the courses in the background and flags the
+// recommendations made from the old text as stale.
+func (s *Server) updateTranscriptText(c *fiber.Ctx) error {
+	// 0) Auth
+	payload, ok := c.Locals(authorizationPayloadKey).(*token.Payload)
+	if !ok || payload == nil {
+		return c.Status(fiber.StatusUnauthorized).JSON(errorResponse(fmt.Errorf("unauthorized")))
+	}
+
+	// 1) Parse path param and body
+	id, err := parseIDParam(c, "id")
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(errorResponse(err))
+	}
+	var req updateTranscriptTextRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(errorResponse(err))
+	}
+	text := strings.TrimSpace(req.Text)
+	if text == "" {
+		return c.Status(fiber.StatusBadRequest).JSON(errorResponse(fmt.Errorf("text is required")))
+	}
+	if utf8.RuneCountInString(text) > maxTranscriptTextChars {
+		return c.Status(fiber.StatusBadRequest).JSON(errorResponse(fmt.Errorf("text is longer than %d characters", maxTranscriptTextChars)))
+	}
+
+	// 2) Fetch transcript + ownership check
+	tr, err := s.store.GetTranscript(c.Context(), id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return c.Status(fiber.StatusNotFound).JSON(errorResponse(fmt.Errorf("not found")))
+		}
+		return c.Status(fiber.StatusInternalServerError).JSON(errorResponse(err))
+	}
+	if tr.UserUsername != payload.Username {
+		return c.Status(fiber.StatusForbidden).JSON(errorResponse(fmt.Errorf("forbidden")))
+	}
+	if s.transcriptProcessing(c.Context(), tr.ID) {
+		return c.Status(fiber.StatusConflict).JSON(errorResponse(fmt.Errorf("transcript is still being processed")))
+	}
+
+	// 3) Nothing to do when the text is unchanged
+	if tr.TextExtracted.Valid && strings.TrimSpace(tr.TextExtracted.String) == text {
+		return c.JSON(fiber.Map{"id": tr.ID, "unchanged": true})
+	}
+
+	// 4) Store the revision and queue the course re-parse
+	meta := transcriptMeta(tr.Meta)
+	meta["text_source"] = transcriptTextManual
+	metaJSON, _ := json.Marshal(meta)
+
+	result, err := s.store.CorrectTranscriptTextTx(c.Context(), db.CorrectTranscriptTextTxParams{
+		Transcript: tr,
+		Text:       text,
+		Meta:       metaJSON,
+	})
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(errorResponse(fmt.Errorf("failed to save transcript text: %v", err)))
+	}
+	s.notifyTranscriptWorkers()
+	log.Printf("[TRANSCRIPT] Transcript %d text corrected (revision %d, %d recommendation(s) marked stale)",
+		tr.ID, result.Revision.Revision, result.RecommendationsMarkedStale)
+
+	return c.Status(fiber.StatusAccepted).JSON(fiber.Map{
+		"id":                           tr.ID,
+		"revision":                     result.Revision.Revision,
+		"job_id":                       result.Job.ID,
+		"status":                       result.Job.Status,
+		"recommendations_marked_stale": result.RecommendationsMarkedStale,
+	})
+}
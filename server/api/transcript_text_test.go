@@ -0,0 +1,196 @@
+// server/api/transcript_text_test.go
+
+package api
+
+import (
+	"bytes"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/golang/mock/gomock"
+	mockdb "github.com/nibir1/go-fiber-postgres-REST-boilerplate/db/mock"
+	db "github.com/nibir1/go-fiber-postgres-REST-boilerplate/db/sqlc"
+	"github.com/nibir1/go-fiber-postgres-REST-boilerplate/token"
+	"github.com/nibir1/go-fiber-postgres-REST-boilerplate/util"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUpdateTranscriptTextAPI(t *testing.T) {
+	username := util.RandomOwner()
+	corrected := "TJTS5012 Research Methods 5 cr 5"
+
+	testCases := []struct {
+		name          string
+		owner         string
+		body          fiber.Map
+		buildStubs    func(store *mockdb.MockStore, tr db.Transcript)
+		setupAuth     func(t *testing.T, req *http.Request, maker token.Maker)
+		checkResponse func(t *testing.T, resp *http.Response)
+	}{
+		{
+			name:  "OK",
+			owner: username,
+			body:  fiber.Map{"text": "  " + corrected + "\n"},
+			buildStubs: func(store *mockdb.MockStore, tr db.Transcript) {
+				store.EXPECT().GetTranscript(gomock.Any(), gomock.Eq(tr.ID)).Times(1).Return(tr, nil)
+				store.EXPECT().GetLatestTranscriptJob(gomock.Any(), gomock.Eq(tr.ID)).Times(1).
+					Return(db.TranscriptJob{Status: transcriptJobDone}, nil)
+				store.EXPECT().
+					CorrectTranscriptTextTx(gomock.Any(), gomock.Eq(db.CorrectTranscriptTextTxParams{
+						Transcript: tr,
+						Text:       corrected,
+						Meta:       []byte(`{"text_source":"manual"}`),
+					})).
+					Times(1).
+					Return(db.CorrectTranscriptTextTxResult{
+						Revision:                   db.TranscriptTextRevision{TranscriptID: tr.ID, Revision: 2, Text: corrected, Source: transcriptTextManual},
+						Job:                        db.TranscriptJob{ID: 7, TranscriptID: tr.ID, Status: transcriptJobQueued},
+						RecommendationsMarkedStale: 3,
+					}, nil)
+			},
+			setupAuth: func(t *testing.T, req *http.Request, maker token.Maker) {
+				addAuthorization(t, req, maker, authorizationTypeBearer, username, time.Minute)
+			},
+			checkResponse: func(t *testing.T, resp *http.Response) {
+				require.Equal(t, http.StatusAccepted, resp.StatusCode)
+
+				var body struct {
+					Revision                   int32  `json:"revision"`
+					JobID                      int64  `json:"job_id"`
+					Status                     string `json:"status"`
+					RecommendationsMarkedStale int64  `json:"recommendations_marked_stale"`
+				}
+				require.NoError(t, json.NewDecoder(resp.Body).Decode(&body))
+				require.Equal(t, int32(2), body.Revision)
+				require.Equal(t, int64(7), body.JobID)
+				require.Equal(t, transcriptJobQueued, body.Status)
+				require.Equal(t, int64(3), body.RecommendationsMarkedStale)
+			},
+		},
+		{
+			name:  "Unchanged",
+			owner: username,
+			body:  fiber.Map{"text": "TJTS5012 Research Methods 5 cr 4"},
+			buildStubs: func(store *mockdb.MockStore, tr db.Transcript) {
+				store.EXPECT().GetTranscript(gomock.Any(), gomock.Eq(tr.ID)).Times(1).Return(tr, nil)
+				store.EXPECT().GetLatestTranscriptJob(gomock.Any(), gomock.Any()).Times(1).
+					Return(db.TranscriptJob{Status: transcriptJobDone}, nil)
+				store.EXPECT().CorrectTranscriptTextTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			setupAuth: func(t *testing.T, req *http.Request, maker token.Maker) {
+				addAuthorization(t, req, maker, authorizationTypeBearer, username, time.Minute)
+			},
+			checkResponse: func(t *testing.T, resp *http.Response) {
+				require.Equal(t, http.StatusOK, resp.StatusCode)
+
+				var body struct {
+					Unchanged bool `json:"unchanged"`
+				}
+				require.NoError(t, json.NewDecoder(resp.Body).Decode(&body))
+				require.True(t, body.Unchanged)
+			},
+		},
+		{
+			name:  "EmptyText",
+			owner: username,
+			body:  fiber.Map{"text": "  \n "},
+			buildStubs: func(store *mockdb.MockStore, tr db.Transcript) {
+				store.EXPECT().GetTranscript(gomock.Any(), gomock.Any()).Times(0)
+			},
+			setupAuth: func(t *testing.T, req *http.Request, maker token.Maker) {
+				addAuthorization(t, req, maker, authorizationTypeBearer, username, time.Minute)
+			},
+			checkResponse: func(t *testing.T, resp *http.Response) {
+				require.Equal(t, http.StatusBadRequest, resp.StatusCode)
+			},
+		},
+		{
+			name:  "StillProcessing",
+			owner: username,
+			body:  fiber.Map{"text": corrected},
+			buildStubs: func(store *mockdb.MockStore, tr db.Transcript) {
+				store.EXPECT().GetTranscript(gomock.Any(), gomock.Eq(tr.ID)).Times(1).Return(tr, nil)
+				store.EXPECT().GetLatestTranscriptJob(gomock.Any(), gomock.Eq(tr.ID)).Times(1).
+					Return(db.TranscriptJob{Status: transcriptJobExtracting}, nil)
+				store.EXPECT().CorrectTranscriptTextTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			setupAuth: func(t *testing.T, req *http.Request, maker token.Maker) {
+				addAuthorization(t, req, maker, authorizationTypeBearer, username, time.Minute)
+			},
+			checkResponse: func(t *testing.T, resp *http.Response) {
+				require.Equal(t, http.StatusConflict, resp.StatusCode)
+			},
+		},
+		{
+			name:  "Forbidden",
+			owner: "someone_else",
+			body:  fiber.Map{"text": corrected},
+			buildStubs: func(store *mockdb.MockStore, tr db.Transcript) {
+				store.EXPECT().GetTranscript(gomock.Any(), gomock.Eq(tr.ID)).Times(1).Return(tr, nil)
+				store.EXPECT().CorrectTranscriptTextTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			setupAuth: func(t *testing.T, req *http.Request, maker token.Maker) {
+				addAuthorization(t, req, maker, authorizationTypeBearer, username, time.Minute)
+			},
+			checkResponse: func(t *testing.T, resp *http.Response) {
+				require.Equal(t, http.StatusForbidden, resp.StatusCode)
+			},
+		},
+		{
+			name:  "NotFound",
+			owner: username,
+			body:  fiber.Map{"text": corrected},
+			buildStubs: func(store *mockdb.MockStore, tr db.Transcript) {
+				store.EXPECT().GetTranscript(gomock.Any(), gomock.Eq(tr.ID)).Times(1).Return(db.Transcript{}, sql.ErrNoRows)
+				store.EXPECT().CorrectTranscriptTextTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			setupAuth: func(t *testing.T, req *http.Request, maker token.Maker) {
+				addAuthorization(t, req, maker, authorizationTypeBearer, username, time.Minute)
+			},
+			checkResponse: func(t *testing.T, resp *http.Response) {
+				require.Equal(t, http.StatusNotFound, resp.StatusCode)
+			},
+		},
+		{
+			name:  "NoAuthorization",
+			owner: username,
+			body:  fiber.Map{"text": corrected},
+			buildStubs: func(store *mockdb.MockStore, tr db.Transcript) {
+				store.EXPECT().GetTranscript(gomock.Any(), gomock.Any()).Times(0)
+			},
+			setupAuth: func(t *testing.T, req *http.Request, maker token.Maker) {},
+			checkResponse: func(t *testing.T, resp *http.Response) {
+				require.Equal(t, http.StatusUnauthorized, resp.StatusCode)
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			tr := newTestTranscript(t, tc.owner)
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStubs(store, tr)
+
+			server := newFiberTestServer(t, store)
+
+			data, err := json.Marshal(tc.body)
+			require.NoError(t, err)
+			req, err := http.NewRequest(http.MethodPatch, fmt.Sprintf("/api/transcripts/%d/text", tr.ID), bytes.NewReader(data))
+			require.NoError(t, err)
+			req.Header.Set("Content-Type", "application/json")
+			tc.setupAuth(t, req, server.tokenMaker)
+
+			resp, err := server.app.Test(req, -1)
+			require.NoError(t, err)
+			tc.checkResponse(t, resp)
+		})
+	}
+}
@@ -4,205 +4,220 @@ package api
 
 import (
 	"database/sql"
-	"encoding/json"
 	"errors"
 	"fmt"
-	"io"
-	"os"
-	"strings"
-	"time"
-
-	"os/exec"
-	"path/filepath"
-
-	"github.com/otiai10/gosseract/v2"
+	"log"
 
 	"github.com/gofiber/fiber/v2"
-	"github.com/ledongthuc/pdf"
+	"github.com/lib/pq"
 	db "github.com/nibir1/go-fiber-postgres-REST-boilerplate/db/sqlc"
 	"github.com/nibir1/go-fiber-postgres-REST-boilerplate/token"
 )
 
+// helper to truncate text for preview
+func truncateString(s string, max int) string {
+	if len(s) <= max {
+		return s
+	}
+	return s[:max] + "...(truncated)"
+}
+
 // -----------------------------------------------------------------------------
-// PDF TEXT EXTRACTION (stubs you can wire to ledongthuc/pdf or gosseract)
+// HANDLERS
 // -----------------------------------------------------------------------------
 
-// extractPDFText extracts text from a PDF file if it contains selectable text.
-func extractPDFText(path string) (string, error) {
-	f, r, err := pdf.Open(path)
-	if err != nil {
-		return "", fmt.Errorf("failed to open PDF: %w", err)
+// POST /api/transcripts/upload  (multipart/form-data: file=<pdf|image|docx|csv>)
+// Returns 202 with the job ID; poll GET /api/transcripts/:id/status for progress.
+// Re-uploading an identical file returns the existing transcript.
+func (s *Server) uploadTranscript(c *fiber.Ctx) error {
+	// 0) Auth
+	payload, ok := c.Locals(authorizationPayloadKey).(*token.Payload)
+	if !ok || payload == nil {
+		return c.Status(fiber.StatusUnauthorized).JSON(errorResponse(fmt.Errorf("unauthorized")))
 	}
-	defer f.Close()
 
-	var buf strings.Builder
-	b, err := r.GetPlainText()
+	// 1) Receive the file, hash it and detect its type
+	upload, status, err := s.receiveTranscriptFile(c)
 	if err != nil {
-		return "", fmt.Errorf("failed to extract text: %w", err)
+		return c.Status(status).JSON(errorResponse(err))
 	}
+	defer upload.cleanup()
 
-	_, err = io.Copy(&buf, b)
-	if err != nil {
-		return "", fmt.Errorf("failed to read PDF text: %w", err)
+	// 2) Identical file already uploaded: reuse it instead of re-running OCR
+	existing, err := s.store.GetTranscriptByHash(c.Context(), db.GetTranscriptByHashParams{
+		UserUsername:  payload.Username,
+		ContentSha256: sqlStringOrNull(upload.SHA256),
+	})
+	if err == nil {
+		return s.duplicateTranscriptResponse(c, existing)
 	}
-
-	text := buf.String()
-	if strings.TrimSpace(text) == "" {
-		return "", errors.New("no text extracted (possible scanned PDF)")
+	if !errors.Is(err, sql.ErrNoRows) {
+		return c.Status(fiber.StatusInternalServerError).JSON(errorResponse(err))
+	}
+	if err := s.storeUpload(c.Context(), &upload, payload.Username); err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(errorResponse(err))
 	}
 
-	return text, nil
+	// 3) Create the transcript and queue its ingestion job; the worker does
+	// text extraction, OCR and course parsing in the background
+	result, err := s.store.CreateTranscriptTx(c.Context(), db.CreateTranscriptParams{
+		UserUsername:  payload.Username,
+		FileKey:       upload.Key,
+		Meta:          upload.meta("upload"),
+		ContentSha256: sqlStringOrNull(upload.SHA256),
+	})
+	if err != nil {
+		s.removeBlob(c.Context(), upload.Key)
+		// Lost a race against a concurrent upload of the same file
+		if pqErr, ok := err.(*pq.Error); ok && pqErr.Code.Name() == "unique_violation" {
+			if existing, gerr := s.store.GetTranscriptByHash(c.Context(), db.GetTranscriptByHashParams{
+				UserUsername:  payload.Username,
+				ContentSha256: sqlStringOrNull(upload.SHA256),
+			}); gerr == nil {
+				return s.duplicateTranscriptResponse(c, existing)
+			}
+		}
+		return c.Status(fiber.StatusInternalServerError).JSON(errorResponse(err))
+	}
+	s.notifyTranscriptWorkers()
+
+	return c.Status(fiber.StatusAccepted).JSON(fiber.Map{
+		"id":         result.Transcript.ID,
+		"job_id":     result.Job.ID,
+		"status":     result.Job.Status,
+		"file_key":   result.Transcript.FileKey,
+		"created_at": result.Transcript.CreatedAt,
+	})
 }
 
-// ocrPDFToText runs OCR using Tesseract via gosseract to extract text from scanned PDFs.
-func ocrPDFToText(pdfPath string) (string, error) {
-	// Step 1: Convert the PDF pages to images (using pdftoppm from poppler-utils)
-	tempDir := os.TempDir()
-	outputPrefix := filepath.Join(tempDir, fmt.Sprintf("ocr_%d", time.Now().UnixNano()))
-	
-	// For local testing, you might use:
-	// cmd := exec.Command("pdftoppm", "-png", pdfPath, outputPrefix)
-
-	// Use the ABSOLUTE PATH for pdftoppm to ensure execution in Docker
-	cmd := exec.Command("/usr/bin/pdftoppm", "-png", pdfPath, outputPrefix)
-	
-	if err := cmd.Run(); err != nil {
-		return "", fmt.Errorf("failed to convert pdf to images: %w", err)
+// PUT /api/transcripts/:id/file  (multipart/form-data: file=<pdf|image|docx|csv>)
+// Replaces the file of a transcript and re-runs ingestion.
+func (s *Server) replaceTranscriptFile(c *fiber.Ctx) error {
+	// 0) Auth
+	payload, ok := c.Locals(authorizationPayloadKey).(*token.Payload)
+	if !ok || payload == nil {
+		return c.Status(fiber.StatusUnauthorized).JSON(errorResponse(fmt.Errorf("unauthorized")))
 	}
 
-	// Step 2: Collect all generated PNGs
-	files, err := filepath.Glob(outputPrefix + "-*.png")
+	// 1) Parse path param
+	id, err := parseIDParam(c, "id")
 	if err != nil {
-		return "", fmt.Errorf("failed to list converted images: %w", err)
-	}
-	if len(files) == 0 {
-		return "", errors.New("no image files found for OCR conversion")
+		return c.Status(fiber.StatusBadRequest).JSON(errorResponse(err))
 	}
 
-	// Step 3: OCR each image page
-	// gosseract client initialization often needs TESSDATA_PREFIX to be set, 
-	// but the Alpine image usually handles the default paths correctly.
-	client := gosseract.NewClient()
-	defer client.Close()
-	client.SetLanguage("eng")
-
-	var result strings.Builder
-	for _, file := range files {
-		if err := client.SetImage(file); err != nil {
-			fmt.Printf("[DEBUG] Failed to set image %s: %v\n", file, err)
-			continue
-		}
-		text, err := client.Text()
-		if err != nil {
-			fmt.Printf("[DEBUG] OCR failed on %s: %v\n", file, err)
-			continue
+	// 2) Fetch transcript + ownership check
+	tr, err := s.store.GetTranscript(c.Context(), id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return c.Status(fiber.StatusNotFound).JSON(errorResponse(fmt.Errorf("not found")))
 		}
-		result.WriteString(text + "\n")
-		os.Remove(file) // cleanup
+		return c.Status(fiber.StatusInternalServerError).JSON(errorResponse(err))
 	}
-
-	output := strings.TrimSpace(result.String())
-	if output == "" {
-		return "", errors.New("OCR produced empty text")
+	if tr.UserUsername != payload.Username {
+		return c.Status(fiber.StatusForbidden).JSON(errorResponse(fmt.Errorf("forbidden")))
+	}
+	if s.transcriptProcessing(c.Context(), tr.ID) {
+		return c.Status(fiber.StatusConflict).JSON(errorResponse(fmt.Errorf("transcript is still being processed")))
 	}
 
-	fmt.Printf("------------------------------------------------------------\n")
-	fmt.Printf("[DEBUG] OCR Extracted text preview:\n\n%s\n", truncateString(output, 600))
-	fmt.Printf("------------------------------------------------------------\n")
+	// 3) Receive the new file, hash it and detect its type
+	upload, status, err := s.receiveTranscriptFile(c)
+	if err != nil {
+		return c.Status(status).JSON(errorResponse(err))
+	}
+	defer upload.cleanup()
 
-	return output, nil
-}
+	// 4) Same content as now, or as another transcript of this user
+	if tr.ContentSha256.Valid && tr.ContentSha256.String == upload.SHA256 {
+		return s.duplicateTranscriptResponse(c, tr)
+	}
+	other, err := s.store.GetTranscriptByHash(c.Context(), db.GetTranscriptByHashParams{
+		UserUsername:  payload.Username,
+		ContentSha256: sqlStringOrNull(upload.SHA256),
+	})
+	if err == nil {
+		return c.Status(fiber.StatusConflict).JSON(fiber.Map{
+			"error":         fmt.Sprintf("this file is already uploaded as transcript %d", other.ID),
+			"transcript_id": other.ID,
+		})
+	}
+	if !errors.Is(err, sql.ErrNoRows) {
+		return c.Status(fiber.StatusInternalServerError).JSON(errorResponse(err))
+	}
+	if err := s.storeUpload(c.Context(), &upload, payload.Username); err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(errorResponse(err))
+	}
 
-// helper to truncate text for preview
-func truncateString(s string, max int) string {
-	if len(s) <= max {
-		return s
+	// 5) Point the transcript at the new file and queue a fresh ingestion job
+	result, err := s.store.ReplaceTranscriptFileTx(c.Context(), db.UpdateTranscriptFileParams{
+		ID:            tr.ID,
+		FileKey:       upload.Key,
+		ContentSha256: sqlStringOrNull(upload.SHA256),
+		Meta:          upload.meta("replace"),
+	})
+	if err != nil {
+		s.removeBlob(c.Context(), upload.Key)
+		return c.Status(fiber.StatusInternalServerError).JSON(errorResponse(err))
 	}
-	return s[:max] + "...(truncated)"
+	s.removeBlob(c.Context(), tr.FileKey)
+	s.notifyTranscriptWorkers()
+
+	return c.Status(fiber.StatusAccepted).JSON(fiber.Map{
+		"id":         result.Transcript.ID,
+		"job_id":     result.Job.ID,
+		"status":     result.Job.Status,
+		"file_key":   result.Transcript.FileKey,
+		"created_at": result.Transcript.CreatedAt,
+	})
 }
 
-// -----------------------------------------------------------------------------
-// HANDLERS
-// -----------------------------------------------------------------------------
-
-// POST /api/transcripts/upload  (multipart/form-data: file=<pdf>)
-func (s *Server) uploadTranscript(c *fiber.Ctx) error {
+// DELETE /api/transcripts/:id[?delete_recommendations=true]
+// Recommendations generated from the transcript are kept (unlinked) unless
+// delete_recommendations is set, in which case they and their summaries go too.
+func (s *Server) deleteTranscript(c *fiber.Ctx) error {
 	// 0) Auth
 	payload, ok := c.Locals(authorizationPayloadKey).(*token.Payload)
 	if !ok || payload == nil {
 		return c.Status(fiber.StatusUnauthorized).JSON(errorResponse(fmt.Errorf("unauthorized")))
 	}
 
-	// 1) Validate file presence and type
-	fileHeader, err := c.FormFile("file")
-	if err != nil {
-		return c.Status(fiber.StatusBadRequest).JSON(errorResponse(fmt.Errorf("missing file: %w", err)))
-	}
-	if !strings.HasSuffix(strings.ToLower(fileHeader.Filename), ".pdf") {
-		return c.Status(fiber.StatusBadRequest).JSON(errorResponse(fmt.Errorf("only PDF files are allowed")))
-	}
-
-	// 2) Save the file
-	path, err := s.saveUploadedFile(fileHeader)
+	// 1) Parse path param
+	id, err := parseIDParam(c, "id")
 	if err != nil {
-		return c.Status(fiber.StatusInternalServerError).JSON(errorResponse(err))
-	}
-
-	// 3) Extract text (try normal first; optionally OCR if enabled)
-	text, err := extractPDFText(path)
-	meta := map[string]any{
-		"ocr_used": false,
-		"source":   "upload",
+		return c.Status(fiber.StatusBadRequest).JSON(errorResponse(err))
 	}
 
-	// DEBUG: Print a short preview of what text was extracted
+	// 2) Fetch transcript + ownership check
+	tr, err := s.store.GetTranscript(c.Context(), id)
 	if err != nil {
-		fmt.Println("[DEBUG] Text extraction failed:", err)
-	} else {
-		if len(text) > 0 {
-			preview := text
-			if len(preview) > 300 {
-				preview = preview[:300] + "...(truncated)"
-			}
-			fmt.Println("------------------------------------------------------------")
-			fmt.Println("[DEBUG] Extracted text preview:")
-			fmt.Println(preview)
-			fmt.Println("------------------------------------------------------------")
-		} else {
-			fmt.Println("[DEBUG] No text extracted from PDF.")
+		if errors.Is(err, sql.ErrNoRows) {
+			return c.Status(fiber.StatusNotFound).JSON(errorResponse(fmt.Errorf("not found")))
 		}
+		return c.Status(fiber.StatusInternalServerError).JSON(errorResponse(err))
 	}
-
-	if err != nil || strings.TrimSpace(text) == "" {
-		// try OCR if allowed
-		if s.config.OCRFallbackEnabled {
-			txt, oerr := ocrPDFToText(path)
-			if oerr == nil && strings.TrimSpace(txt) != "" {
-				text = txt
-				meta["ocr_used"] = true
-			}
-		}
+	if tr.UserUsername != payload.Username {
+		return c.Status(fiber.StatusForbidden).JSON(errorResponse(fmt.Errorf("forbidden")))
 	}
 
-	// 4) Prepare DB payload
-	metaJSON, _ := json.Marshal(meta)
-
-	created, err := s.store.CreateTranscript(c.Context(), db.CreateTranscriptParams{
-		UserUsername:  payload.Username,
-		FilePath:      path,
-		TextExtracted: sqlStringOrNull(text),
-		Meta:          metaJSON,
+	// 3) Delete the row (courses and jobs cascade), then the files
+	result, err := s.store.DeleteTranscriptTx(c.Context(), db.DeleteTranscriptTxParams{
+		ID:                    tr.ID,
+		DeleteRecommendations: c.QueryBool("delete_recommendations"),
 	})
 	if err != nil {
-		return c.Status(fiber.StatusInternalServerError).JSON(errorResponse(err))
+		return c.Status(fiber.StatusInternalServerError).JSON(errorResponse(fmt.Errorf("failed to delete transcript: %v", err)))
 	}
+	s.removeBlob(c.Context(), tr.FileKey)
+	for _, key := range result.SummaryPDFKeys {
+		s.removeBlob(c.Context(), key)
+	}
+	log.Printf("[TRANSCRIPT] Deleted transcript %d (%d recommendation(s) deleted, %d unlinked)",
+		tr.ID, result.RecommendationsDeleted, result.RecommendationsUnlinked)
 
-	return c.Status(fiber.StatusOK).JSON(fiber.Map{
-		"id":         created.ID,
-		"file_path":  created.FilePath,
-		"created_at": created.CreatedAt,
-		"text_bytes": len(text),
-		"ocr_used":   meta["ocr_used"],
+	return c.JSON(fiber.Map{
+		"id":                       tr.ID,
+		"recommendations_deleted":  result.RecommendationsDeleted,
+		"recommendations_unlinked": result.RecommendationsUnlinked,
 	})
 }
 
@@ -258,10 +273,79 @@ func (s *Server) getTranscript(c *fiber.Ctx) error {
 		}
 	}
 
+	// 5) Structured course rows, as stored; reading never parses (older
+	// transcripts are parsed by a queued ingestion job)
+	courses, err := s.store.ListTranscriptCourses(c.Context(), tr.ID)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(errorResponse(err))
+	}
+
 	return c.JSON(fiber.Map{
 		"id":           tr.ID,
-		"file_path":    tr.FilePath,
+		"file_key":     tr.FileKey,
 		"created_at":   tr.CreatedAt,
 		"text_preview": preview,
+		"courses":      courses,
+	})
+}
+
+// GET /api/transcripts/:id/status
+func (s *Server) getTranscriptStatus(c *fiber.Ctx) error {
+	// 0) Auth
+	payload, ok := c.Locals(authorizationPayloadKey).(*token.Payload)
+	if !ok || payload == nil {
+		return c.Status(fiber.StatusUnauthorized).JSON(errorResponse(fmt.Errorf("unauthorized")))
+	}
+
+	// 1) Parse path param
+	id, err := parseIDParam(c, "id")
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(errorResponse(err))
+	}
+
+	// 2) Fetch transcript + ownership check
+	tr, err := s.store.GetTranscript(c.Context(), id)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return c.Status(fiber.StatusNotFound).JSON(errorResponse(fmt.Errorf("not found")))
+		}
+		return c.Status(fiber.StatusInternalServerError).JSON(errorResponse(err))
+	}
+	if tr.UserUsername != payload.Username {
+		return c.Status(fiber.StatusForbidden).JSON(errorResponse(fmt.Errorf("forbidden")))
+	}
+
+	// 3) Latest ingestion job
+	job, err := s.store.GetLatestTranscriptJob(c.Context(), tr.ID)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			// Transcripts uploaded before background ingestion existed
+			return c.JSON(fiber.Map{
+				"transcript_id": tr.ID,
+				"status":        transcriptJobDone,
+				"progress":      1.0,
+			})
+		}
+		return c.Status(fiber.StatusInternalServerError).JSON(errorResponse(err))
+	}
+
+	progress := 0.0
+	switch {
+	case job.Status == transcriptJobDone:
+		progress = 1.0
+	case job.PagesTotal > 0:
+		progress = float64(job.PagesDone) / float64(job.PagesTotal)
+	}
+
+	return c.JSON(fiber.Map{
+		"transcript_id": tr.ID,
+		"job_id":        job.ID,
+		"status":        job.Status,
+		"pages_total":   job.PagesTotal,
+		"pages_done":    job.PagesDone,
+		"progress":      progress,
+		"error":         coalesce(job.Error, ""),
+		"attempts":      job.Attempts,
+		"updated_at":    job.UpdatedAt,
 	})
 }
@@ -0,0 +1,314 @@
+// server/api/transcripts_test.go
+
+package api
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"mime/multipart"
+	"net/http"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	mockdb "github.com/nibir1/go-fiber-postgres-REST-boilerplate/db/mock"
+	db "github.com/nibir1/go-fiber-postgres-REST-boilerplate/db/sqlc"
+	"github.com/nibir1/go-fiber-postgres-REST-boilerplate/storage"
+	"github.com/nibir1/go-fiber-postgres-REST-boilerplate/token"
+	"github.com/nibir1/go-fiber-postgres-REST-boilerplate/util"
+	"github.com/stretchr/testify/require"
+)
+
+// newTestTranscript returns a transcript owned by username; putTestBlob stores its file
+func newTestTranscript(t *testing.T, username string) db.Transcript {
+	return db.Transcript{
+		ID:            1,
+		UserUsername:  username,
+		FileKey:       "uploads/" + username + "/1_transcript.pdf",
+		TextExtracted: sql.NullString{String: "TJTS5012 Research Methods 5 cr 4", Valid: true},
+		Meta:          []byte(`{}`),
+		CreatedAt:     time.Now(),
+	}
+}
+
+// putTestBlob stores data under key in the server's blob store
+func putTestBlob(t *testing.T, server *Server, key string, data []byte) {
+	require.NoError(t, server.blobs.Put(context.Background(), key, bytes.NewReader(data), int64(len(data)), ""))
+}
+
+// blobExists reports whether key is stored in the server's blob store
+func blobExists(t *testing.T, server *Server, key string) bool {
+	rc, err := server.blobs.Get(context.Background(), key)
+	if errors.Is(err, storage.ErrNotFound) {
+		return false
+	}
+	require.NoError(t, err)
+	rc.Close()
+	return true
+}
+
+// newMultipartRequest builds a file upload request with the given content
+func newMultipartRequest(t *testing.T, method, url string, content []byte) *http.Request {
+	body := new(bytes.Buffer)
+	writer := multipart.NewWriter(body)
+	part, err := writer.CreateFormFile("file", "transcript.pdf")
+	require.NoError(t, err)
+	_, err = part.Write(content)
+	require.NoError(t, err)
+	require.NoError(t, writer.Close())
+
+	req, err := http.NewRequest(method, url, body)
+	require.NoError(t, err)
+	req.Header.Set("Content-Type", writer.FormDataContentType())
+	return req
+}
+
+func TestGetTranscriptDoesNotParseAPI(t *testing.T) {
+	username := util.RandomOwner()
+	tr := newTestTranscript(t, username) // has text but was never parsed
+
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	store := mockdb.NewMockStore(ctrl)
+	store.EXPECT().GetTranscript(gomock.Any(), gomock.Eq(tr.ID)).Times(1).Return(tr, nil)
+	store.EXPECT().ListTranscriptCourses(gomock.Any(), gomock.Eq(tr.ID)).Times(1).Return([]db.TranscriptCourse{}, nil)
+	store.EXPECT().ReplaceTranscriptCoursesTx(gomock.Any(), gomock.Any()).Times(0)
+	store.EXPECT().UpdateTranscriptMeta(gomock.Any(), gomock.Any()).Times(0)
+
+	server := newFiberTestServer(t, store)
+	req, err := http.NewRequest(http.MethodGet, fmt.Sprintf("/api/transcripts/%d", tr.ID), nil)
+	require.NoError(t, err)
+	addAuthorization(t, req, server.tokenMaker, authorizationTypeBearer, username, time.Minute)
+
+	resp, err := server.app.Test(req, -1)
+	require.NoError(t, err)
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+
+	var body struct {
+		Courses []db.TranscriptCourse `json:"courses"`
+	}
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&body))
+	require.Empty(t, body.Courses)
+}
+
+func TestDeleteTranscriptAPI(t *testing.T) {
+	username := util.RandomOwner()
+	testSummaryKey := "summaries/" + username + "/summary_1.pdf"
+
+	testCases := []struct {
+		name          string
+		query         string
+		owner         string
+		buildStubs    func(store *mockdb.MockStore, tr db.Transcript)
+		setupAuth     func(t *testing.T, req *http.Request, maker token.Maker)
+		checkResponse func(t *testing.T, resp *http.Response, server *Server, tr db.Transcript)
+	}{
+		{
+			name:  "OKKeepRecommendations",
+			owner: username,
+			buildStubs: func(store *mockdb.MockStore, tr db.Transcript) {
+				store.EXPECT().GetTranscript(gomock.Any(), gomock.Eq(tr.ID)).Times(1).Return(tr, nil)
+				store.EXPECT().
+					DeleteTranscriptTx(gomock.Any(), gomock.Eq(db.DeleteTranscriptTxParams{ID: tr.ID})).
+					Times(1).
+					Return(db.DeleteTranscriptTxResult{RecommendationsUnlinked: 2}, nil)
+			},
+			setupAuth: func(t *testing.T, req *http.Request, maker token.Maker) {
+				addAuthorization(t, req, maker, authorizationTypeBearer, username, time.Minute)
+			},
+			checkResponse: func(t *testing.T, resp *http.Response, server *Server, tr db.Transcript) {
+				require.Equal(t, http.StatusOK, resp.StatusCode)
+
+				var body struct {
+					RecommendationsUnlinked int64 `json:"recommendations_unlinked"`
+				}
+				require.NoError(t, json.NewDecoder(resp.Body).Decode(&body))
+				require.Equal(t, int64(2), body.RecommendationsUnlinked)
+
+				require.False(t, blobExists(t, server, tr.FileKey))
+			},
+		},
+		{
+			name:  "OKDeleteRecommendations",
+			query: "?delete_recommendations=true",
+			owner: username,
+			buildStubs: func(store *mockdb.MockStore, tr db.Transcript) {
+				store.EXPECT().GetTranscript(gomock.Any(), gomock.Eq(tr.ID)).Times(1).Return(tr, nil)
+				store.EXPECT().
+					DeleteTranscriptTx(gomock.Any(), gomock.Eq(db.DeleteTranscriptTxParams{ID: tr.ID, DeleteRecommendations: true})).
+					Times(1).
+					Return(db.DeleteTranscriptTxResult{RecommendationsDeleted: 1, SummaryPDFKeys: []string{testSummaryKey}}, nil)
+			},
+			setupAuth: func(t *testing.T, req *http.Request, maker token.Maker) {
+				addAuthorization(t, req, maker, authorizationTypeBearer, username, time.Minute)
+			},
+			checkResponse: func(t *testing.T, resp *http.Response, server *Server, tr db.Transcript) {
+				require.Equal(t, http.StatusOK, resp.StatusCode)
+				require.False(t, blobExists(t, server, tr.FileKey))
+				require.False(t, blobExists(t, server, testSummaryKey))
+			},
+		},
+		{
+			name:  "Forbidden",
+			owner: "someone_else",
+			buildStubs: func(store *mockdb.MockStore, tr db.Transcript) {
+				store.EXPECT().GetTranscript(gomock.Any(), gomock.Eq(tr.ID)).Times(1).Return(tr, nil)
+				store.EXPECT().DeleteTranscriptTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			setupAuth: func(t *testing.T, req *http.Request, maker token.Maker) {
+				addAuthorization(t, req, maker, authorizationTypeBearer, username, time.Minute)
+			},
+			checkResponse: func(t *testing.T, resp *http.Response, server *Server, tr db.Transcript) {
+				require.Equal(t, http.StatusForbidden, resp.StatusCode)
+
+				require.True(t, blobExists(t, server, tr.FileKey))
+			},
+		},
+		{
+			name:  "NotFound",
+			owner: username,
+			buildStubs: func(store *mockdb.MockStore, tr db.Transcript) {
+				store.EXPECT().GetTranscript(gomock.Any(), gomock.Eq(tr.ID)).Times(1).Return(db.Transcript{}, sql.ErrNoRows)
+				store.EXPECT().DeleteTranscriptTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			setupAuth: func(t *testing.T, req *http.Request, maker token.Maker) {
+				addAuthorization(t, req, maker, authorizationTypeBearer, username, time.Minute)
+			},
+			checkResponse: func(t *testing.T, resp *http.Response, server *Server, tr db.Transcript) {
+				require.Equal(t, http.StatusNotFound, resp.StatusCode)
+			},
+		},
+		{
+			name:  "NoAuthorization",
+			owner: username,
+			buildStubs: func(store *mockdb.MockStore, tr db.Transcript) {
+				store.EXPECT().GetTranscript(gomock.Any(), gomock.Any()).Times(0)
+			},
+			setupAuth: func(t *testing.T, req *http.Request, maker token.Maker) {},
+			checkResponse: func(t *testing.T, resp *http.Response, server *Server, tr db.Transcript) {
+				require.Equal(t, http.StatusUnauthorized, resp.StatusCode)
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			tr := newTestTranscript(t, tc.owner)
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStubs(store, tr)
+
+			server := newFiberTestServer(t, store)
+			putTestBlob(t, server, tr.FileKey, []byte("%PDF-1.7\n"))
+			putTestBlob(t, server, testSummaryKey, []byte("%PDF-1.7\n"))
+
+			req, err := http.NewRequest(http.MethodDelete, fmt.Sprintf("/api/transcripts/%d%s", tr.ID, tc.query), nil)
+			require.NoError(t, err)
+			tc.setupAuth(t, req, server.tokenMaker)
+
+			resp, err := server.app.Test(req, -1)
+			require.NoError(t, err)
+			tc.checkResponse(t, resp, server, tr)
+		})
+	}
+}
+
+func TestUploadTranscriptDuplicateAPI(t *testing.T) {
+	username := util.RandomOwner()
+	content := testPDF(t, 1, "")
+	sum := sha256.Sum256(content)
+	hash := hex.EncodeToString(sum[:])
+
+	existing := newTestTranscript(t, username)
+	existing.ContentSha256 = sql.NullString{String: hash, Valid: true}
+
+	testCases := []struct {
+		name          string
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(t *testing.T, resp *http.Response)
+	}{
+		{
+			name: "ReturnsExisting",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					GetTranscriptByHash(gomock.Any(), gomock.Eq(db.GetTranscriptByHashParams{
+						UserUsername:  username,
+						ContentSha256: sql.NullString{String: hash, Valid: true},
+					})).
+					Times(1).
+					Return(existing, nil)
+				store.EXPECT().
+					GetLatestTranscriptJob(gomock.Any(), gomock.Eq(existing.ID)).
+					Times(1).
+					Return(db.TranscriptJob{ID: 7, TranscriptID: existing.ID, Status: transcriptJobDone}, nil)
+				store.EXPECT().CreateTranscriptTx(gomock.Any(), gomock.Any()).Times(0)
+				store.EXPECT().CreateTranscriptJob(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, resp *http.Response) {
+				require.Equal(t, http.StatusOK, resp.StatusCode)
+
+				var body struct {
+					ID        int64  `json:"id"`
+					Status    string `json:"status"`
+					Duplicate bool   `json:"duplicate"`
+				}
+				require.NoError(t, json.NewDecoder(resp.Body).Decode(&body))
+				require.Equal(t, existing.ID, body.ID)
+				require.Equal(t, transcriptJobDone, body.Status)
+				require.True(t, body.Duplicate)
+			},
+		},
+		{
+			name: "RetriesFailedIngestion",
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().GetTranscriptByHash(gomock.Any(), gomock.Any()).Times(1).Return(existing, nil)
+				store.EXPECT().
+					GetLatestTranscriptJob(gomock.Any(), gomock.Eq(existing.ID)).
+					Times(1).
+					Return(db.TranscriptJob{ID: 7, TranscriptID: existing.ID, Status: transcriptJobFailed}, nil)
+				store.EXPECT().
+					CreateTranscriptJob(gomock.Any(), gomock.Eq(existing.ID)).
+					Times(1).
+					Return(db.TranscriptJob{ID: 8, TranscriptID: existing.ID, Status: transcriptJobQueued}, nil)
+				store.EXPECT().CreateTranscriptTx(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(t *testing.T, resp *http.Response) {
+				require.Equal(t, http.StatusAccepted, resp.StatusCode)
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStubs(store)
+
+			server := newFiberTestServer(t, store)
+
+			req := newMultipartRequest(t, http.MethodPost, "/api/transcripts/upload", content)
+			addAuthorization(t, req, server.tokenMaker, authorizationTypeBearer, username, time.Minute)
+
+			resp, err := server.app.Test(req, -1)
+			require.NoError(t, err)
+			tc.checkResponse(t, resp)
+
+			// The duplicate upload is not stored
+			files, err := filepath.Glob(filepath.Join(server.config.StorageLocalDir, "*"))
+			require.NoError(t, err)
+			require.Empty(t, files)
+		})
+	}
+}
@@ -0,0 +1,137 @@
+// server/api/upload_validation.go
+
+package api
+
+import (
+	"errors"
+	"fmt"
+	"image"
+	_ "image/gif"  // register decoders for image.DecodeConfig
+	_ "image/jpeg" //
+	_ "image/png"  //
+	"os"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/nibir1/go-fiber-postgres-REST-boilerplate/extractor"
+	"github.com/nibir1/go-fiber-postgres-REST-boilerplate/util"
+)
+
+// Machine-readable codes of rejected uploads, returned as "code" next to "error"
+const (
+	uploadErrMissingFile     = "missing_file"
+	uploadErrEmptyFile       = "empty_file"
+	uploadErrTooLarge        = "file_too_large"
+	uploadErrUnsupportedType = "unsupported_file_type"
+	uploadErrHEIC            = "heic_not_supported"
+	uploadErrMalformed       = "malformed_file"
+	uploadErrPDFEncrypted    = "pdf_encrypted"
+	uploadErrPDFPassword     = "pdf_password_protected"
+	uploadErrTooManyPages    = "too_many_pages"
+)
+
+// Defaults for UPLOAD_MAX_SIZE_MB and UPLOAD_MAX_PAGES
+const (
+	defaultUploadMaxSizeMB = 20
+	defaultUploadMaxPages  = 30
+)
+
+// uploadMaxBytes is the largest accepted transcript file.
+func uploadMaxBytes(config util.Config) int64 {
+	mb := config.UploadMaxSizeMB
+	if mb <= 0 {
+		mb = defaultUploadMaxSizeMB
+	}
+	return int64(mb) << 20
+}
+
+// uploadMaxPages is the largest accepted number of PDF pages.
+func uploadMaxPages(config util.Config) int {
+	if config.UploadMaxPages > 0 {
+		return config.UploadMaxPages
+	}
+	return defaultUploadMaxPages
+}
+
+// bodyLimit is the Fiber request body limit: the largest upload plus room
+// for the multipart envelope.
+func bodyLimit(config util.Config) int {
+	return int(uploadMaxBytes(config)) + 1<<20
+}
+
+// errFileTooLarge is the rejection of a file over the size limit.
+func errFileTooLarge(config util.Config) error {
+	return withCode(uploadErrTooLarge, fmt.Errorf("file is larger than the %d MB limit", uploadMaxBytes(config)>>20))
+}
+
+// uploadErrorStatus is the HTTP status for an upload that failed with err:
+// a client error for coded rejections, 500 for anything else.
+func uploadErrorStatus(err error) int {
+	var ce *codedError
+	if !errors.As(err, &ce) {
+		return fiber.StatusInternalServerError
+	}
+	switch ce.Code {
+	case uploadErrTooLarge:
+		return fiber.StatusRequestEntityTooLarge
+	case uploadErrUnsupportedType, uploadErrHEIC:
+		return fiber.StatusUnsupportedMediaType
+	case uploadErrMalformed, uploadErrPDFEncrypted, uploadErrPDFPassword, uploadErrTooManyPages:
+		return fiber.StatusUnprocessableEntity
+	}
+	return fiber.StatusBadRequest
+}
+
+// fiberErrorHandler answers errors raised outside handlers. Requests over the
+// body limit are rejected by Fiber before any handler runs, so they get the
+// upload error code here.
+func fiberErrorHandler(config util.Config) fiber.ErrorHandler {
+	return func(c *fiber.Ctx, err error) error {
+		var fe *fiber.Error
+		if errors.As(err, &fe) && fe.Code == fiber.StatusRequestEntityTooLarge {
+			return c.Status(fiber.StatusRequestEntityTooLarge).JSON(errorResponse(errFileTooLarge(config)))
+		}
+		return fiber.DefaultErrorHandler(c, err)
+	}
+}
+
+// validateTranscriptFile checks that a sniffed file can be ingested, before
+// it is stored or any extractor runs: PDFs must open without a password and
+// stay within the page limit, images must have a readable header. It returns
+// the page count (1 for images, 0 when not applicable).
+func validateTranscriptFile(config util.Config, path, kind, contentType string) (int, error) {
+	switch kind {
+	case transcriptKindPDF:
+		info, err := extractor.InspectPDF(path)
+		switch {
+		case errors.Is(err, extractor.ErrPDFPasswordProtected):
+			return 0, withCode(uploadErrPDFPassword, err)
+		case errors.Is(err, extractor.ErrPDFEncrypted):
+			return 0, withCode(uploadErrPDFEncrypted, extractor.ErrPDFEncrypted)
+		case errors.Is(err, extractor.ErrPDFMalformed):
+			return 0, withCode(uploadErrMalformed, extractor.ErrPDFMalformed)
+		case err != nil:
+			return 0, err
+		}
+		if max := uploadMaxPages(config); info.Pages > max {
+			return 0, withCode(uploadErrTooManyPages, fmt.Errorf("the PDF has %d pages; at most %d are accepted", info.Pages, max))
+		}
+		return info.Pages, nil
+
+	case transcriptKindImage:
+		// The standard library decodes PNG, JPEG and GIF headers; other
+		// formats are left to Tesseract
+		switch contentType {
+		case "image/png", "image/jpeg", "image/gif":
+			f, err := os.Open(path)
+			if err != nil {
+				return 0, err
+			}
+			defer f.Close()
+			if _, _, err := image.DecodeConfig(f); err != nil {
+				return 0, withCode(uploadErrMalformed, errors.New("the image file is damaged or not a valid image"))
+			}
+		}
+		return 1, nil
+	}
+	return 0, nil
+}
@@ -0,0 +1,155 @@
+// server/api/upload_validation_test.go
+
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/golang/mock/gomock"
+	"github.com/jung-kurt/gofpdf"
+	mockdb "github.com/nibir1/go-fiber-postgres-REST-boilerplate/db/mock"
+	"github.com/nibir1/go-fiber-postgres-REST-boilerplate/util"
+	"github.com/stretchr/testify/require"
+)
+
+// testPDF returns a PDF with the given number of pages, password-protected
+// when userPass is set
+func testPDF(t *testing.T, pages int, userPass string) []byte {
+	pdf := gofpdf.New("P", "mm", "A4", "")
+	if userPass != "" {
+		pdf.SetProtection(0, userPass, "owner")
+	}
+	pdf.SetFont("Helvetica", "", 11)
+	for i := 0; i < pages; i++ {
+		pdf.AddPage()
+		pdf.Cell(0, 10, "TJTS5012 Research Methods 5 cr 4")
+	}
+
+	var buf bytes.Buffer
+	require.NoError(t, pdf.Output(&buf))
+	return buf.Bytes()
+}
+
+func TestUploadTranscriptValidationAPI(t *testing.T) {
+	username := util.RandomOwner()
+	valid := testPDF(t, 2, "")
+
+	testCases := []struct {
+		name       string
+		content    []byte
+		wantStatus int
+		wantCode   string
+	}{
+		{
+			name:       "TooLargeForLimit",
+			content:    append(bytes.Clone(valid), bytes.Repeat([]byte{' '}, 1<<20)...),
+			wantStatus: http.StatusRequestEntityTooLarge,
+			wantCode:   uploadErrTooLarge,
+		},
+		{
+			name:       "Empty",
+			content:    nil,
+			wantStatus: http.StatusBadRequest,
+			wantCode:   uploadErrEmptyFile,
+		},
+		{
+			name:       "NotAPDF",
+			content:    []byte("Dear student, this is not a transcript."),
+			wantStatus: http.StatusUnsupportedMediaType,
+			wantCode:   uploadErrUnsupportedType,
+		},
+		{
+			name:       "HEIC",
+			content:    []byte("\x00\x00\x00\x18ftypheic\x00\x00\x00\x00"),
+			wantStatus: http.StatusUnsupportedMediaType,
+			wantCode:   uploadErrHEIC,
+		},
+		{
+			name:       "MalformedPDF",
+			content:    valid[:len(valid)/2],
+			wantStatus: http.StatusUnprocessableEntity,
+			wantCode:   uploadErrMalformed,
+		},
+		{
+			name:       "PasswordProtectedPDF",
+			content:    testPDF(t, 1, "secret"),
+			wantStatus: http.StatusUnprocessableEntity,
+			wantCode:   uploadErrPDFPassword,
+		},
+		{
+			name:       "TooManyPages",
+			content:    testPDF(t, 4, ""),
+			wantStatus: http.StatusUnprocessableEntity,
+			wantCode:   uploadErrTooManyPages,
+		},
+		{
+			name:       "MalformedImage",
+			content:    []byte("\x89PNG\r\n\x1a\n\x00\x00\x00\rIHDR"),
+			wantStatus: http.StatusUnprocessableEntity,
+			wantCode:   uploadErrMalformed,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			// Rejected files never reach the database
+			store := mockdb.NewMockStore(ctrl)
+			store.EXPECT().GetTranscriptByHash(gomock.Any(), gomock.Any()).Times(0)
+			store.EXPECT().CreateTranscriptTx(gomock.Any(), gomock.Any()).Times(0)
+
+			config := util.Config{
+				TokenSymmetricKey: util.RandomString(32),
+				StorageLocalDir:   t.TempDir(),
+				UploadMaxSizeMB:   1,
+				UploadMaxPages:    3,
+			}
+			server, err := NewServer(config, store)
+			require.NoError(t, err)
+
+			req := newMultipartRequest(t, http.MethodPost, "/api/transcripts/upload", tc.content)
+			addAuthorization(t, req, server.tokenMaker, authorizationTypeBearer, username, time.Minute)
+
+			resp, err := server.app.Test(req, -1)
+			require.NoError(t, err)
+			require.Equal(t, tc.wantStatus, resp.StatusCode)
+
+			var body struct {
+				Error string `json:"error"`
+				Code  string `json:"code"`
+			}
+			require.NoError(t, json.NewDecoder(resp.Body).Decode(&body))
+			require.Equal(t, tc.wantCode, body.Code)
+			require.NotEmpty(t, body.Error)
+		})
+	}
+}
+
+// Bodies over Fiber's BodyLimit never reach a handler; the error handler
+// gives them the same code
+func TestFiberErrorHandlerBodyTooLarge(t *testing.T) {
+	app := fiber.New(fiber.Config{ErrorHandler: fiberErrorHandler(util.Config{UploadMaxSizeMB: 5})})
+	app.Get("/too-large", func(c *fiber.Ctx) error { return fiber.ErrRequestEntityTooLarge })
+	app.Get("/teapot", func(c *fiber.Ctx) error { return fiber.ErrTeapot })
+
+	resp, err := app.Test(httptest.NewRequest(http.MethodGet, "/too-large", nil), -1)
+	require.NoError(t, err)
+	require.Equal(t, http.StatusRequestEntityTooLarge, resp.StatusCode)
+
+	var body map[string]string
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&body))
+	require.Equal(t, uploadErrTooLarge, body["code"])
+	require.Equal(t, "file is larger than the 5 MB limit", body["error"])
+
+	resp, err = app.Test(httptest.NewRequest(http.MethodGet, "/teapot", nil), -1)
+	require.NoError(t, err)
+	require.Equal(t, http.StatusTeapot, resp.StatusCode)
+}
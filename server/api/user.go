@@ -10,6 +10,7 @@ import (
 	"github.com/gofiber/fiber/v2"
 	"github.com/lib/pq"                                               // For Postgres-specific error handling
 	db "github.com/nibir1/go-fiber-postgres-REST-boilerplate/db/sqlc" // SQLC database package
+	"github.com/nibir1/go-fiber-postgres-REST-boilerplate/token"      // Auth payload
 	"github.com/nibir1/go-fiber-postgres-REST-boilerplate/util"       // Utility functions (e.g., password hashing)
 )
 
@@ -34,6 +35,8 @@ type userResponse struct {
 	Email             string    `json:"email"`               // Email of user
 	PasswordChangedAt time.Time `json:"password_changed_at"` // Timestamp of last password change
 	CreatedAt         time.Time `json:"created_at"`          // Timestamp of user creation
+	StrictRedaction   bool      `json:"strict_redaction"`    // Stricter masking of personal data sent to the AI
+	Role              string    `json:"role"`                // "student" or "admin"
 }
 
 // newUserResponse converts db.User struct into a userResponse for API response
@@ -44,6 +47,8 @@ func newUserResponse(user db.User) userResponse {
 		Email:             user.Email,
 		PasswordChangedAt: user.PasswordChangedAt,
 		CreatedAt:         user.CreatedAt,
+		StrictRedaction:   user.StrictRedaction,
+		Role:              user.Role,
 	}
 }
 
@@ -61,6 +66,12 @@ type loginUserResponse struct {
 	User        userResponse `json:"user"`         // User details
 }
 
+// updateUserSettingsRequest represents the expected JSON body for updating settings
+// @Description User settings payload
+type updateUserSettingsRequest struct {
+	StrictRedaction *bool `json:"strict_redaction"` // Also mask name parts, e-mails, phone numbers and long numbers
+}
+
 // ---------------------------
 // Handlers
 // ---------------------------
@@ -178,3 +189,47 @@ func (server *Server) loginUser(c *fiber.Ctx) error {
 	// 6. Return 200 OK with access token and user info
 	return c.Status(fiber.StatusOK).JSON(resp)
 }
+
+// updateUserSettings handles PUT /users/me/settings
+
+// UpdateUserSettings godoc
+// @Summary      Update the current user's settings
+// @Description  Opts the user into or out of strict redaction of personal data sent to the AI provider
+// @Tags         Users
+// @Accept       json
+// @Produce      json
+// @Param        settings  body      updateUserSettingsRequest  true  "Settings"
+// @Success      200       {object}  userResponse
+// @Security     ApiKeyAuth
+// @Router       /users/me/settings [put]
+func (server *Server) updateUserSettings(c *fiber.Ctx) error {
+	// 1. Authenticated user
+	payload, ok := c.Locals(authorizationPayloadKey).(*token.Payload)
+	if !ok || payload == nil {
+		return c.Status(fiber.StatusUnauthorized).JSON(errorResponse(errors.New("unauthorized")))
+	}
+
+	// 2. Parse JSON request body
+	var req updateUserSettingsRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(errorResponse(err))
+	}
+	if req.StrictRedaction == nil {
+		return c.Status(fiber.StatusBadRequest).JSON(errorResponse(errors.New("missing required fields")))
+	}
+
+	// 3. Update the setting
+	user, err := server.store.UpdateUserStrictRedaction(c.Context(), db.UpdateUserStrictRedactionParams{
+		Username:        payload.Username,
+		StrictRedaction: *req.StrictRedaction,
+	})
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return c.Status(fiber.StatusNotFound).JSON(errorResponse(err))
+		}
+		return c.Status(fiber.StatusInternalServerError).JSON(errorResponse(err))
+	}
+
+	// 4. Return 200 OK with the updated user
+	return c.Status(fiber.StatusOK).JSON(newUserResponse(user))
+}
@@ -9,6 +9,7 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"testing"
+	"time"
 
 	"github.com/gofiber/fiber/v2"
 	"github.com/golang/mock/gomock"
@@ -332,3 +333,100 @@ func TestLoginUserAPI(t *testing.T) {
 		})
 	}
 }
+
+// ---------------------------
+// TestUpdateUserSettingsAPI
+// ---------------------------
+
+func TestUpdateUserSettingsAPI(t *testing.T) {
+	tempUser := util.RandomUserStruct()
+	user := db.User{
+		Username:        tempUser.Username,
+		FullName:        tempUser.FullName,
+		Email:           tempUser.Email,
+		StrictRedaction: true,
+	}
+
+	testCases := []struct {
+		name          string
+		body          fiber.Map
+		buildStubs    func(store *mockdb.MockStore)
+		checkResponse func(recorder *httptest.ResponseRecorder)
+	}{
+		{
+			name: "OK",
+			body: fiber.Map{"strict_redaction": true},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					UpdateUserStrictRedaction(gomock.Any(), gomock.Eq(db.UpdateUserStrictRedactionParams{
+						Username:        user.Username,
+						StrictRedaction: true,
+					})).
+					Times(1).
+					Return(user, nil)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusOK, recorder.Code)
+				requireBodyMatchUser(t, recorder.Body, user)
+
+				var resp userResponse
+				require.NoError(t, json.Unmarshal(recorder.Body.Bytes(), &resp))
+				require.True(t, resp.StrictRedaction)
+			},
+		},
+		{
+			name: "MissingSetting",
+			body: fiber.Map{},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().UpdateUserStrictRedaction(gomock.Any(), gomock.Any()).Times(0)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusBadRequest, recorder.Code)
+			},
+		},
+		{
+			name: "InternalError",
+			body: fiber.Map{"strict_redaction": false},
+			buildStubs: func(store *mockdb.MockStore) {
+				store.EXPECT().
+					UpdateUserStrictRedaction(gomock.Any(), gomock.Any()).
+					Times(1).
+					Return(db.User{}, sql.ErrConnDone)
+			},
+			checkResponse: func(recorder *httptest.ResponseRecorder) {
+				require.Equal(t, http.StatusInternalServerError, recorder.Code)
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			store := mockdb.NewMockStore(ctrl)
+			tc.buildStubs(store)
+
+			server := newFiberTestServer(t, store)
+			recorder := httptest.NewRecorder()
+
+			data, err := json.Marshal(tc.body)
+			require.NoError(t, err)
+
+			req := httptest.NewRequest(http.MethodPut, "/api/users/me/settings", bytes.NewReader(data))
+			req.Header.Set("Content-Type", "application/json")
+			addAuthorization(t, req, server.tokenMaker, authorizationTypeBearer, user.Username, time.Minute)
+
+			resp, err := server.app.Test(req, -1)
+			require.NoError(t, err)
+
+			bodyBytes := new(bytes.Buffer)
+			_, err = bodyBytes.ReadFrom(resp.Body)
+			require.NoError(t, err)
+			recorder.Body = bodyBytes
+			recorder.Code = resp.StatusCode
+
+			tc.checkResponse(recorder)
+		})
+	}
+}
@@ -0,0 +1,340 @@
+// server/catalog/catalog.go
+
+package catalog
+
+import (
+	"bytes"
+	"database/sql"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	db "github.com/nibir1/go-fiber-postgres-REST-boilerplate/db/sqlc"
+	"go.yaml.in/yaml/v3"
+)
+
+// Format is the file format of a catalogue export
+type Format string
+
+// Supported catalogue formats
+const (
+	FormatCSV  Format = "csv"
+	FormatJSON Format = "json"
+	FormatYAML Format = "yaml"
+)
+
+// Course is one catalogue entry as it appears in an export file. Field names
+// match the courses table; CSV headers are matched case-insensitively.
+type Course struct {
+	Code             string `json:"code" yaml:"code"`
+	Name             string `json:"name" yaml:"name"`
+	Language         string `json:"language" yaml:"language"`
+	GradingScale     string `json:"grading_scale" yaml:"grading_scale"`
+	Organiser        string `json:"organiser" yaml:"organiser"`
+	LearningOutcomes string `json:"learning_outcomes" yaml:"learning_outcomes"`
+	Prerequisites    string `json:"prerequisites" yaml:"prerequisites"`
+	TeacherName      string `json:"teacher_name" yaml:"teacher_name"`
+	TeacherEmail     string `json:"teacher_email" yaml:"teacher_email"`
+	CourseLink       string `json:"course_link" yaml:"course_link"`
+	Credits          Value  `json:"credits" yaml:"credits"`
+	Level            string `json:"level" yaml:"level"`
+	TeachingPeriods  Value  `json:"teaching_periods" yaml:"teaching_periods"`
+	Modality         string `json:"modality" yaml:"modality"`
+	Campus           string `json:"campus" yaml:"campus"`
+}
+
+// Params converts the entry to the database insert parameters; empty optional
+// fields become NULL. The entry must have passed Validate.
+func (c Course) Params() db.CreateCourseParams {
+	credits, _ := ParseCredits(string(c.Credits))
+	periods, _ := ParsePeriods(string(c.TeachingPeriods))
+	return db.CreateCourseParams{
+		Code:             c.Code,
+		Name:             c.Name,
+		Language:         nullString(c.Language),
+		GradingScale:     nullString(c.GradingScale),
+		Organiser:        nullString(c.Organiser),
+		LearningOutcomes: nullString(c.LearningOutcomes),
+		Prerequisites:    nullString(c.Prerequisites),
+		TeacherName:      nullString(c.TeacherName),
+		TeacherEmail:     nullString(c.TeacherEmail),
+		CourseLink:       nullString(c.CourseLink),
+		Credits:          sql.NullFloat64{Float64: credits, Valid: credits > 0},
+		Level:            nullString(c.Level),
+		TeachingPeriods:  periods,
+		Modality:         nullString(c.Modality),
+		Campus:           nullString(c.Campus),
+	}
+}
+
+func nullString(s string) sql.NullString {
+	return sql.NullString{String: s, Valid: s != ""}
+}
+
+// normalize trims every field and upper-cases the code.
+func (c *Course) normalize() {
+	c.Code = strings.ToUpper(strings.TrimSpace(c.Code))
+	c.Name = strings.TrimSpace(c.Name)
+	c.Language = strings.TrimSpace(c.Language)
+	c.GradingScale = strings.TrimSpace(c.GradingScale)
+	c.Organiser = strings.TrimSpace(c.Organiser)
+	c.LearningOutcomes = strings.TrimSpace(c.LearningOutcomes)
+	c.Prerequisites = strings.TrimSpace(c.Prerequisites)
+	c.TeacherName = strings.TrimSpace(c.TeacherName)
+	c.TeacherEmail = strings.TrimSpace(c.TeacherEmail)
+	c.CourseLink = strings.TrimSpace(c.CourseLink)
+	c.Credits = Value(strings.TrimSpace(string(c.Credits)))
+	c.Level = strings.TrimSpace(c.Level)
+	c.TeachingPeriods = Value(strings.TrimSpace(string(c.TeachingPeriods)))
+	c.Modality = strings.TrimSpace(c.Modality)
+	c.Campus = strings.TrimSpace(c.Campus)
+}
+
+// normalizeDetails rewrites credits, level, teaching periods and modality in
+// their canonical form ("5", "master", "1,2", "online").
+func (c *Course) normalizeDetails() error {
+	credits, err := ParseCredits(string(c.Credits))
+	if err != nil {
+		return err
+	}
+	c.Credits = ""
+	if credits > 0 {
+		c.Credits = Value(strconv.FormatFloat(credits, 'f', -1, 64))
+	}
+	if c.Level, err = NormalizeLevel(c.Level); err != nil {
+		return err
+	}
+	periods, err := ParsePeriods(string(c.TeachingPeriods))
+	if err != nil {
+		return err
+	}
+	c.TeachingPeriods = Value(FormatPeriods(periods))
+	c.Modality, err = NormalizeModality(c.Modality)
+	return err
+}
+
+// FormatFromPath picks the format from a file extension.
+func FormatFromPath(path string) (Format, error) {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".csv":
+		return FormatCSV, nil
+	case ".json":
+		return FormatJSON, nil
+	case ".yaml", ".yml":
+		return FormatYAML, nil
+	}
+	return "", fmt.Errorf("%s: unknown catalogue format; use .csv, .json, .yaml or set the format", path)
+}
+
+// ParseFile reads a catalogue export; an empty format is taken from the
+// file extension.
+func ParseFile(path string, format Format) ([]Course, error) {
+	if format == "" {
+		var err error
+		if format, err = FormatFromPath(path); err != nil {
+			return nil, err
+		}
+	}
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	courses, err := Parse(f, format)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return courses, nil
+}
+
+// Parse reads catalogue entries, normalises them and checks that every entry
+// has a code and a name and that no code appears twice.
+func Parse(r io.Reader, format Format) ([]Course, error) {
+	var (
+		courses []Course
+		err     error
+	)
+	switch format {
+	case FormatCSV:
+		courses, err = parseCSV(r)
+	case FormatJSON:
+		courses, err = parseJSON(r)
+	case FormatYAML:
+		courses, err = parseYAML(r)
+	default:
+		return nil, fmt.Errorf("unsupported catalogue format %q", format)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return courses, Validate(courses)
+}
+
+// Validate normalises entries in place and reports missing fields, invalid
+// course details and duplicate codes, naming the entries by position
+// (1-based).
+func Validate(courses []Course) error {
+	var problems []string
+	seen := make(map[string]int, len(courses))
+	for i := range courses {
+		c := &courses[i]
+		c.normalize()
+		switch {
+		case c.Code == "":
+			problems = append(problems, fmt.Sprintf("entry %d: missing code", i+1))
+		case c.Name == "":
+			problems = append(problems, fmt.Sprintf("entry %d (%s): missing name", i+1, c.Code))
+		}
+		if err := c.normalizeDetails(); err != nil {
+			problems = append(problems, fmt.Sprintf("entry %d (%s): %v", i+1, c.Code, err))
+		}
+		if c.Code == "" {
+			continue
+		}
+		if first, ok := seen[c.Code]; ok {
+			problems = append(problems, fmt.Sprintf("entry %d: duplicate code %s (first at entry %d)", i+1, c.Code, first))
+			continue
+		}
+		seen[c.Code] = i + 1
+	}
+	if len(problems) > 0 {
+		return errors.New(strings.Join(problems, "; "))
+	}
+	return nil
+}
+
+// -----------------------------------------------------------------------------
+// FORMATS
+// -----------------------------------------------------------------------------
+
+// csvColumns maps normalised CSV headers to a setter on Course
+var csvColumns = map[string]func(c *Course, v string){
+	"code":              func(c *Course, v string) { c.Code = v },
+	"name":              func(c *Course, v string) { c.Name = v },
+	"language":          func(c *Course, v string) { c.Language = v },
+	"grading_scale":     func(c *Course, v string) { c.GradingScale = v },
+	"organiser":         func(c *Course, v string) { c.Organiser = v },
+	"organizer":         func(c *Course, v string) { c.Organiser = v },
+	"learning_outcomes": func(c *Course, v string) { c.LearningOutcomes = v },
+	"prerequisites":     func(c *Course, v string) { c.Prerequisites = v },
+	"teacher_name":      func(c *Course, v string) { c.TeacherName = v },
+	"teacher_email":     func(c *Course, v string) { c.TeacherEmail = v },
+	"course_link":       func(c *Course, v string) { c.CourseLink = v },
+	"credits":           func(c *Course, v string) { c.Credits = Value(v) },
+	"ects":              func(c *Course, v string) { c.Credits = Value(v) },
+	"level":             func(c *Course, v string) { c.Level = v },
+	"teaching_periods":  func(c *Course, v string) { c.TeachingPeriods = Value(v) },
+	"periods":           func(c *Course, v string) { c.TeachingPeriods = Value(v) },
+	"modality":          func(c *Course, v string) { c.Modality = v },
+	"campus":            func(c *Course, v string) { c.Campus = v },
+}
+
+// parseCSV reads a CSV export with a header row. The delimiter (comma,
+// semicolon or tab, as spreadsheet exports vary by locale) is taken from the
+// header; columns that are not course fields are ignored.
+func parseCSV(r io.Reader) ([]Course, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	data = bytes.TrimPrefix(data, []byte("\xef\xbb\xbf")) // Excel BOM
+
+	header, _, _ := bytes.Cut(data, []byte("\n"))
+	cr := csv.NewReader(bytes.NewReader(data))
+	cr.Comma = sniffDelimiter(string(header))
+	cr.FieldsPerRecord = -1
+
+	rows, err := cr.ReadAll()
+	if err != nil {
+		return nil, err
+	}
+	if len(rows) == 0 {
+		return nil, errors.New("empty CSV file")
+	}
+
+	setters := make([]func(c *Course, v string), len(rows[0]))
+	found := map[string]bool{}
+	for i, h := range rows[0] {
+		key := strings.ToLower(strings.TrimSpace(h))
+		key = strings.NewReplacer(" ", "_", "-", "_").Replace(key)
+		if set, ok := csvColumns[key]; ok {
+			setters[i] = set
+			found[key] = true
+		}
+	}
+	if !found["code"] || !found["name"] {
+		return nil, errors.New("CSV header must include code and name columns")
+	}
+
+	courses := make([]Course, 0, len(rows)-1)
+	for _, row := range rows[1:] {
+		if len(row) == 1 && strings.TrimSpace(row[0]) == "" {
+			continue
+		}
+		var c Course
+		for i, v := range row {
+			if i < len(setters) && setters[i] != nil {
+				setters[i](&c, v)
+			}
+		}
+		courses = append(courses, c)
+	}
+	return courses, nil
+}
+
+func sniffDelimiter(header string) rune {
+	best, bestCount := ',', strings.Count(header, ",")
+	for _, d := range []rune{';', '\t'} {
+		if n := strings.Count(header, string(d)); n > bestCount {
+			best, bestCount = d, n
+		}
+	}
+	return best
+}
+
+// catalogueDocument is the object form of JSON and YAML exports,
+// {"courses": [...]}; a bare list is accepted too.
+type catalogueDocument struct {
+	Courses []Course `json:"courses" yaml:"courses"`
+}
+
+func parseJSON(r io.Reader) ([]Course, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	data = bytes.TrimSpace(data)
+	if bytes.HasPrefix(data, []byte("[")) {
+		var courses []Course
+		err := json.Unmarshal(data, &courses)
+		return courses, err
+	}
+	var doc catalogueDocument
+	err = json.Unmarshal(data, &doc)
+	return doc.Courses, err
+}
+
+func parseYAML(r io.Reader) ([]Course, error) {
+	var node yaml.Node
+	if err := yaml.NewDecoder(r).Decode(&node); err != nil {
+		if errors.Is(err, io.EOF) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	if len(node.Content) > 0 && node.Content[0].Kind == yaml.SequenceNode {
+		var courses []Course
+		err := node.Decode(&courses)
+		return courses, err
+	}
+	var doc catalogueDocument
+	err := node.Decode(&doc)
+	return doc.Courses, err
+}
@@ -0,0 +1,176 @@
+// server/catalog/catalog_test.go
+
+package catalog
+
+import (
+	"database/sql"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParse(t *testing.T) {
+	want := []Course{
+		{Code: "TJTS5012", Name: "Additional Research Methods Module", Language: "English, Finnish", Organiser: "Faculty of Information Technology"},
+		{Code: "TIES454", Name: "Agent Technologies for Developers", TeacherName: "Bekir Afsar"},
+	}
+
+	testCases := []struct {
+		name   string
+		format Format
+		input  string
+	}{
+		{
+			name:   "CSV",
+			format: FormatCSV,
+			input: "code,name,language,organiser,teacher_name\n" +
+				`tjts5012,Additional Research Methods Module,"English, Finnish",Faculty of Information Technology,` + "\n" +
+				"TIES454, Agent Technologies for Developers ,,,Bekir Afsar\n",
+		},
+		{
+			name:   "CSVSemicolonWithBOM",
+			format: FormatCSV,
+			input: "\xef\xbb\xbfCode;Name;Language;Organizer;Teacher Name;Notes\n" +
+				"TJTS5012;Additional Research Methods Module;English, Finnish;Faculty of Information Technology;;Sisu\n" +
+				"TIES454;Agent Technologies for Developers;;;Bekir Afsar;Sisu\n\n",
+		},
+		{
+			name:   "JSONList",
+			format: FormatJSON,
+			input: `[
+				{"code": "TJTS5012", "name": "Additional Research Methods Module", "language": "English, Finnish", "organiser": "Faculty of Information Technology"},
+				{"code": "TIES454", "name": "Agent Technologies for Developers", "teacher_name": "Bekir Afsar"}
+			]`,
+		},
+		{
+			name:   "JSONDocument",
+			format: FormatJSON,
+			input: `{"courses": [
+				{"code": "TJTS5012", "name": "Additional Research Methods Module", "language": "English, Finnish", "organiser": "Faculty of Information Technology"},
+				{"code": "TIES454", "name": "Agent Technologies for Developers", "teacher_name": "Bekir Afsar"}
+			]}`,
+		},
+		{
+			name:   "YAMLList",
+			format: FormatYAML,
+			input: `
+- code: TJTS5012
+  name: Additional Research Methods Module
+  language: English, Finnish
+  organiser: Faculty of Information Technology
+- code: TIES454
+  name: Agent Technologies for Developers
+  teacher_name: Bekir Afsar
+`,
+		},
+		{
+			name:   "YAMLDocument",
+			format: FormatYAML,
+			input: `
+courses:
+  - code: TJTS5012
+    name: Additional Research Methods Module
+    language: English, Finnish
+    organiser: Faculty of Information Technology
+  - code: TIES454
+    name: Agent Technologies for Developers
+    teacher_name: Bekir Afsar
+`,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := Parse(strings.NewReader(tc.input), tc.format)
+			require.NoError(t, err)
+			require.Equal(t, want, got)
+		})
+	}
+}
+
+func TestParseCourseDetails(t *testing.T) {
+	want := []Course{
+		{Code: "TIES454", Name: "Agents", Credits: "5", Level: LevelMaster, TeachingPeriods: "1,2", Modality: ModalityOnline, Campus: "Mattilanniemi"},
+		{Code: "TJTS5012", Name: "Research Methods", Credits: "2.5", Level: LevelBachelor, TeachingPeriods: "4,5", Modality: ModalityHybrid},
+	}
+
+	testCases := []struct {
+		name   string
+		format Format
+		input  string
+	}{
+		{
+			name:   "CSV",
+			format: FormatCSV,
+			input: "code;name;ECTS;level;periods;modality;campus\n" +
+				"TIES454;Agents;5 cr;Master's;1-2;Remote;Mattilanniemi\n" +
+				"TJTS5012;Research Methods;2,5 op;intermediate studies;4 and summer;blended;\n",
+		},
+		{
+			name:   "JSON",
+			format: FormatJSON,
+			input: `[
+				{"code": "TIES454", "name": "Agents", "credits": 5, "level": "master", "teaching_periods": [2, 1], "modality": "online", "campus": "Mattilanniemi"},
+				{"code": "TJTS5012", "name": "Research Methods", "credits": "2.5", "level": "Bachelor", "teaching_periods": "4, 5", "modality": "hybrid"}
+			]`,
+		},
+		{
+			name:   "YAML",
+			format: FormatYAML,
+			input: `
+- {code: TIES454, name: Agents, credits: 5, level: advanced, teaching_periods: [1, 2], modality: online, campus: Mattilanniemi}
+- {code: TJTS5012, name: Research Methods, credits: 2.5, level: bachelor, teaching_periods: 4-5, modality: Hybrid}
+`,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := Parse(strings.NewReader(tc.input), tc.format)
+			require.NoError(t, err)
+			require.Equal(t, want, got)
+		})
+	}
+
+	_, err := Parse(strings.NewReader("code,name,credits,level,periods,modality\nTIES454,Agents,five,licentiate,6,carrier pigeon\n"), FormatCSV)
+	require.EqualError(t, err, `entry 1 (TIES454): invalid credits "five"`)
+	_, err = Parse(strings.NewReader("code,name,periods\nTIES454,Agents,6\n"), FormatCSV)
+	require.EqualError(t, err, "entry 1 (TIES454): teaching period 6 is not between 1 and 5")
+
+	credits, err := ParseCredits("3–5 ECTS")
+	require.NoError(t, err)
+	require.Equal(t, 5.0, credits)
+}
+
+func TestParseInvalid(t *testing.T) {
+	_, err := Parse(strings.NewReader("code,name\nTIES454,Agents\n,No code\nties454,Again\nTJTS5012,\n"), FormatCSV)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "entry 2: missing code")
+	require.Contains(t, err.Error(), "entry 3: duplicate code TIES454 (first at entry 1)")
+	require.Contains(t, err.Error(), "entry 4 (TJTS5012): missing name")
+
+	_, err = Parse(strings.NewReader("code,title\nTIES454,Agents\n"), FormatCSV)
+	require.EqualError(t, err, "CSV header must include code and name columns")
+
+	_, err = FormatFromPath("courses.xlsx")
+	require.Error(t, err)
+}
+
+func TestCourseParams(t *testing.T) {
+	p := Course{Code: "TIES454", Name: "Agents", Language: "English"}.Params()
+	require.Equal(t, sql.NullString{String: "English", Valid: true}, p.Language)
+	require.False(t, p.Prerequisites.Valid)
+	require.False(t, p.Credits.Valid)
+	require.Equal(t, []int32{}, p.TeachingPeriods)
+
+	p = Course{Code: "TIES454", Name: "Agents", Credits: "5", TeachingPeriods: "1,2"}.Params()
+	require.Equal(t, sql.NullFloat64{Float64: 5, Valid: true}, p.Credits)
+	require.Equal(t, []int32{1, 2}, p.TeachingPeriods)
+}
+
+func TestSeedCatalogue(t *testing.T) {
+	courses, err := ParseFile("../db/seed/courses.json", "")
+	require.NoError(t, err)
+	require.NotEmpty(t, courses)
+}
@@ -0,0 +1,234 @@
+// server/catalog/details.go
+
+package catalog
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+
+	"go.yaml.in/yaml/v3"
+)
+
+// Study levels
+const (
+	LevelBachelor = "bachelor"
+	LevelMaster   = "master"
+	LevelDoctoral = "doctoral"
+)
+
+// Teaching modalities
+const (
+	ModalityOnCampus = "on_campus"
+	ModalityOnline   = "online"
+	ModalityHybrid   = "hybrid"
+)
+
+// MaxPeriod is the last teaching period of the year; JYU teaches in periods
+// 1-4 and 5 is the summer.
+const MaxPeriod = 5
+
+// levelAliases maps the ways exports write study levels, in English and
+// Finnish, to a level. Basic and intermediate studies are bachelor level,
+// advanced studies master level.
+var levelAliases = map[string]string{
+	"bachelor":             LevelBachelor,
+	"bachelors":            LevelBachelor,
+	"bachelor's":           LevelBachelor,
+	"bsc":                  LevelBachelor,
+	"basic":                LevelBachelor,
+	"basic studies":        LevelBachelor,
+	"intermediate":         LevelBachelor,
+	"intermediate studies": LevelBachelor,
+	"kandidaatti":          LevelBachelor,
+	"perusopinnot":         LevelBachelor,
+	"aineopinnot":          LevelBachelor,
+	"master":               LevelMaster,
+	"masters":              LevelMaster,
+	"master's":             LevelMaster,
+	"msc":                  LevelMaster,
+	"advanced":             LevelMaster,
+	"advanced studies":     LevelMaster,
+	"maisteri":             LevelMaster,
+	"syventävät opinnot":   LevelMaster,
+	"doctoral":             LevelDoctoral,
+	"doctoral studies":     LevelDoctoral,
+	"phd":                  LevelDoctoral,
+	"postgraduate":         LevelDoctoral,
+	"jatko-opinnot":        LevelDoctoral,
+}
+
+var modalityAliases = map[string]string{
+	"on_campus":         ModalityOnCampus,
+	"on campus":         ModalityOnCampus,
+	"on-campus":         ModalityOnCampus,
+	"campus":            ModalityOnCampus,
+	"contact":           ModalityOnCampus,
+	"contact teaching":  ModalityOnCampus,
+	"in person":         ModalityOnCampus,
+	"lähiopetus":        ModalityOnCampus,
+	"online":            ModalityOnline,
+	"remote":            ModalityOnline,
+	"distance":          ModalityOnline,
+	"distance learning": ModalityOnline,
+	"etäopetus":         ModalityOnline,
+	"verkko-opetus":     ModalityOnline,
+	"hybrid":            ModalityHybrid,
+	"blended":           ModalityHybrid,
+	"monimuoto":         ModalityHybrid,
+	"monimuoto-opetus":  ModalityHybrid,
+}
+
+// NormalizeLevel returns the study level for s ("Master's" -> "master"); an
+// empty s stays empty.
+func NormalizeLevel(s string) (string, error) {
+	return lookupAlias(levelAliases, s, "level", "bachelor, master or doctoral")
+}
+
+// NormalizeModality returns the teaching modality for s ("Contact teaching"
+// -> "on_campus"); an empty s stays empty.
+func NormalizeModality(s string) (string, error) {
+	return lookupAlias(modalityAliases, s, "modality", "on_campus, online or hybrid")
+}
+
+func lookupAlias(aliases map[string]string, s, field, want string) (string, error) {
+	key := strings.Join(strings.Fields(strings.ToLower(s)), " ")
+	if key == "" {
+		return "", nil
+	}
+	if v, ok := aliases[key]; ok {
+		return v, nil
+	}
+	return "", fmt.Errorf("unknown %s %q; use %s", field, s, want)
+}
+
+var creditsPattern = regexp.MustCompile(`^(\d+(?:[.,]\d+)?)(?:\s*[-–]\s*(\d+(?:[.,]\d+)?))?\s*(?:cr|op|ects|credits|opintopistettä)?$`)
+
+// ParseCredits reads a course size such as "5", "5 cr", "2,5 op" or a range
+// "3–5 ECTS", which counts as its upper bound. An empty s is 0.
+func ParseCredits(s string) (float64, error) {
+	s = strings.ToLower(strings.TrimSpace(s))
+	if s == "" {
+		return 0, nil
+	}
+	m := creditsPattern.FindStringSubmatch(s)
+	if m == nil {
+		return 0, fmt.Errorf("invalid credits %q", s)
+	}
+	last := m[1]
+	if m[2] != "" {
+		last = m[2]
+	}
+	credits, err := strconv.ParseFloat(strings.Replace(last, ",", ".", 1), 64)
+	if err != nil || credits <= 0 {
+		return 0, fmt.Errorf("invalid credits %q", s)
+	}
+	return credits, nil
+}
+
+// ParsePeriods reads teaching periods such as "1, 2", "1-3", "period 2" or
+// "4 and summer". The result is sorted and never nil.
+func ParsePeriods(s string) ([]int32, error) {
+	fields := strings.FieldsFunc(strings.ToLower(s), func(r rune) bool {
+		return r == ',' || r == ';' || r == '/' || r == ' '
+	})
+	periods := []int32{}
+	for _, f := range fields {
+		switch f {
+		case "period", "periods", "periodi", "periodit", "and", "ja":
+			continue
+		case "summer", "kesä":
+			periods = append(periods, MaxPeriod)
+			continue
+		}
+		from, to, isRange := strings.Cut(strings.ReplaceAll(f, "–", "-"), "-")
+		if !isRange {
+			to = from
+		}
+		first, err1 := strconv.Atoi(from)
+		last, err2 := strconv.Atoi(to)
+		if err1 != nil || err2 != nil || first > last {
+			return nil, fmt.Errorf("invalid teaching periods %q", s)
+		}
+		for p := first; p <= last; p++ {
+			periods = append(periods, int32(p))
+		}
+	}
+	return NormalizePeriods(periods)
+}
+
+// NormalizePeriods sorts teaching periods, drops duplicates and checks that
+// each is between 1 and MaxPeriod.
+func NormalizePeriods(periods []int32) ([]int32, error) {
+	out := make([]int32, 0, len(periods))
+	for _, p := range periods {
+		if p < 1 || p > MaxPeriod {
+			return nil, fmt.Errorf("teaching period %d is not between 1 and %d", p, MaxPeriod)
+		}
+		out = append(out, p)
+	}
+	slices.Sort(out)
+	return slices.Compact(out), nil
+}
+
+// FormatPeriods writes teaching periods as "1,2".
+func FormatPeriods(periods []int32) string {
+	parts := make([]string, len(periods))
+	for i, p := range periods {
+		parts[i] = strconv.Itoa(int(p))
+	}
+	return strings.Join(parts, ",")
+}
+
+// Value is a catalogue field that exports write as text, a number or a list,
+// e.g. credits 5 or "5 cr" and teaching periods [1, 2] or "1-2". It keeps the
+// text form; Validate checks and normalises it.
+type Value string
+
+// UnmarshalJSON accepts a string, a number, null or a list of those.
+func (v *Value) UnmarshalJSON(data []byte) error {
+	var raw any
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+	items, ok := raw.([]any)
+	if !ok {
+		items = []any{raw}
+	}
+	parts := make([]string, 0, len(items))
+	for _, item := range items {
+		switch x := item.(type) {
+		case nil:
+		case string:
+			parts = append(parts, x)
+		case float64:
+			parts = append(parts, strconv.FormatFloat(x, 'f', -1, 64))
+		default:
+			return fmt.Errorf("unexpected value %s", data)
+		}
+	}
+	*v = Value(strings.Join(parts, ","))
+	return nil
+}
+
+// UnmarshalYAML accepts a scalar or a list of scalars.
+func (v *Value) UnmarshalYAML(node *yaml.Node) error {
+	items := []*yaml.Node{node}
+	if node.Kind == yaml.SequenceNode {
+		items = node.Content
+	}
+	parts := make([]string, 0, len(items))
+	for _, item := range items {
+		if item.Kind != yaml.ScalarNode {
+			return fmt.Errorf("line %d: unexpected value", item.Line)
+		}
+		if item.Tag != "!!null" {
+			parts = append(parts, item.Value)
+		}
+	}
+	*v = Value(strings.Join(parts, ","))
+	return nil
+}
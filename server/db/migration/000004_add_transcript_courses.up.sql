@@ -0,0 +1,16 @@
+-- db/migration/000004_add_transcript_courses.up.sql
+-- Structured per-course records parsed once from an uploaded transcript.
+CREATE TABLE transcript_courses (
+  id BIGSERIAL PRIMARY KEY,
+  transcript_id BIGINT NOT NULL REFERENCES transcripts(id) ON DELETE CASCADE,
+  code VARCHAR NOT NULL,
+  name VARCHAR,
+  credits DOUBLE PRECISION,
+  grade VARCHAR,
+  completed_on DATE,
+  status VARCHAR NOT NULL DEFAULT 'completed', -- completed | failed | in_progress
+  created_at TIMESTAMP NOT NULL DEFAULT now()
+);
+
+CREATE INDEX ON transcript_courses (transcript_id);
+CREATE INDEX ON transcript_courses (code);
@@ -0,0 +1,3 @@
+-- db/migration/000005_add_transcript_jobs.down.sql
+
+DROP TABLE IF EXISTS transcript_jobs;
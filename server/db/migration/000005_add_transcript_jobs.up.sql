@@ -0,0 +1,16 @@
+-- db/migration/000005_add_transcript_jobs.up.sql
+-- Background ingestion jobs (text extraction + OCR) for uploaded transcripts.
+CREATE TABLE transcript_jobs (
+  id BIGSERIAL PRIMARY KEY,
+  transcript_id BIGINT NOT NULL REFERENCES transcripts(id) ON DELETE CASCADE,
+  status VARCHAR NOT NULL DEFAULT 'queued', -- queued | extracting | ocr | done | failed
+  pages_total INT NOT NULL DEFAULT 0,
+  pages_done INT NOT NULL DEFAULT 0,
+  attempts INT NOT NULL DEFAULT 0,
+  error TEXT,
+  created_at TIMESTAMP NOT NULL DEFAULT now(),
+  updated_at TIMESTAMP NOT NULL DEFAULT now()
+);
+
+CREATE INDEX ON transcript_jobs (transcript_id);
+CREATE INDEX ON transcript_jobs (status);
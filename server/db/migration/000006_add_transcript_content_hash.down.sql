@@ -0,0 +1,4 @@
+-- db/migration/000006_add_transcript_content_hash.down.sql
+
+DROP INDEX IF EXISTS transcripts_user_content_sha256_idx;
+ALTER TABLE transcripts DROP COLUMN IF EXISTS content_sha256;
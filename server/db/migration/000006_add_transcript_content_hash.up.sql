@@ -0,0 +1,5 @@
+-- db/migration/000006_add_transcript_content_hash.up.sql
+-- SHA-256 of the uploaded file, so identical re-uploads return the existing transcript.
+ALTER TABLE transcripts ADD COLUMN content_sha256 VARCHAR;
+
+CREATE UNIQUE INDEX transcripts_user_content_sha256_idx ON transcripts (user_username, content_sha256);
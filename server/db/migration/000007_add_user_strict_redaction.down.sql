@@ -0,0 +1,3 @@
+-- db/migration/000007_add_user_strict_redaction.down.sql
+
+ALTER TABLE users DROP COLUMN IF EXISTS strict_redaction;
@@ -0,0 +1,3 @@
+-- db/migration/000007_add_user_strict_redaction.up.sql
+-- Students can opt into stricter masking of personal data sent to the AI provider.
+ALTER TABLE users ADD COLUMN strict_redaction BOOLEAN NOT NULL DEFAULT false;
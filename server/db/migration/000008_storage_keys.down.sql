@@ -0,0 +1,4 @@
+-- db/migration/000008_storage_keys.down.sql
+
+ALTER TABLE summaries RENAME COLUMN pdf_key TO pdf_path;
+ALTER TABLE transcripts RENAME COLUMN file_key TO file_path;
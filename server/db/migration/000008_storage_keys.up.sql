@@ -0,0 +1,16 @@
+-- db/migration/000008_storage_keys.up.sql
+-- Files live in a blob store (local directory or S3-compatible bucket); rows keep
+-- storage keys such as "uploads/alice/1700000000_transcript.pdf" instead of host paths.
+ALTER TABLE transcripts RENAME COLUMN file_path TO file_key;
+ALTER TABLE summaries RENAME COLUMN pdf_path TO pdf_key;
+
+-- Existing files were written below the working directory ("./uploads/x.pdf",
+-- "/app/uploads/x.pdf"); keep the part relative to it, which is the key under
+-- the default STORAGE_LOCAL_DIR.
+UPDATE transcripts
+SET file_key = substring(file_key from '(uploads/[^/]+)$')
+WHERE file_key ~ '(^|/)uploads/[^/]+$';
+
+UPDATE summaries
+SET pdf_key = substring(pdf_key from '(summaries/[^/]+)$')
+WHERE pdf_key ~ '(^|/)summaries/[^/]+$';
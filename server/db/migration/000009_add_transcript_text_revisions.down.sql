@@ -0,0 +1,4 @@
+-- db/migration/000009_add_transcript_text_revisions.down.sql
+
+ALTER TABLE recommendations DROP COLUMN IF EXISTS stale;
+DROP TABLE IF EXISTS transcript_text_revisions;
@@ -0,0 +1,15 @@
+-- db/migration/000009_add_transcript_text_revisions.up.sql
+-- Students can correct the extracted text of a transcript. Every version is kept:
+-- revision 1 is the text the extractors produced, later ones are manual edits.
+CREATE TABLE transcript_text_revisions (
+  id BIGSERIAL PRIMARY KEY,
+  transcript_id BIGINT NOT NULL REFERENCES transcripts(id) ON DELETE CASCADE,
+  revision INT NOT NULL,
+  text TEXT NOT NULL,
+  source VARCHAR NOT NULL,          -- extracted | manual
+  created_at TIMESTAMP NOT NULL DEFAULT now(),
+  UNIQUE (transcript_id, revision)
+);
+
+-- Recommendations made from text that has since been corrected
+ALTER TABLE recommendations ADD COLUMN stale BOOLEAN NOT NULL DEFAULT false;
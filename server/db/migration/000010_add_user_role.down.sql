@@ -0,0 +1,3 @@
+-- db/migration/000010_add_user_role.down.sql
+
+ALTER TABLE users DROP COLUMN IF EXISTS role;
@@ -0,0 +1,4 @@
+-- db/migration/000010_add_user_role.up.sql
+-- Staff with the admin role maintain the course catalogue through the API.
+ALTER TABLE users ADD COLUMN role VARCHAR NOT NULL DEFAULT 'student'
+  CHECK (role IN ('student', 'admin'));
@@ -0,0 +1,6 @@
+-- db/migration/000011_add_course_search.down.sql
+
+DROP INDEX IF EXISTS courses_name_trgm_idx;
+DROP INDEX IF EXISTS courses_code_trgm_idx;
+DROP INDEX IF EXISTS courses_search_idx;
+DROP FUNCTION IF EXISTS course_search_document(TEXT, TEXT, TEXT);
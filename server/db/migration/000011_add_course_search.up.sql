@@ -0,0 +1,24 @@
+-- db/migration/000011_add_course_search.up.sql
+-- Full-text search over the catalogue (English + Finnish) and trigram matching
+-- for misspelled or partial course codes and names.
+CREATE EXTENSION IF NOT EXISTS pg_trgm;
+
+-- Weighted search document: name (A), learning outcomes (B), prerequisites (C).
+-- Immutable so it can back an expression index; queries must call it with the
+-- same arguments for the index to be used.
+CREATE FUNCTION course_search_document(name TEXT, learning_outcomes TEXT, prerequisites TEXT)
+RETURNS tsvector
+LANGUAGE sql IMMUTABLE PARALLEL SAFE
+AS $$
+  SELECT setweight(to_tsvector('english'::regconfig, coalesce(name, '')), 'A')
+      || setweight(to_tsvector('finnish'::regconfig, coalesce(name, '')), 'A')
+      || setweight(to_tsvector('english'::regconfig, coalesce(learning_outcomes, '')), 'B')
+      || setweight(to_tsvector('finnish'::regconfig, coalesce(learning_outcomes, '')), 'B')
+      || setweight(to_tsvector('english'::regconfig, coalesce(prerequisites, '')), 'C')
+      || setweight(to_tsvector('finnish'::regconfig, coalesce(prerequisites, '')), 'C')
+$$;
+
+CREATE INDEX courses_search_idx ON courses
+  USING GIN (course_search_document(name, learning_outcomes, prerequisites));
+CREATE INDEX courses_code_trgm_idx ON courses USING GIN (code gin_trgm_ops);
+CREATE INDEX courses_name_trgm_idx ON courses USING GIN (name gin_trgm_ops);
@@ -0,0 +1,4 @@
+-- db/migration/000012_add_study_plans.down.sql
+
+ALTER TABLE summaries DROP COLUMN IF EXISTS study_plan_id;
+DROP TABLE IF EXISTS study_plans;
@@ -0,0 +1,18 @@
+-- db/migration/000012_add_study_plans.up.sql
+-- Semester-by-semester study plans. The plan itself (settings, semesters,
+-- unscheduled courses, warnings) is one JSON document, edited as a whole.
+CREATE TABLE study_plans (
+  id BIGSERIAL PRIMARY KEY,
+  user_username VARCHAR NOT NULL REFERENCES users(username) ON DELETE CASCADE,
+  transcript_id BIGINT REFERENCES transcripts(id) ON DELETE SET NULL, -- completed courses the plan starts from
+  title VARCHAR NOT NULL,
+  plan JSONB NOT NULL,
+  created_at TIMESTAMP NOT NULL DEFAULT now(),
+  updated_at TIMESTAMP NOT NULL DEFAULT now()
+);
+
+CREATE INDEX ON study_plans(user_username);
+
+-- Summary PDFs can be exported from a study plan instead of a recommendation
+ALTER TABLE summaries
+  ADD COLUMN study_plan_id BIGINT REFERENCES study_plans(id) ON DELETE CASCADE;
@@ -0,0 +1,8 @@
+-- db/migration/000013_add_course_details.down.sql
+
+ALTER TABLE courses
+  DROP COLUMN IF EXISTS campus,
+  DROP COLUMN IF EXISTS modality,
+  DROP COLUMN IF EXISTS teaching_periods,
+  DROP COLUMN IF EXISTS level,
+  DROP COLUMN IF EXISTS credits;
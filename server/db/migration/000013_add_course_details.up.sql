@@ -0,0 +1,10 @@
+-- db/migration/000013_add_course_details.up.sql
+-- What students filter on: size, study level, when and how a course is taught.
+-- JYU teaching periods are 1-4 plus 5 for the summer.
+ALTER TABLE courses
+  ADD COLUMN credits DOUBLE PRECISION CHECK (credits > 0),
+  ADD COLUMN level VARCHAR CHECK (level IN ('bachelor', 'master', 'doctoral')),
+  ADD COLUMN teaching_periods INTEGER[] NOT NULL DEFAULT '{}'
+    CHECK (teaching_periods <@ ARRAY[1, 2, 3, 4, 5]),
+  ADD COLUMN modality VARCHAR CHECK (modality IN ('on_campus', 'online', 'hybrid')),
+  ADD COLUMN campus VARCHAR;
@@ -0,0 +1,11 @@
+-- db/migration/000014_add_course_embeddings.up.sql
+-- One embedding per course for semantic retrieval of recommendation
+-- candidates. Similarity is computed in the API, so no vector extension is
+-- needed; content_hash tells when the course text changed since embedding.
+CREATE TABLE course_embeddings (
+  course_id BIGINT PRIMARY KEY REFERENCES courses(id) ON DELETE CASCADE,
+  model VARCHAR NOT NULL,
+  content_hash VARCHAR NOT NULL,
+  embedding REAL[] NOT NULL,
+  updated_at TIMESTAMP NOT NULL DEFAULT now()
+);
@@ -0,0 +1,3 @@
+-- db/migration/000015_add_recommendation_provenance.down.sql
+
+DROP TABLE IF EXISTS recommendation_provenance;
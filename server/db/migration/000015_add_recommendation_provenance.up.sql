@@ -0,0 +1,21 @@
+-- db/migration/000015_add_recommendation_provenance.up.sql
+-- How each recommendation was produced, for debugging bad advice: the ranker
+-- (model or local BM25), prompt version, a hash of everything that went in,
+-- and what the run cost.
+CREATE TABLE recommendation_provenance (
+  recommendation_id BIGINT PRIMARY KEY REFERENCES recommendations(id) ON DELETE CASCADE,
+  mode VARCHAR NOT NULL,                  -- ai | local
+  model VARCHAR NOT NULL,                 -- e.g. gpt-4o-mini, bm25
+  prompt_version VARCHAR NOT NULL,
+  input_hash VARCHAR NOT NULL,            -- SHA-256 of preference, transcript, filters and candidates
+  candidate_count INT NOT NULL,           -- courses offered to the ranker
+  completed_codes TEXT[] NOT NULL DEFAULT '{}',
+  embedding_model VARCHAR,                -- set when candidates were narrowed by embeddings
+  fallback_reason TEXT,
+  attempts INT NOT NULL DEFAULT 0,        -- model calls, including retries
+  prompt_tokens INT NOT NULL DEFAULT 0,
+  completion_tokens INT NOT NULL DEFAULT 0,
+  total_tokens INT NOT NULL DEFAULT 0,
+  latency_ms BIGINT NOT NULL,
+  created_at TIMESTAMP NOT NULL DEFAULT now()
+);
@@ -0,0 +1,3 @@
+-- db/migration/000016_add_course_feedback.down.sql
+
+DROP TABLE IF EXISTS course_feedback;
@@ -0,0 +1,14 @@
+-- db/migration/000016_add_course_feedback.up.sql
+-- What a student thinks of a recommended course. The latest feedback per
+-- course counts: dismissed courses (dislike, already_taken, not_interested)
+-- are no longer recommended, liked ones steer the ranking.
+CREATE TABLE course_feedback (
+  user_username VARCHAR NOT NULL REFERENCES users(username) ON DELETE CASCADE,
+  course_id BIGINT NOT NULL REFERENCES courses(id) ON DELETE CASCADE,
+  recommendation_id BIGINT REFERENCES recommendations(id) ON DELETE SET NULL,
+  kind VARCHAR NOT NULL CHECK (kind IN ('like', 'dislike', 'already_taken', 'not_interested')),
+  reason TEXT,
+  created_at TIMESTAMP NOT NULL DEFAULT now(),
+  updated_at TIMESTAMP NOT NULL DEFAULT now(),
+  PRIMARY KEY (user_username, course_id)
+);
@@ -0,0 +1,6 @@
+-- db/migration/000017_version_recommendation_payloads.down.sql
+
+ALTER TABLE recommendations DROP CONSTRAINT IF EXISTS recommendations_payload_schema;
+
+UPDATE recommendations
+SET payload = payload - 'schema_version';
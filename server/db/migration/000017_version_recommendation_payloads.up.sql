@@ -0,0 +1,30 @@
+-- db/migration/000017_version_recommendation_payloads.up.sql
+-- Recommendation payloads carry a schema_version. Rows written before it
+-- have the version 1 fields already; they are tidied up so every row
+-- decodes, then the shape is enforced on write.
+
+-- Not an object: nothing in it can be read
+UPDATE recommendations
+SET payload = '{"courses": []}'
+WHERE jsonb_typeof(payload) <> 'object';
+
+-- Missing or null courses become an empty list
+UPDATE recommendations
+SET payload = jsonb_set(payload, '{courses}', '[]')
+WHERE jsonb_typeof(payload->'courses') IS DISTINCT FROM 'array';
+
+-- Scholarships that are not a list are dropped; they are read from the
+-- scholarships table anyway
+UPDATE recommendations
+SET payload = payload - 'scholarships'
+WHERE payload ? 'scholarships' AND jsonb_typeof(payload->'scholarships') <> 'array';
+
+UPDATE recommendations
+SET payload = payload || '{"schema_version": 1}';
+
+ALTER TABLE recommendations
+  ADD CONSTRAINT recommendations_payload_schema CHECK (
+    jsonb_typeof(payload) = 'object'
+    AND jsonb_typeof(payload->'schema_version') = 'number'
+    AND jsonb_typeof(payload->'courses') = 'array'
+  );
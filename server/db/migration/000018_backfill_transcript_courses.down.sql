@@ -0,0 +1,3 @@
+-- db/migration/000018_backfill_transcript_courses.down.sql
+
+ALTER TABLE transcript_jobs DROP COLUMN IF EXISTS parse_only;
@@ -0,0 +1,18 @@
+-- db/migration/000018_backfill_transcript_courses.up.sql
+-- Transcripts uploaded before course parsing existed have text but no
+-- course rows. Queue a parse-only job for each, so the workers parse the
+-- stored text once instead of the first read doing it. Parse-only jobs
+-- never extract the file again: it may be gone, and OCR may read it
+-- differently this time.
+ALTER TABLE transcript_jobs ADD COLUMN parse_only BOOLEAN NOT NULL DEFAULT false;
+
+INSERT INTO transcript_jobs (transcript_id, parse_only)
+SELECT t.id, true
+FROM transcripts t
+WHERE t.text_extracted IS NOT NULL
+  AND NOT coalesce(t.meta ? 'course_parser', false)
+  AND NOT EXISTS (SELECT 1 FROM transcript_courses c WHERE c.transcript_id = t.id)
+  AND NOT EXISTS (
+    SELECT 1 FROM transcript_jobs j
+    WHERE j.transcript_id = t.id AND j.status IN ('queued', 'extracting', 'ocr')
+  );
@@ -0,0 +1,13 @@
+-- db/migration/000019_add_course_prerequisites.up.sql
+-- The parsed prerequisites of each course: requirement is the AND/OR graph
+-- of course codes (JSON null when the text names none) and source is the
+-- prerequisites text it was parsed from, so rows gone stale after an edit
+-- outside the API can be told apart. Existing courses are filled in by the
+-- server on start.
+CREATE TABLE course_prerequisites (
+  course_id BIGINT PRIMARY KEY REFERENCES courses(id) ON DELETE CASCADE,
+  source TEXT NOT NULL,
+  requirement JSONB NOT NULL,
+  advisory BOOLEAN NOT NULL DEFAULT false,
+  updated_at TIMESTAMP NOT NULL DEFAULT now()
+);
@@ -8,7 +8,9 @@ package mockdb
 
 import (
 	context "context"
+	sql "database/sql"
 	reflect "reflect"
+	time "time"
 
 	gomock "github.com/golang/mock/gomock"
 	db "github.com/nibir1/go-fiber-postgres-REST-boilerplate/db/sqlc"
@@ -37,6 +39,66 @@ func (m *MockStore) EXPECT() *MockStoreMockRecorder {
 	return m.recorder
 }
 
+// ClaimTranscriptJob mocks base method.
+func (m *MockStore) ClaimTranscriptJob(arg0 context.Context) (db.TranscriptJob, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ClaimTranscriptJob", arg0)
+	ret0, _ := ret[0].(db.TranscriptJob)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ClaimTranscriptJob indicates an expected call of ClaimTranscriptJob.
+func (mr *MockStoreMockRecorder) ClaimTranscriptJob(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ClaimTranscriptJob", reflect.TypeOf((*MockStore)(nil).ClaimTranscriptJob), arg0)
+}
+
+// CorrectTranscriptTextTx mocks base method.
+func (m *MockStore) CorrectTranscriptTextTx(arg0 context.Context, arg1 db.CorrectTranscriptTextTxParams) (db.CorrectTranscriptTextTxResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CorrectTranscriptTextTx", arg0, arg1)
+	ret0, _ := ret[0].(db.CorrectTranscriptTextTxResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CorrectTranscriptTextTx indicates an expected call of CorrectTranscriptTextTx.
+func (mr *MockStoreMockRecorder) CorrectTranscriptTextTx(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CorrectTranscriptTextTx", reflect.TypeOf((*MockStore)(nil).CorrectTranscriptTextTx), arg0, arg1)
+}
+
+// CountCourses mocks base method.
+func (m *MockStore) CountCourses(arg0 context.Context, arg1 db.CountCoursesParams) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CountCourses", arg0, arg1)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CountCourses indicates an expected call of CountCourses.
+func (mr *MockStoreMockRecorder) CountCourses(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CountCourses", reflect.TypeOf((*MockStore)(nil).CountCourses), arg0, arg1)
+}
+
+// CountTranscriptRecommendations mocks base method.
+func (m *MockStore) CountTranscriptRecommendations(arg0 context.Context, arg1 sql.NullInt64) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CountTranscriptRecommendations", arg0, arg1)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CountTranscriptRecommendations indicates an expected call of CountTranscriptRecommendations.
+func (mr *MockStoreMockRecorder) CountTranscriptRecommendations(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CountTranscriptRecommendations", reflect.TypeOf((*MockStore)(nil).CountTranscriptRecommendations), arg0, arg1)
+}
+
 // CreateCourse mocks base method.
 func (m *MockStore) CreateCourse(arg0 context.Context, arg1 db.CreateCourseParams) (db.Course, error) {
 	m.ctrl.T.Helper()
@@ -52,6 +114,21 @@ func (mr *MockStoreMockRecorder) CreateCourse(arg0, arg1 interface{}) *gomock.Ca
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateCourse", reflect.TypeOf((*MockStore)(nil).CreateCourse), arg0, arg1)
 }
 
+// CreateCourseTx mocks base method.
+func (m *MockStore) CreateCourseTx(arg0 context.Context, arg1 db.CreateCourseTxParams) (db.Course, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateCourseTx", arg0, arg1)
+	ret0, _ := ret[0].(db.Course)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateCourseTx indicates an expected call of CreateCourseTx.
+func (mr *MockStoreMockRecorder) CreateCourseTx(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateCourseTx", reflect.TypeOf((*MockStore)(nil).CreateCourseTx), arg0, arg1)
+}
+
 // CreateRecommendation mocks base method.
 func (m *MockStore) CreateRecommendation(arg0 context.Context, arg1 db.CreateRecommendationParams) (db.Recommendation, error) {
 	m.ctrl.T.Helper()
@@ -67,6 +144,36 @@ func (mr *MockStoreMockRecorder) CreateRecommendation(arg0, arg1 interface{}) *g
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateRecommendation", reflect.TypeOf((*MockStore)(nil).CreateRecommendation), arg0, arg1)
 }
 
+// CreateRecommendationProvenance mocks base method.
+func (m *MockStore) CreateRecommendationProvenance(arg0 context.Context, arg1 db.CreateRecommendationProvenanceParams) (db.RecommendationProvenance, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateRecommendationProvenance", arg0, arg1)
+	ret0, _ := ret[0].(db.RecommendationProvenance)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateRecommendationProvenance indicates an expected call of CreateRecommendationProvenance.
+func (mr *MockStoreMockRecorder) CreateRecommendationProvenance(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateRecommendationProvenance", reflect.TypeOf((*MockStore)(nil).CreateRecommendationProvenance), arg0, arg1)
+}
+
+// CreateRecommendationTx mocks base method.
+func (m *MockStore) CreateRecommendationTx(arg0 context.Context, arg1 db.CreateRecommendationTxParams) (db.CreateRecommendationTxResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateRecommendationTx", arg0, arg1)
+	ret0, _ := ret[0].(db.CreateRecommendationTxResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateRecommendationTx indicates an expected call of CreateRecommendationTx.
+func (mr *MockStoreMockRecorder) CreateRecommendationTx(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateRecommendationTx", reflect.TypeOf((*MockStore)(nil).CreateRecommendationTx), arg0, arg1)
+}
+
 // CreateScholarship mocks base method.
 func (m *MockStore) CreateScholarship(arg0 context.Context, arg1 db.CreateScholarshipParams) (db.Scholarship, error) {
 	m.ctrl.T.Helper()
@@ -82,6 +189,21 @@ func (mr *MockStoreMockRecorder) CreateScholarship(arg0, arg1 interface{}) *gomo
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateScholarship", reflect.TypeOf((*MockStore)(nil).CreateScholarship), arg0, arg1)
 }
 
+// CreateStudyPlan mocks base method.
+func (m *MockStore) CreateStudyPlan(arg0 context.Context, arg1 db.CreateStudyPlanParams) (db.StudyPlan, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateStudyPlan", arg0, arg1)
+	ret0, _ := ret[0].(db.StudyPlan)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateStudyPlan indicates an expected call of CreateStudyPlan.
+func (mr *MockStoreMockRecorder) CreateStudyPlan(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateStudyPlan", reflect.TypeOf((*MockStore)(nil).CreateStudyPlan), arg0, arg1)
+}
+
 // CreateSummary mocks base method.
 func (m *MockStore) CreateSummary(arg0 context.Context, arg1 db.CreateSummaryParams) (db.Summary, error) {
 	m.ctrl.T.Helper()
@@ -112,6 +234,66 @@ func (mr *MockStoreMockRecorder) CreateTranscript(arg0, arg1 interface{}) *gomoc
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateTranscript", reflect.TypeOf((*MockStore)(nil).CreateTranscript), arg0, arg1)
 }
 
+// CreateTranscriptCourse mocks base method.
+func (m *MockStore) CreateTranscriptCourse(arg0 context.Context, arg1 db.CreateTranscriptCourseParams) (db.TranscriptCourse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateTranscriptCourse", arg0, arg1)
+	ret0, _ := ret[0].(db.TranscriptCourse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateTranscriptCourse indicates an expected call of CreateTranscriptCourse.
+func (mr *MockStoreMockRecorder) CreateTranscriptCourse(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateTranscriptCourse", reflect.TypeOf((*MockStore)(nil).CreateTranscriptCourse), arg0, arg1)
+}
+
+// CreateTranscriptJob mocks base method.
+func (m *MockStore) CreateTranscriptJob(arg0 context.Context, arg1 int64) (db.TranscriptJob, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateTranscriptJob", arg0, arg1)
+	ret0, _ := ret[0].(db.TranscriptJob)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateTranscriptJob indicates an expected call of CreateTranscriptJob.
+func (mr *MockStoreMockRecorder) CreateTranscriptJob(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateTranscriptJob", reflect.TypeOf((*MockStore)(nil).CreateTranscriptJob), arg0, arg1)
+}
+
+// CreateTranscriptTextRevision mocks base method.
+func (m *MockStore) CreateTranscriptTextRevision(arg0 context.Context, arg1 db.CreateTranscriptTextRevisionParams) (db.TranscriptTextRevision, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateTranscriptTextRevision", arg0, arg1)
+	ret0, _ := ret[0].(db.TranscriptTextRevision)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateTranscriptTextRevision indicates an expected call of CreateTranscriptTextRevision.
+func (mr *MockStoreMockRecorder) CreateTranscriptTextRevision(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateTranscriptTextRevision", reflect.TypeOf((*MockStore)(nil).CreateTranscriptTextRevision), arg0, arg1)
+}
+
+// CreateTranscriptTx mocks base method.
+func (m *MockStore) CreateTranscriptTx(arg0 context.Context, arg1 db.CreateTranscriptParams) (db.CreateTranscriptTxResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateTranscriptTx", arg0, arg1)
+	ret0, _ := ret[0].(db.CreateTranscriptTxResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CreateTranscriptTx indicates an expected call of CreateTranscriptTx.
+func (mr *MockStoreMockRecorder) CreateTranscriptTx(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateTranscriptTx", reflect.TypeOf((*MockStore)(nil).CreateTranscriptTx), arg0, arg1)
+}
+
 // CreateUser mocks base method.
 func (m *MockStore) CreateUser(arg0 context.Context, arg1 db.CreateUserParams) (db.User, error) {
 	m.ctrl.T.Helper()
@@ -127,6 +309,21 @@ func (mr *MockStoreMockRecorder) CreateUser(arg0, arg1 interface{}) *gomock.Call
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateUser", reflect.TypeOf((*MockStore)(nil).CreateUser), arg0, arg1)
 }
 
+// DeleteCourse mocks base method.
+func (m *MockStore) DeleteCourse(arg0 context.Context, arg1 string) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteCourse", arg0, arg1)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteCourse indicates an expected call of DeleteCourse.
+func (mr *MockStoreMockRecorder) DeleteCourse(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteCourse", reflect.TypeOf((*MockStore)(nil).DeleteCourse), arg0, arg1)
+}
+
 // DeleteScholarship mocks base method.
 func (m *MockStore) DeleteScholarship(arg0 context.Context, arg1 db.DeleteScholarshipParams) error {
 	m.ctrl.T.Helper()
@@ -141,6 +338,20 @@ func (mr *MockStoreMockRecorder) DeleteScholarship(arg0, arg1 interface{}) *gomo
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteScholarship", reflect.TypeOf((*MockStore)(nil).DeleteScholarship), arg0, arg1)
 }
 
+// DeleteStudyPlan mocks base method.
+func (m *MockStore) DeleteStudyPlan(arg0 context.Context, arg1 db.DeleteStudyPlanParams) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteStudyPlan", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteStudyPlan indicates an expected call of DeleteStudyPlan.
+func (mr *MockStoreMockRecorder) DeleteStudyPlan(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteStudyPlan", reflect.TypeOf((*MockStore)(nil).DeleteStudyPlan), arg0, arg1)
+}
+
 // DeleteSummary mocks base method.
 func (m *MockStore) DeleteSummary(arg0 context.Context, arg1 db.DeleteSummaryParams) error {
 	m.ctrl.T.Helper()
@@ -155,6 +366,137 @@ func (mr *MockStoreMockRecorder) DeleteSummary(arg0, arg1 interface{}) *gomock.C
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteSummary", reflect.TypeOf((*MockStore)(nil).DeleteSummary), arg0, arg1)
 }
 
+// DeleteTranscript mocks base method.
+func (m *MockStore) DeleteTranscript(arg0 context.Context, arg1 int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteTranscript", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteTranscript indicates an expected call of DeleteTranscript.
+func (mr *MockStoreMockRecorder) DeleteTranscript(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteTranscript", reflect.TypeOf((*MockStore)(nil).DeleteTranscript), arg0, arg1)
+}
+
+// DeleteTranscriptCourses mocks base method.
+func (m *MockStore) DeleteTranscriptCourses(arg0 context.Context, arg1 int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteTranscriptCourses", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteTranscriptCourses indicates an expected call of DeleteTranscriptCourses.
+func (mr *MockStoreMockRecorder) DeleteTranscriptCourses(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteTranscriptCourses", reflect.TypeOf((*MockStore)(nil).DeleteTranscriptCourses), arg0, arg1)
+}
+
+// DeleteTranscriptRecommendations mocks base method.
+func (m *MockStore) DeleteTranscriptRecommendations(arg0 context.Context, arg1 sql.NullInt64) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteTranscriptRecommendations", arg0, arg1)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteTranscriptRecommendations indicates an expected call of DeleteTranscriptRecommendations.
+func (mr *MockStoreMockRecorder) DeleteTranscriptRecommendations(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteTranscriptRecommendations", reflect.TypeOf((*MockStore)(nil).DeleteTranscriptRecommendations), arg0, arg1)
+}
+
+// DeleteTranscriptTextRevisions mocks base method.
+func (m *MockStore) DeleteTranscriptTextRevisions(arg0 context.Context, arg1 int64) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteTranscriptTextRevisions", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// DeleteTranscriptTextRevisions indicates an expected call of DeleteTranscriptTextRevisions.
+func (mr *MockStoreMockRecorder) DeleteTranscriptTextRevisions(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteTranscriptTextRevisions", reflect.TypeOf((*MockStore)(nil).DeleteTranscriptTextRevisions), arg0, arg1)
+}
+
+// DeleteTranscriptTx mocks base method.
+func (m *MockStore) DeleteTranscriptTx(arg0 context.Context, arg1 db.DeleteTranscriptTxParams) (db.DeleteTranscriptTxResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "DeleteTranscriptTx", arg0, arg1)
+	ret0, _ := ret[0].(db.DeleteTranscriptTxResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// DeleteTranscriptTx indicates an expected call of DeleteTranscriptTx.
+func (mr *MockStoreMockRecorder) DeleteTranscriptTx(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteTranscriptTx", reflect.TypeOf((*MockStore)(nil).DeleteTranscriptTx), arg0, arg1)
+}
+
+// FinishTranscriptJob mocks base method.
+func (m *MockStore) FinishTranscriptJob(arg0 context.Context, arg1 db.FinishTranscriptJobParams) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FinishTranscriptJob", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// FinishTranscriptJob indicates an expected call of FinishTranscriptJob.
+func (mr *MockStoreMockRecorder) FinishTranscriptJob(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FinishTranscriptJob", reflect.TypeOf((*MockStore)(nil).FinishTranscriptJob), arg0, arg1)
+}
+
+// GetCourseByCode mocks base method.
+func (m *MockStore) GetCourseByCode(arg0 context.Context, arg1 string) (db.Course, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetCourseByCode", arg0, arg1)
+	ret0, _ := ret[0].(db.Course)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetCourseByCode indicates an expected call of GetCourseByCode.
+func (mr *MockStoreMockRecorder) GetCourseByCode(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCourseByCode", reflect.TypeOf((*MockStore)(nil).GetCourseByCode), arg0, arg1)
+}
+
+// GetCoursePrerequisites mocks base method.
+func (m *MockStore) GetCoursePrerequisites(arg0 context.Context, arg1 int64) (db.CoursePrerequisite, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetCoursePrerequisites", arg0, arg1)
+	ret0, _ := ret[0].(db.CoursePrerequisite)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetCoursePrerequisites indicates an expected call of GetCoursePrerequisites.
+func (mr *MockStoreMockRecorder) GetCoursePrerequisites(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCoursePrerequisites", reflect.TypeOf((*MockStore)(nil).GetCoursePrerequisites), arg0, arg1)
+}
+
+// GetLatestTranscriptJob mocks base method.
+func (m *MockStore) GetLatestTranscriptJob(arg0 context.Context, arg1 int64) (db.TranscriptJob, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetLatestTranscriptJob", arg0, arg1)
+	ret0, _ := ret[0].(db.TranscriptJob)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetLatestTranscriptJob indicates an expected call of GetLatestTranscriptJob.
+func (mr *MockStoreMockRecorder) GetLatestTranscriptJob(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetLatestTranscriptJob", reflect.TypeOf((*MockStore)(nil).GetLatestTranscriptJob), arg0, arg1)
+}
+
 // GetRecommendation mocks base method.
 func (m *MockStore) GetRecommendation(arg0 context.Context, arg1 int64) (db.Recommendation, error) {
 	m.ctrl.T.Helper()
@@ -170,6 +512,36 @@ func (mr *MockStoreMockRecorder) GetRecommendation(arg0, arg1 interface{}) *gomo
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetRecommendation", reflect.TypeOf((*MockStore)(nil).GetRecommendation), arg0, arg1)
 }
 
+// GetRecommendationProvenance mocks base method.
+func (m *MockStore) GetRecommendationProvenance(arg0 context.Context, arg1 int64) (db.RecommendationProvenance, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetRecommendationProvenance", arg0, arg1)
+	ret0, _ := ret[0].(db.RecommendationProvenance)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetRecommendationProvenance indicates an expected call of GetRecommendationProvenance.
+func (mr *MockStoreMockRecorder) GetRecommendationProvenance(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetRecommendationProvenance", reflect.TypeOf((*MockStore)(nil).GetRecommendationProvenance), arg0, arg1)
+}
+
+// GetStudyPlan mocks base method.
+func (m *MockStore) GetStudyPlan(arg0 context.Context, arg1 int64) (db.StudyPlan, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetStudyPlan", arg0, arg1)
+	ret0, _ := ret[0].(db.StudyPlan)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetStudyPlan indicates an expected call of GetStudyPlan.
+func (mr *MockStoreMockRecorder) GetStudyPlan(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetStudyPlan", reflect.TypeOf((*MockStore)(nil).GetStudyPlan), arg0, arg1)
+}
+
 // GetSummary mocks base method.
 func (m *MockStore) GetSummary(arg0 context.Context, arg1 db.GetSummaryParams) (db.Summary, error) {
 	m.ctrl.T.Helper()
@@ -200,6 +572,21 @@ func (mr *MockStoreMockRecorder) GetTranscript(arg0, arg1 interface{}) *gomock.C
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTranscript", reflect.TypeOf((*MockStore)(nil).GetTranscript), arg0, arg1)
 }
 
+// GetTranscriptByHash mocks base method.
+func (m *MockStore) GetTranscriptByHash(arg0 context.Context, arg1 db.GetTranscriptByHashParams) (db.Transcript, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetTranscriptByHash", arg0, arg1)
+	ret0, _ := ret[0].(db.Transcript)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetTranscriptByHash indicates an expected call of GetTranscriptByHash.
+func (mr *MockStoreMockRecorder) GetTranscriptByHash(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetTranscriptByHash", reflect.TypeOf((*MockStore)(nil).GetTranscriptByHash), arg0, arg1)
+}
+
 // GetUser mocks base method.
 func (m *MockStore) GetUser(arg0 context.Context, arg1 string) (db.User, error) {
 	m.ctrl.T.Helper()
@@ -215,6 +602,21 @@ func (mr *MockStoreMockRecorder) GetUser(arg0, arg1 interface{}) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUser", reflect.TypeOf((*MockStore)(nil).GetUser), arg0, arg1)
 }
 
+// ImportCoursesTx mocks base method.
+func (m *MockStore) ImportCoursesTx(arg0 context.Context, arg1 db.ImportCoursesTxParams) (db.ImportCoursesTxResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ImportCoursesTx", arg0, arg1)
+	ret0, _ := ret[0].(db.ImportCoursesTxResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ImportCoursesTx indicates an expected call of ImportCoursesTx.
+func (mr *MockStoreMockRecorder) ImportCoursesTx(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ImportCoursesTx", reflect.TypeOf((*MockStore)(nil).ImportCoursesTx), arg0, arg1)
+}
+
 // ListAllCourses mocks base method.
 func (m *MockStore) ListAllCourses(arg0 context.Context) ([]db.Course, error) {
 	m.ctrl.T.Helper()
@@ -230,8 +632,53 @@ func (mr *MockStoreMockRecorder) ListAllCourses(arg0 interface{}) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListAllCourses", reflect.TypeOf((*MockStore)(nil).ListAllCourses), arg0)
 }
 
+// ListCourseEmbeddings mocks base method.
+func (m *MockStore) ListCourseEmbeddings(arg0 context.Context, arg1 string) ([]db.CourseEmbedding, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListCourseEmbeddings", arg0, arg1)
+	ret0, _ := ret[0].([]db.CourseEmbedding)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListCourseEmbeddings indicates an expected call of ListCourseEmbeddings.
+func (mr *MockStoreMockRecorder) ListCourseEmbeddings(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListCourseEmbeddings", reflect.TypeOf((*MockStore)(nil).ListCourseEmbeddings), arg0, arg1)
+}
+
+// ListCourseFeedback mocks base method.
+func (m *MockStore) ListCourseFeedback(arg0 context.Context, arg1 string) ([]db.ListCourseFeedbackRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListCourseFeedback", arg0, arg1)
+	ret0, _ := ret[0].([]db.ListCourseFeedbackRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListCourseFeedback indicates an expected call of ListCourseFeedback.
+func (mr *MockStoreMockRecorder) ListCourseFeedback(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListCourseFeedback", reflect.TypeOf((*MockStore)(nil).ListCourseFeedback), arg0, arg1)
+}
+
+// ListCoursePrerequisites mocks base method.
+func (m *MockStore) ListCoursePrerequisites(arg0 context.Context) ([]db.CoursePrerequisite, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListCoursePrerequisites", arg0)
+	ret0, _ := ret[0].([]db.CoursePrerequisite)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListCoursePrerequisites indicates an expected call of ListCoursePrerequisites.
+func (mr *MockStoreMockRecorder) ListCoursePrerequisites(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListCoursePrerequisites", reflect.TypeOf((*MockStore)(nil).ListCoursePrerequisites), arg0)
+}
+
 // ListCourses mocks base method.
-func (m *MockStore) ListCourses(arg0 context.Context, arg1 int64) ([]db.Course, error) {
+func (m *MockStore) ListCourses(arg0 context.Context, arg1 db.ListCoursesParams) ([]db.Course, error) {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "ListCourses", arg0, arg1)
 	ret0, _ := ret[0].([]db.Course)
@@ -245,6 +692,21 @@ func (mr *MockStoreMockRecorder) ListCourses(arg0, arg1 interface{}) *gomock.Cal
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListCourses", reflect.TypeOf((*MockStore)(nil).ListCourses), arg0, arg1)
 }
 
+// ListCoursesWithStalePrerequisites mocks base method.
+func (m *MockStore) ListCoursesWithStalePrerequisites(arg0 context.Context) ([]db.Course, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListCoursesWithStalePrerequisites", arg0)
+	ret0, _ := ret[0].([]db.Course)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListCoursesWithStalePrerequisites indicates an expected call of ListCoursesWithStalePrerequisites.
+func (mr *MockStoreMockRecorder) ListCoursesWithStalePrerequisites(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListCoursesWithStalePrerequisites", reflect.TypeOf((*MockStore)(nil).ListCoursesWithStalePrerequisites), arg0)
+}
+
 // ListRecentScholarshipsByUser mocks base method.
 func (m *MockStore) ListRecentScholarshipsByUser(arg0 context.Context, arg1 db.ListRecentScholarshipsByUserParams) ([]db.Scholarship, error) {
 	m.ctrl.T.Helper()
@@ -290,6 +752,36 @@ func (mr *MockStoreMockRecorder) ListScholarshipsByUser(arg0, arg1 interface{})
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListScholarshipsByUser", reflect.TypeOf((*MockStore)(nil).ListScholarshipsByUser), arg0, arg1)
 }
 
+// ListStudyPlanSummaryKeys mocks base method.
+func (m *MockStore) ListStudyPlanSummaryKeys(arg0 context.Context, arg1 sql.NullInt64) ([]sql.NullString, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListStudyPlanSummaryKeys", arg0, arg1)
+	ret0, _ := ret[0].([]sql.NullString)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListStudyPlanSummaryKeys indicates an expected call of ListStudyPlanSummaryKeys.
+func (mr *MockStoreMockRecorder) ListStudyPlanSummaryKeys(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListStudyPlanSummaryKeys", reflect.TypeOf((*MockStore)(nil).ListStudyPlanSummaryKeys), arg0, arg1)
+}
+
+// ListStudyPlans mocks base method.
+func (m *MockStore) ListStudyPlans(arg0 context.Context, arg1 string) ([]db.StudyPlan, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListStudyPlans", arg0, arg1)
+	ret0, _ := ret[0].([]db.StudyPlan)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListStudyPlans indicates an expected call of ListStudyPlans.
+func (mr *MockStoreMockRecorder) ListStudyPlans(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListStudyPlans", reflect.TypeOf((*MockStore)(nil).ListStudyPlans), arg0, arg1)
+}
+
 // ListSummaries mocks base method.
 func (m *MockStore) ListSummaries(arg0 context.Context, arg1 string) ([]db.Summary, error) {
 	m.ctrl.T.Helper()
@@ -305,6 +797,51 @@ func (mr *MockStoreMockRecorder) ListSummaries(arg0, arg1 interface{}) *gomock.C
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListSummaries", reflect.TypeOf((*MockStore)(nil).ListSummaries), arg0, arg1)
 }
 
+// ListTranscriptCourses mocks base method.
+func (m *MockStore) ListTranscriptCourses(arg0 context.Context, arg1 int64) ([]db.TranscriptCourse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListTranscriptCourses", arg0, arg1)
+	ret0, _ := ret[0].([]db.TranscriptCourse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListTranscriptCourses indicates an expected call of ListTranscriptCourses.
+func (mr *MockStoreMockRecorder) ListTranscriptCourses(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListTranscriptCourses", reflect.TypeOf((*MockStore)(nil).ListTranscriptCourses), arg0, arg1)
+}
+
+// ListTranscriptSummaryKeys mocks base method.
+func (m *MockStore) ListTranscriptSummaryKeys(arg0 context.Context, arg1 sql.NullInt64) ([]sql.NullString, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListTranscriptSummaryKeys", arg0, arg1)
+	ret0, _ := ret[0].([]sql.NullString)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListTranscriptSummaryKeys indicates an expected call of ListTranscriptSummaryKeys.
+func (mr *MockStoreMockRecorder) ListTranscriptSummaryKeys(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListTranscriptSummaryKeys", reflect.TypeOf((*MockStore)(nil).ListTranscriptSummaryKeys), arg0, arg1)
+}
+
+// ListTranscriptTextRevisions mocks base method.
+func (m *MockStore) ListTranscriptTextRevisions(arg0 context.Context, arg1 int64) ([]db.TranscriptTextRevision, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListTranscriptTextRevisions", arg0, arg1)
+	ret0, _ := ret[0].([]db.TranscriptTextRevision)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListTranscriptTextRevisions indicates an expected call of ListTranscriptTextRevisions.
+func (mr *MockStoreMockRecorder) ListTranscriptTextRevisions(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListTranscriptTextRevisions", reflect.TypeOf((*MockStore)(nil).ListTranscriptTextRevisions), arg0, arg1)
+}
+
 // ListTranscripts mocks base method.
 func (m *MockStore) ListTranscripts(arg0 context.Context, arg1 string) ([]db.ListTranscriptsRow, error) {
 	m.ctrl.T.Helper()
@@ -319,3 +856,255 @@ func (mr *MockStoreMockRecorder) ListTranscripts(arg0, arg1 interface{}) *gomock
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListTranscripts", reflect.TypeOf((*MockStore)(nil).ListTranscripts), arg0, arg1)
 }
+
+// MarkTranscriptRecommendationsStale mocks base method.
+func (m *MockStore) MarkTranscriptRecommendationsStale(arg0 context.Context, arg1 sql.NullInt64) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MarkTranscriptRecommendationsStale", arg0, arg1)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// MarkTranscriptRecommendationsStale indicates an expected call of MarkTranscriptRecommendationsStale.
+func (mr *MockStoreMockRecorder) MarkTranscriptRecommendationsStale(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MarkTranscriptRecommendationsStale", reflect.TypeOf((*MockStore)(nil).MarkTranscriptRecommendationsStale), arg0, arg1)
+}
+
+// ReplaceTranscriptCoursesTx mocks base method.
+func (m *MockStore) ReplaceTranscriptCoursesTx(arg0 context.Context, arg1 db.ReplaceTranscriptCoursesTxParams) ([]db.TranscriptCourse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReplaceTranscriptCoursesTx", arg0, arg1)
+	ret0, _ := ret[0].([]db.TranscriptCourse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ReplaceTranscriptCoursesTx indicates an expected call of ReplaceTranscriptCoursesTx.
+func (mr *MockStoreMockRecorder) ReplaceTranscriptCoursesTx(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReplaceTranscriptCoursesTx", reflect.TypeOf((*MockStore)(nil).ReplaceTranscriptCoursesTx), arg0, arg1)
+}
+
+// ReplaceTranscriptFileTx mocks base method.
+func (m *MockStore) ReplaceTranscriptFileTx(arg0 context.Context, arg1 db.UpdateTranscriptFileParams) (db.CreateTranscriptTxResult, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReplaceTranscriptFileTx", arg0, arg1)
+	ret0, _ := ret[0].(db.CreateTranscriptTxResult)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ReplaceTranscriptFileTx indicates an expected call of ReplaceTranscriptFileTx.
+func (mr *MockStoreMockRecorder) ReplaceTranscriptFileTx(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReplaceTranscriptFileTx", reflect.TypeOf((*MockStore)(nil).ReplaceTranscriptFileTx), arg0, arg1)
+}
+
+// RequeueStaleTranscriptJobs mocks base method.
+func (m *MockStore) RequeueStaleTranscriptJobs(arg0 context.Context, arg1 time.Time) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RequeueStaleTranscriptJobs", arg0, arg1)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RequeueStaleTranscriptJobs indicates an expected call of RequeueStaleTranscriptJobs.
+func (mr *MockStoreMockRecorder) RequeueStaleTranscriptJobs(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RequeueStaleTranscriptJobs", reflect.TypeOf((*MockStore)(nil).RequeueStaleTranscriptJobs), arg0, arg1)
+}
+
+// SearchCourses mocks base method.
+func (m *MockStore) SearchCourses(arg0 context.Context, arg1 db.SearchCoursesParams) ([]db.SearchCoursesRow, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SearchCourses", arg0, arg1)
+	ret0, _ := ret[0].([]db.SearchCoursesRow)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SearchCourses indicates an expected call of SearchCourses.
+func (mr *MockStoreMockRecorder) SearchCourses(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SearchCourses", reflect.TypeOf((*MockStore)(nil).SearchCourses), arg0, arg1)
+}
+
+// UpdateCourse mocks base method.
+func (m *MockStore) UpdateCourse(arg0 context.Context, arg1 db.UpdateCourseParams) (db.Course, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateCourse", arg0, arg1)
+	ret0, _ := ret[0].(db.Course)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateCourse indicates an expected call of UpdateCourse.
+func (mr *MockStoreMockRecorder) UpdateCourse(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateCourse", reflect.TypeOf((*MockStore)(nil).UpdateCourse), arg0, arg1)
+}
+
+// UpdateCourseTx mocks base method.
+func (m *MockStore) UpdateCourseTx(arg0 context.Context, arg1 db.UpdateCourseTxParams) (db.Course, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateCourseTx", arg0, arg1)
+	ret0, _ := ret[0].(db.Course)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateCourseTx indicates an expected call of UpdateCourseTx.
+func (mr *MockStoreMockRecorder) UpdateCourseTx(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateCourseTx", reflect.TypeOf((*MockStore)(nil).UpdateCourseTx), arg0, arg1)
+}
+
+// UpdateRecommendationPayload mocks base method.
+func (m *MockStore) UpdateRecommendationPayload(arg0 context.Context, arg1 db.UpdateRecommendationPayloadParams) (db.Recommendation, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateRecommendationPayload", arg0, arg1)
+	ret0, _ := ret[0].(db.Recommendation)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateRecommendationPayload indicates an expected call of UpdateRecommendationPayload.
+func (mr *MockStoreMockRecorder) UpdateRecommendationPayload(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateRecommendationPayload", reflect.TypeOf((*MockStore)(nil).UpdateRecommendationPayload), arg0, arg1)
+}
+
+// UpdateStudyPlan mocks base method.
+func (m *MockStore) UpdateStudyPlan(arg0 context.Context, arg1 db.UpdateStudyPlanParams) (db.StudyPlan, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateStudyPlan", arg0, arg1)
+	ret0, _ := ret[0].(db.StudyPlan)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateStudyPlan indicates an expected call of UpdateStudyPlan.
+func (mr *MockStoreMockRecorder) UpdateStudyPlan(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateStudyPlan", reflect.TypeOf((*MockStore)(nil).UpdateStudyPlan), arg0, arg1)
+}
+
+// UpdateTranscriptFile mocks base method.
+func (m *MockStore) UpdateTranscriptFile(arg0 context.Context, arg1 db.UpdateTranscriptFileParams) (db.Transcript, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateTranscriptFile", arg0, arg1)
+	ret0, _ := ret[0].(db.Transcript)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateTranscriptFile indicates an expected call of UpdateTranscriptFile.
+func (mr *MockStoreMockRecorder) UpdateTranscriptFile(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateTranscriptFile", reflect.TypeOf((*MockStore)(nil).UpdateTranscriptFile), arg0, arg1)
+}
+
+// UpdateTranscriptJobProgress mocks base method.
+func (m *MockStore) UpdateTranscriptJobProgress(arg0 context.Context, arg1 db.UpdateTranscriptJobProgressParams) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateTranscriptJobProgress", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateTranscriptJobProgress indicates an expected call of UpdateTranscriptJobProgress.
+func (mr *MockStoreMockRecorder) UpdateTranscriptJobProgress(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateTranscriptJobProgress", reflect.TypeOf((*MockStore)(nil).UpdateTranscriptJobProgress), arg0, arg1)
+}
+
+// UpdateTranscriptMeta mocks base method.
+func (m *MockStore) UpdateTranscriptMeta(arg0 context.Context, arg1 db.UpdateTranscriptMetaParams) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateTranscriptMeta", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateTranscriptMeta indicates an expected call of UpdateTranscriptMeta.
+func (mr *MockStoreMockRecorder) UpdateTranscriptMeta(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateTranscriptMeta", reflect.TypeOf((*MockStore)(nil).UpdateTranscriptMeta), arg0, arg1)
+}
+
+// UpdateTranscriptText mocks base method.
+func (m *MockStore) UpdateTranscriptText(arg0 context.Context, arg1 db.UpdateTranscriptTextParams) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateTranscriptText", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateTranscriptText indicates an expected call of UpdateTranscriptText.
+func (mr *MockStoreMockRecorder) UpdateTranscriptText(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateTranscriptText", reflect.TypeOf((*MockStore)(nil).UpdateTranscriptText), arg0, arg1)
+}
+
+// UpdateUserStrictRedaction mocks base method.
+func (m *MockStore) UpdateUserStrictRedaction(arg0 context.Context, arg1 db.UpdateUserStrictRedactionParams) (db.User, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateUserStrictRedaction", arg0, arg1)
+	ret0, _ := ret[0].(db.User)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpdateUserStrictRedaction indicates an expected call of UpdateUserStrictRedaction.
+func (mr *MockStoreMockRecorder) UpdateUserStrictRedaction(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateUserStrictRedaction", reflect.TypeOf((*MockStore)(nil).UpdateUserStrictRedaction), arg0, arg1)
+}
+
+// UpsertCourseEmbedding mocks base method.
+func (m *MockStore) UpsertCourseEmbedding(arg0 context.Context, arg1 db.UpsertCourseEmbeddingParams) (db.CourseEmbedding, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpsertCourseEmbedding", arg0, arg1)
+	ret0, _ := ret[0].(db.CourseEmbedding)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpsertCourseEmbedding indicates an expected call of UpsertCourseEmbedding.
+func (mr *MockStoreMockRecorder) UpsertCourseEmbedding(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpsertCourseEmbedding", reflect.TypeOf((*MockStore)(nil).UpsertCourseEmbedding), arg0, arg1)
+}
+
+// UpsertCourseFeedback mocks base method.
+func (m *MockStore) UpsertCourseFeedback(arg0 context.Context, arg1 db.UpsertCourseFeedbackParams) (db.CourseFeedback, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpsertCourseFeedback", arg0, arg1)
+	ret0, _ := ret[0].(db.CourseFeedback)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpsertCourseFeedback indicates an expected call of UpsertCourseFeedback.
+func (mr *MockStoreMockRecorder) UpsertCourseFeedback(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpsertCourseFeedback", reflect.TypeOf((*MockStore)(nil).UpsertCourseFeedback), arg0, arg1)
+}
+
+// UpsertCoursePrerequisites mocks base method.
+func (m *MockStore) UpsertCoursePrerequisites(arg0 context.Context, arg1 db.UpsertCoursePrerequisitesParams) (db.CoursePrerequisite, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpsertCoursePrerequisites", arg0, arg1)
+	ret0, _ := ret[0].(db.CoursePrerequisite)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UpsertCoursePrerequisites indicates an expected call of UpsertCoursePrerequisites.
+func (mr *MockStoreMockRecorder) UpsertCoursePrerequisites(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpsertCoursePrerequisites", reflect.TypeOf((*MockStore)(nil).UpsertCoursePrerequisites), arg0, arg1)
+}
@@ -3,17 +3,97 @@
 -- name: CreateCourse :one
 INSERT INTO courses (
   code, name, language, grading_scale, organiser,
-  learning_outcomes, prerequisites, teacher_name, teacher_email, course_link
+  learning_outcomes, prerequisites, teacher_name, teacher_email, course_link,
+  credits, level, teaching_periods, modality, campus
 ) VALUES (
   $1,   $2,   $3,       $4,            $5,
-  $6,               $7,           $8,          $9,           $10
+  $6,               $7,           $8,          $9,           $10,
+  $11,     $12,   $13,              $14,      $15
 )
 RETURNING *;
 
 -- name: ListCourses :many
 SELECT * FROM courses
-ORDER BY id
-LIMIT $1;
+WHERE (sqlc.narg('language')::varchar IS NULL OR language ILIKE '%' || sqlc.narg('language') || '%')
+  AND (sqlc.narg('organiser')::varchar IS NULL OR organiser ILIKE '%' || sqlc.narg('organiser') || '%')
+  AND (sqlc.narg('teacher')::varchar IS NULL OR teacher_name ILIKE '%' || sqlc.narg('teacher') || '%')
+  AND (sqlc.narg('level')::varchar IS NULL OR level = sqlc.narg('level'))
+  AND (sqlc.narg('credits')::float8 IS NULL OR credits = sqlc.narg('credits'))
+  AND (sqlc.narg('period')::int IS NULL OR sqlc.narg('period') = ANY(teaching_periods))
+  AND (sqlc.narg('modality')::varchar IS NULL OR modality = sqlc.narg('modality'))
+  AND (sqlc.narg('campus')::varchar IS NULL OR campus ILIKE '%' || sqlc.narg('campus') || '%')
+ORDER BY code
+LIMIT sqlc.arg('limit') OFFSET sqlc.arg('offset');
 
 -- name: ListAllCourses :many
 SELECT * FROM courses ORDER BY id ASC;
+
+-- name: CountCourses :one
+SELECT count(*) FROM courses
+WHERE (sqlc.narg('language')::varchar IS NULL OR language ILIKE '%' || sqlc.narg('language') || '%')
+  AND (sqlc.narg('organiser')::varchar IS NULL OR organiser ILIKE '%' || sqlc.narg('organiser') || '%')
+  AND (sqlc.narg('teacher')::varchar IS NULL OR teacher_name ILIKE '%' || sqlc.narg('teacher') || '%')
+  AND (sqlc.narg('level')::varchar IS NULL OR level = sqlc.narg('level'))
+  AND (sqlc.narg('credits')::float8 IS NULL OR credits = sqlc.narg('credits'))
+  AND (sqlc.narg('period')::int IS NULL OR sqlc.narg('period') = ANY(teaching_periods))
+  AND (sqlc.narg('modality')::varchar IS NULL OR modality = sqlc.narg('modality'))
+  AND (sqlc.narg('campus')::varchar IS NULL OR campus ILIKE '%' || sqlc.narg('campus') || '%');
+
+-- name: GetCourseByCode :one
+SELECT * FROM courses
+WHERE code = $1 LIMIT 1;
+
+-- name: UpdateCourse :one
+UPDATE courses
+SET
+  name = $2,
+  language = $3,
+  grading_scale = $4,
+  organiser = $5,
+  learning_outcomes = $6,
+  prerequisites = $7,
+  teacher_name = $8,
+  teacher_email = $9,
+  course_link = $10,
+  credits = $11,
+  level = $12,
+  teaching_periods = $13,
+  modality = $14,
+  campus = $15
+WHERE code = $1
+RETURNING *;
+
+-- name: DeleteCourse :execrows
+DELETE FROM courses
+WHERE code = $1;
+
+-- name: SearchCourses :many
+-- Full-text matches (English or Finnish stemming) and fuzzy code/name matches,
+-- best first. Headlines mark hits with chr(2)...chr(3); each language config
+-- only highlights its own stems, so both are returned.
+WITH search AS (
+  SELECT
+    websearch_to_tsquery('english', sqlc.arg('query')::text) AS en,
+    websearch_to_tsquery('finnish', sqlc.arg('query')::text) AS fi
+)
+SELECT
+  c.id, c.code, c.name, c.language, c.organiser,
+  (
+    ts_rank_cd(course_search_document(c.name, c.learning_outcomes, c.prerequisites), search.en || search.fi, 32)
+    + greatest(similarity(c.code, sqlc.arg('query')::text), word_similarity(sqlc.arg('query')::text, c.name))
+  )::float8 AS rank,
+  ts_headline('english', c.name, search.en,
+    'HighlightAll=true, StartSel=' || chr(2) || ', StopSel=' || chr(3)) AS name_highlight_en,
+  ts_headline('finnish', c.name, search.fi,
+    'HighlightAll=true, StartSel=' || chr(2) || ', StopSel=' || chr(3)) AS name_highlight_fi,
+  ts_headline('english', concat_ws(' ', c.learning_outcomes, c.prerequisites), search.en,
+    'MaxFragments=2, MaxWords=25, MinWords=8, StartSel=' || chr(2) || ', StopSel=' || chr(3)) AS snippet_en,
+  ts_headline('finnish', concat_ws(' ', c.learning_outcomes, c.prerequisites), search.fi,
+    'MaxFragments=2, MaxWords=25, MinWords=8, StartSel=' || chr(2) || ', StopSel=' || chr(3)) AS snippet_fi
+FROM courses c, search
+WHERE course_search_document(c.name, c.learning_outcomes, c.prerequisites) @@ (search.en || search.fi)
+   OR c.code ILIKE sqlc.arg('query')::text || '%'
+   OR c.code % sqlc.arg('query')::text
+   OR sqlc.arg('query')::text <% c.name
+ORDER BY rank DESC, c.code
+LIMIT sqlc.arg('max_results')::int;
@@ -0,0 +1,17 @@
+-- db/query/course_embedding.sql
+-- name: ListCourseEmbeddings :many
+SELECT * FROM course_embeddings
+WHERE model = $1;
+
+-- name: UpsertCourseEmbedding :one
+INSERT INTO course_embeddings (
+  course_id, model, content_hash, embedding
+) VALUES (
+  $1, $2, $3, $4
+)
+ON CONFLICT (course_id) DO UPDATE
+SET model = EXCLUDED.model,
+    content_hash = EXCLUDED.content_hash,
+    embedding = EXCLUDED.embedding,
+    updated_at = now()
+RETURNING *;
@@ -0,0 +1,21 @@
+-- db/query/course_feedback.sql
+-- name: UpsertCourseFeedback :one
+INSERT INTO course_feedback (
+  user_username, course_id, recommendation_id, kind, reason
+) VALUES (
+  $1, $2, $3, $4, $5
+)
+ON CONFLICT (user_username, course_id) DO UPDATE
+SET recommendation_id = EXCLUDED.recommendation_id,
+    kind = EXCLUDED.kind,
+    reason = EXCLUDED.reason,
+    updated_at = now()
+RETURNING *;
+
+-- name: ListCourseFeedback :many
+SELECT f.user_username, f.course_id, f.recommendation_id, f.kind, f.reason,
+       f.created_at, f.updated_at, c.code, c.name
+FROM course_feedback f
+JOIN courses c ON c.id = f.course_id
+WHERE f.user_username = $1
+ORDER BY f.updated_at DESC;
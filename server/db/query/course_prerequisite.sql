@@ -0,0 +1,27 @@
+-- db/query/course_prerequisite.sql
+-- name: GetCoursePrerequisites :one
+SELECT * FROM course_prerequisites
+WHERE course_id = $1 LIMIT 1;
+
+-- name: ListCoursePrerequisites :many
+SELECT * FROM course_prerequisites;
+
+-- name: ListCoursesWithStalePrerequisites :many
+-- Courses without parsed prerequisites, or whose text changed since parsing.
+SELECT c.* FROM courses c
+LEFT JOIN course_prerequisites p ON p.course_id = c.id
+WHERE p.course_id IS NULL OR p.source <> COALESCE(c.prerequisites, '')
+ORDER BY c.id;
+
+-- name: UpsertCoursePrerequisites :one
+INSERT INTO course_prerequisites (
+  course_id, source, requirement, advisory
+) VALUES (
+  $1, $2, $3, $4
+)
+ON CONFLICT (course_id) DO UPDATE
+SET source = EXCLUDED.source,
+    requirement = EXCLUDED.requirement,
+    advisory = EXCLUDED.advisory,
+    updated_at = now()
+RETURNING *;
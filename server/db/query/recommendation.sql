@@ -6,7 +6,7 @@ INSERT INTO recommendations (
 RETURNING *;
 
 -- name: ListRecommendations :many
-SELECT id, user_username, transcript_id, summary, created_at
+SELECT id, user_username, transcript_id, summary, created_at, stale
 FROM recommendations
 WHERE user_username = $1
 ORDER BY id DESC;
@@ -18,4 +18,17 @@ SELECT * FROM recommendations WHERE id = $1 LIMIT 1;
 UPDATE recommendations
 SET payload = $1
 WHERE id = $2 AND user_username = $3
-RETURNING *;
\ No newline at end of file
+RETURNING *;
+
+-- name: CountTranscriptRecommendations :one
+SELECT COUNT(*) FROM recommendations
+WHERE transcript_id = $1;
+
+-- name: DeleteTranscriptRecommendations :execrows
+DELETE FROM recommendations
+WHERE transcript_id = $1;
+
+-- name: MarkTranscriptRecommendationsStale :execrows
+UPDATE recommendations
+SET stale = true
+WHERE transcript_id = $1 AND NOT stale;
@@ -0,0 +1,14 @@
+-- db/query/recommendation_provenance.sql
+-- name: CreateRecommendationProvenance :one
+INSERT INTO recommendation_provenance (
+  recommendation_id, mode, model, prompt_version, input_hash,
+  candidate_count, completed_codes, embedding_model, fallback_reason,
+  attempts, prompt_tokens, completion_tokens, total_tokens, latency_ms
+) VALUES (
+  $1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14
+)
+RETURNING *;
+
+-- name: GetRecommendationProvenance :one
+SELECT * FROM recommendation_provenance
+WHERE recommendation_id = $1 LIMIT 1;
@@ -0,0 +1,28 @@
+-- db/query/study_plan.sql
+-- name: CreateStudyPlan :one
+INSERT INTO study_plans (
+  user_username, transcript_id, title, plan
+) VALUES ($1, $2, $3, $4)
+RETURNING *;
+
+-- name: GetStudyPlan :one
+SELECT * FROM study_plans
+WHERE id = $1 LIMIT 1;
+
+-- name: ListStudyPlans :many
+SELECT * FROM study_plans
+WHERE user_username = $1
+ORDER BY updated_at DESC;
+
+-- name: UpdateStudyPlan :one
+UPDATE study_plans
+SET title = $2,
+    plan = $3,
+    updated_at = now()
+WHERE id = $1
+RETURNING *;
+
+-- name: DeleteStudyPlan :exec
+DELETE FROM study_plans
+WHERE id = $1
+  AND user_username = $2;
@@ -1,8 +1,8 @@
 -- db/query/summary.sql
 -- name: CreateSummary :one
 INSERT INTO summaries (
-  user_username, recommendation_id, summary_text, pdf_path
-) VALUES ($1, $2, $3, $4)
+  user_username, recommendation_id, summary_text, pdf_key, study_plan_id
+) VALUES ($1, $2, $3, $4, $5)
 RETURNING *;
 
 -- name: ListSummaries :many
@@ -22,3 +22,17 @@ LIMIT 1;
 DELETE FROM summaries
 WHERE id = $1
   AND user_username = $2;
+
+
+-- name: ListTranscriptSummaryKeys :many
+SELECT s.pdf_key
+FROM summaries s
+JOIN recommendations r ON r.id = s.recommendation_id
+WHERE r.transcript_id = $1
+  AND s.pdf_key IS NOT NULL;
+
+-- name: ListStudyPlanSummaryKeys :many
+SELECT pdf_key
+FROM summaries
+WHERE study_plan_id = $1
+  AND pdf_key IS NOT NULL;
@@ -1,15 +1,39 @@
 -- db/query/transcript.sql
 -- name: CreateTranscript :one
 INSERT INTO transcripts (
-  user_username, file_path, text_extracted, meta
-) VALUES ($1, $2, $3, $4)
+  user_username, file_key, text_extracted, meta, content_sha256
+) VALUES ($1, $2, $3, $4, $5)
 RETURNING *;
 
 -- name: ListTranscripts :many
-SELECT id, user_username, file_path, created_at
+SELECT id, user_username, file_key, created_at
 FROM transcripts
 WHERE user_username = $1
 ORDER BY id DESC;
 
 -- name: GetTranscript :one
 SELECT * FROM transcripts WHERE id = $1 LIMIT 1;
+
+-- name: GetTranscriptByHash :one
+SELECT * FROM transcripts
+WHERE user_username = $1 AND content_sha256 = $2
+LIMIT 1;
+
+-- name: UpdateTranscriptMeta :exec
+UPDATE transcripts
+SET meta = $2
+WHERE id = $1;
+
+-- name: UpdateTranscriptText :exec
+UPDATE transcripts
+SET text_extracted = $2, meta = $3
+WHERE id = $1;
+
+-- name: UpdateTranscriptFile :one
+UPDATE transcripts
+SET file_key = $2, content_sha256 = $3, meta = $4, text_extracted = NULL
+WHERE id = $1
+RETURNING *;
+
+-- name: DeleteTranscript :exec
+DELETE FROM transcripts WHERE id = $1;
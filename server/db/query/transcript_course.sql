@@ -0,0 +1,15 @@
+-- db/query/transcript_course.sql
+-- name: CreateTranscriptCourse :one
+INSERT INTO transcript_courses (
+  transcript_id, code, name, credits, grade, completed_on, status
+) VALUES ($1, $2, $3, $4, $5, $6, $7)
+RETURNING *;
+
+-- name: ListTranscriptCourses :many
+SELECT * FROM transcript_courses
+WHERE transcript_id = $1
+ORDER BY id ASC;
+
+-- name: DeleteTranscriptCourses :exec
+DELETE FROM transcript_courses
+WHERE transcript_id = $1;
@@ -0,0 +1,40 @@
+-- db/query/transcript_job.sql
+-- name: CreateTranscriptJob :one
+INSERT INTO transcript_jobs (
+  transcript_id
+) VALUES ($1)
+RETURNING *;
+
+-- name: GetLatestTranscriptJob :one
+SELECT * FROM transcript_jobs
+WHERE transcript_id = $1
+ORDER BY id DESC
+LIMIT 1;
+
+-- name: ClaimTranscriptJob :one
+UPDATE transcript_jobs
+SET status = 'extracting', attempts = attempts + 1, updated_at = now()
+WHERE id = (
+  SELECT id FROM transcript_jobs
+  WHERE status = 'queued'
+  ORDER BY id
+  FOR UPDATE SKIP LOCKED
+  LIMIT 1
+)
+RETURNING *;
+
+-- name: UpdateTranscriptJobProgress :exec
+UPDATE transcript_jobs
+SET status = $2, pages_total = $3, pages_done = $4, updated_at = now()
+WHERE id = $1;
+
+-- name: FinishTranscriptJob :exec
+UPDATE transcript_jobs
+SET status = $2, error = $3, updated_at = now()
+WHERE id = $1;
+
+-- name: RequeueStaleTranscriptJobs :execrows
+UPDATE transcript_jobs
+SET status = 'queued', updated_at = now()
+WHERE status IN ('extracting', 'ocr')
+  AND updated_at < $1;
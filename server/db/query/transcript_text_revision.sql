@@ -0,0 +1,20 @@
+-- db/query/transcript_text_revision.sql
+-- name: CreateTranscriptTextRevision :one
+INSERT INTO transcript_text_revisions (
+  transcript_id, revision, text, source
+) VALUES (
+  $1,
+  (SELECT COALESCE(MAX(revision), 0) + 1 FROM transcript_text_revisions WHERE transcript_id = $1),
+  $2,
+  $3
+)
+RETURNING *;
+
+-- name: ListTranscriptTextRevisions :many
+SELECT * FROM transcript_text_revisions
+WHERE transcript_id = $1
+ORDER BY revision DESC;
+
+-- name: DeleteTranscriptTextRevisions :exec
+DELETE FROM transcript_text_revisions
+WHERE transcript_id = $1;
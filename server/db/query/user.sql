@@ -12,4 +12,10 @@ RETURNING *;
 
 -- name: GetUser :one
 SELECT * FROM users
-WHERE username = $1 LIMIT 1;
\ No newline at end of file
+WHERE username = $1 LIMIT 1;
+
+-- name: UpdateUserStrictRedaction :one
+UPDATE users
+SET strict_redaction = $2
+WHERE username = $1
+RETURNING *;
@@ -8,31 +8,79 @@ package db
 import (
 	"context"
 	"database/sql"
+
+	"github.com/lib/pq"
 )
 
+const countCourses = `-- name: CountCourses :one
+SELECT count(*) FROM courses
+WHERE ($1::varchar IS NULL OR language ILIKE '%' || $1 || '%')
+  AND ($2::varchar IS NULL OR organiser ILIKE '%' || $2 || '%')
+  AND ($3::varchar IS NULL OR teacher_name ILIKE '%' || $3 || '%')
+  AND ($4::varchar IS NULL OR level = $4)
+  AND ($5::float8 IS NULL OR credits = $5)
+  AND ($6::int IS NULL OR $6 = ANY(teaching_periods))
+  AND ($7::varchar IS NULL OR modality = $7)
+  AND ($8::varchar IS NULL OR campus ILIKE '%' || $8 || '%')
+`
+
+type CountCoursesParams struct {
+	Language  sql.NullString  `json:"language"`
+	Organiser sql.NullString  `json:"organiser"`
+	Teacher   sql.NullString  `json:"teacher"`
+	Level     sql.NullString  `json:"level"`
+	Credits   sql.NullFloat64 `json:"credits"`
+	Period    sql.NullInt32   `json:"period"`
+	Modality  sql.NullString  `json:"modality"`
+	Campus    sql.NullString  `json:"campus"`
+}
+
+func (q *Queries) CountCourses(ctx context.Context, arg CountCoursesParams) (int64, error) {
+	row := q.db.QueryRowContext(ctx, countCourses,
+		arg.Language,
+		arg.Organiser,
+		arg.Teacher,
+		arg.Level,
+		arg.Credits,
+		arg.Period,
+		arg.Modality,
+		arg.Campus,
+	)
+	var count int64
+	err := row.Scan(&count)
+	return count, err
+}
+
 const createCourse = `-- name: CreateCourse :one
 
 INSERT INTO courses (
   code, name, language, grading_scale, organiser,
-  learning_outcomes, prerequisites, teacher_name, teacher_email, course_link
+  learning_outcomes, prerequisites, teacher_name, teacher_email, course_link,
+  credits, level, teaching_periods, modality, campus
 ) VALUES (
   $1,   $2,   $3,       $4,            $5,
-  $6,               $7,           $8,          $9,           $10
+  $6,               $7,           $8,          $9,           $10,
+  $11,     $12,   $13,              $14,      $15
 )
-RETURNING id, code, name, language, grading_scale, organiser, learning_outcomes, prerequisites, teacher_name, teacher_email, course_link, created_at
+RETURNING id, code, name, language, grading_scale, organiser, learning_outcomes, prerequisites, teacher_name, teacher_email, course_link, created_at, credits, level, teaching_periods, modality, campus
 `
 
 type CreateCourseParams struct {
-	Code             string         `json:"code"`
-	Name             string         `json:"name"`
-	Language         sql.NullString `json:"language"`
-	GradingScale     sql.NullString `json:"grading_scale"`
-	Organiser        sql.NullString `json:"organiser"`
-	LearningOutcomes sql.NullString `json:"learning_outcomes"`
-	Prerequisites    sql.NullString `json:"prerequisites"`
-	TeacherName      sql.NullString `json:"teacher_name"`
-	TeacherEmail     sql.NullString `json:"teacher_email"`
-	CourseLink       sql.NullString `json:"course_link"`
+	Code             string          `json:"code"`
+	Name             string          `json:"name"`
+	Language         sql.NullString  `json:"language"`
+	GradingScale     sql.NullString  `json:"grading_scale"`
+	Organiser        sql.NullString  `json:"organiser"`
+	LearningOutcomes sql.NullString  `json:"learning_outcomes"`
+	Prerequisites    sql.NullString  `json:"prerequisites"`
+	TeacherName      sql.NullString  `json:"teacher_name"`
+	TeacherEmail     sql.NullString  `json:"teacher_email"`
+	CourseLink       sql.NullString  `json:"course_link"`
+	Credits          sql.NullFloat64 `json:"credits"`
+	Level            sql.NullString  `json:"level"`
+	TeachingPeriods  []int32         `json:"teaching_periods"`
+	Modality         sql.NullString  `json:"modality"`
+	Campus           sql.NullString  `json:"campus"`
 }
 
 // server/db/query/course.sql
@@ -48,6 +96,11 @@ func (q *Queries) CreateCourse(ctx context.Context, arg CreateCourseParams) (Cou
 		arg.TeacherName,
 		arg.TeacherEmail,
 		arg.CourseLink,
+		arg.Credits,
+		arg.Level,
+		pq.Array(arg.TeachingPeriods),
+		arg.Modality,
+		arg.Campus,
 	)
 	var i Course
 	err := row.Scan(
@@ -63,12 +116,60 @@ func (q *Queries) CreateCourse(ctx context.Context, arg CreateCourseParams) (Cou
 		&i.TeacherEmail,
 		&i.CourseLink,
 		&i.CreatedAt,
+		&i.Credits,
+		&i.Level,
+		pq.Array(&i.TeachingPeriods),
+		&i.Modality,
+		&i.Campus,
+	)
+	return i, err
+}
+
+const deleteCourse = `-- name: DeleteCourse :execrows
+DELETE FROM courses
+WHERE code = $1
+`
+
+func (q *Queries) DeleteCourse(ctx context.Context, code string) (int64, error) {
+	result, err := q.db.ExecContext(ctx, deleteCourse, code)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+const getCourseByCode = `-- name: GetCourseByCode :one
+SELECT id, code, name, language, grading_scale, organiser, learning_outcomes, prerequisites, teacher_name, teacher_email, course_link, created_at, credits, level, teaching_periods, modality, campus FROM courses
+WHERE code = $1 LIMIT 1
+`
+
+func (q *Queries) GetCourseByCode(ctx context.Context, code string) (Course, error) {
+	row := q.db.QueryRowContext(ctx, getCourseByCode, code)
+	var i Course
+	err := row.Scan(
+		&i.ID,
+		&i.Code,
+		&i.Name,
+		&i.Language,
+		&i.GradingScale,
+		&i.Organiser,
+		&i.LearningOutcomes,
+		&i.Prerequisites,
+		&i.TeacherName,
+		&i.TeacherEmail,
+		&i.CourseLink,
+		&i.CreatedAt,
+		&i.Credits,
+		&i.Level,
+		pq.Array(&i.TeachingPeriods),
+		&i.Modality,
+		&i.Campus,
 	)
 	return i, err
 }
 
 const listAllCourses = `-- name: ListAllCourses :many
-SELECT id, code, name, language, grading_scale, organiser, learning_outcomes, prerequisites, teacher_name, teacher_email, course_link, created_at FROM courses ORDER BY id ASC
+SELECT id, code, name, language, grading_scale, organiser, learning_outcomes, prerequisites, teacher_name, teacher_email, course_link, created_at, credits, level, teaching_periods, modality, campus FROM courses ORDER BY id ASC
 `
 
 func (q *Queries) ListAllCourses(ctx context.Context) ([]Course, error) {
@@ -93,6 +194,11 @@ func (q *Queries) ListAllCourses(ctx context.Context) ([]Course, error) {
 			&i.TeacherEmail,
 			&i.CourseLink,
 			&i.CreatedAt,
+			&i.Credits,
+			&i.Level,
+			pq.Array(&i.TeachingPeriods),
+			&i.Modality,
+			&i.Campus,
 		); err != nil {
 			return nil, err
 		}
@@ -108,13 +214,45 @@ func (q *Queries) ListAllCourses(ctx context.Context) ([]Course, error) {
 }
 
 const listCourses = `-- name: ListCourses :many
-SELECT id, code, name, language, grading_scale, organiser, learning_outcomes, prerequisites, teacher_name, teacher_email, course_link, created_at FROM courses
-ORDER BY id
-LIMIT $1
+SELECT id, code, name, language, grading_scale, organiser, learning_outcomes, prerequisites, teacher_name, teacher_email, course_link, created_at, credits, level, teaching_periods, modality, campus FROM courses
+WHERE ($1::varchar IS NULL OR language ILIKE '%' || $1 || '%')
+  AND ($2::varchar IS NULL OR organiser ILIKE '%' || $2 || '%')
+  AND ($3::varchar IS NULL OR teacher_name ILIKE '%' || $3 || '%')
+  AND ($4::varchar IS NULL OR level = $4)
+  AND ($5::float8 IS NULL OR credits = $5)
+  AND ($6::int IS NULL OR $6 = ANY(teaching_periods))
+  AND ($7::varchar IS NULL OR modality = $7)
+  AND ($8::varchar IS NULL OR campus ILIKE '%' || $8 || '%')
+ORDER BY code
+LIMIT $9 OFFSET $10
 `
 
-func (q *Queries) ListCourses(ctx context.Context, limit int64) ([]Course, error) {
-	rows, err := q.db.QueryContext(ctx, listCourses, limit)
+type ListCoursesParams struct {
+	Language  sql.NullString  `json:"language"`
+	Organiser sql.NullString  `json:"organiser"`
+	Teacher   sql.NullString  `json:"teacher"`
+	Level     sql.NullString  `json:"level"`
+	Credits   sql.NullFloat64 `json:"credits"`
+	Period    sql.NullInt32   `json:"period"`
+	Modality  sql.NullString  `json:"modality"`
+	Campus    sql.NullString  `json:"campus"`
+	Limit     int64           `json:"limit"`
+	Offset    int64           `json:"offset"`
+}
+
+func (q *Queries) ListCourses(ctx context.Context, arg ListCoursesParams) ([]Course, error) {
+	rows, err := q.db.QueryContext(ctx, listCourses,
+		arg.Language,
+		arg.Organiser,
+		arg.Teacher,
+		arg.Level,
+		arg.Credits,
+		arg.Period,
+		arg.Modality,
+		arg.Campus,
+		arg.Limit,
+		arg.Offset,
+	)
 	if err != nil {
 		return nil, err
 	}
@@ -135,6 +273,95 @@ func (q *Queries) ListCourses(ctx context.Context, limit int64) ([]Course, error
 			&i.TeacherEmail,
 			&i.CourseLink,
 			&i.CreatedAt,
+			&i.Credits,
+			&i.Level,
+			pq.Array(&i.TeachingPeriods),
+			&i.Modality,
+			&i.Campus,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const searchCourses = `-- name: SearchCourses :many
+WITH search AS (
+  SELECT
+    websearch_to_tsquery('english', $1::text) AS en,
+    websearch_to_tsquery('finnish', $1::text) AS fi
+)
+SELECT
+  c.id, c.code, c.name, c.language, c.organiser,
+  (
+    ts_rank_cd(course_search_document(c.name, c.learning_outcomes, c.prerequisites), search.en || search.fi, 32)
+    + greatest(similarity(c.code, $1::text), word_similarity($1::text, c.name))
+  )::float8 AS rank,
+  ts_headline('english', c.name, search.en,
+    'HighlightAll=true, StartSel=' || chr(2) || ', StopSel=' || chr(3)) AS name_highlight_en,
+  ts_headline('finnish', c.name, search.fi,
+    'HighlightAll=true, StartSel=' || chr(2) || ', StopSel=' || chr(3)) AS name_highlight_fi,
+  ts_headline('english', concat_ws(' ', c.learning_outcomes, c.prerequisites), search.en,
+    'MaxFragments=2, MaxWords=25, MinWords=8, StartSel=' || chr(2) || ', StopSel=' || chr(3)) AS snippet_en,
+  ts_headline('finnish', concat_ws(' ', c.learning_outcomes, c.prerequisites), search.fi,
+    'MaxFragments=2, MaxWords=25, MinWords=8, StartSel=' || chr(2) || ', StopSel=' || chr(3)) AS snippet_fi
+FROM courses c, search
+WHERE course_search_document(c.name, c.learning_outcomes, c.prerequisites) @@ (search.en || search.fi)
+   OR c.code ILIKE $1::text || '%'
+   OR c.code % $1::text
+   OR $1::text <% c.name
+ORDER BY rank DESC, c.code
+LIMIT $2::int
+`
+
+type SearchCoursesParams struct {
+	Query      string `json:"query"`
+	MaxResults int32  `json:"max_results"`
+}
+
+type SearchCoursesRow struct {
+	ID              int64          `json:"id"`
+	Code            string         `json:"code"`
+	Name            string         `json:"name"`
+	Language        sql.NullString `json:"language"`
+	Organiser       sql.NullString `json:"organiser"`
+	Rank            float64        `json:"rank"`
+	NameHighlightEn string         `json:"name_highlight_en"`
+	NameHighlightFi string         `json:"name_highlight_fi"`
+	SnippetEn       string         `json:"snippet_en"`
+	SnippetFi       string         `json:"snippet_fi"`
+}
+
+// Full-text matches (English or Finnish stemming) and fuzzy code/name matches,
+// best first. Headlines mark hits with chr(2)...chr(3); each language config
+// only highlights its own stems, so both are returned.
+func (q *Queries) SearchCourses(ctx context.Context, arg SearchCoursesParams) ([]SearchCoursesRow, error) {
+	rows, err := q.db.QueryContext(ctx, searchCourses, arg.Query, arg.MaxResults)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []SearchCoursesRow{}
+	for rows.Next() {
+		var i SearchCoursesRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.Code,
+			&i.Name,
+			&i.Language,
+			&i.Organiser,
+			&i.Rank,
+			&i.NameHighlightEn,
+			&i.NameHighlightFi,
+			&i.SnippetEn,
+			&i.SnippetFi,
 		); err != nil {
 			return nil, err
 		}
@@ -148,3 +375,83 @@ func (q *Queries) ListCourses(ctx context.Context, limit int64) ([]Course, error
 	}
 	return items, nil
 }
+
+const updateCourse = `-- name: UpdateCourse :one
+UPDATE courses
+SET
+  name = $2,
+  language = $3,
+  grading_scale = $4,
+  organiser = $5,
+  learning_outcomes = $6,
+  prerequisites = $7,
+  teacher_name = $8,
+  teacher_email = $9,
+  course_link = $10,
+  credits = $11,
+  level = $12,
+  teaching_periods = $13,
+  modality = $14,
+  campus = $15
+WHERE code = $1
+RETURNING id, code, name, language, grading_scale, organiser, learning_outcomes, prerequisites, teacher_name, teacher_email, course_link, created_at, credits, level, teaching_periods, modality, campus
+`
+
+type UpdateCourseParams struct {
+	Code             string          `json:"code"`
+	Name             string          `json:"name"`
+	Language         sql.NullString  `json:"language"`
+	GradingScale     sql.NullString  `json:"grading_scale"`
+	Organiser        sql.NullString  `json:"organiser"`
+	LearningOutcomes sql.NullString  `json:"learning_outcomes"`
+	Prerequisites    sql.NullString  `json:"prerequisites"`
+	TeacherName      sql.NullString  `json:"teacher_name"`
+	TeacherEmail     sql.NullString  `json:"teacher_email"`
+	CourseLink       sql.NullString  `json:"course_link"`
+	Credits          sql.NullFloat64 `json:"credits"`
+	Level            sql.NullString  `json:"level"`
+	TeachingPeriods  []int32         `json:"teaching_periods"`
+	Modality         sql.NullString  `json:"modality"`
+	Campus           sql.NullString  `json:"campus"`
+}
+
+func (q *Queries) UpdateCourse(ctx context.Context, arg UpdateCourseParams) (Course, error) {
+	row := q.db.QueryRowContext(ctx, updateCourse,
+		arg.Code,
+		arg.Name,
+		arg.Language,
+		arg.GradingScale,
+		arg.Organiser,
+		arg.LearningOutcomes,
+		arg.Prerequisites,
+		arg.TeacherName,
+		arg.TeacherEmail,
+		arg.CourseLink,
+		arg.Credits,
+		arg.Level,
+		pq.Array(arg.TeachingPeriods),
+		arg.Modality,
+		arg.Campus,
+	)
+	var i Course
+	err := row.Scan(
+		&i.ID,
+		&i.Code,
+		&i.Name,
+		&i.Language,
+		&i.GradingScale,
+		&i.Organiser,
+		&i.LearningOutcomes,
+		&i.Prerequisites,
+		&i.TeacherName,
+		&i.TeacherEmail,
+		&i.CourseLink,
+		&i.CreatedAt,
+		&i.Credits,
+		&i.Level,
+		pq.Array(&i.TeachingPeriods),
+		&i.Modality,
+		&i.Campus,
+	)
+	return i, err
+}
@@ -11,18 +11,49 @@ import (
 )
 
 type Course struct {
-	ID               int64          `json:"id"`
-	Code             string         `json:"code"`
-	Name             string         `json:"name"`
-	Language         sql.NullString `json:"language"`
-	GradingScale     sql.NullString `json:"grading_scale"`
-	Organiser        sql.NullString `json:"organiser"`
-	LearningOutcomes sql.NullString `json:"learning_outcomes"`
-	Prerequisites    sql.NullString `json:"prerequisites"`
-	TeacherName      sql.NullString `json:"teacher_name"`
-	TeacherEmail     sql.NullString `json:"teacher_email"`
-	CourseLink       sql.NullString `json:"course_link"`
+	ID               int64           `json:"id"`
+	Code             string          `json:"code"`
+	Name             string          `json:"name"`
+	Language         sql.NullString  `json:"language"`
+	GradingScale     sql.NullString  `json:"grading_scale"`
+	Organiser        sql.NullString  `json:"organiser"`
+	LearningOutcomes sql.NullString  `json:"learning_outcomes"`
+	Prerequisites    sql.NullString  `json:"prerequisites"`
+	TeacherName      sql.NullString  `json:"teacher_name"`
+	TeacherEmail     sql.NullString  `json:"teacher_email"`
+	CourseLink       sql.NullString  `json:"course_link"`
+	CreatedAt        time.Time       `json:"created_at"`
+	Credits          sql.NullFloat64 `json:"credits"`
+	Level            sql.NullString  `json:"level"`
+	TeachingPeriods  []int32         `json:"teaching_periods"`
+	Modality         sql.NullString  `json:"modality"`
+	Campus           sql.NullString  `json:"campus"`
+}
+
+type CourseEmbedding struct {
+	CourseID    int64     `json:"course_id"`
+	Model       string    `json:"model"`
+	ContentHash string    `json:"content_hash"`
+	Embedding   []float32 `json:"embedding"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+type CourseFeedback struct {
+	UserUsername     string         `json:"user_username"`
+	CourseID         int64          `json:"course_id"`
+	RecommendationID sql.NullInt64  `json:"recommendation_id"`
+	Kind             string         `json:"kind"`
+	Reason           sql.NullString `json:"reason"`
 	CreatedAt        time.Time      `json:"created_at"`
+	UpdatedAt        time.Time      `json:"updated_at"`
+}
+
+type CoursePrerequisite struct {
+	CourseID    int64           `json:"course_id"`
+	Source      string          `json:"source"`
+	Requirement json.RawMessage `json:"requirement"`
+	Advisory    bool            `json:"advisory"`
+	UpdatedAt   time.Time       `json:"updated_at"`
 }
 
 type Recommendation struct {
@@ -32,6 +63,25 @@ type Recommendation struct {
 	Summary      sql.NullString  `json:"summary"`
 	Payload      json.RawMessage `json:"payload"`
 	CreatedAt    time.Time       `json:"created_at"`
+	Stale        bool            `json:"stale"`
+}
+
+type RecommendationProvenance struct {
+	RecommendationID int64          `json:"recommendation_id"`
+	Mode             string         `json:"mode"`
+	Model            string         `json:"model"`
+	PromptVersion    string         `json:"prompt_version"`
+	InputHash        string         `json:"input_hash"`
+	CandidateCount   int32          `json:"candidate_count"`
+	CompletedCodes   []string       `json:"completed_codes"`
+	EmbeddingModel   sql.NullString `json:"embedding_model"`
+	FallbackReason   sql.NullString `json:"fallback_reason"`
+	Attempts         int32          `json:"attempts"`
+	PromptTokens     int32          `json:"prompt_tokens"`
+	CompletionTokens int32          `json:"completion_tokens"`
+	TotalTokens      int32          `json:"total_tokens"`
+	LatencyMs        int64          `json:"latency_ms"`
+	CreatedAt        time.Time      `json:"created_at"`
 }
 
 type Scholarship struct {
@@ -44,22 +94,68 @@ type Scholarship struct {
 	CreatedAt    time.Time       `json:"created_at"`
 }
 
+type StudyPlan struct {
+	ID           int64           `json:"id"`
+	UserUsername string          `json:"user_username"`
+	TranscriptID sql.NullInt64   `json:"transcript_id"`
+	Title        string          `json:"title"`
+	Plan         json.RawMessage `json:"plan"`
+	CreatedAt    time.Time       `json:"created_at"`
+	UpdatedAt    time.Time       `json:"updated_at"`
+}
+
 type Summary struct {
 	ID               int64          `json:"id"`
 	UserUsername     string         `json:"user_username"`
 	RecommendationID sql.NullInt64  `json:"recommendation_id"`
 	SummaryText      sql.NullString `json:"summary_text"`
-	PdfPath          sql.NullString `json:"pdf_path"`
+	PdfKey           sql.NullString `json:"pdf_key"`
 	CreatedAt        time.Time      `json:"created_at"`
+	StudyPlanID      sql.NullInt64  `json:"study_plan_id"`
 }
 
 type Transcript struct {
 	ID            int64                 `json:"id"`
 	UserUsername  string                `json:"user_username"`
-	FilePath      string                `json:"file_path"`
+	FileKey       string                `json:"file_key"`
 	TextExtracted sql.NullString        `json:"text_extracted"`
 	Meta          []byte         		`json:"meta"`
 	CreatedAt     time.Time             `json:"created_at"`
+	ContentSha256 sql.NullString        `json:"content_sha256"`
+}
+
+type TranscriptCourse struct {
+	ID           int64           `json:"id"`
+	TranscriptID int64           `json:"transcript_id"`
+	Code         string          `json:"code"`
+	Name         sql.NullString  `json:"name"`
+	Credits      sql.NullFloat64 `json:"credits"`
+	Grade        sql.NullString  `json:"grade"`
+	CompletedOn  sql.NullTime    `json:"completed_on"`
+	Status       string          `json:"status"`
+	CreatedAt    time.Time       `json:"created_at"`
+}
+
+type TranscriptJob struct {
+	ID           int64          `json:"id"`
+	TranscriptID int64          `json:"transcript_id"`
+	Status       string         `json:"status"`
+	PagesTotal   int32          `json:"pages_total"`
+	PagesDone    int32          `json:"pages_done"`
+	Attempts     int32          `json:"attempts"`
+	Error        sql.NullString `json:"error"`
+	CreatedAt    time.Time      `json:"created_at"`
+	UpdatedAt    time.Time      `json:"updated_at"`
+	ParseOnly    bool           `json:"parse_only"`
+}
+
+type TranscriptTextRevision struct {
+	ID           int64     `json:"id"`
+	TranscriptID int64     `json:"transcript_id"`
+	Revision     int32     `json:"revision"`
+	Text         string    `json:"text"`
+	Source       string    `json:"source"`
+	CreatedAt    time.Time `json:"created_at"`
 }
 
 type User struct {
@@ -69,4 +165,6 @@ type User struct {
 	Email             string    `json:"email"`
 	PasswordChangedAt time.Time `json:"password_changed_at"`
 	CreatedAt         time.Time `json:"created_at"`
+	StrictRedaction   bool      `json:"strict_redaction"`
+	Role              string    `json:"role"`
 }
@@ -6,35 +6,91 @@ package db
 
 import (
 	"context"
+	"database/sql"
+	"time"
 )
 
 type Querier interface {
+	ClaimTranscriptJob(ctx context.Context) (TranscriptJob, error)
+	CountTranscriptRecommendations(ctx context.Context, transcriptID sql.NullInt64) (int64, error)
+	CountCourses(ctx context.Context, arg CountCoursesParams) (int64, error)
 	// server/db/query/course.sql
 	CreateCourse(ctx context.Context, arg CreateCourseParams) (Course, error)
 	// db/query/recommendation.sql
 	CreateRecommendation(ctx context.Context, arg CreateRecommendationParams) (Recommendation, error)
+	// db/query/recommendation_provenance.sql
+	CreateRecommendationProvenance(ctx context.Context, arg CreateRecommendationProvenanceParams) (RecommendationProvenance, error)
 	// db/query/scholarship.sql
 	CreateScholarship(ctx context.Context, arg CreateScholarshipParams) (Scholarship, error)
+	// db/query/study_plan.sql
+	CreateStudyPlan(ctx context.Context, arg CreateStudyPlanParams) (StudyPlan, error)
 	// db/query/summary.sql
 	CreateSummary(ctx context.Context, arg CreateSummaryParams) (Summary, error)
 	// db/query/transcript.sql
 	CreateTranscript(ctx context.Context, arg CreateTranscriptParams) (Transcript, error)
+	// db/query/transcript_course.sql
+	CreateTranscriptCourse(ctx context.Context, arg CreateTranscriptCourseParams) (TranscriptCourse, error)
+	// db/query/transcript_job.sql
+	CreateTranscriptJob(ctx context.Context, transcriptID int64) (TranscriptJob, error)
+	// db/query/transcript_text_revision.sql
+	CreateTranscriptTextRevision(ctx context.Context, arg CreateTranscriptTextRevisionParams) (TranscriptTextRevision, error)
 	// db/query/user.sql
 	CreateUser(ctx context.Context, arg CreateUserParams) (User, error)
+	DeleteCourse(ctx context.Context, code string) (int64, error)
 	DeleteScholarship(ctx context.Context, arg DeleteScholarshipParams) error
+	DeleteStudyPlan(ctx context.Context, arg DeleteStudyPlanParams) error
 	DeleteSummary(ctx context.Context, arg DeleteSummaryParams) error
+	DeleteTranscript(ctx context.Context, id int64) error
+	DeleteTranscriptCourses(ctx context.Context, transcriptID int64) error
+	DeleteTranscriptRecommendations(ctx context.Context, transcriptID sql.NullInt64) (int64, error)
+	DeleteTranscriptTextRevisions(ctx context.Context, transcriptID int64) error
+	FinishTranscriptJob(ctx context.Context, arg FinishTranscriptJobParams) error
+	GetCourseByCode(ctx context.Context, code string) (Course, error)
+	// db/query/course_prerequisite.sql
+	GetCoursePrerequisites(ctx context.Context, courseID int64) (CoursePrerequisite, error)
+	GetLatestTranscriptJob(ctx context.Context, transcriptID int64) (TranscriptJob, error)
 	GetRecommendation(ctx context.Context, id int64) (Recommendation, error)
+	GetRecommendationProvenance(ctx context.Context, recommendationID int64) (RecommendationProvenance, error)
+	GetStudyPlan(ctx context.Context, id int64) (StudyPlan, error)
 	GetSummary(ctx context.Context, arg GetSummaryParams) (Summary, error)
 	GetTranscript(ctx context.Context, id int64) (Transcript, error)
+	GetTranscriptByHash(ctx context.Context, arg GetTranscriptByHashParams) (Transcript, error)
 	GetUser(ctx context.Context, username string) (User, error)
 	ListAllCourses(ctx context.Context) ([]Course, error)
-	ListCourses(ctx context.Context, limit int64) ([]Course, error)
+	ListCourseEmbeddings(ctx context.Context, model string) ([]CourseEmbedding, error)
+	ListCourseFeedback(ctx context.Context, userUsername string) ([]ListCourseFeedbackRow, error)
+	ListCoursePrerequisites(ctx context.Context) ([]CoursePrerequisite, error)
+	ListCourses(ctx context.Context, arg ListCoursesParams) ([]Course, error)
+	// Courses without parsed prerequisites, or whose text changed since parsing.
+	ListCoursesWithStalePrerequisites(ctx context.Context) ([]Course, error)
 	ListRecentScholarshipsByUser(ctx context.Context, arg ListRecentScholarshipsByUserParams) ([]Scholarship, error)
 	ListRecommendations(ctx context.Context, userUsername string) ([]ListRecommendationsRow, error)
 	ListScholarshipsByUser(ctx context.Context, userUsername string) ([]Scholarship, error)
+	ListStudyPlanSummaryKeys(ctx context.Context, studyPlanID sql.NullInt64) ([]sql.NullString, error)
+	ListStudyPlans(ctx context.Context, userUsername string) ([]StudyPlan, error)
 	ListSummaries(ctx context.Context, userUsername string) ([]Summary, error)
+	ListTranscriptCourses(ctx context.Context, transcriptID int64) ([]TranscriptCourse, error)
+	ListTranscriptSummaryKeys(ctx context.Context, transcriptID sql.NullInt64) ([]sql.NullString, error)
+	ListTranscriptTextRevisions(ctx context.Context, transcriptID int64) ([]TranscriptTextRevision, error)
 	ListTranscripts(ctx context.Context, userUsername string) ([]ListTranscriptsRow, error)
+	MarkTranscriptRecommendationsStale(ctx context.Context, transcriptID sql.NullInt64) (int64, error)
+	RequeueStaleTranscriptJobs(ctx context.Context, updatedAt time.Time) (int64, error)
+	// Full-text matches (English or Finnish stemming) and fuzzy code/name matches,
+	// best first. Headlines mark hits with chr(2)...chr(3); each language config
+	// only highlights its own stems, so both are returned.
+	SearchCourses(ctx context.Context, arg SearchCoursesParams) ([]SearchCoursesRow, error)
+	UpdateCourse(ctx context.Context, arg UpdateCourseParams) (Course, error)
 	UpdateRecommendationPayload(ctx context.Context, arg UpdateRecommendationPayloadParams) (Recommendation, error)
+	UpdateStudyPlan(ctx context.Context, arg UpdateStudyPlanParams) (StudyPlan, error)
+	UpdateTranscriptFile(ctx context.Context, arg UpdateTranscriptFileParams) (Transcript, error)
+	UpdateTranscriptJobProgress(ctx context.Context, arg UpdateTranscriptJobProgressParams) error
+	UpdateTranscriptMeta(ctx context.Context, arg UpdateTranscriptMetaParams) error
+	UpdateTranscriptText(ctx context.Context, arg UpdateTranscriptTextParams) error
+	UpdateUserStrictRedaction(ctx context.Context, arg UpdateUserStrictRedactionParams) (User, error)
+	UpsertCourseEmbedding(ctx context.Context, arg UpsertCourseEmbeddingParams) (CourseEmbedding, error)
+	// db/query/course_feedback.sql
+	UpsertCourseFeedback(ctx context.Context, arg UpsertCourseFeedbackParams) (CourseFeedback, error)
+	UpsertCoursePrerequisites(ctx context.Context, arg UpsertCoursePrerequisitesParams) (CoursePrerequisite, error)
 }
 
 var _ Querier = (*Queries)(nil)
@@ -12,11 +12,23 @@ import (
 	"time"
 )
 
+const countTranscriptRecommendations = `-- name: CountTranscriptRecommendations :one
+SELECT COUNT(*) FROM recommendations
+WHERE transcript_id = $1
+`
+
+func (q *Queries) CountTranscriptRecommendations(ctx context.Context, transcriptID sql.NullInt64) (int64, error) {
+	row := q.db.QueryRowContext(ctx, countTranscriptRecommendations, transcriptID)
+	var count int64
+	err := row.Scan(&count)
+	return count, err
+}
+
 const createRecommendation = `-- name: CreateRecommendation :one
 INSERT INTO recommendations (
   user_username, transcript_id, summary, payload
 ) VALUES ($1, $2, $3, $4)
-RETURNING id, user_username, transcript_id, summary, payload, created_at
+RETURNING id, user_username, transcript_id, summary, payload, created_at, stale
 `
 
 type CreateRecommendationParams struct {
@@ -42,12 +54,26 @@ func (q *Queries) CreateRecommendation(ctx context.Context, arg CreateRecommenda
 		&i.Summary,
 		&i.Payload,
 		&i.CreatedAt,
+		&i.Stale,
 	)
 	return i, err
 }
 
+const deleteTranscriptRecommendations = `-- name: DeleteTranscriptRecommendations :execrows
+DELETE FROM recommendations
+WHERE transcript_id = $1
+`
+
+func (q *Queries) DeleteTranscriptRecommendations(ctx context.Context, transcriptID sql.NullInt64) (int64, error) {
+	result, err := q.db.ExecContext(ctx, deleteTranscriptRecommendations, transcriptID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
 const getRecommendation = `-- name: GetRecommendation :one
-SELECT id, user_username, transcript_id, summary, payload, created_at FROM recommendations WHERE id = $1 LIMIT 1
+SELECT id, user_username, transcript_id, summary, payload, created_at, stale FROM recommendations WHERE id = $1 LIMIT 1
 `
 
 func (q *Queries) GetRecommendation(ctx context.Context, id int64) (Recommendation, error) {
@@ -60,12 +86,13 @@ func (q *Queries) GetRecommendation(ctx context.Context, id int64) (Recommendati
 		&i.Summary,
 		&i.Payload,
 		&i.CreatedAt,
+		&i.Stale,
 	)
 	return i, err
 }
 
 const listRecommendations = `-- name: ListRecommendations :many
-SELECT id, user_username, transcript_id, summary, created_at
+SELECT id, user_username, transcript_id, summary, created_at, stale
 FROM recommendations
 WHERE user_username = $1
 ORDER BY id DESC
@@ -77,6 +104,7 @@ type ListRecommendationsRow struct {
 	TranscriptID sql.NullInt64  `json:"transcript_id"`
 	Summary      sql.NullString `json:"summary"`
 	CreatedAt    time.Time      `json:"created_at"`
+	Stale        bool           `json:"stale"`
 }
 
 func (q *Queries) ListRecommendations(ctx context.Context, userUsername string) ([]ListRecommendationsRow, error) {
@@ -94,6 +122,7 @@ func (q *Queries) ListRecommendations(ctx context.Context, userUsername string)
 			&i.TranscriptID,
 			&i.Summary,
 			&i.CreatedAt,
+			&i.Stale,
 		); err != nil {
 			return nil, err
 		}
@@ -108,11 +137,25 @@ func (q *Queries) ListRecommendations(ctx context.Context, userUsername string)
 	return items, nil
 }
 
+const markTranscriptRecommendationsStale = `-- name: MarkTranscriptRecommendationsStale :execrows
+UPDATE recommendations
+SET stale = true
+WHERE transcript_id = $1 AND NOT stale
+`
+
+func (q *Queries) MarkTranscriptRecommendationsStale(ctx context.Context, transcriptID sql.NullInt64) (int64, error) {
+	result, err := q.db.ExecContext(ctx, markTranscriptRecommendationsStale, transcriptID)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
 const updateRecommendationPayload = `-- name: UpdateRecommendationPayload :one
 UPDATE recommendations
 SET payload = $1
 WHERE id = $2 AND user_username = $3
-RETURNING id, user_username, transcript_id, summary, payload, created_at
+RETURNING id, user_username, transcript_id, summary, payload, created_at, stale
 `
 
 type UpdateRecommendationPayloadParams struct {
@@ -131,6 +174,7 @@ func (q *Queries) UpdateRecommendationPayload(ctx context.Context, arg UpdateRec
 		&i.Summary,
 		&i.Payload,
 		&i.CreatedAt,
+		&i.Stale,
 	)
 	return i, err
 }
@@ -5,11 +5,23 @@ package db
 import (
 	"context"
 	"database/sql"
+	"encoding/json"
+	"fmt"
+	"slices"
 )
 
 // Store defines all database methods we use in EduSphere.
 type Store interface {
 	Querier
+	CreateTranscriptTx(ctx context.Context, arg CreateTranscriptParams) (CreateTranscriptTxResult, error)
+	ReplaceTranscriptCoursesTx(ctx context.Context, arg ReplaceTranscriptCoursesTxParams) ([]TranscriptCourse, error)
+	ReplaceTranscriptFileTx(ctx context.Context, arg UpdateTranscriptFileParams) (CreateTranscriptTxResult, error)
+	CorrectTranscriptTextTx(ctx context.Context, arg CorrectTranscriptTextTxParams) (CorrectTranscriptTextTxResult, error)
+	DeleteTranscriptTx(ctx context.Context, arg DeleteTranscriptTxParams) (DeleteTranscriptTxResult, error)
+	ImportCoursesTx(ctx context.Context, arg ImportCoursesTxParams) (ImportCoursesTxResult, error)
+	CreateCourseTx(ctx context.Context, arg CreateCourseTxParams) (Course, error)
+	UpdateCourseTx(ctx context.Context, arg UpdateCourseTxParams) (Course, error)
+	CreateRecommendationTx(ctx context.Context, arg CreateRecommendationTxParams) (CreateRecommendationTxResult, error)
 }
 
 // SQLStore provides all functions to execute DB queries and transactions.
@@ -43,3 +55,428 @@ func (store *SQLStore) execTx(ctx context.Context, fn func(*Queries) error) erro
 	}
 	return tx.Commit()
 }
+
+// ReplaceTranscriptCoursesTxParams contains the parsed course rows of one transcript.
+type ReplaceTranscriptCoursesTxParams struct {
+	TranscriptID int64
+	Courses      []CreateTranscriptCourseParams
+}
+
+// ReplaceTranscriptCoursesTx deletes any previously parsed course rows of a
+// transcript and inserts the new set within a single transaction.
+func (store *SQLStore) ReplaceTranscriptCoursesTx(ctx context.Context, arg ReplaceTranscriptCoursesTxParams) ([]TranscriptCourse, error) {
+	result := []TranscriptCourse{}
+
+	err := store.execTx(ctx, func(q *Queries) error {
+		if err := q.DeleteTranscriptCourses(ctx, arg.TranscriptID); err != nil {
+			return err
+		}
+
+		for _, course := range arg.Courses {
+			course.TranscriptID = arg.TranscriptID
+			row, err := q.CreateTranscriptCourse(ctx, course)
+			if err != nil {
+				return err
+			}
+			result = append(result, row)
+		}
+		return nil
+	})
+
+	return result, err
+}
+
+// CreateTranscriptTxResult is the newly uploaded transcript and its queued ingestion job.
+type CreateTranscriptTxResult struct {
+	Transcript Transcript    `json:"transcript"`
+	Job        TranscriptJob `json:"job"`
+}
+
+// CreateTranscriptTx creates a transcript row together with a queued ingestion
+// job, so an upload is never stored without the work needed to process it.
+func (store *SQLStore) CreateTranscriptTx(ctx context.Context, arg CreateTranscriptParams) (CreateTranscriptTxResult, error) {
+	var result CreateTranscriptTxResult
+
+	err := store.execTx(ctx, func(q *Queries) error {
+		var err error
+
+		result.Transcript, err = q.CreateTranscript(ctx, arg)
+		if err != nil {
+			return err
+		}
+
+		result.Job, err = q.CreateTranscriptJob(ctx, result.Transcript.ID)
+		return err
+	})
+
+	return result, err
+}
+
+// CreateRecommendationTxParams is a recommendation run and how it was made.
+// Provenance.RecommendationID is set by the transaction.
+type CreateRecommendationTxParams struct {
+	Recommendation CreateRecommendationParams
+	Provenance     CreateRecommendationProvenanceParams
+}
+
+// CreateRecommendationTxResult holds the saved recommendation and provenance.
+type CreateRecommendationTxResult struct {
+	Recommendation Recommendation           `json:"recommendation"`
+	Provenance     RecommendationProvenance `json:"provenance"`
+}
+
+// CreateRecommendationTx saves a recommendation together with its
+// provenance, so no run is stored without a record of how it was made.
+func (store *SQLStore) CreateRecommendationTx(ctx context.Context, arg CreateRecommendationTxParams) (CreateRecommendationTxResult, error) {
+	var result CreateRecommendationTxResult
+
+	err := store.execTx(ctx, func(q *Queries) error {
+		var err error
+
+		result.Recommendation, err = q.CreateRecommendation(ctx, arg.Recommendation)
+		if err != nil {
+			return err
+		}
+
+		provenance := arg.Provenance
+		provenance.RecommendationID = result.Recommendation.ID
+		result.Provenance, err = q.CreateRecommendationProvenance(ctx, provenance)
+		return err
+	})
+
+	return result, err
+}
+
+// ReplaceTranscriptFileTx points a transcript at a new file, drops the course
+// rows and text revisions of the old one, flags recommendations made from it
+// as stale and queues a fresh ingestion job.
+func (store *SQLStore) ReplaceTranscriptFileTx(ctx context.Context, arg UpdateTranscriptFileParams) (CreateTranscriptTxResult, error) {
+	var result CreateTranscriptTxResult
+
+	err := store.execTx(ctx, func(q *Queries) error {
+		var err error
+
+		result.Transcript, err = q.UpdateTranscriptFile(ctx, arg)
+		if err != nil {
+			return err
+		}
+
+		if err := q.DeleteTranscriptCourses(ctx, arg.ID); err != nil {
+			return err
+		}
+		if err := q.DeleteTranscriptTextRevisions(ctx, arg.ID); err != nil {
+			return err
+		}
+		if _, err := q.MarkTranscriptRecommendationsStale(ctx, sql.NullInt64{Int64: arg.ID, Valid: true}); err != nil {
+			return err
+		}
+
+		result.Job, err = q.CreateTranscriptJob(ctx, arg.ID)
+		return err
+	})
+
+	return result, err
+}
+
+// CorrectTranscriptTextTxParams is a manual correction of a transcript's text.
+type CorrectTranscriptTextTxParams struct {
+	Transcript Transcript
+	Text       string
+	// Meta is the transcript meta to store with the corrected text
+	Meta []byte
+}
+
+// CorrectTranscriptTextTxResult is the stored revision and the queued job that
+// re-parses the courses from the corrected text.
+type CorrectTranscriptTextTxResult struct {
+	Revision                   TranscriptTextRevision `json:"revision"`
+	Job                        TranscriptJob          `json:"job"`
+	RecommendationsMarkedStale int64                  `json:"recommendations_marked_stale"`
+}
+
+// CorrectTranscriptTextTx replaces the text of a transcript with a manual
+// correction. The extracted text is kept as revision 1 the first time, every
+// correction adds a revision, recommendations made from the transcript are
+// flagged as stale and a job is queued to re-parse the courses.
+func (store *SQLStore) CorrectTranscriptTextTx(ctx context.Context, arg CorrectTranscriptTextTxParams) (CorrectTranscriptTextTxResult, error) {
+	var result CorrectTranscriptTextTxResult
+
+	err := store.execTx(ctx, func(q *Queries) error {
+		tr := arg.Transcript
+
+		revisions, err := q.ListTranscriptTextRevisions(ctx, tr.ID)
+		if err != nil {
+			return err
+		}
+		if len(revisions) == 0 && tr.TextExtracted.Valid {
+			if _, err := q.CreateTranscriptTextRevision(ctx, CreateTranscriptTextRevisionParams{
+				TranscriptID: tr.ID,
+				Text:         tr.TextExtracted.String,
+				Source:       "extracted",
+			}); err != nil {
+				return err
+			}
+		}
+
+		result.Revision, err = q.CreateTranscriptTextRevision(ctx, CreateTranscriptTextRevisionParams{
+			TranscriptID: tr.ID,
+			Text:         arg.Text,
+			Source:       "manual",
+		})
+		if err != nil {
+			return err
+		}
+
+		if err := q.UpdateTranscriptText(ctx, UpdateTranscriptTextParams{
+			ID:            tr.ID,
+			TextExtracted: sql.NullString{String: arg.Text, Valid: true},
+			Meta:          arg.Meta,
+		}); err != nil {
+			return err
+		}
+
+		result.RecommendationsMarkedStale, err = q.MarkTranscriptRecommendationsStale(ctx, sql.NullInt64{Int64: tr.ID, Valid: true})
+		if err != nil {
+			return err
+		}
+
+		result.Job, err = q.CreateTranscriptJob(ctx, tr.ID)
+		return err
+	})
+
+	return result, err
+}
+
+// DeleteTranscriptTxParams selects the transcript to delete and what happens
+// to the recommendations generated from it.
+type DeleteTranscriptTxParams struct {
+	ID int64
+	// DeleteRecommendations deletes linked recommendations (and their summaries);
+	// otherwise they are kept and unlinked from the transcript
+	DeleteRecommendations bool
+}
+
+// DeleteTranscriptTxResult reports what happened to linked recommendations.
+type DeleteTranscriptTxResult struct {
+	RecommendationsDeleted  int64 `json:"recommendations_deleted"`
+	RecommendationsUnlinked int64 `json:"recommendations_unlinked"`
+	// SummaryPDFKeys are the storage keys of summaries deleted with the recommendations
+	SummaryPDFKeys []string `json:"-"`
+}
+
+// DeleteTranscriptTx deletes a transcript row. Course rows and ingestion jobs
+// cascade; recommendations are deleted (with their summaries) or unlinked
+// (ON DELETE SET NULL).
+func (store *SQLStore) DeleteTranscriptTx(ctx context.Context, arg DeleteTranscriptTxParams) (DeleteTranscriptTxResult, error) {
+	var result DeleteTranscriptTxResult
+
+	err := store.execTx(ctx, func(q *Queries) error {
+		transcriptID := sql.NullInt64{Int64: arg.ID, Valid: true}
+
+		if arg.DeleteRecommendations {
+			keys, err := q.ListTranscriptSummaryKeys(ctx, transcriptID)
+			if err != nil {
+				return err
+			}
+			for _, k := range keys {
+				result.SummaryPDFKeys = append(result.SummaryPDFKeys, k.String)
+			}
+
+			n, err := q.DeleteTranscriptRecommendations(ctx, transcriptID)
+			if err != nil {
+				return err
+			}
+			result.RecommendationsDeleted = n
+		} else {
+			n, err := q.CountTranscriptRecommendations(ctx, transcriptID)
+			if err != nil {
+				return err
+			}
+			result.RecommendationsUnlinked = n
+		}
+
+		return q.DeleteTranscript(ctx, arg.ID)
+	})
+
+	return result, err
+}
+
+// ImportCoursesTxParams is a catalogue import; courses are matched by code.
+type ImportCoursesTxParams struct {
+	Courses []CreateCourseParams
+	// Parsed prerequisites by course code, for every course in Courses
+	Prerequisites map[string]CoursePrerequisitesParams
+	// DryRun compares the courses with the catalogue without writing anything
+	DryRun bool
+}
+
+// ImportCoursesTxResult lists the imported course codes by outcome.
+type ImportCoursesTxResult struct {
+	Inserted  []string `json:"inserted"`
+	Updated   []string `json:"updated"`
+	Unchanged []string `json:"unchanged"`
+}
+
+// ImportCoursesTx upserts catalogue courses by code within a single
+// transaction: new codes are inserted, existing courses whose fields differ
+// are updated and the rest are left alone. Written courses get their parsed
+// prerequisites stored as well.
+func (store *SQLStore) ImportCoursesTx(ctx context.Context, arg ImportCoursesTxParams) (ImportCoursesTxResult, error) {
+	var result ImportCoursesTxResult
+
+	err := store.execTx(ctx, func(q *Queries) error {
+		existing, err := q.ListAllCourses(ctx)
+		if err != nil {
+			return err
+		}
+		byCode := make(map[string]Course, len(existing))
+		for _, c := range existing {
+			byCode[c.Code] = c
+		}
+
+		for _, c := range arg.Courses {
+			current, ok := byCode[c.Code]
+			switch {
+			case !ok:
+				result.Inserted = append(result.Inserted, c.Code)
+				if !arg.DryRun {
+					course, err := q.CreateCourse(ctx, c)
+					if err != nil {
+						return err
+					}
+					if err := storeImportedPrerequisites(ctx, q, course, arg.Prerequisites); err != nil {
+						return err
+					}
+				}
+			case courseDiffers(current, c):
+				result.Updated = append(result.Updated, c.Code)
+				if !arg.DryRun {
+					course, err := q.UpdateCourse(ctx, UpdateCourseParams{
+						Code:             c.Code,
+						Name:             c.Name,
+						Language:         c.Language,
+						GradingScale:     c.GradingScale,
+						Organiser:        c.Organiser,
+						LearningOutcomes: c.LearningOutcomes,
+						Prerequisites:    c.Prerequisites,
+						TeacherName:      c.TeacherName,
+						TeacherEmail:     c.TeacherEmail,
+						CourseLink:       c.CourseLink,
+						Credits:          c.Credits,
+						Level:            c.Level,
+						TeachingPeriods:  c.TeachingPeriods,
+						Modality:         c.Modality,
+						Campus:           c.Campus,
+					})
+					if err != nil {
+						return err
+					}
+					if err := storeImportedPrerequisites(ctx, q, course, arg.Prerequisites); err != nil {
+						return err
+					}
+				}
+			default:
+				result.Unchanged = append(result.Unchanged, c.Code)
+			}
+		}
+		return nil
+	})
+
+	return result, err
+}
+
+// courseDiffers reports whether importing c would change the stored course.
+func courseDiffers(current Course, c CreateCourseParams) bool {
+	return current.Name != c.Name ||
+		current.Language != c.Language ||
+		current.GradingScale != c.GradingScale ||
+		current.Organiser != c.Organiser ||
+		current.LearningOutcomes != c.LearningOutcomes ||
+		current.Prerequisites != c.Prerequisites ||
+		current.TeacherName != c.TeacherName ||
+		current.TeacherEmail != c.TeacherEmail ||
+		current.CourseLink != c.CourseLink ||
+		current.Credits != c.Credits ||
+		current.Level != c.Level ||
+		!slices.Equal(current.TeachingPeriods, c.TeachingPeriods) ||
+		current.Modality != c.Modality ||
+		current.Campus != c.Campus
+}
+
+// CoursePrerequisitesParams is the parsed prerequisite graph of a course.
+// Source is the prerequisites text it was parsed from and Requirement the
+// JSON requirement ("null" when the text names no courses).
+type CoursePrerequisitesParams struct {
+	Source      string
+	Requirement json.RawMessage
+	Advisory    bool
+}
+
+// CreateCourseTxParams is a new catalogue course and its parsed
+// prerequisites.
+type CreateCourseTxParams struct {
+	Course        CreateCourseParams
+	Prerequisites CoursePrerequisitesParams
+}
+
+// CreateCourseTx creates a catalogue course and stores its parsed
+// prerequisites within a single transaction.
+func (store *SQLStore) CreateCourseTx(ctx context.Context, arg CreateCourseTxParams) (Course, error) {
+	var course Course
+
+	err := store.execTx(ctx, func(q *Queries) error {
+		var err error
+		course, err = q.CreateCourse(ctx, arg.Course)
+		if err != nil {
+			return err
+		}
+		return storeCoursePrerequisites(ctx, q, course.ID, arg.Prerequisites)
+	})
+
+	return course, err
+}
+
+// UpdateCourseTxParams is an updated catalogue course and its parsed
+// prerequisites.
+type UpdateCourseTxParams struct {
+	Course        UpdateCourseParams
+	Prerequisites CoursePrerequisitesParams
+}
+
+// UpdateCourseTx updates a catalogue course and stores its parsed
+// prerequisites within a single transaction.
+func (store *SQLStore) UpdateCourseTx(ctx context.Context, arg UpdateCourseTxParams) (Course, error) {
+	var course Course
+
+	err := store.execTx(ctx, func(q *Queries) error {
+		var err error
+		course, err = q.UpdateCourse(ctx, arg.Course)
+		if err != nil {
+			return err
+		}
+		return storeCoursePrerequisites(ctx, q, course.ID, arg.Prerequisites)
+	})
+
+	return course, err
+}
+
+// storeImportedPrerequisites stores the parsed prerequisites of an imported
+// course, looked up by its code.
+func storeImportedPrerequisites(ctx context.Context, q *Queries, course Course, byCode map[string]CoursePrerequisitesParams) error {
+	p, ok := byCode[course.Code]
+	if !ok {
+		return fmt.Errorf("no parsed prerequisites for %s", course.Code)
+	}
+	return storeCoursePrerequisites(ctx, q, course.ID, p)
+}
+
+// storeCoursePrerequisites stores the parsed prerequisite graph of a course.
+func storeCoursePrerequisites(ctx context.Context, q *Queries, courseID int64, p CoursePrerequisitesParams) error {
+	_, err := q.UpsertCoursePrerequisites(ctx, UpsertCoursePrerequisitesParams{
+		CourseID:    courseID,
+		Source:      p.Source,
+		Requirement: p.Requirement,
+		Advisory:    p.Advisory,
+	})
+	return err
+}
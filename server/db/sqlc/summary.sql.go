@@ -12,16 +12,17 @@ import (
 
 const createSummary = `-- name: CreateSummary :one
 INSERT INTO summaries (
-  user_username, recommendation_id, summary_text, pdf_path
-) VALUES ($1, $2, $3, $4)
-RETURNING id, user_username, recommendation_id, summary_text, pdf_path, created_at
+  user_username, recommendation_id, summary_text, pdf_key, study_plan_id
+) VALUES ($1, $2, $3, $4, $5)
+RETURNING id, user_username, recommendation_id, summary_text, pdf_key, created_at, study_plan_id
 `
 
 type CreateSummaryParams struct {
 	UserUsername     string         `json:"user_username"`
 	RecommendationID sql.NullInt64  `json:"recommendation_id"`
 	SummaryText      sql.NullString `json:"summary_text"`
-	PdfPath          sql.NullString `json:"pdf_path"`
+	PdfKey           sql.NullString `json:"pdf_key"`
+	StudyPlanID      sql.NullInt64  `json:"study_plan_id"`
 }
 
 // db/query/summary.sql
@@ -30,7 +31,8 @@ func (q *Queries) CreateSummary(ctx context.Context, arg CreateSummaryParams) (S
 		arg.UserUsername,
 		arg.RecommendationID,
 		arg.SummaryText,
-		arg.PdfPath,
+		arg.PdfKey,
+		arg.StudyPlanID,
 	)
 	var i Summary
 	err := row.Scan(
@@ -38,8 +40,9 @@ func (q *Queries) CreateSummary(ctx context.Context, arg CreateSummaryParams) (S
 		&i.UserUsername,
 		&i.RecommendationID,
 		&i.SummaryText,
-		&i.PdfPath,
+		&i.PdfKey,
 		&i.CreatedAt,
+		&i.StudyPlanID,
 	)
 	return i, err
 }
@@ -61,7 +64,7 @@ func (q *Queries) DeleteSummary(ctx context.Context, arg DeleteSummaryParams) er
 }
 
 const getSummary = `-- name: GetSummary :one
-SELECT id, user_username, recommendation_id, summary_text, pdf_path, created_at
+SELECT id, user_username, recommendation_id, summary_text, pdf_key, created_at, study_plan_id
 FROM summaries
 WHERE id = $1
   AND user_username = $2
@@ -81,14 +84,45 @@ func (q *Queries) GetSummary(ctx context.Context, arg GetSummaryParams) (Summary
 		&i.UserUsername,
 		&i.RecommendationID,
 		&i.SummaryText,
-		&i.PdfPath,
+		&i.PdfKey,
 		&i.CreatedAt,
+		&i.StudyPlanID,
 	)
 	return i, err
 }
 
+const listStudyPlanSummaryKeys = `-- name: ListStudyPlanSummaryKeys :many
+SELECT pdf_key
+FROM summaries
+WHERE study_plan_id = $1
+  AND pdf_key IS NOT NULL
+`
+
+func (q *Queries) ListStudyPlanSummaryKeys(ctx context.Context, studyPlanID sql.NullInt64) ([]sql.NullString, error) {
+	rows, err := q.db.QueryContext(ctx, listStudyPlanSummaryKeys, studyPlanID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []sql.NullString{}
+	for rows.Next() {
+		var pdf_key sql.NullString
+		if err := rows.Scan(&pdf_key); err != nil {
+			return nil, err
+		}
+		items = append(items, pdf_key)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const listSummaries = `-- name: ListSummaries :many
-SELECT id, user_username, recommendation_id, summary_text, pdf_path, created_at
+SELECT id, user_username, recommendation_id, summary_text, pdf_key, created_at, study_plan_id
 FROM summaries
 WHERE user_username = $1
 ORDER BY created_at DESC
@@ -108,8 +142,9 @@ func (q *Queries) ListSummaries(ctx context.Context, userUsername string) ([]Sum
 			&i.UserUsername,
 			&i.RecommendationID,
 			&i.SummaryText,
-			&i.PdfPath,
+			&i.PdfKey,
 			&i.CreatedAt,
+			&i.StudyPlanID,
 		); err != nil {
 			return nil, err
 		}
@@ -123,3 +158,34 @@ func (q *Queries) ListSummaries(ctx context.Context, userUsername string) ([]Sum
 	}
 	return items, nil
 }
+
+const listTranscriptSummaryKeys = `-- name: ListTranscriptSummaryKeys :many
+SELECT s.pdf_key
+FROM summaries s
+JOIN recommendations r ON r.id = s.recommendation_id
+WHERE r.transcript_id = $1
+  AND s.pdf_key IS NOT NULL
+`
+
+func (q *Queries) ListTranscriptSummaryKeys(ctx context.Context, transcriptID sql.NullInt64) ([]sql.NullString, error) {
+	rows, err := q.db.QueryContext(ctx, listTranscriptSummaryKeys, transcriptID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []sql.NullString{}
+	for rows.Next() {
+		var pdf_key sql.NullString
+		if err := rows.Scan(&pdf_key); err != nil {
+			return nil, err
+		}
+		items = append(items, pdf_key)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
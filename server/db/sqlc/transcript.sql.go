@@ -13,40 +13,52 @@ import (
 
 const createTranscript = `-- name: CreateTranscript :one
 INSERT INTO transcripts (
-  user_username, file_path, text_extracted, meta
-) VALUES ($1, $2, $3, $4)
-RETURNING id, user_username, file_path, text_extracted, meta, created_at
+  user_username, file_key, text_extracted, meta, content_sha256
+) VALUES ($1, $2, $3, $4, $5)
+RETURNING id, user_username, file_key, text_extracted, meta, created_at, content_sha256
 `
 
 type CreateTranscriptParams struct {
 	UserUsername  string                `json:"user_username"`
-	FilePath      string                `json:"file_path"`
+	FileKey      string                `json:"file_key"`
 	TextExtracted sql.NullString        `json:"text_extracted"`
 	Meta          []byte         		`json:"meta"`
+	ContentSha256 sql.NullString        `json:"content_sha256"`
 }
 
 // db/query/transcript.sql
 func (q *Queries) CreateTranscript(ctx context.Context, arg CreateTranscriptParams) (Transcript, error) {
 	row := q.db.QueryRowContext(ctx, createTranscript,
 		arg.UserUsername,
-		arg.FilePath,
+		arg.FileKey,
 		arg.TextExtracted,
 		arg.Meta,
+		arg.ContentSha256,
 	)
 	var i Transcript
 	err := row.Scan(
 		&i.ID,
 		&i.UserUsername,
-		&i.FilePath,
+		&i.FileKey,
 		&i.TextExtracted,
 		&i.Meta,
 		&i.CreatedAt,
+		&i.ContentSha256,
 	)
 	return i, err
 }
 
+const deleteTranscript = `-- name: DeleteTranscript :exec
+DELETE FROM transcripts WHERE id = $1
+`
+
+func (q *Queries) DeleteTranscript(ctx context.Context, id int64) error {
+	_, err := q.db.ExecContext(ctx, deleteTranscript, id)
+	return err
+}
+
 const getTranscript = `-- name: GetTranscript :one
-SELECT id, user_username, file_path, text_extracted, meta, created_at FROM transcripts WHERE id = $1 LIMIT 1
+SELECT id, user_username, file_key, text_extracted, meta, created_at, content_sha256 FROM transcripts WHERE id = $1 LIMIT 1
 `
 
 func (q *Queries) GetTranscript(ctx context.Context, id int64) (Transcript, error) {
@@ -55,16 +67,43 @@ func (q *Queries) GetTranscript(ctx context.Context, id int64) (Transcript, erro
 	err := row.Scan(
 		&i.ID,
 		&i.UserUsername,
-		&i.FilePath,
+		&i.FileKey,
+		&i.TextExtracted,
+		&i.Meta,
+		&i.CreatedAt,
+		&i.ContentSha256,
+	)
+	return i, err
+}
+
+const getTranscriptByHash = `-- name: GetTranscriptByHash :one
+SELECT id, user_username, file_key, text_extracted, meta, created_at, content_sha256 FROM transcripts
+WHERE user_username = $1 AND content_sha256 = $2
+LIMIT 1
+`
+
+type GetTranscriptByHashParams struct {
+	UserUsername  string         `json:"user_username"`
+	ContentSha256 sql.NullString `json:"content_sha256"`
+}
+
+func (q *Queries) GetTranscriptByHash(ctx context.Context, arg GetTranscriptByHashParams) (Transcript, error) {
+	row := q.db.QueryRowContext(ctx, getTranscriptByHash, arg.UserUsername, arg.ContentSha256)
+	var i Transcript
+	err := row.Scan(
+		&i.ID,
+		&i.UserUsername,
+		&i.FileKey,
 		&i.TextExtracted,
 		&i.Meta,
 		&i.CreatedAt,
+		&i.ContentSha256,
 	)
 	return i, err
 }
 
 const listTranscripts = `-- name: ListTranscripts :many
-SELECT id, user_username, file_path, created_at
+SELECT id, user_username, file_key, created_at
 FROM transcripts
 WHERE user_username = $1
 ORDER BY id DESC
@@ -73,7 +112,7 @@ ORDER BY id DESC
 type ListTranscriptsRow struct {
 	ID           int64     `json:"id"`
 	UserUsername string    `json:"user_username"`
-	FilePath     string    `json:"file_path"`
+	FileKey     string    `json:"file_key"`
 	CreatedAt    time.Time `json:"created_at"`
 }
 
@@ -89,7 +128,7 @@ func (q *Queries) ListTranscripts(ctx context.Context, userUsername string) ([]L
 		if err := rows.Scan(
 			&i.ID,
 			&i.UserUsername,
-			&i.FilePath,
+			&i.FileKey,
 			&i.CreatedAt,
 		); err != nil {
 			return nil, err
@@ -104,3 +143,70 @@ func (q *Queries) ListTranscripts(ctx context.Context, userUsername string) ([]L
 	}
 	return items, nil
 }
+
+const updateTranscriptFile = `-- name: UpdateTranscriptFile :one
+UPDATE transcripts
+SET file_key = $2, content_sha256 = $3, meta = $4, text_extracted = NULL
+WHERE id = $1
+RETURNING id, user_username, file_key, text_extracted, meta, created_at, content_sha256
+`
+
+type UpdateTranscriptFileParams struct {
+	ID            int64          `json:"id"`
+	FileKey      string         `json:"file_key"`
+	ContentSha256 sql.NullString `json:"content_sha256"`
+	Meta          []byte         `json:"meta"`
+}
+
+func (q *Queries) UpdateTranscriptFile(ctx context.Context, arg UpdateTranscriptFileParams) (Transcript, error) {
+	row := q.db.QueryRowContext(ctx, updateTranscriptFile,
+		arg.ID,
+		arg.FileKey,
+		arg.ContentSha256,
+		arg.Meta,
+	)
+	var i Transcript
+	err := row.Scan(
+		&i.ID,
+		&i.UserUsername,
+		&i.FileKey,
+		&i.TextExtracted,
+		&i.Meta,
+		&i.CreatedAt,
+		&i.ContentSha256,
+	)
+	return i, err
+}
+
+const updateTranscriptMeta = `-- name: UpdateTranscriptMeta :exec
+UPDATE transcripts
+SET meta = $2
+WHERE id = $1
+`
+
+type UpdateTranscriptMetaParams struct {
+	ID   int64  `json:"id"`
+	Meta []byte `json:"meta"`
+}
+
+func (q *Queries) UpdateTranscriptMeta(ctx context.Context, arg UpdateTranscriptMetaParams) error {
+	_, err := q.db.ExecContext(ctx, updateTranscriptMeta, arg.ID, arg.Meta)
+	return err
+}
+
+const updateTranscriptText = `-- name: UpdateTranscriptText :exec
+UPDATE transcripts
+SET text_extracted = $2, meta = $3
+WHERE id = $1
+`
+
+type UpdateTranscriptTextParams struct {
+	ID            int64          `json:"id"`
+	TextExtracted sql.NullString `json:"text_extracted"`
+	Meta          []byte         `json:"meta"`
+}
+
+func (q *Queries) UpdateTranscriptText(ctx context.Context, arg UpdateTranscriptTextParams) error {
+	_, err := q.db.ExecContext(ctx, updateTranscriptText, arg.ID, arg.TextExtracted, arg.Meta)
+	return err
+}
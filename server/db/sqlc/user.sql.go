@@ -18,7 +18,7 @@ INSERT INTO users (
 ) VALUES (
   $1, $2, $3, $4
 )
-RETURNING username, hashed_password, full_name, email, password_changed_at, created_at
+RETURNING username, hashed_password, full_name, email, password_changed_at, created_at, strict_redaction, role
 `
 
 type CreateUserParams struct {
@@ -44,12 +44,14 @@ func (q *Queries) CreateUser(ctx context.Context, arg CreateUserParams) (User, e
 		&i.Email,
 		&i.PasswordChangedAt,
 		&i.CreatedAt,
+		&i.StrictRedaction,
+		&i.Role,
 	)
 	return i, err
 }
 
 const getUser = `-- name: GetUser :one
-SELECT username, hashed_password, full_name, email, password_changed_at, created_at FROM users
+SELECT username, hashed_password, full_name, email, password_changed_at, created_at, strict_redaction, role FROM users
 WHERE username = $1 LIMIT 1
 `
 
@@ -63,6 +65,36 @@ func (q *Queries) GetUser(ctx context.Context, username string) (User, error) {
 		&i.Email,
 		&i.PasswordChangedAt,
 		&i.CreatedAt,
+		&i.StrictRedaction,
+		&i.Role,
+	)
+	return i, err
+}
+
+const updateUserStrictRedaction = `-- name: UpdateUserStrictRedaction :one
+UPDATE users
+SET strict_redaction = $2
+WHERE username = $1
+RETURNING username, hashed_password, full_name, email, password_changed_at, created_at, strict_redaction, role
+`
+
+type UpdateUserStrictRedactionParams struct {
+	Username        string `json:"username"`
+	StrictRedaction bool   `json:"strict_redaction"`
+}
+
+func (q *Queries) UpdateUserStrictRedaction(ctx context.Context, arg UpdateUserStrictRedactionParams) (User, error) {
+	row := q.db.QueryRowContext(ctx, updateUserStrictRedaction, arg.Username, arg.StrictRedaction)
+	var i User
+	err := row.Scan(
+		&i.Username,
+		&i.HashedPassword,
+		&i.FullName,
+		&i.Email,
+		&i.PasswordChangedAt,
+		&i.CreatedAt,
+		&i.StrictRedaction,
+		&i.Role,
 	)
 	return i, err
 }
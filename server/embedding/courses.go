@@ -0,0 +1,132 @@
+// server/embedding/courses.go
+
+package embedding
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"sort"
+	"strings"
+
+	db "github.com/nibir1/go-fiber-postgres-REST-boilerplate/db/sqlc"
+)
+
+// Store is the part of db.Store that course embeddings use
+type Store interface {
+	ListCourseEmbeddings(ctx context.Context, model string) ([]db.CourseEmbedding, error)
+	UpsertCourseEmbedding(ctx context.Context, arg db.UpsertCourseEmbeddingParams) (db.CourseEmbedding, error)
+}
+
+// CourseText is the text embedded for a course: its code, name, level and
+// learning outcomes.
+func CourseText(course db.Course) string {
+	parts := []string{course.Code, course.Name}
+	if course.Level.Valid {
+		parts = append(parts, course.Level.String+" level")
+	}
+	if course.LearningOutcomes.Valid {
+		parts = append(parts, course.LearningOutcomes.String)
+	}
+	return strings.Join(parts, "\n")
+}
+
+func contentHash(text string) string {
+	sum := sha256.Sum256([]byte(text))
+	return hex.EncodeToString(sum[:])
+}
+
+type indexEntry struct {
+	hash   string
+	vector []float32
+}
+
+// Index holds course embeddings of one model in memory for similarity search
+type Index struct {
+	model   string
+	entries map[int64]indexEntry
+}
+
+// Model returns the model of the indexed embeddings.
+func (ix *Index) Model() string {
+	return ix.model
+}
+
+// Len returns the number of indexed courses.
+func (ix *Index) Len() int {
+	return len(ix.entries)
+}
+
+// Sync brings ix up to date for courses: courses that have no embedding from
+// e's model, or whose text changed since it was made, are embedded and saved.
+// A nil ix, or one of another model, is loaded from the store first. It
+// returns the index and the number of courses embedded.
+func Sync(ctx context.Context, store Store, e Embedder, ix *Index, courses []db.Course) (*Index, int, error) {
+	if ix == nil || ix.model != e.Model() {
+		rows, err := store.ListCourseEmbeddings(ctx, e.Model())
+		if err != nil {
+			return nil, 0, fmt.Errorf("failed to load course embeddings: %w", err)
+		}
+		ix = &Index{model: e.Model(), entries: make(map[int64]indexEntry, len(rows))}
+		for _, row := range rows {
+			ix.entries[row.CourseID] = indexEntry{hash: row.ContentHash, vector: row.Embedding}
+		}
+	}
+
+	var (
+		stale  []db.Course
+		texts  []string
+		hashes []string
+	)
+	for _, course := range courses {
+		text := CourseText(course)
+		hash := contentHash(text)
+		if entry, ok := ix.entries[course.ID]; ok && entry.hash == hash {
+			continue
+		}
+		stale = append(stale, course)
+		texts = append(texts, text)
+		hashes = append(hashes, hash)
+	}
+	if len(stale) == 0 {
+		return ix, 0, nil
+	}
+
+	vectors, err := e.Embed(ctx, texts)
+	if err != nil {
+		return ix, 0, fmt.Errorf("failed to embed courses: %w", err)
+	}
+	for i, course := range stale {
+		if _, err := store.UpsertCourseEmbedding(ctx, db.UpsertCourseEmbeddingParams{
+			CourseID:    course.ID,
+			Model:       ix.model,
+			ContentHash: hashes[i],
+			Embedding:   vectors[i],
+		}); err != nil {
+			return ix, i, fmt.Errorf("failed to save embedding of %s: %w", course.Code, err)
+		}
+		ix.entries[course.ID] = indexEntry{hash: hashes[i], vector: vectors[i]}
+	}
+	return ix, len(stale), nil
+}
+
+// Match is a course and its similarity to a query
+type Match struct {
+	CourseID int64   `json:"course_id"`
+	Score    float64 `json:"score"`
+}
+
+// Search returns up to n of the given courses most similar to query, best
+// first; ties keep the order of ids. Courses without an embedding score 0.
+func (ix *Index) Search(query []float32, ids []int64, n int) []Match {
+	matches := make([]Match, 0, len(ids))
+	for _, id := range ids {
+		matches = append(matches, Match{CourseID: id, Score: Cosine(query, ix.entries[id].vector)})
+	}
+	sort.SliceStable(matches, func(i, j int) bool { return matches[i].Score > matches[j].Score })
+	if n >= 0 && len(matches) > n {
+		matches = matches[:n]
+	}
+	return matches
+}
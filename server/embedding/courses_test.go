@@ -0,0 +1,110 @@
+// server/embedding/courses_test.go
+
+package embedding
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	db "github.com/nibir1/go-fiber-postgres-REST-boilerplate/db/sqlc"
+)
+
+// memStore keeps course embeddings in memory, keyed by model and course
+type memStore struct {
+	rows    map[string]map[int64]db.CourseEmbedding
+	upserts int
+}
+
+func (m *memStore) ListCourseEmbeddings(_ context.Context, model string) ([]db.CourseEmbedding, error) {
+	var out []db.CourseEmbedding
+	for _, row := range m.rows[model] {
+		out = append(out, row)
+	}
+	return out, nil
+}
+
+func (m *memStore) UpsertCourseEmbedding(_ context.Context, arg db.UpsertCourseEmbeddingParams) (db.CourseEmbedding, error) {
+	if m.rows == nil {
+		m.rows = map[string]map[int64]db.CourseEmbedding{}
+	}
+	if m.rows[arg.Model] == nil {
+		m.rows[arg.Model] = map[int64]db.CourseEmbedding{}
+	}
+	row := db.CourseEmbedding{CourseID: arg.CourseID, Model: arg.Model, ContentHash: arg.ContentHash, Embedding: arg.Embedding}
+	m.rows[arg.Model][arg.CourseID] = row
+	m.upserts++
+	return row, nil
+}
+
+func testCourse(id int64, code, name, outcomes string) db.Course {
+	return db.Course{
+		ID:               id,
+		Code:             code,
+		Name:             name,
+		LearningOutcomes: sql.NullString{String: outcomes, Valid: outcomes != ""},
+	}
+}
+
+func TestCourseText(t *testing.T) {
+	course := testCourse(1, "TJTA1010", "Databases", "SQL and data modelling")
+	require.Equal(t, "TJTA1010\nDatabases\nSQL and data modelling", CourseText(course))
+
+	course.Level = sql.NullString{String: "master", Valid: true}
+	require.Equal(t, "TJTA1010\nDatabases\nmaster level\nSQL and data modelling", CourseText(course))
+}
+
+func TestSyncAndSearch(t *testing.T) {
+	ctx := context.Background()
+	e := NewLocalEmbedder(256)
+	store := &memStore{}
+	courses := []db.Course{
+		testCourse(1, "TIES3270", "Cyber Security", "Threats, cryptography and network security"),
+		testCourse(2, "KIEL1001", "Finnish 1", "Everyday Finnish conversation"),
+		testCourse(3, "TIEA2150", "Machine Learning", "Neural networks and statistical learning"),
+	}
+
+	// 1) Everything is embedded and stored the first time
+	ix, embedded, err := Sync(ctx, store, e, nil, courses)
+	require.NoError(t, err)
+	require.Equal(t, 3, embedded)
+	require.Equal(t, 3, store.upserts)
+	require.Equal(t, 3, ix.Len())
+	require.Equal(t, e.Model(), ix.Model())
+
+	// 2) Nothing changed, whether the index is reused or reloaded
+	_, embedded, err = Sync(ctx, store, e, ix, courses)
+	require.NoError(t, err)
+	require.Zero(t, embedded)
+	ix, embedded, err = Sync(ctx, store, e, nil, courses)
+	require.NoError(t, err)
+	require.Zero(t, embedded)
+
+	// 3) Only a changed course is embedded again
+	courses[1].LearningOutcomes.String = "Written and spoken Finnish"
+	ix, embedded, err = Sync(ctx, store, e, ix, courses)
+	require.NoError(t, err)
+	require.Equal(t, 1, embedded)
+	require.Equal(t, 4, store.upserts)
+
+	// 4) Another model has its own vectors
+	other := NewLocalEmbedder(64)
+	_, embedded, err = Sync(ctx, store, other, ix, courses)
+	require.NoError(t, err)
+	require.Equal(t, 3, embedded)
+
+	query, err := e.Embed(ctx, []string{"I want to learn about security and cryptography"})
+	require.NoError(t, err)
+
+	matches := ix.Search(query[0], []int64{1, 2, 3}, 2)
+	require.Len(t, matches, 2)
+	require.Equal(t, int64(1), matches[0].CourseID)
+	require.Greater(t, matches[0].Score, matches[1].Score)
+
+	// Only the given courses are searched; unknown ones score 0
+	matches = ix.Search(query[0], []int64{99}, 5)
+	require.Equal(t, []Match{{CourseID: 99}}, matches)
+	require.Empty(t, ix.Search(query[0], []int64{1, 2, 3}, 0))
+}
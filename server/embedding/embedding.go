@@ -0,0 +1,121 @@
+// server/embedding/embedding.go
+
+package embedding
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// Names of the built-in providers, as used in the EMBEDDING_PROVIDER config
+const (
+	Local  = "local"
+	OpenAI = "openai"
+)
+
+// Embedder is an interface for turning texts into vectors whose cosine
+// similarity reflects how related the texts are
+type Embedder interface {
+	// Model names the vector space; vectors from different models must not be compared
+	Model() string
+
+	// Embed returns one vector per text, in order
+	Embed(ctx context.Context, texts []string) ([][]float32, error)
+}
+
+// Options configure the built-in providers
+type Options struct {
+	OpenAIAPIKey  string
+	OpenAIModel   string // defaults to text-embedding-3-small
+	OpenAIBaseURL string // defaults to https://api.openai.com/v1
+	Dimensions    int    // local vector size; defaults to 512
+}
+
+// Factory creates an embedder from options
+type Factory func(opts Options) (Embedder, error)
+
+var (
+	registryMu sync.RWMutex
+	registry   = map[string]Factory{}
+)
+
+// Register makes a provider available by name. It panics on duplicates,
+// like database/sql drivers.
+func Register(name string, factory Factory) {
+	registryMu.Lock()
+	defer registryMu.Unlock()
+
+	if _, dup := registry[name]; dup {
+		panic("embedding: Register called twice for " + name)
+	}
+	registry[name] = factory
+}
+
+// Registered returns the names of all registered providers.
+func Registered() []string {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+
+	names := make([]string, 0, len(registry))
+	for name := range registry {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// New creates the registered provider with the given name.
+func New(name string, opts Options) (Embedder, error) {
+	registryMu.RLock()
+	factory, ok := registry[name]
+	registryMu.RUnlock()
+
+	if !ok {
+		return nil, fmt.Errorf("unknown embedding provider %q (available: %s)", name, strings.Join(Registered(), ", "))
+	}
+	return factory(opts)
+}
+
+func init() {
+	Register(Local, func(opts Options) (Embedder, error) { return NewLocalEmbedder(opts.Dimensions), nil })
+	Register(OpenAI, func(opts Options) (Embedder, error) {
+		return NewOpenAIEmbedder(opts.OpenAIAPIKey, opts.OpenAIModel, opts.OpenAIBaseURL)
+	})
+}
+
+// Cosine returns the cosine similarity of a and b, or 0 if their lengths
+// differ or either is zero.
+func Cosine(a, b []float32) float64 {
+	if len(a) != len(b) {
+		return 0
+	}
+	var dot, na, nb float64
+	for i := range a {
+		dot += float64(a[i]) * float64(b[i])
+		na += float64(a[i]) * float64(a[i])
+		nb += float64(b[i]) * float64(b[i])
+	}
+	if na == 0 || nb == 0 {
+		return 0
+	}
+	return dot / math.Sqrt(na*nb)
+}
+
+// normalize scales v to unit length in place.
+func normalize(v []float32) {
+	var sum float64
+	for _, x := range v {
+		sum += float64(x) * float64(x)
+	}
+	if sum == 0 {
+		return
+	}
+	n := float32(math.Sqrt(sum))
+	for i := range v {
+		v[i] /= n
+	}
+}
@@ -0,0 +1,96 @@
+// server/embedding/embedding_test.go
+
+package embedding
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestLocalEmbedder(t *testing.T) {
+	e := NewLocalEmbedder(0)
+	require.Equal(t, "local-hash-512", e.Model())
+
+	texts := []string{
+		"Information security and cryptography",
+		"Network security: firewalls, intrusion detection and cryptography",
+		"Finnish for beginners",
+		"",
+	}
+	first, err := e.Embed(context.Background(), texts)
+	require.NoError(t, err)
+	require.Len(t, first, len(texts))
+	for _, v := range first {
+		require.Len(t, v, 512)
+	}
+
+	// Deterministic
+	again, err := e.Embed(context.Background(), texts)
+	require.NoError(t, err)
+	require.Equal(t, first, again)
+
+	require.InDelta(t, 1, Cosine(first[0], first[0]), 1e-6)
+	require.Greater(t, Cosine(first[0], first[1]), Cosine(first[0], first[2]))
+	require.Zero(t, Cosine(first[0], first[3]))
+}
+
+func TestCosine(t *testing.T) {
+	require.InDelta(t, 1, Cosine([]float32{1, 2}, []float32{2, 4}), 1e-9)
+	require.InDelta(t, 0, Cosine([]float32{1, 0}, []float32{0, 1}), 1e-9)
+	require.InDelta(t, -1, Cosine([]float32{1, 0}, []float32{-3, 0}), 1e-9)
+	require.Zero(t, Cosine([]float32{1}, []float32{1, 0}))
+	require.Zero(t, Cosine(nil, nil))
+}
+
+func TestNew(t *testing.T) {
+	e, err := New(Local, Options{Dimensions: 64})
+	require.NoError(t, err)
+	require.Equal(t, "local-hash-64", e.Model())
+
+	_, err = New(OpenAI, Options{})
+	require.ErrorContains(t, err, "API key")
+
+	_, err = New("word2vec", Options{})
+	require.ErrorContains(t, err, `unknown embedding provider "word2vec" (available: local, openai)`)
+}
+
+func TestOpenAIEmbedder(t *testing.T) {
+	var got openAIEmbeddingRequest
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, "/embeddings", r.URL.Path)
+		require.Equal(t, "Bearer sk-test", r.Header.Get("Authorization"))
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&got))
+
+		// Out of order, as the API allows
+		w.Write([]byte(`{"data":[{"index":1,"embedding":[0,1]},{"index":0,"embedding":[1,0]}]}`))
+	}))
+	defer srv.Close()
+
+	e, err := NewOpenAIEmbedder("sk-test", "", srv.URL+"/")
+	require.NoError(t, err)
+	require.Equal(t, "openai-text-embedding-3-small", e.Model())
+
+	vectors, err := e.Embed(context.Background(), []string{"databases", ""})
+	require.NoError(t, err)
+	require.Equal(t, [][]float32{{1, 0}, {0, 1}}, vectors)
+	require.Equal(t, "text-embedding-3-small", got.Model)
+	require.Equal(t, []string{"databases", " "}, got.Input)
+}
+
+func TestOpenAIEmbedderError(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusTooManyRequests)
+		w.Write([]byte(`{"error":{"message":"rate limited"}}`))
+	}))
+	defer srv.Close()
+
+	e, err := NewOpenAIEmbedder("sk-test", "text-embedding-3-large", srv.URL)
+	require.NoError(t, err)
+	_, err = e.Embed(context.Background(), []string{"databases"})
+	require.ErrorContains(t, err, "openai returned 429")
+}
@@ -0,0 +1,90 @@
+// server/embedding/local.go
+
+package embedding
+
+import (
+	"context"
+	"fmt"
+	"hash/fnv"
+	"strings"
+	"unicode"
+)
+
+const defaultLocalDimensions = 512
+
+// Weight of a word's character 4-grams relative to the word itself; the
+// n-grams let inflected Finnish forms ("tietoturvan", "tietoturvaa") meet
+const localNgramWeight = 0.25
+
+type localEmbedder struct {
+	dims int
+}
+
+// NewLocalEmbedder returns a deterministic embedder that hashes words and
+// their character 4-grams into dims buckets. It needs no network, so tests
+// and offline setups can use it; it only captures shared vocabulary, not
+// meaning.
+func NewLocalEmbedder(dims int) Embedder {
+	if dims <= 0 {
+		dims = defaultLocalDimensions
+	}
+	return &localEmbedder{dims: dims}
+}
+
+func (e *localEmbedder) Model() string {
+	return fmt.Sprintf("local-hash-%d", e.dims)
+}
+
+func (e *localEmbedder) Embed(_ context.Context, texts []string) ([][]float32, error) {
+	out := make([][]float32, len(texts))
+	for i, text := range texts {
+		v := make([]float32, e.dims)
+		for _, word := range localWords(text) {
+			e.add(v, word, 1)
+			padded := []rune("^" + word + "$")
+			for j := 0; j+4 <= len(padded); j++ {
+				e.add(v, string(padded[j:j+4]), localNgramWeight)
+			}
+		}
+		normalize(v)
+		out[i] = v
+	}
+	return out, nil
+}
+
+// add hashes feature into a bucket; the top bit picks the sign so unrelated
+// features cancel out rather than pile up.
+func (e *localEmbedder) add(v []float32, feature string, weight float32) {
+	h := fnv.New64a()
+	h.Write([]byte(feature))
+	sum := h.Sum64()
+	if sum>>63 == 1 {
+		weight = -weight
+	}
+	v[sum%uint64(e.dims)] += weight
+}
+
+// localStopwords are left out of local embeddings; they appear in every
+// course description
+var localStopwords = map[string]bool{
+	"the": true, "and": true, "for": true, "with": true, "are": true, "will": true,
+	"can": true, "able": true, "this": true, "that": true, "course": true,
+	"students": true, "student": true, "after": true, "completing": true,
+	"understand": true, "knowledge": true, "their": true, "how": true,
+	"ja": true, "tai": true, "sekä": true, "opiskelija": true, "kurssin": true,
+}
+
+// localWords lower-cases text and splits it into words of at least two
+// letters or digits, without stopwords.
+func localWords(text string) []string {
+	fields := strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	words := fields[:0]
+	for _, f := range fields {
+		if len([]rune(f)) >= 2 && !localStopwords[f] {
+			words = append(words, f)
+		}
+	}
+	return words
+}
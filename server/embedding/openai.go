@@ -0,0 +1,132 @@
+// server/embedding/openai.go
+
+package embedding
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+)
+
+const (
+	defaultOpenAIEmbeddingModel = "text-embedding-3-small"
+	defaultOpenAIBaseURL        = "https://api.openai.com/v1"
+
+	// Inputs per request; the API accepts up to 2048
+	openAIEmbeddingBatch = 100
+)
+
+type openAIEmbedder struct {
+	apiKey  string
+	model   string
+	baseURL string
+	client  *http.Client
+}
+
+// NewOpenAIEmbedder returns an embedder backed by the OpenAI embeddings API.
+func NewOpenAIEmbedder(apiKey, model, baseURL string) (Embedder, error) {
+	if apiKey == "" {
+		return nil, errors.New("missing OpenAI API key")
+	}
+	if model == "" {
+		model = defaultOpenAIEmbeddingModel
+	}
+	if baseURL == "" {
+		baseURL = defaultOpenAIBaseURL
+	}
+	return &openAIEmbedder{
+		apiKey:  apiKey,
+		model:   model,
+		baseURL: strings.TrimRight(baseURL, "/"),
+		client:  &http.Client{Timeout: 60 * time.Second},
+	}, nil
+}
+
+func (e *openAIEmbedder) Model() string {
+	return "openai-" + e.model
+}
+
+type openAIEmbeddingRequest struct {
+	Model string   `json:"model"`
+	Input []string `json:"input"`
+}
+
+type openAIEmbeddingResponse struct {
+	Data []struct {
+		Index     int       `json:"index"`
+		Embedding []float32 `json:"embedding"`
+	} `json:"data"`
+	Error *struct {
+		Message string `json:"message"`
+		Type    string `json:"type"`
+	} `json:"error,omitempty"`
+}
+
+func (e *openAIEmbedder) Embed(ctx context.Context, texts []string) ([][]float32, error) {
+	out := make([][]float32, 0, len(texts))
+	for start := 0; start < len(texts); start += openAIEmbeddingBatch {
+		end := min(start+openAIEmbeddingBatch, len(texts))
+		vectors, err := e.embedBatch(ctx, texts[start:end])
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, vectors...)
+	}
+	return out, nil
+}
+
+func (e *openAIEmbedder) embedBatch(ctx context.Context, texts []string) ([][]float32, error) {
+	// The API rejects empty strings
+	input := make([]string, len(texts))
+	for i, t := range texts {
+		input[i] = t
+		if strings.TrimSpace(t) == "" {
+			input[i] = " "
+		}
+	}
+	b, _ := json.Marshal(openAIEmbeddingRequest{Model: e.model, Input: input})
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, e.baseURL+"/embeddings", bytes.NewReader(b))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create OpenAI request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer "+e.apiKey)
+
+	resp, err := e.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to reach OpenAI: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 400 {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 500))
+		return nil, fmt.Errorf("openai returned %d: %s", resp.StatusCode, string(body))
+	}
+
+	var out openAIEmbeddingResponse
+	if err := json.NewDecoder(resp.Body).Decode(&out); err != nil {
+		return nil, fmt.Errorf("invalid OpenAI response: %w", err)
+	}
+	if out.Error != nil && out.Error.Message != "" {
+		return nil, fmt.Errorf("openai error: %s (%s)", out.Error.Message, out.Error.Type)
+	}
+	if len(out.Data) != len(texts) {
+		return nil, fmt.Errorf("openai returned %d embeddings for %d inputs", len(out.Data), len(texts))
+	}
+
+	vectors := make([][]float32, len(texts))
+	for _, d := range out.Data {
+		if d.Index < 0 || d.Index >= len(texts) {
+			return nil, fmt.Errorf("openai returned embedding index %d out of range", d.Index)
+		}
+		vectors[d.Index] = d.Embedding
+	}
+	return vectors, nil
+}
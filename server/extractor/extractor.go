@@ -0,0 +1,257 @@
+// server/extractor/extractor.go
+
+package extractor
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// Names of the built-in extractors, as used in the TEXT_EXTRACTORS config
+const (
+	PDF       = "pdf"
+	Pdftotext = "pdftotext"
+	Tesseract = "tesseract"
+)
+
+// PageText is the text of a single page and how confident the extractor is in it
+type PageText struct {
+	Page       int     `json:"page"`
+	Text       string  `json:"-"`
+	Chars      int     `json:"chars"`
+	Confidence float64 `json:"confidence"`         // 0..1; 1 for embedded text, word confidence for OCR
+	Language   string  `json:"language,omitempty"` // Tesseract language the page was read with
+}
+
+// Result is the output of one extractor run
+type Result struct {
+	Extractor string     `json:"extractor"`
+	Pages     []PageText `json:"pages"`
+}
+
+// Text joins the pages into the transcript text.
+func (r Result) Text() string {
+	var buf strings.Builder
+	for _, p := range r.Pages {
+		if strings.TrimSpace(p.Text) == "" {
+			continue
+		}
+		buf.WriteString(strings.TrimRight(p.Text, "\n") + "\n")
+	}
+	return strings.TrimSpace(buf.String())
+}
+
+// Confidence is the mean page confidence. Pages without text count as 0, so a
+// PDF whose pages are partly scans scores low and falls through to OCR.
+func (r Result) Confidence() float64 {
+	if len(r.Pages) == 0 {
+		return 0
+	}
+	var sum float64
+	for _, p := range r.Pages {
+		sum += p.Confidence
+	}
+	return sum / float64(len(r.Pages))
+}
+
+// LowConfidencePages lists the pages whose confidence is below min.
+func (r Result) LowConfidencePages(min float64) []int {
+	pages := []int{}
+	for _, p := range r.Pages {
+		if p.Confidence < min {
+			pages = append(pages, p.Page)
+		}
+	}
+	return pages
+}
+
+// Progress is called after each processed page with the running totals
+type Progress func(done, total int)
+
+// TextExtractor is an interface for turning a document into per-page text
+type TextExtractor interface {
+	// Name returns the registered name of the extractor
+	Name() string
+
+	// Extract reads the document at path and returns its pages
+	Extract(ctx context.Context, path string, progress Progress) (Result, error)
+}
+
+// Options configure the built-in extractors
+type Options struct {
+	PdftotextPath string   // defaults to "pdftotext" on PATH
+	PdftoppmPath  string   // defaults to "pdftoppm" on PATH
+	OCRLanguages  []string // Tesseract traineddata to detect between; the first is the default
+}
+
+// Factory creates an extractor from options
+type Factory func(opts Options) (TextExtractor, error)
+
+var (
+	registryMu sync.RWMutex
+	registry   = map[string]Factory{}
+)
+
+// Register makes an extractor available by name. It panics on duplicates,
+// like database/sql drivers.
+func Register(name string, factory Factory) {
+	registryMu.Lock()
+	defer registryMu.Unlock()
+
+	if _, dup := registry[name]; dup {
+		panic("extractor: Register called twice for " + name)
+	}
+	registry[name] = factory
+}
+
+// Registered returns the names of all registered extractors.
+func Registered() []string {
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+
+	names := make([]string, 0, len(registry))
+	for name := range registry {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// New creates the registered extractor with the given name.
+func New(name string, opts Options) (TextExtractor, error) {
+	registryMu.RLock()
+	factory, ok := registry[name]
+	registryMu.RUnlock()
+
+	if !ok {
+		return nil, fmt.Errorf("unknown text extractor %q (available: %s)", name, strings.Join(Registered(), ", "))
+	}
+	return factory(opts)
+}
+
+func init() {
+	Register(PDF, func(Options) (TextExtractor, error) { return NewPDFExtractor(), nil })
+	Register(Pdftotext, func(opts Options) (TextExtractor, error) { return NewPdftotextExtractor(opts.PdftotextPath), nil })
+	Register(Tesseract, func(opts Options) (TextExtractor, error) {
+		return NewTesseractExtractor(opts.PdftoppmPath, opts.OCRLanguages), nil
+	})
+}
+
+// -----------------------------------------------------------------------------
+// CHAIN
+// -----------------------------------------------------------------------------
+
+// Attempt records how one extractor of a chain did
+type Attempt struct {
+	Extractor  string  `json:"extractor"`
+	Pages      int     `json:"pages"`
+	Confidence float64 `json:"confidence"`
+	Error      string  `json:"error,omitempty"`
+}
+
+// Chain tries extractors in order until one returns text with enough confidence
+type Chain struct {
+	extractors    []TextExtractor
+	minConfidence float64
+}
+
+// NewChain creates a chain from extractor names in the order they should be tried.
+func NewChain(names []string, minConfidence float64, opts Options) (*Chain, error) {
+	chain := &Chain{minConfidence: minConfidence}
+	for _, name := range names {
+		name = strings.ToLower(strings.TrimSpace(name))
+		if name == "" {
+			continue
+		}
+		ex, err := New(name, opts)
+		if err != nil {
+			return nil, err
+		}
+		chain.extractors = append(chain.extractors, ex)
+	}
+	if len(chain.extractors) == 0 {
+		return nil, errors.New("no text extractors configured")
+	}
+	return chain, nil
+}
+
+// Names returns the extractor names in the order they are tried.
+func (c *Chain) Names() []string {
+	names := make([]string, 0, len(c.extractors))
+	for _, ex := range c.extractors {
+		names = append(names, ex.Name())
+	}
+	return names
+}
+
+// Extract runs the extractors in order. The first result with text and a
+// confidence of at least the chain minimum wins; if none qualifies, the best
+// result with any text is returned. progress is asked for a callback per extractor.
+func (c *Chain) Extract(ctx context.Context, path string, progress func(extractor string) Progress) (Result, []Attempt, error) {
+	var (
+		best     Result
+		bestConf = -1.0
+		attempts []Attempt
+	)
+
+	for _, ex := range c.extractors {
+		if err := ctx.Err(); err != nil {
+			return Result{}, attempts, err
+		}
+
+		var p Progress
+		if progress != nil {
+			p = progress(ex.Name())
+		}
+
+		res, err := ex.Extract(ctx, path, p)
+		attempt := Attempt{Extractor: ex.Name(), Pages: len(res.Pages), Confidence: res.Confidence()}
+		if err == nil && res.Text() == "" {
+			err = errors.New("no text extracted")
+		}
+		if err != nil {
+			attempt.Error = err.Error()
+			attempts = append(attempts, attempt)
+			continue
+		}
+		attempts = append(attempts, attempt)
+
+		if attempt.Confidence >= c.minConfidence {
+			return res, attempts, nil
+		}
+		if attempt.Confidence > bestConf {
+			best, bestConf = res, attempt.Confidence
+		}
+	}
+
+	if bestConf >= 0 {
+		return best, attempts, nil
+	}
+	return Result{}, attempts, fmt.Errorf("all text extractors failed (%s)", describeAttempts(attempts))
+}
+
+func describeAttempts(attempts []Attempt) string {
+	parts := make([]string, 0, len(attempts))
+	for _, a := range attempts {
+		parts = append(parts, a.Extractor+": "+a.Error)
+	}
+	return strings.Join(parts, "; ")
+}
+
+// newPage builds a PageText, counting the non-space characters.
+func newPage(page int, text string, confidence float64) PageText {
+	chars := 0
+	for _, r := range text {
+		if r != ' ' && r != '\n' && r != '\t' && r != '\r' {
+			chars++
+		}
+	}
+	if chars == 0 {
+		confidence = 0
+	}
+	return PageText{Page: page, Text: text, Chars: chars, Confidence: confidence}
+}
@@ -0,0 +1,93 @@
+// server/extractor/extractor_test.go
+
+package extractor
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// fakeExtractor returns a fixed result, for testing the chain
+type fakeExtractor struct {
+	name  string
+	pages []PageText
+	err   error
+	calls int
+}
+
+func (f *fakeExtractor) Name() string { return f.name }
+
+func (f *fakeExtractor) Extract(ctx context.Context, path string, progress Progress) (Result, error) {
+	f.calls++
+	if progress != nil {
+		progress(len(f.pages), len(f.pages))
+	}
+	return Result{Extractor: f.name, Pages: f.pages}, f.err
+}
+
+func TestResult(t *testing.T) {
+	res := Result{Pages: []PageText{
+		newPage(1, "TJTS5012 Research Methods 5 cr 4\n", 0.9),
+		newPage(2, "  \n", 1),
+		newPage(3, "ITKA203 Operating Systems 5 cr 3", 0.3),
+	}}
+
+	require.Equal(t, 0, res.Pages[1].Chars)
+	require.Zero(t, res.Pages[1].Confidence)
+	require.InDelta(t, 0.4, res.Confidence(), 1e-9)
+	require.Equal(t, []int{2, 3}, res.LowConfidencePages(0.6))
+	require.Equal(t, "TJTS5012 Research Methods 5 cr 4\nITKA203 Operating Systems 5 cr 3", res.Text())
+}
+
+func TestChainTriesInOrder(t *testing.T) {
+	failing := &fakeExtractor{name: "failing", err: errors.New("boom")}
+	empty := &fakeExtractor{name: "empty", pages: []PageText{newPage(1, "", 1)}}
+	good := &fakeExtractor{name: "good", pages: []PageText{newPage(1, "text", 0.95)}}
+	unused := &fakeExtractor{name: "unused", pages: []PageText{newPage(1, "other", 1)}}
+	chain := &Chain{extractors: []TextExtractor{failing, empty, good, unused}, minConfidence: 0.6}
+
+	var progressed []string
+	res, attempts, err := chain.Extract(context.Background(), "transcript.pdf", func(name string) Progress {
+		return func(done, total int) { progressed = append(progressed, name) }
+	})
+	require.NoError(t, err)
+	require.Equal(t, "good", res.Extractor)
+	require.Equal(t, "text", res.Text())
+
+	require.Len(t, attempts, 3)
+	require.Equal(t, "boom", attempts[0].Error)
+	require.Equal(t, "no text extracted", attempts[1].Error)
+	require.Empty(t, attempts[2].Error)
+	require.Equal(t, []string{"failing", "empty", "good"}, progressed)
+	require.Zero(t, unused.calls)
+}
+
+func TestChainFallsBackToBestResult(t *testing.T) {
+	low := &fakeExtractor{name: "low", pages: []PageText{newPage(1, "blurry", 0.3)}}
+	lower := &fakeExtractor{name: "lower", pages: []PageText{newPage(1, "blurrier", 0.2)}}
+	chain := &Chain{extractors: []TextExtractor{low, lower}, minConfidence: 0.6}
+
+	res, attempts, err := chain.Extract(context.Background(), "transcript.pdf", nil)
+	require.NoError(t, err)
+	require.Equal(t, "low", res.Extractor)
+	require.Len(t, attempts, 2)
+
+	chain = &Chain{extractors: []TextExtractor{&fakeExtractor{name: "failing", err: errors.New("boom")}}}
+	_, _, err = chain.Extract(context.Background(), "transcript.pdf", nil)
+	require.ErrorContains(t, err, "failing: boom")
+}
+
+func TestNewChain(t *testing.T) {
+	chain, err := NewChain([]string{" PDF", "", "tesseract "}, 0.6, Options{})
+	require.NoError(t, err)
+	require.Equal(t, []string{PDF, Tesseract}, chain.Names())
+
+	_, err = NewChain([]string{"pdf", "magic"}, 0.6, Options{})
+	require.ErrorContains(t, err, `unknown text extractor "magic"`)
+
+	_, err = NewChain(nil, 0.6, Options{})
+	require.Error(t, err)
+}
@@ -0,0 +1,108 @@
+// server/extractor/language.go
+
+package extractor
+
+import (
+	"strings"
+	"unicode"
+)
+
+// languageProfile holds the common and transcript-specific words of a
+// language, keyed by Tesseract traineddata name
+type languageProfile struct {
+	name    string
+	words   map[string]bool
+	letters string // letters that are frequent in the language but rare in English
+}
+
+var languageProfiles = map[string]languageProfile{
+	"eng": {
+		name: "English",
+		words: wordSet("the and of in to for is with on by course courses credits grade grades date " +
+			"transcript records student university total completed passed study studies module"),
+	},
+	"fin": {
+		name: "Finnish",
+		words: wordSet("ja on ei tai että opintojakso opintojaksot opintopiste opintopistettä op arvosana " +
+			"arvosanat suoritus suoritukset suorituspäivä päivämäärä opintosuoritusote opiskelija " +
+			"yliopisto yhteensä hyväksytty hylätty hyv hyl kurssi tutkinto laajuus"),
+		letters: "äö",
+	},
+	"swe": {
+		name: "Swedish",
+		words: wordSet("och är att av för med på som en ett kurs kurser betyg studiepoäng högskolepoäng " +
+			"poäng datum studieprestationer studerande universitet totalt godkänd underkänd avklarad"),
+		letters: "åäö",
+	},
+}
+
+func wordSet(words string) map[string]bool {
+	set := make(map[string]bool)
+	for _, w := range strings.Fields(words) {
+		set[w] = true
+	}
+	return set
+}
+
+// LanguageName returns the English name of a Tesseract language code, or the
+// code itself if it is not known.
+func LanguageName(code string) string {
+	if p, ok := languageProfiles[code]; ok {
+		return p.name
+	}
+	return code
+}
+
+// DetectLanguage guesses which of the candidate Tesseract languages the text is
+// written in by counting common words and language-specific letters. It returns
+// the first candidate when nothing scores, so OCR falls back to the default.
+func DetectLanguage(text string, candidates []string) string {
+	if len(candidates) == 0 {
+		return ""
+	}
+
+	words := strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r)
+	})
+	lower := strings.ToLower(text)
+
+	best, bestScore := candidates[0], 0
+	for _, code := range candidates {
+		profile, ok := languageProfiles[code]
+		if !ok {
+			continue
+		}
+
+		score := 0
+		for _, w := range words {
+			if profile.words[w] {
+				score += 2
+			}
+		}
+		for _, r := range profile.letters {
+			score += strings.Count(lower, string(r))
+		}
+
+		if score > bestScore {
+			best, bestScore = code, score
+		}
+	}
+	return best
+}
+
+// Language returns the language detected on most of the text of a result, or
+// "" when the extractor did not detect languages.
+func (r Result) Language() string {
+	chars := make(map[string]int)
+	best := ""
+	for _, p := range r.Pages {
+		if p.Language == "" {
+			continue
+		}
+		chars[p.Language] += p.Chars
+		if best == "" || chars[p.Language] > chars[best] {
+			best = p.Language
+		}
+	}
+	return best
+}
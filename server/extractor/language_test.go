@@ -0,0 +1,60 @@
+// server/extractor/language_test.go
+
+package extractor
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestDetectLanguage(t *testing.T) {
+	candidates := []string{"eng", "fin", "swe"}
+
+	testCases := []struct {
+		name string
+		text string
+		want string
+	}{
+		{
+			name: "English",
+			text: "Transcript of Records\nCode Name Credits Grade Date\nTJTS5012 Research Methods 5 cr 4 12.05.2023\nTotal 5 credits",
+			want: "eng",
+		},
+		{
+			name: "Finnish",
+			text: "Opintosuoritusote\nKoodi Opintojakso Laajuus Arvosana Päivämäärä\nITKA203 Käyttöjärjestelmät 5 op hyv. 14.12.2021\nYhteensä 5 op",
+			want: "fin",
+		},
+		{
+			name: "Swedish",
+			text: "Studieprestationer\nKurs Betyg Studiepoäng Datum\nDatastrukturer och algoritmer 5 sp godkänd 2022-05-20\nTotalt 5 studiepoäng",
+			want: "swe",
+		},
+		{
+			name: "NothingRecognised",
+			text: "12345 67890",
+			want: "eng",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			require.Equal(t, tc.want, DetectLanguage(tc.text, candidates))
+		})
+	}
+
+	// Only configured languages are considered
+	require.Equal(t, "eng", DetectLanguage("Opintosuoritusote ja arvosana", []string{"eng", "swe"}))
+	require.Empty(t, DetectLanguage("text", nil))
+}
+
+func TestResultLanguage(t *testing.T) {
+	fin := newPage(1, "Opintosuoritusote ja paljon tekstiä", 0.9)
+	fin.Language = "fin"
+	eng := newPage(2, "Short", 0.9)
+	eng.Language = "eng"
+
+	require.Equal(t, "fin", Result{Pages: []PageText{eng, fin}}.Language())
+	require.Empty(t, Result{Pages: []PageText{newPage(1, "text", 1)}}.Language())
+}
@@ -0,0 +1,74 @@
+// server/extractor/pdf_extractor.go
+
+package extractor
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/ledongthuc/pdf"
+)
+
+// PDFExtractor reads the embedded text layer with ledongthuc/pdf (pure Go)
+type PDFExtractor struct{}
+
+// NewPDFExtractor creates a new PDFExtractor
+func NewPDFExtractor() TextExtractor {
+	return &PDFExtractor{}
+}
+
+// Name returns the registered name of the extractor
+func (e *PDFExtractor) Name() string {
+	return PDF
+}
+
+// Extract returns the text of every page. Embedded text is exact, so pages with
+// text get confidence 1. The pdf package panics on some malformed content
+// streams; that is returned as an error so the chain can try the next
+// extractor.
+func (e *PDFExtractor) Extract(ctx context.Context, path string, progress Progress) (_ Result, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("malformed PDF: %v", r)
+		}
+	}()
+
+	f, r, err := pdf.Open(path)
+	if err != nil {
+		return Result{}, fmt.Errorf("failed to open PDF: %w", err)
+	}
+	defer f.Close()
+
+	res := Result{Extractor: PDF}
+	total := r.NumPage()
+	for i := 1; i <= total; i++ {
+		if err := ctx.Err(); err != nil {
+			return Result{}, err
+		}
+
+		var buf strings.Builder
+		page := r.Page(i)
+		if !page.V.IsNull() {
+			// Keep the row structure so course lines stay on their own line
+			rows, err := page.GetTextByRow()
+			if err != nil {
+				return Result{}, fmt.Errorf("failed to extract text on page %d: %w", i, err)
+			}
+			for _, row := range rows {
+				words := make([]string, 0, len(row.Content))
+				for _, word := range row.Content {
+					words = append(words, word.S)
+				}
+				buf.WriteString(strings.Join(words, " ") + "\n")
+			}
+		}
+		res.Pages = append(res.Pages, newPage(i, buf.String(), 1))
+
+		if progress != nil {
+			progress(i, total)
+		}
+	}
+
+	return res, nil
+}
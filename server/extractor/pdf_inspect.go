@@ -0,0 +1,70 @@
+// server/extractor/pdf_inspect.go
+
+package extractor
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/ledongthuc/pdf"
+)
+
+// Reasons a PDF cannot be ingested, returned by InspectPDF
+var (
+	ErrPDFMalformed         = errors.New("the PDF file is damaged or not a valid PDF")
+	ErrPDFPasswordProtected = errors.New("the PDF is password-protected; remove the password and upload it again")
+	ErrPDFEncrypted         = errors.New("the PDF uses an encryption that cannot be read; save an unencrypted copy and upload it again")
+)
+
+// PDFInfo describes a PDF that can be opened for text extraction
+type PDFInfo struct {
+	Pages int
+	// Encrypted is set for PDFs encrypted without a user password (permission
+	// restrictions only), which open without asking for one
+	Encrypted bool
+}
+
+// InspectPDF parses the cross-reference table and page tree of the PDF at
+// path, so damaged or locked files are rejected before any extractor or OCR
+// runs. The underlying parser panics on some malformed input; that is
+// reported as ErrPDFMalformed.
+func InspectPDF(path string) (info PDFInfo, err error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return PDFInfo{}, err
+	}
+	defer f.Close()
+
+	st, err := f.Stat()
+	if err != nil {
+		return PDFInfo{}, err
+	}
+
+	defer func() {
+		if r := recover(); r != nil {
+			info, err = PDFInfo{}, fmt.Errorf("%w: %v", ErrPDFMalformed, r)
+		}
+	}()
+
+	r, err := pdf.NewReader(f, st.Size())
+	if err != nil {
+		switch {
+		case errors.Is(err, pdf.ErrInvalidPassword):
+			return PDFInfo{}, ErrPDFPasswordProtected
+		case strings.Contains(err.Error(), "encryption"):
+			// e.g. "unsupported PDF: encryption version V=5"
+			return PDFInfo{}, fmt.Errorf("%w: %v", ErrPDFEncrypted, err)
+		default:
+			return PDFInfo{}, fmt.Errorf("%w: %v", ErrPDFMalformed, err)
+		}
+	}
+
+	info.Encrypted = !r.Trailer().Key("Encrypt").IsNull()
+	info.Pages = r.NumPage()
+	if info.Pages == 0 {
+		return PDFInfo{}, fmt.Errorf("%w: no pages", ErrPDFMalformed)
+	}
+	return info, nil
+}
@@ -0,0 +1,65 @@
+// server/extractor/pdf_inspect_test.go
+
+package extractor
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/jung-kurt/gofpdf"
+	"github.com/stretchr/testify/require"
+)
+
+// writeTestPDF writes a PDF with the given number of pages, optionally
+// protected with a user and owner password
+func writeTestPDF(t *testing.T, pages int, userPass, ownerPass string) string {
+	pdf := gofpdf.New("P", "mm", "A4", "")
+	if ownerPass != "" {
+		pdf.SetProtection(gofpdf.CnProtectPrint, userPass, ownerPass)
+	}
+	pdf.SetFont("Helvetica", "", 11)
+	for i := 0; i < pages; i++ {
+		pdf.AddPage()
+		pdf.Cell(0, 10, "TJTS5012 Research Methods 5 cr 4")
+	}
+
+	var buf bytes.Buffer
+	require.NoError(t, pdf.Output(&buf))
+	path := filepath.Join(t.TempDir(), "transcript.pdf")
+	require.NoError(t, os.WriteFile(path, buf.Bytes(), 0o644))
+	return path
+}
+
+func TestInspectPDF(t *testing.T) {
+	info, err := InspectPDF(writeTestPDF(t, 3, "", ""))
+	require.NoError(t, err)
+	require.Equal(t, PDFInfo{Pages: 3}, info)
+
+	// Permission restrictions only: opens without a password
+	info, err = InspectPDF(writeTestPDF(t, 1, "", "owner"))
+	require.NoError(t, err)
+	require.Equal(t, PDFInfo{Pages: 1, Encrypted: true}, info)
+
+	_, err = InspectPDF(writeTestPDF(t, 1, "secret", "owner"))
+	require.ErrorIs(t, err, ErrPDFPasswordProtected)
+}
+
+func TestInspectPDFMalformed(t *testing.T) {
+	valid, err := os.ReadFile(writeTestPDF(t, 2, "", ""))
+	require.NoError(t, err)
+
+	for name, data := range map[string][]byte{
+		"header only": []byte("%PDF-1.7\n"),
+		"truncated":   valid[:len(valid)/2],
+		"garbage":     append([]byte("%PDF-1.4\n"), bytes.Repeat([]byte("\x00\xff"), 512)...),
+		"bad xref":    bytes.Replace(valid, []byte("xref"), []byte("xxxx"), 1),
+	} {
+		path := filepath.Join(t.TempDir(), "bad.pdf")
+		require.NoError(t, os.WriteFile(path, data, 0o644))
+
+		_, err := InspectPDF(path)
+		require.ErrorIs(t, err, ErrPDFMalformed, name)
+	}
+}
@@ -0,0 +1,72 @@
+// server/extractor/pdftotext_extractor.go
+
+package extractor
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os/exec"
+	"strings"
+)
+
+// PdftotextExtractor runs poppler's `pdftotext -layout`, which keeps table
+// columns aligned better than the pure Go reader on some transcripts
+type PdftotextExtractor struct {
+	// Path to the pdftotext binary; empty means look it up on PATH
+	binary string
+}
+
+// NewPdftotextExtractor creates a new PdftotextExtractor
+func NewPdftotextExtractor(binary string) TextExtractor {
+	return &PdftotextExtractor{binary: binary}
+}
+
+// Name returns the registered name of the extractor
+func (e *PdftotextExtractor) Name() string {
+	return Pdftotext
+}
+
+// Extract returns the text of every page. pdftotext separates pages with a
+// form feed, which is how the output is split back into pages.
+func (e *PdftotextExtractor) Extract(ctx context.Context, path string, progress Progress) (Result, error) {
+	binary, err := lookupBinary(e.binary, "pdftotext")
+	if err != nil {
+		return Result{}, err
+	}
+
+	var stdout, stderr bytes.Buffer
+	cmd := exec.CommandContext(ctx, binary, "-layout", "-enc", "UTF-8", path, "-")
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		return Result{}, fmt.Errorf("pdftotext failed: %w: %s", err, strings.TrimSpace(stderr.String()))
+	}
+
+	pages := strings.Split(stdout.String(), "\f")
+	// The output ends with a form feed after the last page
+	if len(pages) > 1 && strings.TrimSpace(pages[len(pages)-1]) == "" {
+		pages = pages[:len(pages)-1]
+	}
+
+	res := Result{Extractor: Pdftotext}
+	for i, text := range pages {
+		res.Pages = append(res.Pages, newPage(i+1, text, 1))
+		if progress != nil {
+			progress(i+1, len(pages))
+		}
+	}
+	return res, nil
+}
+
+// lookupBinary resolves a configured binary path, falling back to PATH.
+func lookupBinary(configured, name string) (string, error) {
+	if configured == "" {
+		configured = name
+	}
+	path, err := exec.LookPath(configured)
+	if err != nil {
+		return "", fmt.Errorf("%s not found: %w", name, err)
+	}
+	return path, nil
+}
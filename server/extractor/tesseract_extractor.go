@@ -0,0 +1,173 @@
+// server/extractor/tesseract_extractor.go
+
+package extractor
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/otiai10/gosseract/v2"
+)
+
+// TesseractExtractor OCRs scanned PDFs (rasterised with poppler's pdftoppm)
+// and image files with Tesseract via gosseract
+type TesseractExtractor struct {
+	// Path to the pdftoppm binary; empty means look it up on PATH
+	pdftoppm string
+	// Tesseract traineddata names, e.g. ["eng", "fin", "swe"]; the first is
+	// used for the detection pass
+	languages []string
+}
+
+// NewTesseractExtractor creates a new TesseractExtractor
+func NewTesseractExtractor(pdftoppm string, languages []string) TextExtractor {
+	if len(languages) == 0 {
+		languages = []string{"eng"}
+	}
+	return &TesseractExtractor{pdftoppm: pdftoppm, languages: languages}
+}
+
+// Name returns the registered name of the extractor
+func (e *TesseractExtractor) Name() string {
+	return Tesseract
+}
+
+// Extract OCRs every page. With several languages configured, each page gets a
+// quick pass with the default language to detect its language and is then
+// re-read with that traineddata. Page confidence is the mean word confidence.
+func (e *TesseractExtractor) Extract(ctx context.Context, path string, progress Progress) (Result, error) {
+	images := []string{path}
+	if isPDF(path) {
+		dir, err := os.MkdirTemp("", "ocr_")
+		if err != nil {
+			return Result{}, err
+		}
+		defer os.RemoveAll(dir)
+
+		if images, err = e.rasterize(ctx, path, dir); err != nil {
+			return Result{}, err
+		}
+	}
+
+	client := gosseract.NewClient()
+	defer client.Close()
+
+	res := Result{Extractor: Tesseract}
+	for i, image := range images {
+		if err := ctx.Err(); err != nil {
+			return Result{}, err
+		}
+
+		page, err := e.ocrPage(client, i+1, image)
+		if err != nil {
+			// Keep going: one unreadable page should not lose the others
+			page = newPage(i+1, "", 0)
+		}
+		res.Pages = append(res.Pages, page)
+
+		if progress != nil {
+			progress(i+1, len(images))
+		}
+	}
+
+	return res, nil
+}
+
+// rasterize converts the PDF pages to PNG files in dir, in page order.
+func (e *TesseractExtractor) rasterize(ctx context.Context, pdfPath, dir string) ([]string, error) {
+	binary, err := lookupBinary(e.pdftoppm, "pdftoppm")
+	if err != nil {
+		return nil, err
+	}
+
+	var stderr bytes.Buffer
+	cmd := exec.CommandContext(ctx, binary, "-r", "300", "-png", pdfPath, filepath.Join(dir, "page"))
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		return nil, fmt.Errorf("failed to convert pdf to images: %w: %s", err, strings.TrimSpace(stderr.String()))
+	}
+
+	// pdftoppm zero-pads page numbers, so lexical order is page order
+	files, err := filepath.Glob(filepath.Join(dir, "page-*.png"))
+	if err != nil {
+		return nil, fmt.Errorf("failed to list converted images: %w", err)
+	}
+	if len(files) == 0 {
+		return nil, fmt.Errorf("no image files found for OCR conversion")
+	}
+	sort.Strings(files)
+	return files, nil
+}
+
+// ocrPage reads one page image, detecting its language first when there is a choice.
+func (e *TesseractExtractor) ocrPage(client *gosseract.Client, page int, image string) (PageText, error) {
+	if err := client.SetImage(image); err != nil {
+		return PageText{}, fmt.Errorf("failed to set image: %w", err)
+	}
+
+	language := e.languages[0]
+	if len(e.languages) > 1 {
+		// Quick pass: text only, no word boxes
+		if err := client.SetLanguage(language); err != nil {
+			return PageText{}, err
+		}
+		if draft, err := client.Text(); err == nil {
+			language = DetectLanguage(draft, e.languages)
+		}
+	}
+
+	text, confidence, err := ocrImage(client, language)
+	if err != nil && language != e.languages[0] {
+		// Detected traineddata may not be installed; fall back to the default
+		language = e.languages[0]
+		text, confidence, err = ocrImage(client, language)
+	}
+	if err != nil {
+		return PageText{}, err
+	}
+
+	p := newPage(page, text, confidence)
+	p.Language = language
+	return p, nil
+}
+
+// ocrImage reads the current image with the given language and returns the
+// text and its mean word confidence (0..1).
+func ocrImage(client *gosseract.Client, language string) (string, float64, error) {
+	if err := client.SetLanguage(language); err != nil {
+		return "", 0, fmt.Errorf("failed to set OCR language %q: %w", language, err)
+	}
+	text, err := client.Text()
+	if err != nil {
+		return "", 0, err
+	}
+
+	boxes, err := client.GetBoundingBoxes(gosseract.RIL_WORD)
+	if err != nil || len(boxes) == 0 {
+		return text, 0, nil
+	}
+	var sum float64
+	for _, box := range boxes {
+		sum += box.Confidence
+	}
+	return text, sum / float64(len(boxes)) / 100, nil
+}
+
+// isPDF checks the file signature rather than the extension.
+func isPDF(path string) bool {
+	f, err := os.Open(path)
+	if err != nil {
+		return false
+	}
+	defer f.Close()
+
+	head := make([]byte, 5)
+	n, _ := f.Read(head)
+	return bytes.Equal(head[:n], []byte("%PDF-"))
+}
@@ -0,0 +1,151 @@
+// server/grading/grading.go
+
+package grading
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// Scale identifies the grading scale a grade was given on
+type Scale string
+
+// Supported grading scales
+const (
+	// Finnish general scale 0-5, where 0 is a fail
+	ScaleFinnish Scale = "0-5"
+	// Pass/fail, e.g. hyv./hyl., Approved/Rejected
+	ScalePassFail Scale = "pass_fail"
+	// ECTS letter grades A-F
+	ScaleECTS Scale = "ects"
+	// US grade points 0.0-4.0 or letters A-F with +/- modifiers
+	ScaleUS Scale = "us_4"
+	// Unknown means no scale could be determined
+	ScaleUnknown Scale = ""
+)
+
+// Grade is a transcript grade normalised to the Finnish 0-5 scale
+type Grade struct {
+	Raw   string `json:"raw"`
+	Scale Scale  `json:"scale"`
+	// Points on the Finnish 0-5 scale; meaningless when Numeric is false
+	Points  float64 `json:"points"`
+	Numeric bool    `json:"numeric"`
+	Passed  bool    `json:"passed"`
+}
+
+var (
+	passGrades = map[string]bool{
+		"hyv": true, "hyväksytty": true, "pass": true, "passed": true,
+		"approved": true, "completed": true, "godkänd": true, "s": true,
+	}
+	failGrades = map[string]bool{
+		"hyl": true, "hylätty": true, "fail": true, "failed": true,
+		"rejected": true, "underkänd": true, "u": true,
+	}
+	// ECTS A-F mapped onto the Finnish scale (A=5 ... E=1, F=0)
+	ectsPoints = map[string]float64{"A": 5, "B": 4, "C": 3, "D": 2, "E": 1, "FX": 0, "F": 0}
+	// US letter grades as grade points
+	usPoints = map[string]float64{
+		"A+": 4.0, "A": 4.0, "A-": 3.7,
+		"B+": 3.3, "B": 3.0, "B-": 2.7,
+		"C+": 2.3, "C": 2.0, "C-": 1.7,
+		"D+": 1.3, "D": 1.0, "D-": 0.7,
+		"F": 0,
+	}
+)
+
+// ParseScale maps a catalogue grading_scale description such as
+// "General scale, 0-5" or "Pass-Fail" to a Scale.
+func ParseScale(description string) Scale {
+	d := strings.ToLower(strings.TrimSpace(description))
+	switch {
+	case d == "":
+		return ScaleUnknown
+	case strings.Contains(d, "0-5"), strings.Contains(d, "0–5"):
+		return ScaleFinnish
+	case strings.Contains(d, "pass"), strings.Contains(d, "fail"),
+		strings.Contains(d, "approved"), strings.Contains(d, "rejected"),
+		strings.Contains(d, "hyv"), strings.Contains(d, "hyl"):
+		return ScalePassFail
+	case strings.Contains(d, "ects"):
+		return ScaleECTS
+	case strings.Contains(d, "4.0"), strings.Contains(d, "gpa"):
+		return ScaleUS
+	default:
+		return ScaleUnknown
+	}
+}
+
+// Parse normalises a raw grade. The hint is the scale the course is known to
+// use (e.g. from the course catalogue); with ScaleUnknown the scale is guessed
+// from the grade itself, preferring the Finnish scale for bare integers.
+func Parse(raw string, hint Scale) (Grade, error) {
+	g := Grade{Raw: raw}
+	s := strings.TrimSpace(raw)
+	if s == "" {
+		return g, fmt.Errorf("empty grade")
+	}
+
+	// 1) Pass/fail words on any scale
+	word := strings.ToLower(strings.TrimSuffix(s, "."))
+	if passGrades[word] {
+		g.Scale, g.Passed = ScalePassFail, true
+		return g, nil
+	}
+	if failGrades[word] {
+		g.Scale = ScalePassFail
+		return g, nil
+	}
+
+	// 2) Numbers: 0-5 integers, or 0.0-4.0 grade points
+	if n, err := strconv.ParseFloat(strings.Replace(s, ",", ".", 1), 64); err == nil {
+		return parseNumeric(g, n, hint)
+	}
+
+	// 3) Letters: ECTS A-F, or US letters with +/- modifiers
+	letter := strings.ToUpper(s)
+	if _, ok := ectsPoints[letter]; ok && hint != ScaleUS {
+		g.Scale, g.Numeric = ScaleECTS, true
+		g.Points = ectsPoints[letter]
+		g.Passed = g.Points > 0
+		return g, nil
+	}
+	if gp, ok := usPoints[letter]; ok {
+		g.Scale, g.Numeric = ScaleUS, true
+		g.Points = usToFinnish(gp)
+		g.Passed = gp > 0
+		return g, nil
+	}
+
+	return g, fmt.Errorf("unrecognised grade %q", raw)
+}
+
+func parseNumeric(g Grade, n float64, hint Scale) (Grade, error) {
+	isInteger := n == math.Trunc(n)
+	switch {
+	case hint == ScaleUS && n >= 0 && n <= 4:
+		g.Scale, g.Points = ScaleUS, usToFinnish(n)
+	case isInteger && n >= 0 && n <= 5:
+		g.Scale, g.Points = ScaleFinnish, n
+	case !isInteger && n >= 0 && n <= 4:
+		g.Scale, g.Points = ScaleUS, usToFinnish(n)
+	default:
+		return g, fmt.Errorf("grade %q is out of range", g.Raw)
+	}
+	g.Numeric = true
+	g.Passed = g.Points > 0
+	return g, nil
+}
+
+// usToFinnish maps US grade points linearly onto the Finnish scale (4.0 = 5).
+func usToFinnish(gp float64) float64 {
+	return gp * 5 / 4
+}
+
+// FinnishToUS maps a Finnish 0-5 figure linearly onto the US 4.0 scale.
+func FinnishToUS(points float64) float64 {
+	return points * 4 / 5
+}
@@ -0,0 +1,90 @@
+// server/grading/grading_test.go
+
+package grading
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParse(t *testing.T) {
+	testCases := []struct {
+		name    string
+		raw     string
+		hint    Scale
+		scale   Scale
+		points  float64
+		numeric bool
+		passed  bool
+	}{
+		{name: "Finnish", raw: "4", scale: ScaleFinnish, points: 4, numeric: true, passed: true},
+		{name: "FinnishFail", raw: "0", scale: ScaleFinnish, points: 0, numeric: true},
+		{name: "Hyvaksytty", raw: "hyv.", scale: ScalePassFail, passed: true},
+		{name: "Hylatty", raw: "Hyl.", scale: ScalePassFail},
+		{name: "Approved", raw: "Approved", scale: ScalePassFail, passed: true},
+		{name: "ECTS", raw: "B", scale: ScaleECTS, points: 4, numeric: true, passed: true},
+		{name: "ECTSFail", raw: "F", scale: ScaleECTS, points: 0, numeric: true},
+		{name: "USLetter", raw: "B+", scale: ScaleUS, points: 4.125, numeric: true, passed: true},
+		{name: "USPoints", raw: "3,2", scale: ScaleUS, points: 4, numeric: true, passed: true},
+		{name: "USHintInteger", raw: "4", hint: ScaleUS, scale: ScaleUS, points: 5, numeric: true, passed: true},
+		{name: "USHintLetter", raw: "A", hint: ScaleUS, scale: ScaleUS, points: 5, numeric: true, passed: true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			g, err := Parse(tc.raw, tc.hint)
+			require.NoError(t, err)
+			require.Equal(t, tc.scale, g.Scale)
+			require.InDelta(t, tc.points, g.Points, 1e-9)
+			require.Equal(t, tc.numeric, g.Numeric)
+			require.Equal(t, tc.passed, g.Passed)
+		})
+	}
+}
+
+func TestParseInvalid(t *testing.T) {
+	for _, raw := range []string{"", "7", "excellent", "4.5"} {
+		_, err := Parse(raw, ScaleUnknown)
+		require.Error(t, err, raw)
+	}
+}
+
+func TestParseScale(t *testing.T) {
+	require.Equal(t, ScaleFinnish, ParseScale("General scale, 0-5"))
+	require.Equal(t, ScaleFinnish, ParseScale(" General scale, 0-5"))
+	require.Equal(t, ScalePassFail, ParseScale("Rejected-Approved"))
+	require.Equal(t, ScalePassFail, ParseScale(" Pass-Fail"))
+	require.Equal(t, ScaleUnknown, ParseScale(""))
+}
+
+func TestCompute(t *testing.T) {
+	st := Compute([]Course{
+		{Code: "TJTS5012", Credits: 5, Grade: "5"},
+		{Code: "TIES454", Credits: 10, Grade: "2"},
+		{Code: "ITKA203", Credits: 5, Grade: "hyv."},
+		{Code: "MATA101", Credits: 5, Grade: "0"},
+		{Code: "KIEL001", Credits: 2, Grade: ""},
+		{Code: "XYZ100", Credits: 3, Grade: "??"},
+	})
+
+	// (5*5 + 2*10) / 15 = 3.0
+	require.Equal(t, 3.0, st.GPA)
+	require.Equal(t, 2.4, st.GPAUS)
+	require.Equal(t, 22.0, st.TotalCredits)
+	require.Equal(t, 15.0, st.GradedCredits)
+	require.Equal(t, 5.0, st.PassFailCredits)
+	require.Equal(t, 5.0, st.FailedCredits)
+	require.Equal(t, 3, st.PassedCourses)
+	require.Equal(t, 1, st.FailedCourses)
+	require.Equal(t, 1, st.UngradedCourses)
+	require.Equal(t, []string{"XYZ100"}, st.Unrecognised)
+	require.Equal(t, map[Scale]int{ScaleFinnish: 2, ScalePassFail: 1}, st.Scales)
+}
+
+func TestComputeNoGrades(t *testing.T) {
+	st := Compute(nil)
+	require.Zero(t, st.GPA)
+	require.Zero(t, st.TotalCredits)
+	require.Empty(t, st.Unrecognised)
+}
@@ -0,0 +1,91 @@
+// server/grading/stats.go
+
+package grading
+
+import (
+	"math"
+	"strings"
+)
+
+// Course is one completed or failed course attempt fed into Compute
+type Course struct {
+	Code    string
+	Credits float64
+	Grade   string
+	// Scale the course is graded on, if known
+	Scale Scale
+}
+
+// Stats is the academic standing computed from a set of courses
+type Stats struct {
+	// Weighted GPA on the Finnish 0-5 scale over passed, numerically graded courses
+	GPA float64 `json:"gpa"`
+	// The same GPA expressed on the US 4.0 scale
+	GPAUS float64 `json:"gpa_us"`
+
+	// ECTS credits of all passed courses, graded or pass/fail
+	TotalCredits    float64 `json:"total_credits"`
+	GradedCredits   float64 `json:"graded_credits"`
+	PassFailCredits float64 `json:"pass_fail_credits"`
+	FailedCredits   float64 `json:"failed_credits"`
+
+	PassedCourses int `json:"passed_courses"`
+	FailedCourses int `json:"failed_courses"`
+	// Completed courses without a grade count towards credits only
+	UngradedCourses int `json:"ungraded_courses"`
+	// Course codes whose grade could not be read
+	Unrecognised []string `json:"unrecognised"`
+
+	// Number of passed courses per grading scale
+	Scales map[Scale]int `json:"scales"`
+}
+
+// Compute normalises the grades of the given courses and totals them. Failed
+// attempts are excluded from the GPA and from the credit total, as on Finnish
+// transcripts.
+func Compute(courses []Course) Stats {
+	st := Stats{Unrecognised: []string{}, Scales: map[Scale]int{}}
+
+	var weighted float64
+	for _, c := range courses {
+		credits := math.Max(c.Credits, 0)
+
+		if strings.TrimSpace(c.Grade) == "" {
+			st.UngradedCourses++
+			st.TotalCredits += credits
+			continue
+		}
+
+		g, err := Parse(c.Grade, c.Scale)
+		if err != nil {
+			st.Unrecognised = append(st.Unrecognised, c.Code)
+			continue
+		}
+
+		if !g.Passed {
+			st.FailedCourses++
+			st.FailedCredits += credits
+			continue
+		}
+
+		st.PassedCourses++
+		st.Scales[g.Scale]++
+		st.TotalCredits += credits
+		if g.Numeric {
+			st.GradedCredits += credits
+			weighted += g.Points * credits
+		} else {
+			st.PassFailCredits += credits
+		}
+	}
+
+	if st.GradedCredits > 0 {
+		st.GPA = round2(weighted / st.GradedCredits)
+		st.GPAUS = round2(FinnishToUS(weighted / st.GradedCredits))
+	}
+	return st
+}
+
+func round2(f float64) float64 {
+	return math.Round(f*100) / 100
+}
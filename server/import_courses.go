@@ -0,0 +1,142 @@
+// server/import_courses.go
+
+package main
+
+import (
+	"context"
+	"database/sql"
+	"flag"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/nibir1/go-fiber-postgres-REST-boilerplate/api"
+	"github.com/nibir1/go-fiber-postgres-REST-boilerplate/catalog"
+	db "github.com/nibir1/go-fiber-postgres-REST-boilerplate/db/sqlc"
+	"github.com/nibir1/go-fiber-postgres-REST-boilerplate/embedding"
+	"github.com/nibir1/go-fiber-postgres-REST-boilerplate/util"
+)
+
+// runImportCourses implements `edusphere import-courses [flags] FILE...`:
+// it reads CSV, JSON or YAML catalogue exports and upserts them by course
+// code into the database configured in app.env. It returns the exit code.
+func runImportCourses(args []string, stdout, stderr io.Writer) int {
+	fs := flag.NewFlagSet("import-courses", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	dryRun := fs.Bool("dry-run", false, "report what would change without writing to the database")
+	format := fs.String("format", "", "file format: csv, json or yaml (default: from the file extension)")
+	verbose := fs.Bool("v", false, "also list unchanged courses")
+	skipEmbeddings := fs.Bool("skip-embeddings", false, "do not compute course embeddings after importing")
+	fs.Usage = func() {
+		fmt.Fprintln(stderr, "usage: edusphere import-courses [-dry-run] [-format csv|json|yaml] [-skip-embeddings] [-v] FILE...")
+		fs.PrintDefaults()
+	}
+	if err := fs.Parse(args); err != nil {
+		return 2
+	}
+	if fs.NArg() == 0 {
+		fs.Usage()
+		return 2
+	}
+
+	// 1) Read and validate every file before touching the database
+	var courses []catalog.Course
+	for _, path := range fs.Args() {
+		parsed, err := catalog.ParseFile(path, catalog.Format(strings.ToLower(*format)))
+		if err != nil {
+			fmt.Fprintln(stderr, "import-courses:", err)
+			return 1
+		}
+		courses = append(courses, parsed...)
+	}
+	if err := catalog.Validate(courses); err != nil {
+		fmt.Fprintln(stderr, "import-courses:", err)
+		return 1
+	}
+
+	// 2) Upsert
+	config, err := util.LoadConfig(".")
+	if err != nil {
+		fmt.Fprintln(stderr, "import-courses: cannot load configuration:", err)
+		return 1
+	}
+	conn, err := sql.Open(config.DBDriver, config.DBSource)
+	if err != nil {
+		fmt.Fprintln(stderr, "import-courses: cannot connect to db:", err)
+		return 1
+	}
+	defer conn.Close()
+
+	params := make([]db.CreateCourseParams, 0, len(courses))
+	prereqs := make(map[string]db.CoursePrerequisitesParams, len(courses))
+	for _, c := range courses {
+		p := c.Params()
+		parsed, err := api.ParseCoursePrerequisites(p.Code, p.Prerequisites.String)
+		if err != nil {
+			fmt.Fprintln(stderr, "import-courses:", err)
+			return 1
+		}
+		params = append(params, p)
+		prereqs[p.Code] = parsed
+	}
+	store := db.NewStore(conn)
+	result, err := store.ImportCoursesTx(context.Background(), db.ImportCoursesTxParams{
+		Courses:       params,
+		Prerequisites: prereqs,
+		DryRun:        *dryRun,
+	})
+	if err != nil {
+		fmt.Fprintln(stderr, "import-courses:", err)
+		return 1
+	}
+
+	// 3) Report
+	printImportReport(stdout, result, *dryRun, *verbose)
+
+	// 4) Embed new and changed courses so recommendations need not; a
+	// failure is only a warning, the server embeds what is missing on demand
+	if !*dryRun && !*skipEmbeddings {
+		if err := syncCourseEmbeddings(context.Background(), config, store, stdout); err != nil {
+			fmt.Fprintln(stderr, "import-courses: warning: course embeddings not updated:", err)
+		}
+	}
+	return 0
+}
+
+func syncCourseEmbeddings(ctx context.Context, config util.Config, store db.Store, w io.Writer) error {
+	embedder, err := api.NewEmbedder(config)
+	if err != nil {
+		return err
+	}
+	courses, err := store.ListAllCourses(ctx)
+	if err != nil {
+		return err
+	}
+	_, embedded, err := embedding.Sync(ctx, store, embedder, nil, courses)
+	if err != nil {
+		return err
+	}
+	fmt.Fprintf(w, "%d course embeddings updated (%s)\n", embedded, embedder.Model())
+	return nil
+}
+
+func printImportReport(w io.Writer, result db.ImportCoursesTxResult, dryRun, verbose bool) {
+	for _, code := range result.Inserted {
+		fmt.Fprintf(w, "+ %s\n", code)
+	}
+	for _, code := range result.Updated {
+		fmt.Fprintf(w, "~ %s\n", code)
+	}
+	if verbose {
+		for _, code := range result.Unchanged {
+			fmt.Fprintf(w, "= %s\n", code)
+		}
+	}
+
+	summary := fmt.Sprintf("%d inserted, %d updated, %d unchanged",
+		len(result.Inserted), len(result.Updated), len(result.Unchanged))
+	if dryRun {
+		summary += " (dry run, nothing was written)"
+	}
+	fmt.Fprintln(w, summary)
+}
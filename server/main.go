@@ -16,6 +16,7 @@ package main
 import (
 	"database/sql" // For database connectivity
 	"log"          // For logging errors
+	"os"           // For command-line arguments
 
 	// Database driver for PostgreSQL
 	_ "github.com/lib/pq"
@@ -35,6 +36,16 @@ import (
 // @name Authorization
 
 func main() {
+	// Subcommands; without one the API server starts
+	if len(os.Args) > 1 {
+		switch os.Args[1] {
+		case "import-courses":
+			os.Exit(runImportCourses(os.Args[2:], os.Stdout, os.Stderr))
+		default:
+			log.Fatalf("unknown command %q (available: import-courses)", os.Args[1])
+		}
+	}
+
 	// Load configuration from current directory
 	config, err := util.LoadConfig(".")
 	if err != nil {
@@ -0,0 +1,460 @@
+// server/prereq/prereq.go
+
+package prereq
+
+import (
+	"regexp"
+	"strings"
+	"unicode"
+)
+
+// Kind is the type of a requirement node
+type Kind string
+
+// Requirement node kinds
+const (
+	// A single course that must be completed
+	KindCourse Kind = "course"
+	// Every item must be met
+	KindAll Kind = "all"
+	// At least one item must be met
+	KindAny Kind = "any"
+)
+
+// Requirement is a node of the prerequisite graph: one course, or a group of
+// requirements of which all or any must be met
+type Requirement struct {
+	Kind  Kind           `json:"kind"`
+	Code  string         `json:"code,omitempty"`
+	Items []*Requirement `json:"items,omitempty"`
+}
+
+// Prerequisites is the structured form of a course's prerequisite text
+type Prerequisites struct {
+	// Requirement is nil when the text names no courses
+	Requirement *Requirement `json:"requirement"`
+	// Advisory is set when the courses are only recommended, may be taken at
+	// the same time, or can be replaced by equivalent knowledge. Unmet
+	// advisory prerequisites are reported but do not block enrolment.
+	Advisory bool `json:"advisory"`
+}
+
+var (
+	// JYU/Sisu course codes, e.g. TJTS5012, TIES454, TJTSM51, MATA101A
+	codePattern = regexp.MustCompile(`^[A-ZÅÄÖ]{2,6}[0-9]{2,5}[A-Z]?$`)
+	// Sisu's structured sections, e.g. "Compulsory prerequisites: Option 1: ..."
+	compulsoryPattern  = regexp.MustCompile(`(?i)compulsory prerequisites|pakolliset esitiedot`)
+	recommendedPattern = regexp.MustCompile(`(?i)recommended prerequisites|suositellut esitiedot`)
+	optionPattern      = regexp.MustCompile(`(?i)\b(?:option|vaihtoehto)\s*\d+\s*:`)
+	// Wording that makes the listed courses advisory rather than required
+	advisoryPattern = regexp.MustCompile(`(?i)recommend|suggest|beneficial|helpful|useful|preferab|not mandatory|not required|simultaneous|concurrent|at the same time|or equivalent|or similar|or comparable|or corresponding|equivalent (?:competence|knowledge|skills)|suosit|hyödyll|samanaikaisesti|tai vastaava|tai niitä vastaava`)
+)
+
+// Parse reads free-text prerequisites such as
+//
+//	KOGS1003 Empirical Research Methods and KOGS1004 User Research or KOGS1005 Argumentative Design
+//	TIES4211 (or the earlier TIEA211), TIEA311 and TIES471 must be on the board
+//	Compulsory prerequisites: Option 1: 5 cr TIEA2120 Web GUI programming, 5 cr ITKA2004 Databases
+//
+// into a requirement over course codes. "and", "ja", "sekä", commas and
+// sentence breaks join requirements; "or", "tai" and "/" give alternatives
+// and bind more loosely than "and", as do Sisu's numbered options. Words that
+// are not course codes or connectives are ignored. ownCode, the code of the
+// course the text belongs to, is never required of itself.
+func Parse(text, ownCode string) Prerequisites {
+	var p Prerequisites
+	if loc := compulsoryPattern.FindStringIndex(text); loc != nil {
+		text = text[loc[1]:]
+		if r := recommendedPattern.FindStringIndex(text); r != nil {
+			text = text[:r[0]]
+		}
+	} else if loc := recommendedPattern.FindStringIndex(text); loc != nil {
+		text = text[loc[1]:]
+		p.Advisory = true
+	} else {
+		p.Advisory = advisoryPattern.MatchString(text)
+	}
+
+	parts := optionPattern.Split(text, -1)
+	req := parseClause(parts[0])
+	if len(parts) > 1 {
+		var options *Requirement
+		for _, part := range parts[1:] {
+			options = group(KindAny, options, parseClause(part))
+		}
+		req = group(KindAll, req, options)
+	}
+
+	p.Requirement = prune(req, strings.ToUpper(strings.TrimSpace(ownCode)))
+	return p
+}
+
+// Met reports whether the completed courses satisfy the requirement; a text
+// without course codes is always met.
+func (p Prerequisites) Met(completed map[string]bool) bool {
+	return p.Requirement == nil || p.Requirement.Met(completed)
+}
+
+// Blocks reports whether unmet prerequisites rule the course out.
+func (p Prerequisites) Blocks(completed map[string]bool) bool {
+	return !p.Advisory && !p.Met(completed)
+}
+
+// Missing lists what is still needed, see Requirement.Missing.
+func (p Prerequisites) Missing(completed map[string]bool) []string {
+	if p.Requirement == nil {
+		return nil
+	}
+	return p.Requirement.Missing(completed)
+}
+
+// Met reports whether the completed course codes satisfy r.
+func (r *Requirement) Met(completed map[string]bool) bool {
+	switch r.Kind {
+	case KindCourse:
+		return completed[r.Code]
+	case KindAll:
+		for _, item := range r.Items {
+			if !item.Met(completed) {
+				return false
+			}
+		}
+		return true
+	case KindAny:
+		for _, item := range r.Items {
+			if item.Met(completed) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// Missing lists the unmet parts of r: course codes, and unmet alternatives
+// as one entry each, e.g. "TIES4211 or TIEA211".
+func (r *Requirement) Missing(completed map[string]bool) []string {
+	if r.Met(completed) {
+		return nil
+	}
+	if r.Kind == KindAll {
+		var missing []string
+		for _, item := range r.Items {
+			missing = append(missing, item.Missing(completed)...)
+		}
+		return missing
+	}
+	return []string{r.String()}
+}
+
+// Codes returns every course code named in r, in order of appearance.
+func (r *Requirement) Codes() []string {
+	if r.Kind == KindCourse {
+		return []string{r.Code}
+	}
+	var codes []string
+	seen := map[string]bool{}
+	for _, item := range r.Items {
+		for _, code := range item.Codes() {
+			if !seen[code] {
+				seen[code] = true
+				codes = append(codes, code)
+			}
+		}
+	}
+	return codes
+}
+
+// String renders r as text, e.g. "TJTS5010 and (TIES4211 or TIEA211)".
+func (r *Requirement) String() string {
+	if r.Kind == KindCourse {
+		return r.Code
+	}
+	sep := " and "
+	if r.Kind == KindAny {
+		sep = " or "
+	}
+	parts := make([]string, 0, len(r.Items))
+	for _, item := range r.Items {
+		if item.Kind != KindCourse {
+			parts = append(parts, "("+item.String()+")")
+		} else {
+			parts = append(parts, item.String())
+		}
+	}
+	return strings.Join(parts, sep)
+}
+
+// -----------------------------------------------------------------------------
+// PARSER
+// -----------------------------------------------------------------------------
+
+type tokenKind int
+
+const (
+	tokCode tokenKind = iota
+	tokAnd
+	tokOr
+	tokSep
+	tokOpen
+	tokClose
+)
+
+type token struct {
+	kind tokenKind
+	code string
+}
+
+// tokenize keeps course codes, connectives and punctuation; all other words
+// are dropped.
+func tokenize(text string) []token {
+	var toks []token
+	runes := []rune(text)
+	for i := 0; i < len(runes); i++ {
+		r := runes[i]
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			j := i
+			for j < len(runes) && (unicode.IsLetter(runes[j]) || unicode.IsDigit(runes[j])) {
+				j++
+			}
+			word := string(runes[i:j])
+			switch strings.ToLower(word) {
+			case "and", "ja", "sekä":
+				toks = append(toks, token{kind: tokAnd})
+			case "or", "tai":
+				toks = append(toks, token{kind: tokOr})
+			default:
+				if codePattern.MatchString(word) {
+					toks = append(toks, token{kind: tokCode, code: word})
+				}
+			}
+			i = j - 1
+			continue
+		}
+		switch r {
+		case '&', '+':
+			toks = append(toks, token{kind: tokAnd})
+		case '/':
+			toks = append(toks, token{kind: tokOr})
+		case ',', ';', '.', '\n':
+			toks = append(toks, token{kind: tokSep})
+		case '(', '[':
+			toks = append(toks, token{kind: tokOpen})
+		case ')', ']':
+			toks = append(toks, token{kind: tokClose})
+		}
+	}
+	return toks
+}
+
+// parseClause parses the text of one option: requirements separated by
+// commas or sentence breaks, all of which must be met.
+func parseClause(text string) *Requirement {
+	toks := unwrapAlternatives(tokenize(text))
+	if !balanced(toks) {
+		toks = dropParens(toks)
+	}
+
+	var req *Requirement
+	depth, start := 0, 0
+	for i := 0; i <= len(toks); i++ {
+		if i < len(toks) {
+			switch toks[i].kind {
+			case tokOpen:
+				depth++
+				continue
+			case tokClose:
+				depth--
+				continue
+			case tokSep:
+				if depth > 0 {
+					continue
+				}
+			default:
+				continue
+			}
+		}
+		req = group(KindAll, req, parseItem(toks[start:i]))
+		start = i + 1
+	}
+	return req
+}
+
+func parseItem(toks []token) *Requirement {
+	p := &parser{toks: toks}
+	var req *Requirement
+	for p.pos < len(p.toks) {
+		pos := p.pos
+		req = group(KindAll, req, p.parseOr())
+		if p.pos == pos {
+			p.pos++
+		}
+	}
+	return req
+}
+
+// unwrapAlternatives turns "A (or B)" into "A or B".
+func unwrapAlternatives(toks []token) []token {
+	for i := 0; i+1 < len(toks); i++ {
+		if toks[i].kind != tokOpen || toks[i+1].kind != tokOr {
+			continue
+		}
+		depth := 0
+		for j := i; j < len(toks); j++ {
+			if toks[j].kind == tokOpen {
+				depth++
+			} else if toks[j].kind == tokClose {
+				depth--
+			}
+			if depth == 0 {
+				toks = append(toks[:j], toks[j+1:]...)
+				toks = append(toks[:i], toks[i+1:]...)
+				break
+			}
+		}
+	}
+	return toks
+}
+
+func balanced(toks []token) bool {
+	depth := 0
+	for _, t := range toks {
+		switch t.kind {
+		case tokOpen:
+			depth++
+		case tokClose:
+			depth--
+			if depth < 0 {
+				return false
+			}
+		}
+	}
+	return depth == 0
+}
+
+func dropParens(toks []token) []token {
+	kept := toks[:0]
+	for _, t := range toks {
+		if t.kind != tokOpen && t.kind != tokClose {
+			kept = append(kept, t)
+		}
+	}
+	return kept
+}
+
+// parser is a forgiving recursive-descent parser: stray connectives and
+// empty groups are skipped, and adjacent codes are joined with "and".
+type parser struct {
+	toks []token
+	pos  int
+}
+
+func (p *parser) parseOr() *Requirement {
+	left := p.parseAnd()
+	for p.pos < len(p.toks) && p.toks[p.pos].kind == tokOr {
+		p.pos++
+		left = group(KindAny, left, p.parseAnd())
+	}
+	return left
+}
+
+func (p *parser) parseAnd() *Requirement {
+	left := p.parsePrimary()
+	for p.pos < len(p.toks) {
+		switch p.toks[p.pos].kind {
+		case tokAnd, tokSep:
+			p.pos++
+		case tokCode, tokOpen:
+		default:
+			return left
+		}
+		left = group(KindAll, left, p.parsePrimary())
+	}
+	return left
+}
+
+func (p *parser) parsePrimary() *Requirement {
+	for p.pos < len(p.toks) {
+		t := p.toks[p.pos]
+		switch t.kind {
+		case tokCode:
+			p.pos++
+			return &Requirement{Kind: KindCourse, Code: t.code}
+		case tokOpen:
+			p.pos++
+			req := p.parseOr()
+			for p.pos < len(p.toks) && p.toks[p.pos].kind != tokClose {
+				p.pos++
+				req = group(KindAll, req, p.parseOr())
+			}
+			p.pos++
+			return req
+		case tokClose:
+			return nil
+		default:
+			p.pos++
+		}
+	}
+	return nil
+}
+
+// group combines a and b under kind, skipping nil operands and flattening
+// nested groups of the same kind.
+func group(kind Kind, a, b *Requirement) *Requirement {
+	if a == nil {
+		return b
+	}
+	if b == nil {
+		return a
+	}
+	items := make([]*Requirement, 0, 2)
+	for _, r := range []*Requirement{a, b} {
+		if r.Kind == kind {
+			items = append(items, r.Items...)
+		} else {
+			items = append(items, r)
+		}
+	}
+	return &Requirement{Kind: kind, Items: items}
+}
+
+// prune removes ownCode and duplicate items and collapses groups left with a
+// single item.
+func prune(r *Requirement, ownCode string) *Requirement {
+	if r == nil {
+		return nil
+	}
+	if r.Kind == KindCourse {
+		if r.Code == ownCode {
+			return nil
+		}
+		return r
+	}
+
+	var pruned []*Requirement
+	for _, item := range r.Items {
+		if item = prune(item, ownCode); item == nil {
+			continue
+		}
+		if item.Kind == r.Kind {
+			pruned = append(pruned, item.Items...)
+		} else {
+			pruned = append(pruned, item)
+		}
+	}
+
+	var items []*Requirement
+	seen := map[string]bool{}
+	for _, item := range pruned {
+		if key := item.String(); !seen[key] {
+			seen[key] = true
+			items = append(items, item)
+		}
+	}
+	switch len(items) {
+	case 0:
+		return nil
+	case 1:
+		return items[0]
+	}
+	return &Requirement{Kind: r.Kind, Items: items}
+}
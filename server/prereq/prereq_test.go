@@ -0,0 +1,129 @@
+// server/prereq/prereq_test.go
+
+package prereq
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParse(t *testing.T) {
+	testCases := []struct {
+		name     string
+		text     string
+		own      string
+		want     string
+		advisory bool
+	}{
+		{name: "And", text: "TJTS5010 and TJTS5011", want: "TJTS5010 and TJTS5011"},
+		{name: "CommaList", text: "TSAS4032,TSAS4033", want: "TSAS4032 and TSAS4033"},
+		{name: "Single", text: "KOGS1003 Empirical research methods", want: "KOGS1003"},
+		{name: "NoCodes", text: "The information has not been given."},
+		{
+			name: "NamesWithAnd",
+			text: "KOGS1001 Cognitive Science, Humans and Technology and KOGS1002 User Psychology",
+			want: "KOGS1001 and KOGS1002",
+		},
+		{
+			name: "AndBindsTighterThanOr",
+			text: "KOGS1003 Empirical Research Methods and KOGS1004 User Research or KOGS1005 Argumentative Design, or equivalent competence",
+			want: "(KOGS1003 and KOGS1004) or KOGS1005", advisory: true,
+		},
+		{
+			name: "Finnish",
+			text: "KOGS1003 Empiiriset tutkimusmenetelmät ja KOGS1004 Käyttäjätutkimus tai KOGS1005 Argumentoiva suunnittelu, tai vastaava osaaminen",
+			want: "(KOGS1003 and KOGS1004) or KOGS1005", advisory: true,
+		},
+		{
+			name: "ParenthesisedAlternative",
+			text: "TIES4211 (or the earlier TIEA211), TIEA311 and TIES471 must be on the board or this course is not relevant.",
+			want: "(TIES4211 or TIEA211) and TIEA311 and TIES471",
+		},
+		{
+			name: "SlashAlternatives",
+			text: "KOTEA261 Tietotekniikan rooli oppimistilanteissa / TIEA261 Tietotekniikan rooli opetuksessa 2 op, KOTEA361/TIEA361 Tietotekniikan opettajan työvälineitä 5 op.",
+			want: "(KOTEA261 or TIEA261) and (KOTEA361 or TIEA361)",
+		},
+		{
+			name: "UnbalancedParens",
+			text: "This course requires completion of the course (Collective Intelligence and Agent Technology (Course code: TIES4530) and programming skills (especially in Java).",
+			own:  "TIES454",
+			want: "TIES4530",
+		},
+		{
+			name: "Recommended",
+			text: "It is recommended that students have passed TJTSM51 Information Security Management / KYBS3070 Information Security Management.",
+			want: "TJTSM51 or KYBS3070", advisory: true,
+		},
+		{
+			name: "Simultaneous",
+			text: "TIEA3000 Introduction to Embedded Systems, TIES536 Embedded Internet, TEKS4440 IoT Device Communications (can also be completed simultaneously)",
+			want: "TIEA3000 and TIES536 and TEKS4440", advisory: true,
+		},
+		{
+			name: "CompulsoryOptions",
+			text: "Tietokonegrafiikan perusteet sekä Algoritmit 1 ja 2 täytyy olla, lineaarialgebran kurssi on vahvasti suositeltava. Compulsory prerequisites: Option 1: 5 cr TIEA311 Introduction to Computer Graphics, 5 cr ITKA201 Algorithms 1, 4 cr TIEA211 Algorithms 2, Option 2: 5 cr TIES4211 Algorithms, Recommended prerequisites Option 1: 7 cr MATP121 Linear Algebra",
+			want: "(TIEA311 and ITKA201 and TIEA211) or TIES4211",
+		},
+		{
+			name: "RecommendedSection",
+			text: "Students start the work during the second year. Recommended prerequisites Option 1: 5 cr TJTS5002 Information Systems Theories, 2 cr TJTS5010 Research Frameworks",
+			want: "TJTS5002 and TJTS5010", advisory: true,
+		},
+		{name: "OwnCode", text: "ITKS5440, TIES4530, TIES4570", own: "ties4570", want: "ITKS5440 and TIES4530"},
+		{name: "Duplicates", text: "TEKA2002, TEKA2002 and TEKP1110", want: "TEKA2002 and TEKP1110"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			p := Parse(tc.text, tc.own)
+			require.Equal(t, tc.advisory, p.Advisory)
+			if tc.want == "" {
+				require.Nil(t, p.Requirement)
+				return
+			}
+			require.NotNil(t, p.Requirement)
+			require.Equal(t, tc.want, p.Requirement.String())
+		})
+	}
+}
+
+func TestMissing(t *testing.T) {
+	p := Parse("TIES4211 (or the earlier TIEA211), TIEA311 and TIES471", "")
+	require.Equal(t, []string{"TIES4211", "TIEA211", "TIEA311", "TIES471"}, p.Requirement.Codes())
+
+	completed := map[string]bool{"TIEA311": true}
+	require.False(t, p.Met(completed))
+	require.True(t, p.Blocks(completed))
+	require.Equal(t, []string{"TIES4211 or TIEA211", "TIES471"}, p.Missing(completed))
+
+	completed["TIEA211"] = true
+	completed["TIES471"] = true
+	require.True(t, p.Met(completed))
+	require.False(t, p.Blocks(completed))
+	require.Empty(t, p.Missing(completed))
+
+	// Advisory prerequisites are reported but never block
+	p = Parse("It is recommended you have already completed the Information Security Management course (TJTSM51).", "")
+	require.False(t, p.Blocks(nil))
+	require.Equal(t, []string{"TJTSM51"}, p.Missing(nil))
+
+	// No course codes: nothing to meet
+	p = Parse("Basic programming skills", "")
+	require.True(t, p.Met(nil))
+	require.Empty(t, p.Missing(nil))
+}
+
+func TestRequirementJSON(t *testing.T) {
+	data, err := json.Marshal(Parse("TJTS5010 and TJTS5011 or TJTS5001", ""))
+	require.NoError(t, err)
+	require.JSONEq(t, `{
+		"requirement": {"kind": "any", "items": [
+			{"kind": "all", "items": [{"kind": "course", "code": "TJTS5010"}, {"kind": "course", "code": "TJTS5011"}]},
+			{"kind": "course", "code": "TJTS5001"}
+		]},
+		"advisory": false
+	}`, string(data))
+}
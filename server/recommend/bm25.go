@@ -0,0 +1,269 @@
+// server/recommend/bm25.go
+
+// Package recommend ranks catalogue courses against a student's preference
+// and transcript without a language model, using BM25 over course names and
+// learning outcomes.
+package recommend
+
+import (
+	"math"
+	"sort"
+	"strings"
+	"unicode"
+)
+
+// BM25 parameters: term frequency saturation and document length
+// normalisation, at their usual values
+const (
+	bm25K1 = 1.2
+	bm25B  = 0.75
+)
+
+// A name term counts as often as this many learning outcome terms
+const nameWeight = 2
+
+// Transcript course names weigh this much against the preference
+const historyWeight = 0.5
+
+// Names of courses the student liked weigh this much against the
+// preference; more than the transcript, as the student chose them
+const likedWeight = 0.75
+
+// Course is a catalogue course to rank
+type Course struct {
+	ID               int64
+	Name             string
+	LearningOutcomes string
+}
+
+// Query is what courses are ranked against: the student's free-text
+// preference, the names of courses on their transcript and the names of
+// recommended courses they liked
+type Query struct {
+	Preference string
+	History    []string
+	Liked      []string
+}
+
+// Match is a ranked course and why it matched
+type Match struct {
+	CourseID int64
+	Score    float64
+
+	// Preference words the course matched, most important first
+	Terms []string
+
+	// The transcript course the course builds on most, if any
+	Related string
+
+	// The liked course it is most similar to, if any
+	Liked string
+}
+
+type queryTerm struct {
+	weight  float64
+	surface string // as the student wrote it, for rationales
+	history []int  // transcript courses with the term
+	liked   []int  // liked courses with the term
+}
+
+// Rank returns up to n courses that share words with the query, best
+// first. Ties keep the order of courses.
+func Rank(courses []Course, q Query, n int) []Match {
+	terms := queryTerms(q)
+	if len(terms) == 0 || len(courses) == 0 {
+		return []Match{}
+	}
+
+	// 1) Term frequencies, document frequencies and lengths
+	docs := make([]map[string]float64, len(courses))
+	lengths := make([]float64, len(courses))
+	df := map[string]int{}
+	var total float64
+	for i, c := range courses {
+		tf := map[string]float64{}
+		for _, w := range Words(c.Name) {
+			tf[w] += nameWeight
+		}
+		for _, w := range Words(c.LearningOutcomes) {
+			tf[w]++
+		}
+		for w, f := range tf {
+			df[w]++
+			lengths[i] += f
+		}
+		docs[i] = tf
+		total += lengths[i]
+	}
+	avgLen := total / float64(len(courses))
+	if avgLen == 0 {
+		avgLen = 1
+	}
+
+	// 2) Score
+	matches := make([]Match, 0, len(courses))
+	for i, c := range courses {
+		var score float64
+		prefScores := map[string]float64{}
+		historyScores := map[int]float64{}
+		likedScores := map[int]float64{}
+		for w, qt := range terms {
+			f := docs[i][w]
+			if f == 0 {
+				continue
+			}
+			idf := math.Log(1 + (float64(len(courses)-df[w])+0.5)/(float64(df[w])+0.5))
+			s := qt.weight * idf * f * (bm25K1 + 1) / (f + bm25K1*(1-bm25B+bm25B*lengths[i]/avgLen))
+			score += s
+			if qt.surface != "" {
+				prefScores[qt.surface] += s
+			}
+			for _, h := range qt.history {
+				historyScores[h] += s
+			}
+			for _, l := range qt.liked {
+				likedScores[l] += s
+			}
+		}
+		if score == 0 {
+			continue
+		}
+		m := Match{CourseID: c.ID, Score: score, Terms: topKeys(prefScores, 3)}
+		if h, ok := bestHistory(historyScores); ok {
+			m.Related = q.History[h]
+		}
+		if l, ok := bestHistory(likedScores); ok {
+			m.Liked = q.Liked[l]
+		}
+		matches = append(matches, m)
+	}
+
+	sort.SliceStable(matches, func(i, j int) bool { return matches[i].Score > matches[j].Score })
+	if n >= 0 && len(matches) > n {
+		matches = matches[:n]
+	}
+	return matches
+}
+
+// queryTerms weighs the preference words 1 each, the transcript words
+// historyWeight and the liked course words likedWeight each, summed over
+// repeats.
+func queryTerms(q Query) map[string]*queryTerm {
+	terms := map[string]*queryTerm{}
+	get := func(w string) *queryTerm {
+		if terms[w] == nil {
+			terms[w] = &queryTerm{}
+		}
+		return terms[w]
+	}
+	for _, raw := range rawWords(q.Preference) {
+		w := stem(raw)
+		if w == "" {
+			continue
+		}
+		qt := get(w)
+		qt.weight++
+		if qt.surface == "" {
+			qt.surface = raw
+		}
+	}
+	for i, name := range q.History {
+		seen := map[string]bool{}
+		for _, w := range Words(name) {
+			qt := get(w)
+			qt.weight += historyWeight
+			if !seen[w] {
+				qt.history = append(qt.history, i)
+				seen[w] = true
+			}
+		}
+	}
+	for i, name := range q.Liked {
+		seen := map[string]bool{}
+		for _, w := range Words(name) {
+			qt := get(w)
+			qt.weight += likedWeight
+			if !seen[w] {
+				qt.liked = append(qt.liked, i)
+				seen[w] = true
+			}
+		}
+	}
+	return terms
+}
+
+// topKeys returns up to n keys of scores, highest first, ties by key.
+func topKeys(scores map[string]float64, n int) []string {
+	keys := make([]string, 0, len(scores))
+	for k := range scores {
+		keys = append(keys, k)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if scores[keys[i]] != scores[keys[j]] {
+			return scores[keys[i]] > scores[keys[j]]
+		}
+		return keys[i] < keys[j]
+	})
+	if len(keys) > n {
+		keys = keys[:n]
+	}
+	return keys
+}
+
+// bestHistory returns the transcript (or liked) course with the highest
+// score; the earliest wins a tie.
+func bestHistory(scores map[int]float64) (int, bool) {
+	best, found := 0, false
+	for h, s := range scores {
+		if !found || s > scores[best] || (s == scores[best] && h < best) {
+			best, found = h, true
+		}
+	}
+	return best, found
+}
+
+// stopwords are left out of matching; they appear in most course texts
+var stopwords = map[string]bool{
+	"the": true, "and": true, "for": true, "with": true, "are": true, "will": true,
+	"can": true, "able": true, "this": true, "that": true, "course": true,
+	"courses": true, "students": true, "student": true, "after": true,
+	"completing": true, "understand": true, "knowledge": true, "their": true,
+	"how": true, "about": true, "want": true, "like": true, "would": true,
+	"interested": true, "learn": true, "learning": true, "more": true,
+	"some": true, "also": true, "from": true, "into": true, "basic": true,
+	"basics": true, "introduction": true, "ja": true, "tai": true,
+	"sekä": true, "opiskelija": true, "kurssin": true, "haluan": true,
+	"oppia": true,
+}
+
+// Words splits text into matchable words: lower-cased, without stopwords,
+// with simple English plurals folded ("networks" -> "network").
+func Words(text string) []string {
+	raw := rawWords(text)
+	out := raw[:0]
+	for _, w := range raw {
+		if s := stem(w); s != "" {
+			out = append(out, s)
+		}
+	}
+	return out
+}
+
+func rawWords(text string) []string {
+	return strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+}
+
+func stem(w string) string {
+	if len([]rune(w)) < 2 || stopwords[w] {
+		return ""
+	}
+	switch {
+	case strings.HasSuffix(w, "ies") && len(w) > 4:
+		return strings.TrimSuffix(w, "ies") + "y"
+	case strings.HasSuffix(w, "s") && !strings.HasSuffix(w, "ss") && !strings.HasSuffix(w, "us") && len(w) > 3:
+		return strings.TrimSuffix(w, "s")
+	}
+	return w
+}
@@ -0,0 +1,59 @@
+// server/recommend/bm25_test.go
+
+package recommend
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+var testCourses = []Course{
+	{ID: 1, Name: "Finnish 1", LearningOutcomes: "Everyday Finnish conversation"},
+	{ID: 2, Name: "Machine Learning", LearningOutcomes: "Neural networks and statistical learning"},
+	{ID: 3, Name: "Cyber Security", LearningOutcomes: "Threats, cryptography and network security"},
+	{ID: 4, Name: "Computer Networks", LearningOutcomes: "Protocols, routing and network programming"},
+}
+
+func TestWords(t *testing.T) {
+	require.Equal(t, []string{"network", "security", "study", "class"},
+		Words("The Networks and Security studies, class 1"))
+	require.Empty(t, Words("and the for"))
+}
+
+func TestRank(t *testing.T) {
+	matches := Rank(testCourses, Query{Preference: "I want to learn about security and cryptography"}, 5)
+	require.Len(t, matches, 1)
+	require.Equal(t, int64(3), matches[0].CourseID)
+	require.Equal(t, []string{"security", "cryptography"}, matches[0].Terms)
+	require.Empty(t, matches[0].Related)
+
+	// The name counts more than the learning outcomes
+	matches = Rank(testCourses, Query{Preference: "networks"}, 5)
+	require.Len(t, matches, 3)
+	require.Equal(t, int64(4), matches[0].CourseID)
+	require.Equal(t, []string{"networks"}, matches[0].Terms)
+
+	// Transcript course names count too, and are named in the match
+	matches = Rank(testCourses, Query{Preference: "cryptography", History: []string{"Swedish 2", "Network Programming"}}, 2)
+	require.Len(t, matches, 2)
+	require.Equal(t, int64(3), matches[0].CourseID)
+	require.Equal(t, "Network Programming", matches[0].Related)
+	require.Equal(t, int64(4), matches[1].CourseID)
+	require.Empty(t, matches[1].Terms)
+	require.Equal(t, "Network Programming", matches[1].Related)
+
+	// Deterministic
+	require.Equal(t, matches, Rank(testCourses, Query{Preference: "cryptography", History: []string{"Swedish 2", "Network Programming"}}, 2))
+
+	// Liked courses pull similar ones up and are named in the match
+	matches = Rank(testCourses, Query{Preference: "cryptography", Liked: []string{"Deep Neural Networks"}}, 5)
+	require.Len(t, matches, 3)
+	require.Equal(t, int64(3), matches[0].CourseID)
+	require.Equal(t, int64(2), matches[1].CourseID)
+	require.Empty(t, matches[1].Terms)
+	require.Equal(t, "Deep Neural Networks", matches[1].Liked)
+
+	require.Empty(t, Rank(testCourses, Query{Preference: "the and"}, 5))
+	require.Empty(t, Rank(nil, Query{Preference: "security"}, 5))
+}
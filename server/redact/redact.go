@@ -0,0 +1,228 @@
+// server/redact/redact.go
+
+package redact
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Level selects how aggressively personal data is masked
+type Level int
+
+const (
+	// Standard masks labelled fields (name, student number, date of birth,
+	// address), Finnish personal identity codes and the student's known name
+	Standard Level = iota
+	// Strict additionally masks every part of the student's name, e-mail
+	// addresses, phone numbers, unlabelled long numbers and street addresses
+	Strict
+)
+
+// Kinds of personal data, used in placeholders such as [NAME_1]
+const (
+	KindName          = "NAME"
+	KindStudentNumber = "STUDENT_NUMBER"
+	KindDateOfBirth   = "DOB"
+	KindAddress       = "ADDRESS"
+	KindPersonalID    = "PERSONAL_ID"
+	KindEmail         = "EMAIL"
+	KindPhone         = "PHONE"
+)
+
+// fieldPattern finds a labelled value ("Student number: 1234567") on one line
+type fieldPattern struct {
+	kind    string
+	pattern *regexp.Regexp
+}
+
+var (
+	fieldPatterns = []fieldPattern{
+		{KindName, regexp.MustCompile(`(?im)^[ \t]*(?:student(?:'s)? name|full name|name|nimi|opiskelijan nimi|opiskelija|namn|studerande)[ \t]*:[ \t]*([^\n:]+?)[ \t]*$`)},
+		{KindStudentNumber, regexp.MustCompile(`(?i)(?:student (?:number|no\.?|id)|opiskelijanumero|matrikkelinumero|studentnummer)[ \t]*:?[ \t]*([A-Z]?\d{4,12})\b`)},
+		{KindDateOfBirth, regexp.MustCompile(`(?i)(?:date of birth|birth date|birthday|born|syntymäaika|födelsedatum)[ \t]*:?[ \t]*(\d{1,2}\.\d{1,2}\.\d{4}|\d{4}-\d{2}-\d{2}|\d{1,2}/\d{1,2}/\d{4})`)},
+		{KindAddress, regexp.MustCompile(`(?im)^[ \t]*(?:home address|postal address|address|kotiosoite|postiosoite|osoite|adress)[ \t]*:[ \t]*([^\n]+?)[ \t]*$`)},
+	}
+
+	// Finnish personal identity code (henkilötunnus), e.g. 131052-308T
+	personalIDPattern = regexp.MustCompile(`\b\d{6}[-+A-FU-Y]\d{3}[0-9A-Y]\b`)
+
+	// Strict level only
+	emailPattern  = regexp.MustCompile(`[\w.+-]+@[\w-]+(?:\.[\w-]+)+`)
+	phonePattern  = regexp.MustCompile(`(?:\+\d{1,3}|\b0)\d{1,3}[ -]?\d{2,4}[ -]?\d{3,7}\b`)
+	numberPattern = regexp.MustCompile(`\b\d{5,12}\b`)
+	streetPattern = regexp.MustCompile(`(?i)\b[\p{L}-]+(?:katu|tie|kuja|polku|väylä|gatan|vägen| street| road| avenue)[ \t]+\d+(?:[ \t]?[A-Z](?:[ \t]?\d+)?)?\b`)
+	postalPattern = regexp.MustCompile(`\b\d{5}[ \t]+\p{Lu}\p{L}+`)
+)
+
+// Redactor masks personal data in text sent to an external model and puts it
+// back into model output. A Redactor keeps its placeholder mapping, so use one
+// per request and run every message of the request through it.
+type Redactor struct {
+	level Level
+	names []string
+
+	byValue       map[string]string // kind + "\x00" + lower(value) -> placeholder
+	byPlaceholder map[string]string // placeholder -> original value
+	counts        map[string]int
+}
+
+// New creates a Redactor. names are the student's known names (e.g. the full
+// name from their profile); they are masked wherever they appear.
+func New(level Level, names ...string) *Redactor {
+	r := &Redactor{
+		level:         level,
+		byValue:       make(map[string]string),
+		byPlaceholder: make(map[string]string),
+		counts:        make(map[string]int),
+	}
+	for _, n := range names {
+		if n = strings.Join(strings.Fields(n), " "); n != "" {
+			r.names = append(r.names, n)
+		}
+	}
+	return r
+}
+
+// Redact returns text with personal data replaced by placeholders.
+func (r *Redactor) Redact(text string) string {
+	if strings.TrimSpace(text) == "" {
+		return text
+	}
+
+	type finding struct{ kind, value string }
+	var found []finding
+	add := func(kind, value string) {
+		value = strings.TrimSpace(value)
+		if value != "" && !isPlaceholder(value) {
+			found = append(found, finding{kind, value})
+		}
+	}
+
+	// 1) Labelled fields
+	for _, fp := range fieldPatterns {
+		for _, m := range fp.pattern.FindAllStringSubmatch(text, -1) {
+			add(fp.kind, m[1])
+		}
+	}
+	for _, m := range personalIDPattern.FindAllString(text, -1) {
+		add(KindPersonalID, m)
+	}
+
+	// 2) Known names, also in "Last, First" order
+	for _, n := range r.names {
+		add(KindName, n)
+		if parts := strings.Fields(n); len(parts) > 1 {
+			add(KindName, parts[len(parts)-1]+", "+strings.Join(parts[:len(parts)-1], " "))
+		}
+	}
+
+	// 3) Strict extras
+	if r.level >= Strict {
+		for _, n := range r.names {
+			for _, part := range strings.Fields(n) {
+				if utf8.RuneCountInString(part) >= 3 {
+					add(KindName, part)
+				}
+			}
+		}
+		for _, m := range emailPattern.FindAllString(text, -1) {
+			add(KindEmail, m)
+		}
+		for _, m := range streetPattern.FindAllString(text, -1) {
+			add(KindAddress, m)
+		}
+		for _, m := range postalPattern.FindAllString(text, -1) {
+			add(KindAddress, m)
+		}
+		for _, m := range phonePattern.FindAllString(text, -1) {
+			add(KindPhone, m)
+		}
+		for _, m := range numberPattern.FindAllString(text, -1) {
+			add(KindStudentNumber, m)
+		}
+	}
+
+	// Longest values first, so "Matti Meikäläinen" wins over "Matti"
+	sort.SliceStable(found, func(i, j int) bool { return len(found[i].value) > len(found[j].value) })
+	for _, f := range found {
+		text = replaceWord(text, f.value, func() string { return r.placeholder(f.kind, f.value) })
+	}
+	return text
+}
+
+// Restore puts the original values back in place of the placeholders.
+func (r *Redactor) Restore(text string) string {
+	if len(r.byPlaceholder) == 0 || !strings.Contains(text, "[") {
+		return text
+	}
+	pairs := make([]string, 0, 2*len(r.byPlaceholder))
+	for p, v := range r.byPlaceholder {
+		pairs = append(pairs, p, v)
+	}
+	return strings.NewReplacer(pairs...).Replace(text)
+}
+
+// Counts returns how many distinct values of each kind were masked.
+func (r *Redactor) Counts() map[string]int {
+	out := make(map[string]int, len(r.counts))
+	for k, v := range r.counts {
+		out[k] = v
+	}
+	return out
+}
+
+// placeholder returns the stable placeholder for a value, creating it on first use.
+func (r *Redactor) placeholder(kind, value string) string {
+	key := kind + "\x00" + strings.ToLower(value)
+	if p, ok := r.byValue[key]; ok {
+		return p
+	}
+	r.counts[kind]++
+	p := fmt.Sprintf("[%s_%d]", kind, r.counts[kind])
+	r.byValue[key] = p
+	r.byPlaceholder[p] = value
+	return p
+}
+
+var placeholderPattern = regexp.MustCompile(`^\[[A-Z_]+_\d+\]$`)
+
+func isPlaceholder(s string) bool {
+	return placeholderPattern.MatchString(s)
+}
+
+// replaceWord replaces case-insensitive occurrences of value that are not
+// part of a longer word or number. replacement is only called on a match, so
+// values that do not occur use up no placeholder.
+func replaceWord(text, value string, replacement func() string) string {
+	re, err := regexp.Compile(`(?i)` + regexp.QuoteMeta(value))
+	if err != nil {
+		return text
+	}
+
+	var sb strings.Builder
+	last := 0
+	for _, loc := range re.FindAllStringIndex(text, -1) {
+		before, _ := utf8.DecodeLastRuneInString(text[:loc[0]])
+		after, _ := utf8.DecodeRuneInString(text[loc[1]:])
+		if isWordRune(before) || isWordRune(after) {
+			continue
+		}
+		sb.WriteString(text[last:loc[0]])
+		sb.WriteString(replacement())
+		last = loc[1]
+	}
+	if last == 0 {
+		return text
+	}
+	sb.WriteString(text[last:])
+	return sb.String()
+}
+
+func isWordRune(r rune) bool {
+	return r != utf8.RuneError && (unicode.IsLetter(r) || unicode.IsDigit(r))
+}
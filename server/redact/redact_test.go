@@ -0,0 +1,88 @@
+// server/redact/redact_test.go
+
+package redact
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+const testTranscript = `University of Jyväskylä
+Transcript of Records
+Name: Meikäläinen, Matti
+Student number: 1234567
+Date of birth: 14.03.1999
+Personal identity code: 140399-123A
+Address: Kauppakatu 12 A 3, 40100 Jyväskylä
+E-mail: matti.meikalainen@student.jyu.fi
+Phone 040 123 4567
+
+TJTS5012 Additional Research Methods Module 5 cr 4 12.05.2023
+ITKA203 Käyttöjärjestelmät 5 op hyv. 14.12.2021
+Student 1234567 has completed 10 credits.`
+
+func TestRedactStandard(t *testing.T) {
+	r := New(Standard, "Matti Meikäläinen")
+	out := r.Redact(testTranscript)
+
+	for _, pii := range []string{"Meikäläinen, Matti", "1234567", "14.03.1999", "140399-123A", "Kauppakatu 12 A 3"} {
+		require.NotContains(t, out, pii)
+	}
+	require.Contains(t, out, "Name: [NAME_")
+	require.Contains(t, out, "Student [STUDENT_NUMBER_1] has completed")
+	require.Contains(t, out, "Date of birth: [DOB_1]")
+	require.Contains(t, out, "[PERSONAL_ID_1]")
+
+	// Course data is untouched
+	require.Contains(t, out, "TJTS5012 Additional Research Methods Module 5 cr 4 12.05.2023")
+	require.Contains(t, out, "ITKA203 Käyttöjärjestelmät 5 op hyv. 14.12.2021")
+
+	// Standard leaves unlabelled contact details alone
+	require.Contains(t, out, "matti.meikalainen@student.jyu.fi")
+
+	require.Equal(t, testTranscript, r.Restore(out))
+}
+
+func TestRedactStrict(t *testing.T) {
+	r := New(Strict, "Matti Meikäläinen")
+	out := r.Redact(testTranscript + "\nMatti took extra courses.")
+
+	for _, pii := range []string{"Matti", "Meikäläinen", "matti.meikalainen@", "040 123 4567", "1234567"} {
+		require.NotContains(t, out, pii)
+	}
+	require.Contains(t, out, "[EMAIL_1]")
+	require.Contains(t, out, "[PHONE_1]")
+	require.Contains(t, out, "TJTS5012 Additional Research Methods Module 5 cr 4 12.05.2023")
+}
+
+func TestRedactStablePlaceholders(t *testing.T) {
+	r := New(Standard, "Matti Meikäläinen")
+	a := r.Redact("Hello Matti Meikäläinen")
+	b := r.Redact("MATTI MEIKÄLÄINEN asked a question")
+
+	require.Equal(t, "Hello [NAME_1]", a)
+	require.Equal(t, "[NAME_1] asked a question", b)
+	require.Equal(t, "Dear Matti Meikäläinen,", r.Restore("Dear [NAME_1],"))
+}
+
+func TestRedactWholeWordsOnly(t *testing.T) {
+	r := New(Strict, "Ana Li")
+	out := r.Redact("Ana studies Analytics and Linear Algebra")
+	require.Equal(t, "[NAME_1] studies Analytics and Linear Algebra", out)
+}
+
+func TestStreamRestorer(t *testing.T) {
+	r := New(Standard, "Matti Meikäläinen")
+	redacted := r.Redact("Matti Meikäläinen")
+	require.Equal(t, "[NAME_1]", redacted)
+
+	s := NewStreamRestorer(r)
+	var sb strings.Builder
+	for _, chunk := range []string{"Hello ", "[NA", "ME", "_1]", ", your ", "GPA [is] 4", "."} {
+		sb.WriteString(s.Write(chunk))
+	}
+	sb.WriteString(s.Flush())
+	require.Equal(t, "Hello Matti Meikäläinen, your GPA [is] 4.", sb.String())
+}
@@ -0,0 +1,45 @@
+// server/redact/stream.go
+
+package redact
+
+import "strings"
+
+// maxPlaceholderLen bounds how much streamed text is held back while waiting
+// for the end of a possible placeholder
+const maxPlaceholderLen = 32
+
+// StreamRestorer restores placeholders in streamed model output, where a
+// placeholder such as [NAME_1] may be split across chunks
+type StreamRestorer struct {
+	r   *Redactor
+	buf string
+}
+
+// NewStreamRestorer creates a StreamRestorer for the given Redactor
+func NewStreamRestorer(r *Redactor) *StreamRestorer {
+	return &StreamRestorer{r: r}
+}
+
+// Write adds a chunk and returns the restored text that is safe to emit. A
+// trailing, unfinished placeholder is held back until a later chunk completes it.
+func (s *StreamRestorer) Write(chunk string) string {
+	s.buf += chunk
+
+	open := strings.LastIndex(s.buf, "[")
+	if open >= 0 && !strings.Contains(s.buf[open:], "]") && len(s.buf)-open < maxPlaceholderLen {
+		out := s.r.Restore(s.buf[:open])
+		s.buf = s.buf[open:]
+		return out
+	}
+
+	out := s.r.Restore(s.buf)
+	s.buf = ""
+	return out
+}
+
+// Flush returns whatever text is still held back.
+func (s *StreamRestorer) Flush() string {
+	out := s.r.Restore(s.buf)
+	s.buf = ""
+	return out
+}
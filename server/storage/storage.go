@@ -0,0 +1,107 @@
+// server/storage/storage.go
+
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"strings"
+	"time"
+)
+
+// Supported storage backends (STORAGE_BACKEND)
+const (
+	Local = "local"
+	S3    = "s3"
+)
+
+// ErrNotFound is returned when no object exists under a key
+var ErrNotFound = errors.New("object not found")
+
+// BlobStore is an interface for storing uploaded and generated files under
+// slash-separated keys such as "uploads/alice/transcript.pdf". The database
+// keeps keys, never host paths, so every API replica sees the same files.
+type BlobStore interface {
+	// Put stores the content of r under key. size is the content length, or -1 if unknown
+	Put(ctx context.Context, key string, r io.Reader, size int64, contentType string) error
+
+	// Get opens the object stored under key; it returns ErrNotFound if there is none
+	Get(ctx context.Context, key string) (io.ReadCloser, error)
+
+	// Delete removes the object stored under key; deleting a missing object is not an error
+	Delete(ctx context.Context, key string) error
+}
+
+// Presigner is implemented by stores that can hand out temporary download URLs
+type Presigner interface {
+	// PresignGet returns a URL that downloads key without credentials until expiry
+	PresignGet(ctx context.Context, key string, expiry time.Duration) (string, error)
+}
+
+// LocalPather is implemented by stores whose objects are files on this host
+type LocalPather interface {
+	// LocalPath returns the file path of key
+	LocalPath(key string) (string, error)
+}
+
+// ValidateKey checks that key is a clean relative path, so it cannot escape
+// the store's root directory or bucket prefix.
+func ValidateKey(key string) error {
+	if key == "" || strings.HasPrefix(key, "/") || strings.Contains(key, "\\") || path.Clean(key) != key {
+		return fmt.Errorf("invalid storage key %q", key)
+	}
+	for _, part := range strings.Split(key, "/") {
+		if part == ".." || part == "." {
+			return fmt.Errorf("invalid storage key %q", key)
+		}
+	}
+	return nil
+}
+
+// LocalCopy makes the object under key available as a file on this host, for
+// tools that need a path (pdftotext, Tesseract). Local stores return the file
+// itself; other stores download a temporary copy. Always call cleanup.
+func LocalCopy(ctx context.Context, store BlobStore, key string) (string, func(), error) {
+	noop := func() {}
+
+	if lp, ok := store.(LocalPather); ok {
+		p, err := lp.LocalPath(key)
+		if err != nil {
+			return "", noop, err
+		}
+		if _, err := os.Stat(p); err != nil {
+			if os.IsNotExist(err) {
+				return "", noop, ErrNotFound
+			}
+			return "", noop, err
+		}
+		return p, noop, nil
+	}
+
+	rc, err := store.Get(ctx, key)
+	if err != nil {
+		return "", noop, err
+	}
+	defer rc.Close()
+
+	f, err := os.CreateTemp("", "blob_*"+path.Ext(key))
+	if err != nil {
+		return "", noop, err
+	}
+	cleanup := func() { _ = os.Remove(f.Name()) }
+
+	if _, err := io.Copy(f, rc); err != nil {
+		f.Close()
+		cleanup()
+		return "", noop, fmt.Errorf("failed to download %s: %w", key, err)
+	}
+	if err := f.Close(); err != nil {
+		cleanup()
+		return "", noop, err
+	}
+	return f.Name(), cleanup, nil
+}
@@ -0,0 +1,560 @@
+// server/studyplan/studyplan.go
+
+package studyplan
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/nibir1/go-fiber-postgres-REST-boilerplate/prereq"
+)
+
+// Semester seasons; JYU teaches in an autumn and a spring semester
+const (
+	Autumn = "Autumn"
+	Spring = "Spring"
+)
+
+// Term is one semester, e.g. Autumn 2026. It is written as text in JSON.
+type Term struct {
+	Season string
+	Year   int
+}
+
+var termPattern = regexp.MustCompile(`(?i)^\s*(autumn|fall|syksy|spring|kevät)[\s-]*(\d{4})\s*$`)
+
+// ParseTerm reads a term such as "Autumn 2026", "spring-2027" or "syksy 2026".
+func ParseTerm(s string) (Term, error) {
+	m := termPattern.FindStringSubmatch(s)
+	if m == nil {
+		return Term{}, fmt.Errorf("invalid term %q, use e.g. \"Autumn 2026\" or \"Spring 2027\"", s)
+	}
+	year, _ := strconv.Atoi(m[2])
+	switch strings.ToLower(m[1]) {
+	case "autumn", "fall", "syksy":
+		return Term{Season: Autumn, Year: year}, nil
+	default:
+		return Term{Season: Spring, Year: year}, nil
+	}
+}
+
+// NextTerm is the first semester that has not started at t: the coming
+// autumn until the end of July, otherwise the next spring.
+func NextTerm(t time.Time) Term {
+	if t.Month() <= time.July {
+		return Term{Season: Autumn, Year: t.Year()}
+	}
+	return Term{Season: Spring, Year: t.Year() + 1}
+}
+
+// Next returns the semester after t.
+func (t Term) Next() Term {
+	if t.Season == Autumn {
+		return Term{Season: Spring, Year: t.Year + 1}
+	}
+	return Term{Season: Autumn, Year: t.Year}
+}
+
+// IsZero reports whether t is unset.
+func (t Term) IsZero() bool {
+	return t.Season == ""
+}
+
+func (t Term) String() string {
+	if t.IsZero() {
+		return ""
+	}
+	return fmt.Sprintf("%s %d", t.Season, t.Year)
+}
+
+// MarshalText implements encoding.TextMarshaler.
+func (t Term) MarshalText() ([]byte, error) {
+	return []byte(t.String()), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler.
+func (t *Term) UnmarshalText(text []byte) error {
+	parsed, err := ParseTerm(string(text))
+	if err != nil {
+		return err
+	}
+	*t = parsed
+	return nil
+}
+
+// Options are the constraints a plan is built with
+type Options struct {
+	Start      Term    `json:"start"`
+	Semesters  int     `json:"semesters"`
+	MaxCredits float64 `json:"max_credits_per_semester"`
+	// Language the student wants to study in; "" accepts any
+	Language string `json:"language,omitempty"`
+}
+
+// Course is a catalogue course as the planner sees it
+type Course struct {
+	ID            int64
+	Code          string
+	Name          string
+	Language      string
+	Credits       float64
+	Prerequisites prereq.Prerequisites
+}
+
+// Plan is a semester-by-semester study plan
+type Plan struct {
+	Settings     Options       `json:"settings"`
+	Semesters    []Semester    `json:"semesters"`
+	Unscheduled  []Unscheduled `json:"unscheduled"`
+	TotalCredits float64       `json:"total_credits"`
+	// Problems found by Check, e.g. after a manual edit
+	Warnings []string `json:"warnings"`
+}
+
+// Semester is one term of a plan
+type Semester struct {
+	Term    Term            `json:"term"`
+	Credits float64         `json:"credits"`
+	Courses []PlannedCourse `json:"courses"`
+}
+
+// PlannedCourse is a course placed in a semester. Requested courses were
+// asked for; the others were added as prerequisites of RequiredBy.
+type PlannedCourse struct {
+	CourseID   int64    `json:"course_id"`
+	Code       string   `json:"code"`
+	Name       string   `json:"name"`
+	Credits    float64  `json:"credits"`
+	Language   string   `json:"language,omitempty"`
+	Requested  bool     `json:"requested"`
+	RequiredBy []string `json:"required_by,omitempty"`
+}
+
+// Unscheduled is a requested course the plan could not place, and why
+type Unscheduled struct {
+	Code   string `json:"code"`
+	Name   string `json:"name,omitempty"`
+	Reason string `json:"reason"`
+}
+
+// Layout is a manually arranged semester: its term and course codes
+type Layout struct {
+	Term  Term     `json:"term"`
+	Codes []string `json:"codes"`
+}
+
+func normalizeCode(code string) string {
+	return strings.ToUpper(strings.TrimSpace(code))
+}
+
+func index(catalogue []Course) map[string]Course {
+	byCode := make(map[string]Course, len(catalogue))
+	for _, c := range catalogue {
+		byCode[normalizeCode(c.Code)] = c
+	}
+	return byCode
+}
+
+// -----------------------------------------------------------------------------
+// BUILD
+// -----------------------------------------------------------------------------
+
+// Build plans the target courses over opts.Semesters semesters starting at
+// opts.Start. Required prerequisites that are not completed are added to the
+// plan (for alternatives, preferring courses already planned, then courses in
+// the preferred language), and every course is placed after the planned
+// courses its prerequisites name. Semesters are filled up to opts.MaxCredits,
+// starting with the courses that head the longest prerequisite chains.
+// Targets that are completed, unknown, in another language or do not fit are
+// listed in Unscheduled.
+func Build(catalogue []Course, completed map[string]bool, targets []string, opts Options) Plan {
+	p := &planner{
+		byCode:    index(catalogue),
+		completed: completed,
+		opts:      opts,
+		selected:  map[string]*PlannedCourse{},
+	}
+	plan := Plan{Settings: opts, Unscheduled: []Unscheduled{}}
+
+	seen := map[string]bool{}
+	for _, code := range targets {
+		code = normalizeCode(code)
+		if code == "" || seen[code] {
+			continue
+		}
+		seen[code] = true
+
+		course, ok := p.byCode[code]
+		reason := ""
+		switch {
+		case !ok:
+			reason = "not in the catalogue"
+		case completed[code]:
+			reason = "already completed"
+		case !p.languageOK(course):
+			reason = fmt.Sprintf("not taught in %s", opts.Language)
+		default:
+			if err := p.add(code); err != nil {
+				reason = err.Error()
+			}
+		}
+		if reason != "" {
+			plan.Unscheduled = append(plan.Unscheduled, Unscheduled{Code: code, Name: course.Name, Reason: reason})
+		}
+	}
+
+	p.schedule(&plan)
+	plan.Check(catalogue, completed)
+	return plan
+}
+
+type planner struct {
+	byCode    map[string]Course
+	completed map[string]bool
+	opts      Options
+	selected  map[string]*PlannedCourse
+	order     []string
+}
+
+// pick is a course to add to the plan, required by another planned course
+// unless requiredBy is empty
+type pick struct {
+	code       string
+	requiredBy string
+}
+
+// add puts a requested course and its missing prerequisites into the plan.
+func (p *planner) add(code string) error {
+	picks, err := p.resolve(code, map[string]bool{})
+	if err != nil {
+		return err
+	}
+	for _, pk := range picks {
+		pc, ok := p.selected[pk.code]
+		if !ok {
+			c := p.byCode[pk.code]
+			pc = &PlannedCourse{CourseID: c.ID, Code: c.Code, Name: c.Name, Credits: c.Credits, Language: c.Language}
+			p.selected[pk.code] = pc
+			p.order = append(p.order, pk.code)
+		}
+		if pk.requiredBy != "" && !contains(pc.RequiredBy, pk.requiredBy) {
+			pc.RequiredBy = append(pc.RequiredBy, pk.requiredBy)
+		}
+	}
+	p.selected[code].Requested = true
+	return nil
+}
+
+// resolve returns code preceded by the courses needed to meet its required
+// prerequisites. visiting guards against prerequisite cycles.
+func (p *planner) resolve(code string, visiting map[string]bool) ([]pick, error) {
+	if p.completed[code] || visiting[code] {
+		return nil, nil
+	}
+	if _, ok := p.selected[code]; ok {
+		return []pick{{code: code}}, nil
+	}
+	visiting[code] = true
+	defer delete(visiting, code)
+
+	var picks []pick
+	pre := p.byCode[code].Prerequisites
+	if pre.Blocks(p.completed) {
+		var err error
+		if picks, err = p.satisfy(pre.Requirement, code, visiting); err != nil {
+			return nil, err
+		}
+	}
+	return append(picks, pick{code: code}), nil
+}
+
+func (p *planner) satisfy(req *prereq.Requirement, parent string, visiting map[string]bool) ([]pick, error) {
+	switch req.Kind {
+	case prereq.KindCourse:
+		if p.completed[req.Code] {
+			return nil, nil
+		}
+		if _, ok := p.byCode[req.Code]; !ok {
+			return nil, fmt.Errorf("prerequisite %s is not in the catalogue", req.Code)
+		}
+		picks, err := p.resolve(req.Code, visiting)
+		if err != nil {
+			return nil, err
+		}
+		if n := len(picks); n > 0 {
+			picks[n-1].requiredBy = parent
+		}
+		return picks, nil
+
+	case prereq.KindAll:
+		var picks []pick
+		for _, item := range req.Items {
+			sub, err := p.satisfy(item, parent, visiting)
+			if err != nil {
+				return nil, err
+			}
+			picks = append(picks, sub...)
+		}
+		return picks, nil
+
+	default:
+		if req.Met(p.completed) {
+			return nil, nil
+		}
+		var firstErr error
+		for _, item := range p.rankAlternatives(req.Items) {
+			picks, err := p.satisfy(item, parent, visiting)
+			if err == nil {
+				return picks, nil
+			}
+			if firstErr == nil {
+				firstErr = err
+			}
+		}
+		return nil, firstErr
+	}
+}
+
+// rankAlternatives orders alternatives: already planned first, then those
+// taught in the preferred language, otherwise as written.
+func (p *planner) rankAlternatives(items []*prereq.Requirement) []*prereq.Requirement {
+	score := func(r *prereq.Requirement) int {
+		planned, language := true, true
+		for _, code := range r.Codes() {
+			if _, ok := p.selected[code]; !ok && !p.completed[code] {
+				planned = false
+			}
+			if c, ok := p.byCode[code]; !ok || !p.languageOK(c) {
+				language = false
+			}
+		}
+		switch {
+		case planned:
+			return 0
+		case language:
+			return 1
+		}
+		return 2
+	}
+	ranked := append([]*prereq.Requirement(nil), items...)
+	sort.SliceStable(ranked, func(i, j int) bool { return score(ranked[i]) < score(ranked[j]) })
+	return ranked
+}
+
+func (p *planner) languageOK(c Course) bool {
+	if p.opts.Language == "" || c.Language == "" {
+		return true
+	}
+	return strings.Contains(strings.ToLower(c.Language), strings.ToLower(p.opts.Language))
+}
+
+// dependencies returns the planned courses named in code's prerequisites,
+// required or advisory.
+func (p *planner) dependencies(code string) []string {
+	req := p.byCode[code].Prerequisites.Requirement
+	if req == nil {
+		return nil
+	}
+	var deps []string
+	for _, dep := range req.Codes() {
+		if _, ok := p.selected[dep]; ok && dep != code {
+			deps = append(deps, dep)
+		}
+	}
+	return deps
+}
+
+// schedule places the selected courses into semesters.
+func (p *planner) schedule(plan *Plan) {
+	// Height: length of the longest chain of planned courses waiting on a course
+	dependents := map[string][]string{}
+	for _, code := range p.order {
+		for _, dep := range p.dependencies(code) {
+			dependents[dep] = append(dependents[dep], code)
+		}
+	}
+	height := map[string]int{}
+	var measure func(code string, visiting map[string]bool) int
+	measure = func(code string, visiting map[string]bool) int {
+		if h, ok := height[code]; ok {
+			return h
+		}
+		if visiting[code] {
+			return 0
+		}
+		visiting[code] = true
+		h := 1
+		for _, d := range dependents[code] {
+			if dh := measure(d, visiting) + 1; dh > h {
+				h = dh
+			}
+		}
+		delete(visiting, code)
+		height[code] = h
+		return h
+	}
+
+	var remaining []string
+	for _, code := range p.order {
+		measure(code, map[string]bool{})
+		pc := p.selected[code]
+		if pc.Credits > p.opts.MaxCredits {
+			plan.Unscheduled = append(plan.Unscheduled, Unscheduled{
+				Code:   pc.Code,
+				Name:   pc.Name,
+				Reason: fmt.Sprintf("%g credits exceed the limit of %g per semester", pc.Credits, p.opts.MaxCredits),
+			})
+			continue
+		}
+		remaining = append(remaining, code)
+	}
+	sort.SliceStable(remaining, func(i, j int) bool { return height[remaining[i]] > height[remaining[j]] })
+
+	done := map[string]bool{}
+	term := p.opts.Start
+	plan.Semesters = make([]Semester, 0, p.opts.Semesters)
+	for i := 0; i < p.opts.Semesters; i++ {
+		sem := Semester{Term: term, Courses: []PlannedCourse{}}
+		var next []string
+		for _, code := range remaining {
+			pc := p.selected[code]
+			if !allDone(p.dependencies(code), done) || sem.Credits+pc.Credits > p.opts.MaxCredits {
+				next = append(next, code)
+				continue
+			}
+			sem.Courses = append(sem.Courses, *pc)
+			sem.Credits += pc.Credits
+		}
+		for _, c := range sem.Courses {
+			done[normalizeCode(c.Code)] = true
+		}
+		plan.Semesters = append(plan.Semesters, sem)
+		remaining = next
+		term = term.Next()
+	}
+
+	for _, code := range remaining {
+		pc := p.selected[code]
+		plan.Unscheduled = append(plan.Unscheduled, Unscheduled{
+			Code:   pc.Code,
+			Name:   pc.Name,
+			Reason: fmt.Sprintf("does not fit in %d semesters", p.opts.Semesters),
+		})
+	}
+}
+
+func allDone(codes []string, done map[string]bool) bool {
+	for _, code := range codes {
+		if !done[code] {
+			return false
+		}
+	}
+	return true
+}
+
+func contains(list []string, s string) bool {
+	for _, v := range list {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
+
+// -----------------------------------------------------------------------------
+// EDIT & CHECK
+// -----------------------------------------------------------------------------
+
+// Rearrange returns the plan with its semesters replaced by layout; course
+// details come from the catalogue. Courses new to the plan count as
+// requested, and scheduled courses leave Unscheduled. The result is checked
+// but not rejected for warnings: students may overrule the planner.
+func (p Plan) Rearrange(catalogue []Course, completed map[string]bool, layout []Layout) (Plan, error) {
+	byCode := index(catalogue)
+	previous := map[string]PlannedCourse{}
+	for _, sem := range p.Semesters {
+		for _, c := range sem.Courses {
+			previous[normalizeCode(c.Code)] = c
+		}
+	}
+
+	placed := map[string]Term{}
+	semesters := make([]Semester, 0, len(layout))
+	for i, l := range layout {
+		if l.Term.IsZero() {
+			return Plan{}, fmt.Errorf("semester %d has no term", i+1)
+		}
+		sem := Semester{Term: l.Term, Courses: []PlannedCourse{}}
+		for _, code := range l.Codes {
+			code = normalizeCode(code)
+			if t, dup := placed[code]; dup {
+				return Plan{}, fmt.Errorf("%s is planned twice (%s and %s)", code, t, l.Term)
+			}
+			course, ok := byCode[code]
+			if !ok {
+				return Plan{}, fmt.Errorf("%s is not in the catalogue", code)
+			}
+			placed[code] = l.Term
+
+			pc, ok := previous[code]
+			if !ok {
+				pc = PlannedCourse{Requested: true}
+			}
+			pc.CourseID, pc.Code, pc.Name = course.ID, course.Code, course.Name
+			pc.Credits, pc.Language = course.Credits, course.Language
+			sem.Courses = append(sem.Courses, pc)
+		}
+		semesters = append(semesters, sem)
+	}
+
+	out := p
+	out.Semesters = semesters
+	out.Settings.Semesters = len(semesters)
+	if len(semesters) > 0 {
+		out.Settings.Start = semesters[0].Term
+	}
+	out.Unscheduled = []Unscheduled{}
+	for _, u := range p.Unscheduled {
+		if _, ok := placed[normalizeCode(u.Code)]; !ok {
+			out.Unscheduled = append(out.Unscheduled, u)
+		}
+	}
+	out.Check(catalogue, completed)
+	return out, nil
+}
+
+// Check recomputes the credit totals and lists semesters over the credit cap
+// and courses planned before their required prerequisites are completed.
+func (p *Plan) Check(catalogue []Course, completed map[string]bool) {
+	byCode := index(catalogue)
+	done := make(map[string]bool, len(completed))
+	for code := range completed {
+		done[code] = true
+	}
+
+	p.TotalCredits = 0
+	p.Warnings = []string{}
+	for i := range p.Semesters {
+		sem := &p.Semesters[i]
+		sem.Credits = 0
+		for _, c := range sem.Courses {
+			sem.Credits += c.Credits
+			if pre := byCode[normalizeCode(c.Code)].Prerequisites; pre.Blocks(done) {
+				p.Warnings = append(p.Warnings, fmt.Sprintf("%s (%s) is planned before its prerequisites: %s",
+					c.Code, sem.Term, strings.Join(pre.Missing(done), ", ")))
+			}
+		}
+		if p.Settings.MaxCredits > 0 && sem.Credits > p.Settings.MaxCredits {
+			p.Warnings = append(p.Warnings, fmt.Sprintf("%s: %g credits exceed the limit of %g per semester",
+				sem.Term, sem.Credits, p.Settings.MaxCredits))
+		}
+		for _, c := range sem.Courses {
+			done[normalizeCode(c.Code)] = true
+		}
+		p.TotalCredits += sem.Credits
+	}
+}
@@ -0,0 +1,139 @@
+// server/studyplan/studyplan_test.go
+
+package studyplan
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/nibir1/go-fiber-postgres-REST-boilerplate/prereq"
+	"github.com/stretchr/testify/require"
+)
+
+func testCatalogueCourse(id int64, code, language, prerequisites string) Course {
+	return Course{
+		ID:            id,
+		Code:          code,
+		Name:          code + " name",
+		Language:      language,
+		Credits:       5,
+		Prerequisites: prereq.Parse(prerequisites, code),
+	}
+}
+
+var testCatalogue = []Course{
+	testCatalogueCourse(1, "TIEA311", "Finnish", ""),
+	testCatalogueCourse(2, "ITKA201", "English, Finnish", ""),
+	testCatalogueCourse(3, "TIEA211", "Finnish", ""),
+	testCatalogueCourse(4, "TIES4211", "English", ""),
+	testCatalogueCourse(5, "TIES471", "English", "Compulsory prerequisites: Option 1: 5 cr TIEA311, 5 cr ITKA201"),
+	testCatalogueCourse(6, "TIES513", "English", "TIES4211 (or the earlier TIEA211), TIEA311 and TIES471"),
+	testCatalogueCourse(7, "TJTS5012", "English", "It is recommended to complete TJTS5010 first."),
+	testCatalogueCourse(8, "TJTS5010", "English", ""),
+	testCatalogueCourse(9, "KOGS2200", "Finnish", ""),
+	testCatalogueCourse(10, "TIES598", "English", "TIES5999 and TIES483"),
+}
+
+func plannedCodes(sem Semester) []string {
+	codes := make([]string, 0, len(sem.Courses))
+	for _, c := range sem.Courses {
+		codes = append(codes, c.Code)
+	}
+	return codes
+}
+
+func TestBuild(t *testing.T) {
+	opts := Options{Start: Term{Season: Autumn, Year: 2026}, Semesters: 3, MaxCredits: 10, Language: "English"}
+	completed := map[string]bool{"ITKA201": true}
+
+	plan := Build(testCatalogue, completed, []string{"ties513", "TJTS5012", "TJTS5010", "ITKA201", "KOGS2200", "TIES598", "NOPE101"}, opts)
+
+	// TIES513 pulls in TIEA311 and TIES471 (whose other prerequisite is
+	// completed) and picks the English TIES4211 over TIEA211. Chains go first;
+	// TJTS5012 waits for its recommended TJTS5010.
+	require.Len(t, plan.Semesters, 3)
+	require.Equal(t, "Autumn 2026", plan.Semesters[0].Term.String())
+	require.Equal(t, []string{"TIEA311", "TIES4211"}, plannedCodes(plan.Semesters[0]))
+	require.Equal(t, []string{"TIES471", "TJTS5010"}, plannedCodes(plan.Semesters[1]))
+	require.Equal(t, []string{"TIES513", "TJTS5012"}, plannedCodes(plan.Semesters[2]))
+	require.Equal(t, "Autumn 2027", plan.Semesters[2].Term.String())
+	require.Equal(t, 30.0, plan.TotalCredits)
+	require.Empty(t, plan.Warnings)
+
+	tiea311 := plan.Semesters[0].Courses[0]
+	require.False(t, tiea311.Requested)
+	require.Equal(t, []string{"TIES513", "TIES471"}, tiea311.RequiredBy)
+	require.True(t, plan.Semesters[2].Courses[0].Requested)
+
+	require.Equal(t, []Unscheduled{
+		{Code: "ITKA201", Name: "ITKA201 name", Reason: "already completed"},
+		{Code: "KOGS2200", Name: "KOGS2200 name", Reason: "not taught in English"},
+		{Code: "TIES598", Name: "TIES598 name", Reason: "prerequisite TIES5999 is not in the catalogue"},
+		{Code: "NOPE101", Reason: "not in the catalogue"},
+	}, plan.Unscheduled)
+}
+
+func TestBuildCapacity(t *testing.T) {
+	opts := Options{Start: Term{Season: Spring, Year: 2027}, Semesters: 2, MaxCredits: 5}
+	plan := Build(testCatalogue, map[string]bool{}, []string{"TIES513"}, opts)
+
+	require.Equal(t, []string{"TIEA311"}, plannedCodes(plan.Semesters[0]))
+	require.Equal(t, []string{"ITKA201"}, plannedCodes(plan.Semesters[1]))
+	require.Len(t, plan.Unscheduled, 3)
+	for _, u := range plan.Unscheduled {
+		require.Equal(t, "does not fit in 2 semesters", u.Reason)
+	}
+
+	big := testCatalogueCourse(11, "TJTS5900", "English", "")
+	big.Credits = 30
+	plan = Build(append(testCatalogue, big), nil, []string{"TJTS5900"}, opts)
+	require.Equal(t, "30 credits exceed the limit of 5 per semester", plan.Unscheduled[0].Reason)
+}
+
+func TestRearrange(t *testing.T) {
+	opts := Options{Start: Term{Season: Autumn, Year: 2026}, Semesters: 2, MaxCredits: 10}
+	plan := Build(testCatalogue, nil, []string{"TJTS5012", "TIES4211"}, opts)
+
+	edited, err := plan.Rearrange(testCatalogue, nil, []Layout{
+		{Term: Term{Season: Autumn, Year: 2026}, Codes: []string{"TIES471", "TIES4211", "tjts5010"}},
+		{Term: Term{Season: Spring, Year: 2027}, Codes: []string{"TJTS5012"}},
+	})
+	require.NoError(t, err)
+	require.Equal(t, 20.0, edited.TotalCredits)
+	require.True(t, edited.Semesters[0].Courses[0].Requested)
+	require.Equal(t, []string{
+		"TIES471 (Autumn 2026) is planned before its prerequisites: TIEA311, ITKA201",
+		"Autumn 2026: 15 credits exceed the limit of 10 per semester",
+	}, edited.Warnings)
+
+	_, err = plan.Rearrange(testCatalogue, nil, []Layout{
+		{Term: Term{Season: Autumn, Year: 2026}, Codes: []string{"TIES471"}},
+		{Term: Term{Season: Spring, Year: 2027}, Codes: []string{"ties471"}},
+	})
+	require.EqualError(t, err, "TIES471 is planned twice (Autumn 2026 and Spring 2027)")
+
+	_, err = plan.Rearrange(testCatalogue, nil, []Layout{{Term: Term{Season: Autumn, Year: 2026}, Codes: []string{"NOPE101"}}})
+	require.EqualError(t, err, "NOPE101 is not in the catalogue")
+}
+
+func TestTerm(t *testing.T) {
+	term, err := ParseTerm("syksy-2026")
+	require.NoError(t, err)
+	require.Equal(t, Term{Season: Autumn, Year: 2026}, term)
+	require.Equal(t, Term{Season: Spring, Year: 2027}, term.Next())
+	require.Equal(t, Term{Season: Autumn, Year: 2027}, term.Next().Next())
+
+	_, err = ParseTerm("winter 2026")
+	require.Error(t, err)
+
+	require.Equal(t, Term{Season: Autumn, Year: 2026}, NextTerm(time.Date(2026, time.March, 1, 0, 0, 0, 0, time.UTC)))
+	require.Equal(t, Term{Season: Spring, Year: 2027}, NextTerm(time.Date(2026, time.October, 16, 0, 0, 0, 0, time.UTC)))
+
+	var layout Layout
+	require.NoError(t, json.Unmarshal([]byte(`{"term": "Spring 2027", "codes": ["TIES471"]}`), &layout))
+	require.Equal(t, Term{Season: Spring, Year: 2027}, layout.Term)
+	data, err := json.Marshal(layout)
+	require.NoError(t, err)
+	require.JSONEq(t, `{"term": "Spring 2027", "codes": ["TIES471"]}`, string(data))
+}
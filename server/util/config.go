@@ -17,13 +17,50 @@ type Config struct {
 	TokenSymmetricKey   string        `mapstructure:"TOKEN_SYMMETRIC_KEY"`
 	AccessTokenDuration time.Duration `mapstructure:"ACCESS_TOKEN_DURATION"`
 	AllowedOrigins      string        `mapstructure:"ALLOWED_ORIGINS"`
-	UploadDir           string        `mapstructure:"UPLOAD_DIR"`
+
+	// File storage: "local" keeps files below StorageLocalDir, "s3" in an
+	// S3-compatible bucket (AWS S3, MinIO)
+	StorageBackend       string        `mapstructure:"STORAGE_BACKEND"`
+	StorageLocalDir      string        `mapstructure:"STORAGE_LOCAL_DIR"`
+	StoragePresignExpiry time.Duration `mapstructure:"STORAGE_PRESIGN_EXPIRY"`
+	S3Endpoint           string        `mapstructure:"S3_ENDPOINT"`
+	S3Region             string        `mapstructure:"S3_REGION"`
+	S3Bucket             string        `mapstructure:"S3_BUCKET"`
+	S3AccessKeyID        string        `mapstructure:"S3_ACCESS_KEY_ID"`
+	S3SecretAccessKey    string        `mapstructure:"S3_SECRET_ACCESS_KEY"`
+	S3UsePathStyle       bool          `mapstructure:"S3_USE_PATH_STYLE"`
+
+	// Transcript upload limits
+	UploadMaxSizeMB int `mapstructure:"UPLOAD_MAX_SIZE_MB"`
+	UploadMaxPages  int `mapstructure:"UPLOAD_MAX_PAGES"`
 
 	// AI Inference (OpenAI)
 	OpenAIAPIKey       string `mapstructure:"OPENAI_API_KEY"`
 	OpenAIModel        string `mapstructure:"OPENAI_MODEL"`
 	OCRFallbackEnabled bool   `mapstructure:"OCR_FALLBACK_ENABLED"`
 
+	// Course embeddings: "local" (hashed vocabulary, no network) or "openai".
+	// Recommendations send only the RecommendationCandidates courses most
+	// similar to the student's preference and transcript to the model
+	EmbeddingProvider        string `mapstructure:"EMBEDDING_PROVIDER"`
+	EmbeddingModel           string `mapstructure:"EMBEDDING_MODEL"`
+	RecommendationCandidates int    `mapstructure:"RECOMMENDATION_CANDIDATES"`
+
+	// Text extraction: comma-separated extractors tried in order (pdf, pdftotext, tesseract)
+	TextExtractors             string  `mapstructure:"TEXT_EXTRACTORS"`
+	TextExtractorMinConfidence float64 `mapstructure:"TEXT_EXTRACTOR_MIN_CONFIDENCE"`
+	PdftotextPath              string  `mapstructure:"PDFTOTEXT_PATH"`
+	PdftoppmPath               string  `mapstructure:"PDFTOPPM_PATH"`
+
+	// OCR languages (Tesseract traineddata), comma-separated; the first is the default
+	// and the language of each page is detected among them
+	OCRLanguages string `mapstructure:"OCR_LANGUAGES"`
+
+	// Transcript ingestion workers
+	TranscriptWorkers         int           `mapstructure:"TRANSCRIPT_WORKERS"`
+	TranscriptJobPollInterval time.Duration `mapstructure:"TRANSCRIPT_JOB_POLL_INTERVAL"`
+	TranscriptJobStaleAfter   time.Duration `mapstructure:"TRANSCRIPT_JOB_STALE_AFTER"`
+
 	// Web Search (Brave API)
 	WebSearchEnabled    bool   `mapstructure:"WEB_SEARCH_ENABLED"`
 	WebSearchMaxResults int    `mapstructure:"WEB_SEARCH_MAX_RESULTS"`
@@ -49,7 +86,27 @@ func LoadConfig(path string) (config Config, err error) {
 	// Sensible defaults
 	viper.SetDefault("OPENAI_MODEL", "gpt-4o-mini")
 	viper.SetDefault("OCR_FALLBACK_ENABLED", true)
-	viper.SetDefault("UPLOAD_DIR", "uploads")
+
+	viper.SetDefault("EMBEDDING_PROVIDER", "local")
+	viper.SetDefault("EMBEDDING_MODEL", "text-embedding-3-small")
+	viper.SetDefault("RECOMMENDATION_CANDIDATES", 40)
+
+	viper.SetDefault("STORAGE_BACKEND", "local")
+	viper.SetDefault("STORAGE_LOCAL_DIR", ".")
+	viper.SetDefault("STORAGE_PRESIGN_EXPIRY", "15m")
+	viper.SetDefault("S3_REGION", "us-east-1")
+	viper.SetDefault("S3_USE_PATH_STYLE", true)
+
+	viper.SetDefault("UPLOAD_MAX_SIZE_MB", 20)
+	viper.SetDefault("UPLOAD_MAX_PAGES", 30)
+
+	viper.SetDefault("TEXT_EXTRACTORS", "pdf,pdftotext,tesseract")
+	viper.SetDefault("TEXT_EXTRACTOR_MIN_CONFIDENCE", 0.6)
+	viper.SetDefault("OCR_LANGUAGES", "eng,fin,swe")
+
+	viper.SetDefault("TRANSCRIPT_WORKERS", 2)
+	viper.SetDefault("TRANSCRIPT_JOB_POLL_INTERVAL", "5s")
+	viper.SetDefault("TRANSCRIPT_JOB_STALE_AFTER", "10m")
 
 	viper.SetDefault("WEB_SEARCH_PROVIDER", "brave")
 	viper.SetDefault("WEB_SEARCH_ENABLED", true)